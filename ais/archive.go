@@ -8,9 +8,7 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"bytes"
-	"compress/gzip"
 	"io"
-	"os"
 	"path/filepath"
 
 	"github.com/NVIDIA/aistore/cmn"
@@ -31,15 +29,21 @@ type (
 		io.LimitedReader
 	}
 	cslClose struct {
-		gzr io.ReadCloser
-		R   io.Reader
-		N   int64
+		dr io.ReadCloser // decompressor
+		R  io.Reader
+		N  int64
 	}
 	cslFile struct {
 		file io.ReadCloser
 		size int64
 	}
 
+	// object's content: *os.File or decrypted (see tgtsse.go)
+	objReader interface {
+		io.ReadSeeker
+		io.ReaderAt
+	}
+
 	detect struct {
 		offset int
 		sig    []byte
@@ -51,8 +55,10 @@ var (
 	magicTar  = detect{offset: 257, sig: []byte("ustar"), mime: cos.ExtTar}
 	magicGzip = detect{sig: []byte{0x1f, 0x8b}, mime: cos.ExtTarTgz}
 	magicZip  = detect{sig: []byte{0x50, 0x4b}, mime: cos.ExtZip}
+	magicLz4  = detect{sig: []byte{0x04, 0x22, 0x4d, 0x18}, mime: cos.ExtTarLz4}
+	magicZstd = detect{sig: []byte{0x28, 0xb5, 0x2f, 0xfd}, mime: cos.ExtTarZst}
 
-	allMagics = []detect{magicTar, magicGzip, magicZip} // NOTE: must contain all
+	allMagics = []detect{magicTar, magicGzip, magicZip, magicLz4, magicZstd} // NOTE: must contain all
 )
 
 func (csl *cslLimited) Size() int64 { return csl.N }
@@ -60,7 +66,7 @@ func (*cslLimited) Close() error    { return nil }
 
 func (csc *cslClose) Read(b []byte) (int, error) { return csc.R.Read(b) }
 func (csc *cslClose) Size() int64                { return csc.N }
-func (csc *cslClose) Close() error               { return csc.gzr.Close() }
+func (csc *cslClose) Close() error               { return csc.dr.Close() }
 
 func (csf *cslFile) Read(b []byte) (int, error) { return csf.file.Read(b) }
 func (csf *cslFile) Size() int64                { return csf.size }
@@ -74,7 +80,7 @@ func notFoundInArch(filename, archname string) error {
 // GET OBJECT: archive //
 /////////////////////////
 
-func (goi *getObjInfo) freadArch(file *os.File) (cos.ReadCloseSizer, error) {
+func (goi *getObjInfo) freadArch(file objReader, size int64) (cos.ReadCloseSizer, error) {
 	mime, err := goi.mime(file)
 	if err != nil {
 		return nil, err
@@ -84,17 +90,17 @@ func (goi *getObjInfo) freadArch(file *os.File) (cos.ReadCloseSizer, error) {
 	switch mime {
 	case cos.ExtTar:
 		return freadTar(file, filename, archname)
-	case cos.ExtTarTgz, cos.ExtTgz:
-		return freadTgz(file, filename, archname)
+	case cos.ExtTarTgz, cos.ExtTgz, cos.ExtTarLz4, cos.ExtTarZst:
+		return freadTarComp(file, filename, archname, mime)
 	case cos.ExtZip:
-		return freadZip(file, filename, archname, goi.lom.SizeBytes())
+		return freadZip(file, filename, archname, size)
 	default:
 		debug.Assert(false)
 		return nil, cos.NewUnknownMimeError(mime)
 	}
 }
 
-func (goi *getObjInfo) mime(file *os.File) (m string, err error) {
+func (goi *getObjInfo) mime(file objReader) (m string, err error) {
 	// either ok or non-empty user-defined mime type (that must work)
 	if m, err = cos.Mime(goi.archive.mime, goi.lom.ObjName); err == nil || goi.archive.mime != "" {
 		return
@@ -143,18 +149,20 @@ func freadTar(reader io.Reader, filename, archname string) (*cslLimited, error)
 	}
 }
 
-func freadTgz(reader io.Reader, filename, archname string) (csc *cslClose, err error) {
+// compressed TAR: gzip, lz4, or zstd
+func freadTarComp(reader io.Reader, filename, archname, mime string) (csc *cslClose, err error) {
 	var (
-		gzr *gzip.Reader
+		dr  io.ReadCloser
 		csl *cslLimited
 	)
-	if gzr, err = gzip.NewReader(reader); err != nil {
+	if dr, err = cos.NewTarDecompressor(reader, mime); err != nil {
 		return
 	}
-	if csl, err = freadTar(gzr, filename, archname); err != nil {
+	if csl, err = freadTar(dr, filename, archname); err != nil {
+		dr.Close()
 		return
 	}
-	csc = &cslClose{gzr: gzr /*to close*/, R: csl /*to read from*/, N: csl.N /*size*/}
+	csc = &cslClose{dr: dr /*to close*/, R: csl /*to read from*/, N: csl.N /*size*/}
 	return
 }
 
@@ -43,7 +43,9 @@ type (
 		// list of invalid tokens(revoked or of deleted users)
 		// Authn sends these tokens to primary for broadcasting
 		revokedTokens map[string]bool
-		version       int64
+		// S3 access key ID => token of the key's owner (pushed by AuthN)
+		s3Keys  map[string]string
+		version int64
 	}
 )
 
@@ -81,6 +83,13 @@ func (a *authManager) updateRevokedList(tokens *tokenList) {
 		a.revokedTokens[token] = true
 		delete(a.tokens, token)
 	}
+	if tokens.S3Keys != nil {
+		// (the entire set - replaces the current one)
+		for _, token := range a.s3Keys {
+			delete(a.tokens, token)
+		}
+		a.s3Keys = tokens.S3Keys
+	}
 	// clean up the list from obsolete data
 	for token := range a.revokedTokens {
 		rec, err := a.extractTokenData(token)
@@ -106,10 +115,28 @@ func (a *authManager) validateToken(token string) (ar *authn.Token, err error) {
 	}
 
 	ar, err = a.extractTokenData(token)
+	if err == nil && ar.S3Key != "" && a.s3Keys[ar.S3Key] != token {
+		// the S3 key was deleted, or its token reissued
+		ar, err = nil, authn.ErrTokenExpired
+	}
 	a.Unlock()
 	return
 }
 
+// Returns the token of the owner of a given S3 access key
+func (a *authManager) s3Token(keyID string) (token string, err error) {
+	a.Lock()
+	token, ok := a.s3Keys[keyID]
+	a.Unlock()
+	if !ok {
+		return "", authn.ErrInvalidToken
+	}
+	if _, err = a.validateToken(token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
 // Decrypts token and returns information about a user for whom the token
 // was issued. Return error is the token expired or does not include all
 // mandatory fields
@@ -151,6 +178,12 @@ func (a *authManager) revokedTokenList() *tokenList {
 		tlist.Tokens[idx] = token
 		idx++
 	}
+	if a.s3Keys != nil {
+		tlist.S3Keys = make(map[string]string, len(a.s3Keys))
+		for keyID, token := range a.s3Keys {
+			tlist.S3Keys[keyID] = token
+		}
+	}
 
 	a.Unlock()
 	return &tokenList{TokenList: tlist}
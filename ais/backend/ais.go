@@ -489,6 +489,9 @@ func (m *AISBackendProvider) PutObj(r io.ReadCloser, lom *cluster.LOM) (errCode
 		Reader:     r.(cos.ReadOpenCloser),
 		Size:       uint64(lom.SizeBytes(true)), // _special_ because it's still workfile.
 	}
+	if v, ok := lom.GetCustomKey(cmn.ReplicaObjMD); ok {
+		args.CustomMD = cos.SimpleKVs{cmn.ReplicaObjMD: v} // (see xs/replicate.go)
+	}
 	if err = api.PutObject(args); err != nil {
 		errCode, err = extractErrCode(err)
 		return
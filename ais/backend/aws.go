@@ -26,6 +26,7 @@ import (
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -48,12 +49,15 @@ type (
 )
 
 var (
-	clients map[string]*s3.S3 // one client per AWS region
+	clients map[string]*s3.S3 // one client per AWS region (and per S3-compatible endpoint and profile)
 	cmu     sync.RWMutex
 )
 
 // interface guard
-var _ cluster.BackendProvider = (*awsProvider)(nil)
+var (
+	_ cluster.BackendProvider = (*awsProvider)(nil)
+	_ cluster.RangeReader     = (*awsProvider)(nil)
+)
 
 func NewAWS(t cluster.Target) (cluster.BackendProvider, error) {
 	clients = make(map[string]*s3.S3, 2)
@@ -62,6 +66,19 @@ func NewAWS(t cluster.Target) (cluster.BackendProvider, error) {
 
 func (*awsProvider) Provider() string { return cmn.ProviderAmazon }
 
+// remote bucket with its properties - in particular, those of the Cloud backend
+// of an ais bucket (see ExtraPropsAWS)
+func (awsp *awsProvider) remoteBck(bck *cluster.Bck) *cmn.Bck {
+	cloudBck := bck.RemoteBck()
+	if cloudBck.Props != nil {
+		return cloudBck
+	}
+	if props, ok := awsp.t.Bowner().Get().Get(cluster.NewBckEmbed(*cloudBck)); ok {
+		cloudBck = &cmn.Bck{Name: cloudBck.Name, Provider: cloudBck.Provider, Ns: cloudBck.Ns, Props: props}
+	}
+	return cloudBck
+}
+
 // https://docs.aws.amazon.com/cli/latest/userguide/cli-usage-pagination.html#cli-usage-pagination-serverside
 func (*awsProvider) MaxPageSize() uint { return 1000 }
 
@@ -77,16 +94,19 @@ func (awsp *awsProvider) CreateBucket(_ *cluster.Bck) (errCode int, err error) {
 // HEAD BUCKET //
 /////////////////
 
-func (*awsProvider) HeadBucket(_ ctx, bck *cluster.Bck) (bckProps cos.SimpleKVs, errCode int, err error) {
+func (awsp *awsProvider) HeadBucket(_ ctx, bck *cluster.Bck) (bckProps cos.SimpleKVs, errCode int, err error) {
 	var (
 		svc      *s3.S3
 		region   string
-		cloudBck = bck.RemoteBck()
+		cloudBck = awsp.remoteBck(bck)
 	)
 	if verbose {
 		glog.Infof("[head_bucket] %s", cloudBck.Name)
 	}
-	svc, region, _ = newClient(sessConf{bck: cloudBck}, "") // nolint:errcheck // on purpose
+	if svc, region, err = newClient(sessConf{bck: cloudBck}, ""); svc == nil {
+		errCode = http.StatusBadRequest
+		return
+	}
 	if region == "" {
 		// AWS bucket may not yet exist in the BMD -
 		// get the region manually and recreate S3 client.
@@ -126,14 +146,13 @@ func (awsp *awsProvider) ListObjects(bck *cluster.Bck, msg *cmn.ListObjsMsg) (bc
 	var (
 		svc      *s3.S3
 		h        = cmn.BackendHelpers.Amazon
-		cloudBck = bck.RemoteBck()
+		cloudBck = awsp.remoteBck(bck)
 	)
 	if verbose {
 		glog.Infof("list_objects %s", cloudBck.Name)
 	}
-	svc, _, err = newClient(sessConf{bck: cloudBck}, "[list_objects]")
-	if err != nil && verbose {
-		glog.Warning(err)
+	if svc, errCode, err = bckClient(cloudBck, "[list_objects]"); err != nil {
+		return
 	}
 
 	params := &s3.ListObjectsV2Input{Bucket: aws.String(cloudBck.Name)}
@@ -260,16 +279,15 @@ func (*awsProvider) ListBuckets(cmn.QueryBcks) (bcks cmn.Bcks, errCode int, err
 // HEAD OBJECT //
 /////////////////
 
-func (*awsProvider) HeadObj(_ ctx, lom *cluster.LOM) (oa *cmn.ObjAttrs, errCode int, err error) {
+func (awsp *awsProvider) HeadObj(_ ctx, lom *cluster.LOM) (oa *cmn.ObjAttrs, errCode int, err error) {
 	var (
 		headOutput *s3.HeadObjectOutput
 		svc        *s3.S3
 		h          = cmn.BackendHelpers.Amazon
-		cloudBck   = lom.Bck().RemoteBck()
+		cloudBck   = awsp.remoteBck(lom.Bck())
 	)
-	svc, _, err = newClient(sessConf{bck: cloudBck}, "[head_object]")
-	if err != nil && verbose {
-		glog.Warning(err)
+	if svc, errCode, err = bckClient(cloudBck, "[head_object]"); err != nil {
+		return
 	}
 	headOutput, err = svc.HeadObject(&s3.HeadObjectInput{
 		Bucket: aws.String(cloudBck.Name),
@@ -330,16 +348,15 @@ func (awsp *awsProvider) GetObj(ctx context.Context, lom *cluster.LOM, owt cmn.O
 // GET OBJ READER //
 ////////////////////
 
-func (*awsProvider) GetObjReader(ctx context.Context, lom *cluster.LOM) (r io.ReadCloser, expCksum *cos.Cksum,
+func (awsp *awsProvider) GetObjReader(ctx context.Context, lom *cluster.LOM) (r io.ReadCloser, expCksum *cos.Cksum,
 	errCode int, err error) {
 	var (
 		obj      *s3.GetObjectOutput
 		svc      *s3.S3
-		cloudBck = lom.Bck().RemoteBck()
+		cloudBck = awsp.remoteBck(lom.Bck())
 	)
-	svc, _, err = newClient(sessConf{bck: cloudBck}, "[get_object]")
-	if err != nil && verbose {
-		glog.Warning(err)
+	if svc, errCode, err = bckClient(cloudBck, "[get_object]"); err != nil {
+		return
 	}
 	obj, err = svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(cloudBck.Name),
@@ -363,6 +380,36 @@ func (*awsProvider) GetObjReader(ctx context.Context, lom *cluster.LOM) (r io.Re
 	return wrapReader(ctx, obj.Body), expCksum, 0, nil
 }
 
+func (awsp *awsProvider) GetObjReaderRange(ctx context.Context, lom *cluster.LOM, offset, length int64,
+	oa *cmn.ObjAttrs) (r io.ReadCloser, errCode int, err error) {
+	var (
+		obj      *s3.GetObjectOutput
+		svc      *s3.S3
+		cloudBck = awsp.remoteBck(lom.Bck())
+		input    = &s3.GetObjectInput{
+			Bucket: aws.String(cloudBck.Name),
+			Key:    aws.String(lom.ObjName),
+			Range:  aws.String(cmn.RangeHdr(offset, length).Get(cmn.HdrRange)),
+		}
+	)
+	if svc, errCode, err = bckClient(cloudBck, "[get_object_range]"); err != nil {
+		return
+	}
+	if oa != nil {
+		if v, ok := oa.GetCustomKey(cmn.VersionObjMD); ok {
+			input.VersionId = aws.String(v)
+		} else if v, ok := oa.GetCustomKey(cmn.ETag); ok {
+			input.IfMatch = aws.String("\"" + v + "\"")
+		}
+	}
+	obj, err = svc.GetObjectWithContext(ctx, input)
+	if err != nil {
+		errCode, err = awsErrorToAISError(err, cloudBck)
+		return
+	}
+	return wrapReader(ctx, obj.Body), 0, nil
+}
+
 func setCustomS3(lom *cluster.LOM, obj *s3.GetObjectOutput) (expCksum *cos.Cksum) {
 	h := cmn.BackendHelpers.Amazon
 	if v, ok := h.EncodeVersion(obj.VersionId); ok {
@@ -381,20 +428,19 @@ func setCustomS3(lom *cluster.LOM, obj *s3.GetObjectOutput) (expCksum *cos.Cksum
 // PUT OBJECT //
 ////////////////
 
-func (*awsProvider) PutObj(r io.ReadCloser, lom *cluster.LOM) (errCode int, err error) {
+func (awsp *awsProvider) PutObj(r io.ReadCloser, lom *cluster.LOM) (errCode int, err error) {
 	var (
 		svc                   *s3.S3
 		uploadOutput          *s3manager.UploadOutput
 		h                     = cmn.BackendHelpers.Amazon
 		cksumType, cksumValue = lom.Checksum().Get()
-		cloudBck              = lom.Bck().RemoteBck()
+		cloudBck              = awsp.remoteBck(lom.Bck())
 		md                    = make(map[string]*string, 2)
 	)
 	defer cos.Close(r)
 
-	svc, _, err = newClient(sessConf{bck: cloudBck}, "[put_object]")
-	if err != nil && verbose {
-		glog.Warning(err)
+	if svc, errCode, err = bckClient(cloudBck, "[put_object]"); err != nil {
+		return
 	}
 
 	md[awsChecksumType] = aws.String(cksumType)
@@ -431,14 +477,13 @@ func (*awsProvider) PutObj(r io.ReadCloser, lom *cluster.LOM) (errCode int, err
 // DELETE OBJECT //
 ///////////////////
 
-func (*awsProvider) DeleteObj(lom *cluster.LOM) (errCode int, err error) {
+func (awsp *awsProvider) DeleteObj(lom *cluster.LOM) (errCode int, err error) {
 	var (
 		svc      *s3.S3
-		cloudBck = lom.Bck().RemoteBck()
+		cloudBck = awsp.remoteBck(lom.Bck())
 	)
-	svc, _, err = newClient(sessConf{bck: cloudBck}, "[delete_object]")
-	if err != nil && verbose {
-		glog.Warning(err)
+	if svc, errCode, err = bckClient(cloudBck, "[delete_object]"); err != nil {
+		return
 	}
 	_, err = svc.DeleteObject(&s3.DeleteObjectInput{
 		Bucket: aws.String(cloudBck.Name),
@@ -459,20 +504,47 @@ func (*awsProvider) DeleteObj(lom *cluster.LOM) (errCode int, err error) {
 //
 
 // newClient creates new S3 client that can be used to make requests. It is
-// guaranteed that the client is initialized even in case of errors.
+// guaranteed that the client is initialized even in case of errors - except
+// when the bucket's S3-compatible endpoint cannot be resolved (in which case
+// falling back to AWS is not an option).
 //
 // Quoting S3 SDK:
 //     "S3 methods are safe to use concurrently. It is not safe to
 //      modify mutate any of the struct's properties though."
 func newClient(conf sessConf, tag string) (svc *s3.S3, region string, err error) {
+	var (
+		ep      *cmn.S3Endpoint
+		profile string
+		key     string
+	)
 	region = conf.region
-	if region == "" && conf.bck != nil && conf.bck.Props != nil {
-		region = conf.bck.Props.Extra.AWS.CloudRegion
+	if conf.bck != nil && conf.bck.Props != nil {
+		extra := &conf.bck.Props.Extra.AWS
+		if region == "" {
+			region = extra.CloudRegion
+		}
+		profile = extra.Profile
+		if extra.Endpoint != "" {
+			if ep, err = cmn.GCO.Get().Backend.S3Endpoint(extra.Endpoint); err != nil {
+				err = fmt.Errorf("bucket %s: %v", conf.bck, err)
+				return
+			}
+			if region == "" {
+				region = ep.Region
+			}
+			if region == "" {
+				region = endpoints.UsEast1RegionID
+			}
+		}
 	}
 	// reuse
 	if region != "" {
+		key = region
+		if ep != nil || profile != "" {
+			key = fmt.Sprintf("%s|%s|%+v", region, profile, ep)
+		}
 		cmu.RLock()
-		svc = clients[region]
+		svc = clients[key]
 		cmu.RUnlock()
 		if svc != nil {
 			return
@@ -480,7 +552,7 @@ func newClient(conf sessConf, tag string) (svc *s3.S3, region string, err error)
 	}
 	// create
 	var (
-		sess    = _session()
+		sess    = _session(ep, profile)
 		awsConf = &aws.Config{}
 	)
 	if region == "" {
@@ -496,18 +568,48 @@ func newClient(conf sessConf, tag string) (svc *s3.S3, region string, err error)
 	debug.Assertf(region == *svc.Config.Region, "%s != %s", region, *svc.Config.Region)
 
 	cmu.Lock()
-	clients[region] = svc
+	clients[key] = svc
 	cmu.Unlock()
 	return
 }
 
-// Create session using default creds from ~/.aws/credentials and environment variables.
-func _session() *session.Session {
+// client for a given (remote) bucket - see newClient
+func bckClient(cloudBck *cmn.Bck, tag string) (svc *s3.S3, errCode int, err error) {
+	svc, _, err = newClient(sessConf{bck: cloudBck}, tag)
+	if svc == nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if err != nil && verbose {
+		glog.Warning(err)
+	}
+	return svc, 0, nil
+}
+
+// Create session using default creds from ~/.aws/credentials and environment variables
+// or, for S3-compatible endpoints, the endpoint's credentials; the bucket's profile, if specified,
+// takes precedence.
+func _session(ep *cmn.S3Endpoint, profile string) *session.Session {
 	// TODO: avoid creating sessions for each request
-	return session.Must(session.NewSessionWithOptions(session.Options{
+	opts := session.Options{
 		SharedConfigState: session.SharedConfigEnable,
 		Config:            aws.Config{HTTPClient: cmn.NewClient(cmn.TransportArgs{})},
-	}))
+	}
+	if ep != nil {
+		opts.Config.Endpoint = aws.String(ep.URL)
+		opts.Config.S3ForcePathStyle = aws.Bool(ep.PathStyle)
+		if ep.SkipVerify {
+			opts.Config.HTTPClient = cmn.NewClient(cmn.TransportArgs{UseHTTPS: true, SkipVerify: true})
+		}
+		if profile == "" {
+			if ep.AccessKey != "" {
+				opts.Config.Credentials = credentials.NewStaticCredentials(ep.AccessKey, ep.SecretKey, "")
+			} else {
+				profile = ep.Profile
+			}
+		}
+	}
+	opts.Profile = profile
+	return session.Must(session.NewSessionWithOptions(opts))
 }
 
 func getBucketLocation(svc *s3.S3, bckName string) (region string, err error) {
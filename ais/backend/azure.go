@@ -66,6 +66,7 @@ var (
 
 	// interface guard
 	_ cluster.BackendProvider = (*azureProvider)(nil)
+	_ cluster.RangeReader     = (*azureProvider)(nil)
 )
 
 func azureProto() string {
@@ -422,6 +423,33 @@ func (ap *azureProvider) GetObjReader(ctx context.Context, lom *cluster.LOM) (re
 	return wrapReader(ctx, resp.Body(retryOpts)), expCksum, 0, nil
 }
 
+func (ap *azureProvider) GetObjReaderRange(ctx context.Context, lom *cluster.LOM, offset, length int64,
+	oa *cmn.ObjAttrs) (reader io.ReadCloser, errCode int, err error) {
+	var (
+		cond     azblob.BlobAccessConditions
+		cloudBck = lom.Bck().RemoteBck()
+		cntURL   = ap.s.NewContainerURL(cloudBck.Name)
+		blobURL  = cntURL.NewBlobURL(lom.ObjName)
+	)
+	if oa != nil {
+		if v, ok := oa.GetCustomKey(cmn.ETag); ok {
+			cond.ModifiedAccessConditions.IfMatch = azblob.ETag("\"" + v + "\"")
+		}
+	}
+	resp, err := blobURL.Download(ctx, offset, length, cond, false, defaultKeyOptions)
+	if err != nil {
+		errCode, err = azureErrorToAISError(err, cloudBck, lom.ObjName)
+		return nil, errCode, err
+	}
+	if resp.StatusCode() >= http.StatusBadRequest {
+		err := fmt.Errorf(cmn.FmtErrFailed, cmn.ProviderAzure, "get object range",
+			cloudBck.Name+"/"+lom.ObjName, strconv.Itoa(resp.StatusCode()))
+		return nil, resp.StatusCode(), err
+	}
+	retryOpts := azblob.RetryReaderOptions{MaxRetryRequests: 3}
+	return wrapReader(ctx, resp.Body(retryOpts)), 0, nil
+}
+
 ////////////////
 // PUT OBJECT //
 ////////////////
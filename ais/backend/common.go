@@ -6,9 +6,14 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cmn"
@@ -17,8 +22,20 @@ import (
 
 type ctx = context.Context // used when omitted for shortness sake
 
+// lists objects of a (POSIX, HDFS) directory tree: walks the tree in the lexicographical
+// order of the resulting object names (which is why it does not use filepath.Walk)
+// and skips subtrees that precede the continuation token
+type dirLister struct {
+	readDir func(dir string) ([]os.FileInfo, error) // files and directories
+	msg     *cmn.ListObjsMsg
+	list    *cmn.BucketList
+	token   string // list objects that go after
+}
+
 var verbose bool
 
+var errPageFull = errors.New("page full")
+
 func Init() {
 	verbose = bool(glog.FastV(4, glog.SmoduleBackend))
 }
@@ -49,3 +66,52 @@ func calcPageSize(pageSize, maxPageSize uint) uint {
 
 // nolint:deadcode,unused // It is used but in `*_mock.go` files.
 func newErrInitBackend(provider string) error { return &cmn.ErrInitBackend{Provider: provider} }
+
+// `prefix` is the object name prefix of the directory (e.g. "a/b/")
+func (l *dirLister) walk(dir, prefix string) error {
+	fis, err := l.readDir(dir)
+	if err != nil {
+		return err
+	}
+	var (
+		names = make([]string, 0, len(fis)) // object names and object name prefixes of subdirectories
+		infos = make(map[string]os.FileInfo, len(fis))
+	)
+	for _, fi := range fis {
+		name := prefix + fi.Name()
+		if fi.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+		infos[name] = fi
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fi := infos[name]
+		if fi.IsDir() {
+			if !cmn.DirNameContainsPrefix(name, l.msg.Prefix) {
+				continue
+			}
+			if name < l.token && !strings.HasPrefix(l.token, name) {
+				continue // the entire subtree precedes the token
+			}
+			if err := l.walk(filepath.Join(dir, fi.Name()), name); err != nil {
+				return err
+			}
+			continue
+		}
+		if !cmn.ObjNameContainsPrefix(name, l.msg.Prefix) || name <= l.token {
+			continue
+		}
+		entry := &cmn.BucketEntry{Name: name}
+		if l.msg.WantProp(cmn.GetPropsSize) {
+			entry.Size = fi.Size()
+		}
+		l.list.Entries = append(l.list.Entries, entry)
+		if uint(len(l.list.Entries)) >= l.msg.PageSize {
+			return errPageFull
+		}
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -58,6 +59,7 @@ var (
 
 	// interface guard
 	_ cluster.BackendProvider = (*gcpProvider)(nil)
+	_ cluster.RangeReader     = (*gcpProvider)(nil)
 )
 
 func NewGCP(t cluster.Target) (bp cluster.BackendProvider, err error) {
@@ -332,6 +334,27 @@ func (*gcpProvider) GetObjReader(ctx context.Context, lom *cluster.LOM) (r io.Re
 	return
 }
 
+func (*gcpProvider) GetObjReaderRange(ctx context.Context, lom *cluster.LOM, offset, length int64,
+	oa *cmn.ObjAttrs) (r io.ReadCloser, errCode int, err error) {
+	var (
+		rc       *storage.Reader
+		cloudBck = lom.Bck().RemoteBck()
+		o        = gcpClient.Bucket(cloudBck.Name).Object(lom.ObjName)
+	)
+	if oa != nil {
+		if gen, err := strconv.ParseInt(oa.Ver, 10, 64); err == nil {
+			o = o.Generation(gen)
+		}
+	}
+	rc, err = o.NewRangeReader(ctx, offset, length)
+	if err != nil {
+		errCode, err = gcpErrorToAISError(err, cloudBck)
+		return
+	}
+	r = wrapReader(ctx, rc)
+	return
+}
+
 func setCustomGs(lom *cluster.LOM, attrs *storage.ObjectAttrs) (expCksum *cos.Cksum) {
 	h := cmn.BackendHelpers.Google
 	if v, ok := h.EncodeVersion(attrs.Generation); ok {
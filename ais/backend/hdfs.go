@@ -24,8 +24,15 @@ import (
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/colinmarc/hdfs/v2"
+	krb "github.com/jcmturner/gokrb5/v8/client"
+	krbconfig "github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/keytab"
 )
 
+// HDFS backend: bucket is a directory tree (bucket property `extra.hdfs.ref_directory`);
+// objects are files, object names are file paths relative to the reference directory.
+// Optionally, authenticates with Kerberos (see cmn.BackendConfHDFS).
+
 type (
 	hdfsProvider struct {
 		t cluster.Target
@@ -33,6 +40,13 @@ type (
 	}
 )
 
+const (
+	hdfsDefaultKrb5Conf = "/etc/krb5.conf"
+	// suffix of the files that are being written (same as `hdfs dfs -put`);
+	// such files are not listed
+	hdfsWorkfileSuffix = "._COPYING_"
+)
+
 // interface guard
 var _ cluster.BackendProvider = (*hdfsProvider)(nil)
 
@@ -41,11 +55,21 @@ func NewHDFS(t cluster.Target) (cluster.BackendProvider, error) {
 	debug.Assert(ok)
 	hdfsConf := providerConf.(cmn.BackendConfHDFS)
 
-	client, err := hdfs.NewClient(hdfs.ClientOptions{
-		Addresses:           hdfsConf.Addresses,
-		User:                hdfsConf.User,
-		UseDatanodeHostname: hdfsConf.UseDatanodeHostname,
-	})
+	opts := hdfs.ClientOptions{
+		Addresses:              hdfsConf.Addresses,
+		User:                   hdfsConf.User,
+		UseDatanodeHostname:    hdfsConf.UseDatanodeHostname,
+		DataTransferProtection: hdfsConf.DataTransferProtection,
+	}
+	if hdfsConf.Kerberos != nil {
+		krbClient, err := newKerberosClient(hdfsConf.Kerberos)
+		if err != nil {
+			return nil, err
+		}
+		opts.KerberosClient = krbClient
+		opts.KerberosServicePrincipleName = hdfsConf.Kerberos.ServicePrincipal
+	}
+	client, err := hdfs.NewClient(opts)
 	if err != nil {
 		return nil, err
 	}
@@ -55,6 +79,31 @@ func NewHDFS(t cluster.Target) (cluster.BackendProvider, error) {
 	return &hdfsProvider{t: t, c: client}, nil
 }
 
+// logs in with the keytab; the client then renews its tickets as needed
+func newKerberosClient(conf *cmn.HDFSKerberosConf) (*krb.Client, error) {
+	krb5Conf := conf.Krb5Conf
+	if krb5Conf == "" {
+		krb5Conf = hdfsDefaultKrb5Conf
+	}
+	cfg, err := krbconfig.Load(krb5Conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Kerberos configuration %q: %v", krb5Conf, err)
+	}
+	kt, err := keytab.Load(conf.Keytab)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Kerberos keytab %q: %v", conf.Keytab, err)
+	}
+	username, realm := conf.Principal, cfg.LibDefaults.DefaultRealm
+	if i := strings.LastIndexByte(username, '@'); i >= 0 {
+		username, realm = username[:i], username[i+1:]
+	}
+	client := krb.NewWithKeytab(username, realm, kt, cfg)
+	if err := client.Login(); err != nil {
+		return nil, fmt.Errorf("Kerberos login of %q failed: %v", conf.Principal, err)
+	}
+	return client, nil
+}
+
 func hdfsErrorToAISError(err error) (int, error) {
 	if os.IsNotExist(err) {
 		return http.StatusNotFound, err
@@ -68,6 +117,18 @@ func hdfsErrorToAISError(err error) (int, error) {
 	return http.StatusBadRequest, err
 }
 
+// object's pathname - must be located inside the bucket's reference directory
+func hdfsPath(lom *cluster.LOM) (string, error) {
+	var (
+		dir      = filepath.Clean(lom.Bck().Props.Extra.HDFS.RefDirectory)
+		filePath = filepath.Join(dir, lom.ObjName)
+	)
+	if filePath == dir || !cos.IsSubdir(dir, filePath) {
+		return "", fmt.Errorf("invalid object name %q: resolves outside %s", lom.ObjName, lom.Bck())
+	}
+	return filePath, nil
+}
+
 func (*hdfsProvider) Provider() string  { return cmn.ProviderHDFS }
 func (*hdfsProvider) MaxPageSize() uint { return 10000 }
 
@@ -114,82 +175,73 @@ func (hp *hdfsProvider) HeadBucket(_ ctx, bck *cluster.Bck) (bckProps cos.Simple
 // LIST OBJECTS //
 //////////////////
 
+// NOTE: see dirLister
 func (hp *hdfsProvider) ListObjects(bck *cluster.Bck, msg *cmn.ListObjsMsg) (bckList *cmn.BucketList,
 	errCode int, err error) {
 	msg.PageSize = calcPageSize(msg.PageSize, hp.MaxPageSize())
-
-	h := cmn.BackendHelpers.HDFS
-	bckList = &cmn.BucketList{Entries: make([]*cmn.BucketEntry, 0, msg.PageSize)}
-	err = hp.c.Walk(bck.Props.Extra.HDFS.RefDirectory, func(path string, fi os.FileInfo, err error) error {
-		if err != nil {
-			if cos.IsEOF(err) {
-				return nil
-			}
-			return err
-		}
-		if uint(len(bckList.Entries)) >= msg.PageSize {
-			return skipDir(fi)
-		}
-		objName := strings.TrimPrefix(strings.TrimPrefix(path, bck.Props.Extra.HDFS.RefDirectory), string(filepath.Separator))
-		if msg.Prefix != "" {
-			if fi.IsDir() {
-				if !cmn.DirNameContainsPrefix(objName, msg.Prefix) {
-					return skipDir(fi)
-				}
-			} else if !cmn.ObjNameContainsPrefix(objName, msg.Prefix) {
-				return skipDir(fi)
-			}
-		}
-		if msg.ContinuationToken != "" && objName <= msg.ContinuationToken {
-			return nil
-		}
-		if msg.StartAfter != "" && objName <= msg.StartAfter {
-			return nil
-		}
-		if fi.IsDir() {
-			return nil
-		}
-		entry := &cmn.BucketEntry{
-			Name:    objName,
-			Version: "",
-		}
-		if msg.WantProp(cmn.GetPropsSize) {
-			entry.Size = fi.Size()
+	var (
+		refDirectory = bck.Props.Extra.HDFS.RefDirectory
+		l            = &dirLister{
+			readDir: hp.readDir,
+			msg:     msg,
+			list:    &cmn.BucketList{Entries: make([]*cmn.BucketEntry, 0, msg.PageSize)},
+			token:   msg.ContinuationToken,
 		}
-		if msg.WantProp(cmn.GetPropsChecksum) {
-			fr, err := hp.c.Open(path)
+	)
+	if msg.StartAfter > l.token {
+		l.token = msg.StartAfter
+	}
+	if err = l.walk(refDirectory, ""); err != nil && err != errPageFull {
+		errCode, err = hdfsErrorToAISError(err)
+		return nil, errCode, err
+	}
+	if msg.WantProp(cmn.GetPropsChecksum) {
+		h := cmn.BackendHelpers.HDFS
+		for _, entry := range l.list.Entries {
+			fr, err := hp.c.Open(filepath.Join(refDirectory, entry.Name))
 			if err != nil {
-				return err
+				if os.IsNotExist(err) {
+					continue // removed in the meantime
+				}
+				errCode, err = hdfsErrorToAISError(err)
+				return nil, errCode, err
 			}
-			defer fr.Close()
 			cksum, err := fr.Checksum()
+			fr.Close()
 			if err != nil {
-				return err
+				errCode, err = hdfsErrorToAISError(err)
+				return nil, errCode, err
 			}
 			if v, ok := h.EncodeCksum(cksum); ok {
 				entry.Checksum = v
 			}
 		}
-		bckList.Entries = append(bckList.Entries, entry)
-		return nil
-	})
-	if err != nil {
-		errCode, err = hdfsErrorToAISError(err)
-		return nil, errCode, err
 	}
 	// Set continuation token only if we reached the page size.
-	if uint(len(bckList.Entries)) >= msg.PageSize {
-		bckList.ContinuationToken = bckList.Entries[len(bckList.Entries)-1].Name
+	if uint(len(l.list.Entries)) >= msg.PageSize {
+		l.list.ContinuationToken = l.list.Entries[len(l.list.Entries)-1].Name
 	}
-	return bckList, 0, nil
+	return l.list, 0, nil
 }
 
-// `hdfs.Walk` does not correctly handle `SkipDir` if the `fi` is non-directory.
-func skipDir(fi os.FileInfo) error {
-	if fi.IsDir() {
-		return filepath.SkipDir
+// files and directories, excluding the files that are being written
+func (hp *hdfsProvider) readDir(dir string) ([]os.FileInfo, error) {
+	fis, err := hp.c.ReadDir(dir)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return hdfsFilterWorkfiles(fis), nil
+}
+
+func hdfsFilterWorkfiles(fis []os.FileInfo) []os.FileInfo {
+	filtered := fis[:0]
+	for _, fi := range fis {
+		if !fi.IsDir() && strings.HasSuffix(fi.Name(), hdfsWorkfileSuffix) {
+			continue
+		}
+		filtered = append(filtered, fi)
+	}
+	return filtered
 }
 
 //////////////////
@@ -207,16 +259,22 @@ func (*hdfsProvider) ListBuckets(cmn.QueryBcks) (buckets cmn.Bcks, errCode int,
 
 func (hp *hdfsProvider) HeadObj(_ ctx, lom *cluster.LOM) (oa *cmn.ObjAttrs, errCode int, err error) {
 	var (
-		fr       *hdfs.FileReader
-		filePath = filepath.Join(lom.Bck().Props.Extra.HDFS.RefDirectory, lom.ObjName)
+		fi       os.FileInfo
+		filePath string
 	)
-	if fr, err = hp.c.Open(filePath); err != nil {
+	if filePath, err = hdfsPath(lom); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if fi, err = hp.c.Stat(filePath); err != nil {
 		errCode, err = hdfsErrorToAISError(err)
 		return
 	}
+	if fi.IsDir() {
+		return nil, http.StatusNotFound, cmn.NewErrNotFound("%s: object", lom)
+	}
 	oa = &cmn.ObjAttrs{}
 	oa.SetCustomKey(cmn.SourceObjMD, cmn.ProviderHDFS)
-	oa.Size = fr.Stat().Size()
+	oa.Size = fi.Size()
 	if verbose {
 		glog.Infof("[head_object] %s", lom)
 	}
@@ -253,12 +311,19 @@ func (hp *hdfsProvider) GetObj(ctx context.Context, lom *cluster.LOM, owt cmn.OW
 
 func (hp *hdfsProvider) GetObjReader(ctx context.Context, lom *cluster.LOM) (r io.ReadCloser,
 	expectedCksm *cos.Cksum, errCode int, err error) {
-	filePath := filepath.Join(lom.Bck().Props.Extra.HDFS.RefDirectory, lom.ObjName)
+	filePath, err := hdfsPath(lom)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, err
+	}
 	fr, err := hp.c.Open(filePath)
 	if err != nil {
 		errCode, err = hdfsErrorToAISError(err)
 		return
 	}
+	if fr.Stat().IsDir() {
+		fr.Close()
+		return nil, nil, http.StatusNotFound, cmn.NewErrNotFound("%s: object", lom)
+	}
 	lom.SetCustomKey(cmn.SourceObjMD, cmn.ProviderHDFS)
 	setSize(ctx, fr.Stat().Size())
 	return wrapReader(ctx, fr), nil, 0, nil
@@ -268,45 +333,52 @@ func (hp *hdfsProvider) GetObjReader(ctx context.Context, lom *cluster.LOM) (r i
 // PUT OBJECT //
 ////////////////
 
+// write via temporary file (in the same directory) and rename, so that the readers
+// never see partially written objects (HDFS rename is atomic)
 func (hp *hdfsProvider) PutObj(r io.ReadCloser, lom *cluster.LOM) (errCode int, err error) {
-	filePath := filepath.Join(lom.Bck().Props.Extra.HDFS.RefDirectory, lom.ObjName)
-	fw, err := hp.c.Create(filePath)
+	var (
+		fw                *hdfs.FileWriter
+		filePath, tmpPath string
+	)
+	defer cos.Close(r)
+	if filePath, err = hdfsPath(lom); err != nil {
+		return http.StatusBadRequest, err
+	}
+	tmpPath = filePath + "." + cos.GenTie() + hdfsWorkfileSuffix
+	fw, err = hp.c.Create(tmpPath)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			goto finish
+			return hdfsErrorToAISError(err)
 		}
-
 		// Create any missing directories.
 		if err = hp.c.MkdirAll(filepath.Dir(filePath), cos.PermRWXRX|os.ModeDir); err != nil {
-			goto finish
+			return hdfsErrorToAISError(err)
 		}
-
 		// Retry creating file. If it doesn't succeed we give up and report error.
-		fw, err = hp.c.Create(filePath)
-		if err != nil {
-			goto finish
+		if fw, err = hp.c.Create(tmpPath); err != nil {
+			return hdfsErrorToAISError(err)
 		}
 	}
-
 	if _, err = io.Copy(fw, r); err != nil {
 		fw.Close()
 		goto finish
 	}
-
-	err = fw.Close()
+	if err = fw.Close(); err != nil {
+		goto finish
+	}
+	err = hp.c.Rename(tmpPath, filePath)
 
 finish:
-	cos.Close(r)
-
-	// TODO: Cleanup if there was an error during `c.Create` or `io.Copy` (remove directories and file).
 	if err != nil {
+		if errRm := hp.c.Remove(tmpPath); errRm != nil && !os.IsNotExist(errRm) {
+			glog.Errorf("nested error: %v (failed to remove %s: %v)", err, tmpPath, errRm)
+		}
 		errCode, err = hdfsErrorToAISError(err)
-		return errCode, err
+		return
 	}
 	if verbose {
 		glog.Infof("[put_object] %s", lom)
 	}
-
 	return 0, nil
 }
 
@@ -315,7 +387,10 @@ finish:
 ///////////////////
 
 func (hp *hdfsProvider) DeleteObj(lom *cluster.LOM) (errCode int, err error) {
-	filePath := filepath.Join(lom.Bck().Props.Extra.HDFS.RefDirectory, lom.ObjName)
+	filePath, err := hdfsPath(lom)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
 	if err := hp.c.Remove(filePath); err != nil {
 		errCode, err = hdfsErrorToAISError(err)
 		return errCode, err
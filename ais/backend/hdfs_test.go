@@ -0,0 +1,93 @@
+//go:build hdfs
+// +build hdfs
+
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+type hdfsTestFileInfo struct {
+	name string
+	dir  bool
+}
+
+func (fi *hdfsTestFileInfo) Name() string    { return fi.name }
+func (*hdfsTestFileInfo) Size() int64        { return 1 }
+func (*hdfsTestFileInfo) Mode() os.FileMode  { return 0o644 }
+func (*hdfsTestFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi *hdfsTestFileInfo) IsDir() bool     { return fi.dir }
+func (*hdfsTestFileInfo) Sys() interface{}   { return nil }
+
+// NOTE: does not require HDFS cluster - the directory tree is emulated
+func TestHDFSList(t *testing.T) {
+	var (
+		tree  = make(map[string][]os.FileInfo) // dir => (HDFS-sorted) content
+		files = []string{"a/x", "a/y/z", "a-b", "a0", "b/c/d", "b/c/e.1234" + hdfsWorkfileSuffix, "f" + hdfsWorkfileSuffix}
+	)
+	for _, name := range files {
+		var (
+			dir   = "/ref"
+			parts = strings.Split(name, "/")
+		)
+		for i, part := range parts {
+			fi := &hdfsTestFileInfo{name: part, dir: i < len(parts)-1}
+			found := false
+			for _, other := range tree[dir] {
+				found = found || other.Name() == part
+			}
+			if !found {
+				tree[dir] = append(tree[dir], fi)
+			}
+			dir = filepath.Join(dir, part)
+		}
+	}
+	readDir := func(dir string) ([]os.FileInfo, error) {
+		fis, ok := tree[dir]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return hdfsFilterWorkfiles(append([]os.FileInfo{}, fis...)), nil
+	}
+	list := func(prefix, token string, pageSize uint) (names []string) {
+		l := &dirLister{
+			readDir: readDir,
+			msg:     &cmn.ListObjsMsg{Prefix: prefix, PageSize: pageSize},
+			list:    &cmn.BucketList{},
+			token:   token,
+		}
+		err := l.walk("/ref", "")
+		tassert.Fatalf(t, err == nil || err == errPageFull, "unexpected error: %v", err)
+		for _, entry := range l.list.Entries {
+			names = append(names, entry.Name)
+		}
+		return
+	}
+	tests := []struct {
+		prefix, token string
+		pageSize      uint
+		expected      []string
+	}{
+		{"", "", 100, []string{"a-b", "a/x", "a/y/z", "a0", "b/c/d"}},
+		{"", "", 3, []string{"a-b", "a/x", "a/y/z"}},
+		{"", "a/y/z", 3, []string{"a0", "b/c/d"}},
+		{"b/", "", 100, []string{"b/c/d"}},
+	}
+	for _, test := range tests {
+		names := list(test.prefix, test.token, test.pageSize)
+		tassert.Errorf(t, reflect.DeepEqual(names, test.expected), "prefix %q, token %q: expected %v, got %v",
+			test.prefix, test.token, test.expected, names)
+	}
+}
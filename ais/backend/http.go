@@ -5,10 +5,14 @@
 package backend
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -20,17 +24,34 @@ import (
 	"github.com/NVIDIA/aistore/fs"
 )
 
+// HTTP(S) backend: bucket is a URL "directory" (bucket property `extra.http.original_url`) of a web
+// server; objects are the files under it. To list objects, the backend reads either the bucket's
+// manifest (`extra.http.manifest`) or, if not specified, the index pages that web servers generate
+// for directories (e.g. nginx autoindex, Apache mod_autoindex). Object names are relative URL paths
+// as they appear in the index (i.e., URL-encoded).
+
+const httpMaxIndexSize = 64 * cos.MiB // index page or manifest
+
 type (
 	httpProvider struct {
 		t           cluster.Target
 		httpClient  *http.Client
 		httpsClient *http.Client
 	}
+	httpLister struct {
+		hp      *httpProvider
+		msg     *cmn.ListObjsMsg
+		list    *cmn.BucketList
+		token   string // list objects that go after
+		errCode int
+	}
 )
 
 // interface guard
 var _ cluster.BackendProvider = (*httpProvider)(nil)
 
+var reHref = regexp.MustCompile(`(?i)<a\s[^>]*href\s*=\s*["']([^"']+)["']`)
+
 func NewHTTP(t cluster.Target, config *cmn.Config) (cluster.BackendProvider, error) {
 	hp := &httpProvider{t: t}
 	hp.httpClient = cmn.NewClient(cmn.TransportArgs{
@@ -99,9 +120,39 @@ func (hp *httpProvider) HeadBucket(ctx context.Context, bck *cluster.Bck) (bckPr
 	return
 }
 
-func (*httpProvider) ListObjects(*cluster.Bck, *cmn.ListObjsMsg) (bckList *cmn.BucketList, errCode int, err error) {
-	debug.Assert(false)
-	return
+func (hp *httpProvider) ListObjects(bck *cluster.Bck, msg *cmn.ListObjsMsg) (bckList *cmn.BucketList,
+	errCode int, err error) {
+	var (
+		extra = &bck.Props.Extra.HTTP
+		l     = &httpLister{hp: hp, msg: msg, token: msg.ContinuationToken}
+	)
+	msg.PageSize = calcPageSize(msg.PageSize, hp.MaxPageSize())
+	l.list = &cmn.BucketList{Entries: make([]*cmn.BucketEntry, 0, msg.PageSize)}
+	if msg.StartAfter > l.token {
+		l.token = msg.StartAfter
+	}
+	if extra.OrigURLBck == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("failed to list %s: original_url is empty", bck)
+	}
+	if extra.Manifest != "" {
+		err = l.manifest(extra.OrigURLBck, extra.Manifest)
+	} else {
+		err = l.walk(extra.OrigURLBck, "")
+	}
+	if err != nil && err != errPageFull {
+		if l.errCode == 0 {
+			l.errCode = http.StatusBadRequest
+		}
+		return nil, l.errCode, err
+	}
+	// Set continuation token only if we reached the page size.
+	if uint(len(l.list.Entries)) >= msg.PageSize {
+		l.list.ContinuationToken = l.list.Entries[len(l.list.Entries)-1].Name
+	}
+	if verbose {
+		glog.Infof("[list_objects] %s: %d object(s)", bck, len(l.list.Entries))
+	}
+	return l.list, 0, nil
 }
 
 func (*httpProvider) ListBuckets(cmn.QueryBcks) (bcks cmn.Bcks, errCode int, err error) {
@@ -220,3 +271,166 @@ func (hp *httpProvider) PutObj(io.ReadCloser, *cluster.LOM) (int, error) {
 func (hp *httpProvider) DeleteObj(*cluster.LOM) (int, error) {
 	return http.StatusBadRequest, fmt.Errorf(cmn.FmtErrUnsupported, hp.Provider(), "deleting object")
 }
+
+////////////////
+// httpLister //
+////////////////
+
+func (l *httpLister) get(u string) (body []byte, err error) {
+	resp, err := l.hp.client(u).Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		l.errCode = resp.StatusCode
+		return nil, fmt.Errorf("GET(%s) failed, status %d", u, resp.StatusCode)
+	}
+	if body, err = readAllLimit(resp.Body, httpMaxIndexSize); err != nil {
+		err = fmt.Errorf("GET(%s): %v", u, err)
+	}
+	return
+}
+
+// same as io.ReadAll but fails (rather than truncates) when the size exceeds the limit
+func readAllLimit(r io.Reader, limit int64) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err == nil && int64(len(b)) > limit {
+		err = fmt.Errorf("size exceeds %s", cos.B2S(limit, 0))
+	}
+	return b, err
+}
+
+// object names listed in the manifest: one per line, either relative to the bucket's URL
+// or absolute (under the bucket's URL); bash-style templates (e.g. `shard-{0000..0999}.tar`)
+// get expanded; empty lines and #-comments are skipped
+func (l *httpLister) manifest(origURLBck, manifest string) error {
+	base, err := url.Parse(origURLBck)
+	if err != nil {
+		return err
+	}
+	u, err := base.Parse(manifest)
+	if err != nil {
+		return err
+	}
+	body, err := l.get(u.String())
+	if err != nil {
+		return err
+	}
+	var (
+		names   = make([]string, 0, 64)
+		scanner = bufio.NewScanner(strings.NewReader(string(body)))
+	)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if cos.IsHTTP(line) || cos.IsHTTPS(line) {
+			if !strings.HasPrefix(line, origURLBck) {
+				return fmt.Errorf("manifest %s: %q is not under %q", u, line, origURLBck)
+			}
+			line = line[len(origURLBck):]
+		}
+		line = strings.TrimPrefix(line, "/")
+		if pt, err := cos.ParseBashTemplate(line); err == nil && len(pt.Ranges) > 0 {
+			names = append(names, pt.ToSlice()...)
+		} else {
+			names = append(names, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if (i > 0 && name == names[i-1]) || !cmn.ObjNameContainsPrefix(name, l.msg.Prefix) || name <= l.token {
+			continue
+		}
+		l.list.Entries = append(l.list.Entries, &cmn.BucketEntry{Name: name})
+		if uint(len(l.list.Entries)) >= l.msg.PageSize {
+			return errPageFull
+		}
+	}
+	return nil
+}
+
+// walk the index pages in the lexicographical order of the resulting object names,
+// skipping subtrees that precede the continuation token (same as dirLister.walk in common.go);
+// `prefix` is the object name prefix of the directory (e.g. "a/b/")
+func (l *httpLister) walk(dirURL, prefix string) error {
+	links, err := l.index(dirURL)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(links))
+	for _, link := range links {
+		names = append(names, prefix+link)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if strings.HasSuffix(name, "/") {
+			if !cmn.DirNameContainsPrefix(name, l.msg.Prefix) {
+				continue
+			}
+			if name < l.token && !strings.HasPrefix(l.token, name) {
+				continue // the entire subtree precedes the token
+			}
+			if err := l.walk(dirURL+name[len(prefix):], name); err != nil {
+				return err
+			}
+			continue
+		}
+		if !cmn.ObjNameContainsPrefix(name, l.msg.Prefix) || name <= l.token {
+			continue
+		}
+		l.list.Entries = append(l.list.Entries, &cmn.BucketEntry{Name: name})
+		if uint(len(l.list.Entries)) >= l.msg.PageSize {
+			return errPageFull
+		}
+	}
+	return nil
+}
+
+// index returns the (deduplicated) links of a given index page that point to its immediate
+// children: files and (with trailing slash) subdirectories; parent directory, sorting
+// (query) links, and links to other locations are skipped
+func (l *httpLister) index(dirURL string) (links []string, err error) {
+	base, err := url.Parse(dirURL)
+	if err != nil {
+		return nil, err
+	}
+	body, err := l.get(dirURL)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		dir  = base.EscapedPath()
+		seen = make(map[string]struct{}, 16)
+	)
+	for _, match := range reHref.FindAllSubmatch(body, -1) {
+		href := string(match[1])
+		if strings.ContainsAny(href, "?#") {
+			continue
+		}
+		u, err := base.Parse(href)
+		if err != nil || u.Scheme != base.Scheme || u.Host != base.Host {
+			continue
+		}
+		p := u.EscapedPath()
+		if len(p) <= len(dir) || !strings.HasPrefix(p, dir) {
+			continue
+		}
+		link := p[len(dir):]
+		if i := strings.IndexByte(link, '/'); i >= 0 && i < len(link)-1 {
+			continue // not an immediate child
+		}
+		if _, ok := seen[link]; ok {
+			continue
+		}
+		seen[link] = struct{}{}
+		links = append(links, link)
+	}
+	return links, nil
+}
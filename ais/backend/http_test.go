@@ -0,0 +1,113 @@
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestHTTPList(t *testing.T) {
+	// nginx (autoindex) and Apache (mod_autoindex) style index pages
+	pages := map[string]string{
+		"/data/": `<html><head><title>Index of /data/</title></head><body><h1>Index of /data/</h1><hr><pre>
+<a href="../">../</a>
+<a href="a/">a/</a>                                                 01-Jan-2021 00:00       -
+<a href="a-b">a-b</a>                                               01-Jan-2021 00:00       3
+<a href="a0">a0</a>                                                 01-Jan-2021 00:00       2
+<a href="b/">b/</a>                                                 01-Jan-2021 00:00       -
+<a href="e%20f.tar">e f.tar</a>                                     01-Jan-2021 00:00      10
+<a href="https://example.com/e">elsewhere</a>
+</pre><hr></body></html>`,
+		"/data/a/": `<table><tr><th><a href="?C=N;O=D">Name</a></th></tr>
+<tr><td><a href="/data/">Parent Directory</a></td></tr>
+<tr><td><a href="x">x</a></td></tr>
+<tr><td><a href="y/">y/</a></td></tr>
+<tr><td><a href="/data/a/x">x</a></td></tr>
+</table>`,
+		"/data/a/y/": `<a href="z">z</a>`,
+		"/data/b/":   `<a href="c/">c/</a>`,
+		"/data/b/c/": `<a HREF='d'>d</a>`,
+		"/data/manifest.txt": `# objects
+a/x
+/a-b
+shard-{1..3}.tar
+%s/data/e
+`,
+	}
+	var fetched []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fetched = append(fetched, r.URL.Path)
+		if strings.Contains(page, "%s") {
+			page = fmt.Sprintf(page, "http://"+r.Host)
+		}
+		w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	hp := &httpProvider{httpClient: srv.Client(), httpsClient: srv.Client()}
+	list := func(manifest, prefix, token string, pageSize uint) (names []string) {
+		l := &httpLister{
+			hp:    hp,
+			msg:   &cmn.ListObjsMsg{Prefix: prefix, PageSize: pageSize},
+			list:  &cmn.BucketList{},
+			token: token,
+		}
+		var err error
+		if manifest != "" {
+			err = l.manifest(srv.URL+"/data/", manifest)
+		} else {
+			err = l.walk(srv.URL+"/data/", "")
+		}
+		tassert.Fatalf(t, err == nil || err == errPageFull, "unexpected error: %v", err)
+		for _, entry := range l.list.Entries {
+			names = append(names, entry.Name)
+		}
+		return
+	}
+	tests := []struct {
+		manifest, prefix, token string
+		pageSize                uint
+		expected                []string
+	}{
+		{"", "", "", 100, []string{"a-b", "a/x", "a/y/z", "a0", "b/c/d", "e%20f.tar"}},
+		{"", "", "", 2, []string{"a-b", "a/x"}},
+		{"", "", "a/x", 2, []string{"a/y/z", "a0"}},
+		{"", "a/", "", 100, []string{"a/x", "a/y/z"}},
+		{"", "b/c", "", 100, []string{"b/c/d"}},
+		{"", "x", "", 100, nil},
+		{"manifest.txt", "", "", 100, []string{"a-b", "a/x", "e", "shard-1.tar", "shard-2.tar", "shard-3.tar"}},
+		{"/data/manifest.txt", "shard-", "shard-1.tar", 1, []string{"shard-2.tar"}},
+	}
+	for _, test := range tests {
+		names := list(test.manifest, test.prefix, test.token, test.pageSize)
+		tassert.Errorf(t, reflect.DeepEqual(names, test.expected), "manifest %q, prefix %q, token %q: expected %v, got %v",
+			test.manifest, test.prefix, test.token, test.expected, names)
+	}
+
+	// the subtrees that precede the continuation token are not fetched
+	fetched = fetched[:0]
+	list("", "", "a0", 100)
+	tassert.Errorf(t, reflect.DeepEqual(fetched, []string{"/data/", "/data/b/", "/data/b/c/"}), "fetched %v", fetched)
+}
+
+func TestHTTPReadAllLimit(t *testing.T) {
+	b, err := readAllLimit(strings.NewReader("0123456789"), 10)
+	tassert.Errorf(t, err == nil && string(b) == "0123456789", "unexpected %q, %v", b, err)
+	_, err = readAllLimit(strings.NewReader("0123456789a"), 10)
+	tassert.Errorf(t, err != nil, "expected error (not truncation) when exceeding the limit")
+}
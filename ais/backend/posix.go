@@ -0,0 +1,413 @@
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// POSIX backend: bucket is a directory tree (bucket property `extra.posix.ref_directory`) that,
+// like a shared NFS mount, must be accessible - under the same path - on all targets.
+// Objects are regular files; object names are file paths relative to the reference directory.
+
+type (
+	posixProvider struct {
+		t cluster.Target
+	}
+)
+
+// interface guard
+var (
+	_ cluster.BackendProvider = (*posixProvider)(nil)
+	_ cluster.RangeReader     = (*posixProvider)(nil)
+)
+
+func NewPOSIX(t cluster.Target) (cluster.BackendProvider, error) {
+	return &posixProvider{t: t}, nil
+}
+
+func posixErrorToAISError(err error) (int, error) {
+	if os.IsNotExist(err) {
+		return http.StatusNotFound, err
+	}
+	if os.IsExist(err) {
+		return http.StatusConflict, err
+	}
+	if os.IsPermission(err) {
+		return http.StatusForbidden, err
+	}
+	return http.StatusInternalServerError, err
+}
+
+// object's pathname - must be located inside the bucket's reference directory
+func posixPath(lom *cluster.LOM) (string, error) {
+	return posixObjPath(lom.Bck().Props.Extra.POSIX.RefDirectory, lom.ObjName, lom.Bck())
+}
+
+// the containment is checked both lexically and upon resolving symbolic links - a link
+// inside the reference directory must not lead outside of it
+func posixObjPath(refDirectory, objName string, bck *cluster.Bck) (string, error) {
+	var (
+		dir      = filepath.Clean(refDirectory)
+		filePath = filepath.Join(dir, objName)
+	)
+	if filePath == dir || !cos.IsSubdir(dir, filePath) {
+		return "", fmt.Errorf("invalid object name %q: resolves outside %s", objName, bck)
+	}
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	realPath, err := evalSymlinks(filePath)
+	if err != nil {
+		return "", err
+	}
+	if realPath == realDir || !cos.IsSubdir(realDir, realPath) {
+		return "", fmt.Errorf("invalid object name %q: links outside %s", objName, bck)
+	}
+	return filePath, nil
+}
+
+// same as filepath.EvalSymlinks except that the path (e.g., of the object being PUT)
+// may not exist - in which case its longest existing prefix gets resolved, and dangling
+// links are resolved to their (nonexistent) targets
+func evalSymlinks(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err == nil || !os.IsNotExist(err) {
+		return resolved, err
+	}
+	if fi, errLs := os.Lstat(path); errLs == nil && fi.Mode()&os.ModeSymlink != 0 {
+		// dangling link
+		target, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		return evalSymlinks(target)
+	}
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path, nil
+	}
+	if resolved, err = evalSymlinks(parent); err != nil {
+		return "", err
+	}
+	return filepath.Join(resolved, filepath.Base(path)), nil
+}
+
+func (*posixProvider) Provider() string  { return cmn.ProviderPOSIX }
+func (*posixProvider) MaxPageSize() uint { return 10000 }
+
+///////////////////
+// CREATE BUCKET //
+///////////////////
+
+func (pp *posixProvider) CreateBucket(bck *cluster.Bck) (errCode int, err error) {
+	return pp.checkRefDirectory(bck)
+}
+
+// the reference directory must exist, be permitted by the configuration
+// (see cmn.BackendConfPOSIX), and must not overlap with any of the mountpaths
+func (*posixProvider) checkRefDirectory(bck *cluster.Bck) (errCode int, err error) {
+	var posixConf cmn.BackendConfPOSIX
+	debug.Assert(bck.Props != nil)
+	refDirectory := bck.Props.Extra.POSIX.RefDirectory
+	if conf, ok := cmn.GCO.Get().Backend.ProviderConf(cmn.ProviderPOSIX); ok {
+		if err = cos.MorphMarshal(conf, &posixConf); err != nil {
+			return http.StatusInternalServerError, err
+		}
+	}
+	if err = posixConf.CheckRefDirectory(refDirectory); err != nil {
+		return http.StatusBadRequest, err
+	}
+	refDirectory = filepath.Clean(refDirectory)
+	avail, disabled := fs.Get()
+	for _, mpis := range []fs.MPI{avail, disabled} {
+		for mpath := range mpis {
+			if cos.IsSubdir(mpath, refDirectory) || cos.IsSubdir(refDirectory, mpath) {
+				err = fmt.Errorf("reference directory %q of %s overlaps with mountpath %q", refDirectory, bck, mpath)
+				return http.StatusBadRequest, err
+			}
+		}
+	}
+	fi, err := os.Stat(refDirectory)
+	if err != nil {
+		return posixErrorToAISError(err)
+	}
+	if !fi.IsDir() {
+		return http.StatusBadRequest, fmt.Errorf("specified path %q does not point to directory", refDirectory)
+	}
+	return 0, nil
+}
+
+/////////////////
+// HEAD BUCKET //
+/////////////////
+
+func (pp *posixProvider) HeadBucket(_ ctx, bck *cluster.Bck) (bckProps cos.SimpleKVs, errCode int, err error) {
+	if errCode, err = pp.checkRefDirectory(bck); err != nil {
+		return
+	}
+	bckProps = make(cos.SimpleKVs)
+	bckProps[cmn.HdrBackendProvider] = cmn.ProviderPOSIX
+	bckProps[cmn.HdrBucketVerEnabled] = "false"
+	return
+}
+
+//////////////////
+// LIST OBJECTS //
+//////////////////
+
+// NOTE: see dirLister
+func (pp *posixProvider) ListObjects(bck *cluster.Bck, msg *cmn.ListObjsMsg) (bckList *cmn.BucketList,
+	errCode int, err error) {
+	msg.PageSize = calcPageSize(msg.PageSize, pp.MaxPageSize())
+	l := &dirLister{
+		readDir: posixReadDir(bck.Props.Extra.POSIX.RefDirectory),
+		msg:     msg,
+		list:    &cmn.BucketList{Entries: make([]*cmn.BucketEntry, 0, msg.PageSize)},
+		token:   msg.ContinuationToken,
+	}
+	if msg.StartAfter > l.token {
+		l.token = msg.StartAfter
+	}
+	err = l.walk(bck.Props.Extra.POSIX.RefDirectory, "")
+	if err != nil {
+		if err != errPageFull {
+			errCode, err = posixErrorToAISError(err)
+			return nil, errCode, err
+		}
+		err = nil
+	}
+	// Set continuation token only if we reached the page size.
+	if uint(len(l.list.Entries)) >= msg.PageSize {
+		l.list.ContinuationToken = l.list.Entries[len(l.list.Entries)-1].Name
+	}
+	return l.list, 0, nil
+}
+
+// dirLister.readDir: regular files and directories; symlinks to files are followed unless
+// they lead outside the reference directory
+func posixReadDir(refDirectory string) func(dir string) ([]os.FileInfo, error) {
+	realRoot, errRoot := filepath.EvalSymlinks(refDirectory)
+	return func(dir string) ([]os.FileInfo, error) {
+		if errRoot != nil {
+			return nil, errRoot
+		}
+		dirents, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		fis := make([]os.FileInfo, 0, len(dirents))
+		for _, dirent := range dirents {
+			fi, err := dirent.Info()
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue // removed in the meantime
+				}
+				return nil, err
+			}
+			if fi.Mode()&os.ModeSymlink != 0 {
+				// follow symlinks to files but not to directories (cycles)
+				path := filepath.Join(dir, dirent.Name())
+				if realPath, err := filepath.EvalSymlinks(path); err != nil || !cos.IsSubdir(realRoot, realPath) {
+					continue
+				}
+				if fi, err = os.Stat(path); err != nil || fi.IsDir() {
+					continue
+				}
+			}
+			if fi.IsDir() || fi.Mode().IsRegular() {
+				fis = append(fis, fi)
+			}
+		}
+		return fis, nil
+	}
+}
+
+//////////////////
+// LIST BUCKETS //
+//////////////////
+
+func (*posixProvider) ListBuckets(cmn.QueryBcks) (buckets cmn.Bcks, errCode int, err error) {
+	debug.Assert(false)
+	return
+}
+
+/////////////////
+// HEAD OBJECT //
+/////////////////
+
+func (*posixProvider) HeadObj(_ ctx, lom *cluster.LOM) (oa *cmn.ObjAttrs, errCode int, err error) {
+	var (
+		fi       os.FileInfo
+		filePath string
+	)
+	if filePath, err = posixPath(lom); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if fi, err = os.Stat(filePath); err != nil {
+		errCode, err = posixErrorToAISError(err)
+		return
+	}
+	if !fi.Mode().IsRegular() {
+		return nil, http.StatusNotFound, cmn.NewErrNotFound("%s: object", lom)
+	}
+	oa = &cmn.ObjAttrs{}
+	oa.SetCustomKey(cmn.SourceObjMD, cmn.ProviderPOSIX)
+	oa.Size = fi.Size()
+	if verbose {
+		glog.Infof("[head_object] %s", lom)
+	}
+	return
+}
+
+////////////////
+// GET OBJECT //
+////////////////
+
+func (pp *posixProvider) GetObj(ctx context.Context, lom *cluster.LOM, owt cmn.OWT) (errCode int, err error) {
+	reader, _, errCode, err := pp.GetObjReader(ctx, lom)
+	if err != nil {
+		return errCode, err
+	}
+	params := cluster.PutObjectParams{
+		Tag:    fs.WorkfileColdget,
+		Reader: reader,
+		OWT:    owt,
+		Atime:  time.Now(),
+	}
+	if err = pp.t.PutObject(lom, params); err != nil {
+		return
+	}
+	if verbose {
+		glog.Infof("[get_object] %s", lom)
+	}
+	return
+}
+
+////////////////////
+// GET OBJ READER //
+////////////////////
+
+func (*posixProvider) GetObjReader(ctx context.Context, lom *cluster.LOM) (r io.ReadCloser,
+	expectedCksm *cos.Cksum, errCode int, err error) {
+	var (
+		fh       *os.File
+		fi       os.FileInfo
+		filePath string
+	)
+	if filePath, err = posixPath(lom); err != nil {
+		errCode = http.StatusBadRequest
+		return
+	}
+	if fh, err = os.Open(filePath); err != nil {
+		errCode, err = posixErrorToAISError(err)
+		return
+	}
+	if fi, err = fh.Stat(); err != nil {
+		fh.Close()
+		errCode, err = posixErrorToAISError(err)
+		return
+	}
+	if !fi.Mode().IsRegular() {
+		fh.Close()
+		err = cmn.NewErrNotFound("%s: object", lom)
+		return nil, nil, http.StatusNotFound, err
+	}
+	lom.SetCustomKey(cmn.SourceObjMD, cmn.ProviderPOSIX)
+	setSize(ctx, fi.Size())
+	return wrapReader(ctx, fh), nil, 0, nil
+}
+
+// NOTE: the version is not pinned - local files do not have one
+func (*posixProvider) GetObjReaderRange(ctx context.Context, lom *cluster.LOM, offset, length int64,
+	_ *cmn.ObjAttrs) (r io.ReadCloser, errCode int, err error) {
+	var filePath string
+	if filePath, err = posixPath(lom); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if r, err = cos.NewFileSectionHandle(filePath, offset, length); err != nil {
+		errCode, err = posixErrorToAISError(err)
+		return
+	}
+	return wrapReader(ctx, r), 0, nil
+}
+
+////////////////
+// PUT OBJECT //
+////////////////
+
+// write via temporary file (in the same directory) and rename, so that the readers
+// never see partially written objects
+func (*posixProvider) PutObj(r io.ReadCloser, lom *cluster.LOM) (errCode int, err error) {
+	var (
+		fh               *os.File
+		filePath, tmpFQN string
+	)
+	defer cos.Close(r)
+	if filePath, err = posixPath(lom); err != nil {
+		return http.StatusBadRequest, err
+	}
+	tmpFQN = filePath + ".ais-" + cos.GenTie()
+	if fh, err = cos.CreateFile(tmpFQN); err != nil {
+		goto finish
+	}
+	if _, err = io.Copy(fh, r); err != nil {
+		fh.Close()
+		goto finish
+	}
+	if err = fh.Close(); err != nil {
+		goto finish
+	}
+	err = os.Rename(tmpFQN, filePath)
+
+finish:
+	if err != nil {
+		if errRm := cos.RemoveFile(tmpFQN); errRm != nil {
+			glog.Errorf("nested error: %v (failed to remove %s: %v)", err, tmpFQN, errRm)
+		}
+		errCode, err = posixErrorToAISError(err)
+		return
+	}
+	if verbose {
+		glog.Infof("[put_object] %s", lom)
+	}
+	return 0, nil
+}
+
+///////////////////
+// DELETE OBJECT //
+///////////////////
+
+func (*posixProvider) DeleteObj(lom *cluster.LOM) (errCode int, err error) {
+	var filePath string
+	if filePath, err = posixPath(lom); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if err = os.Remove(filePath); err != nil {
+		errCode, err = posixErrorToAISError(err)
+		return
+	}
+	if verbose {
+		glog.Infof("[delete_object] %s", lom)
+	}
+	return 0, nil
+}
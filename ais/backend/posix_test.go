@@ -0,0 +1,102 @@
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestPOSIXList(t *testing.T) {
+	dir := t.TempDir()
+	// NOTE: "a-b" < "a/x" < "a0" (unlike the order of filepath.Walk)
+	for _, name := range []string{"a/x", "a/y/z", "a-b", "a0", "b/c/d", "e"} {
+		fqn := filepath.Join(dir, name)
+		tassert.CheckFatal(t, os.MkdirAll(filepath.Dir(fqn), 0o755))
+		tassert.CheckFatal(t, os.WriteFile(fqn, []byte(name), 0o644))
+	}
+	tassert.CheckFatal(t, os.MkdirAll(filepath.Join(dir, "empty"), 0o755))
+	// links: to a file (listed), outside the directory (not listed)
+	tassert.CheckFatal(t, os.Symlink("../a/x", filepath.Join(dir, "b/link")))
+	outside := filepath.Join(t.TempDir(), "secret")
+	tassert.CheckFatal(t, os.WriteFile(outside, []byte("secret"), 0o644))
+	tassert.CheckFatal(t, os.Symlink(outside, filepath.Join(dir, "b/outside")))
+
+	list := func(prefix, token string, pageSize uint) (names []string) {
+		l := &dirLister{
+			readDir: posixReadDir(dir),
+			msg:     &cmn.ListObjsMsg{Prefix: prefix, PageSize: pageSize},
+			list:    &cmn.BucketList{},
+			token:   token,
+		}
+		err := l.walk(dir, "")
+		tassert.Fatalf(t, err == nil || err == errPageFull, "unexpected error: %v", err)
+		for _, entry := range l.list.Entries {
+			names = append(names, entry.Name)
+		}
+		return
+	}
+	tests := []struct {
+		prefix, token string
+		pageSize      uint
+		expected      []string
+	}{
+		{"", "", 100, []string{"a-b", "a/x", "a/y/z", "a0", "b/c/d", "b/link", "e"}},
+		{"", "", 2, []string{"a-b", "a/x"}},
+		{"", "a/x", 2, []string{"a/y/z", "a0"}},
+		{"", "a0", 100, []string{"b/c/d", "b/link", "e"}},
+		{"a/", "", 100, []string{"a/x", "a/y/z"}},
+		{"a", "a-b", 100, []string{"a/x", "a/y/z", "a0"}},
+		{"b/c", "", 100, []string{"b/c/d"}},
+		{"x", "", 100, nil},
+	}
+	for _, test := range tests {
+		names := list(test.prefix, test.token, test.pageSize)
+		tassert.Errorf(t, reflect.DeepEqual(names, test.expected), "prefix %q, token %q: expected %v, got %v",
+			test.prefix, test.token, test.expected, names)
+	}
+}
+
+func TestPOSIXObjPath(t *testing.T) {
+	var (
+		root    = t.TempDir()
+		dir     = filepath.Join(root, "ref")
+		outside = filepath.Join(root, "outside")
+		bck     = cluster.NewBck("ref", cmn.ProviderPOSIX, cmn.NsGlobal)
+	)
+	tassert.CheckFatal(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	tassert.CheckFatal(t, os.MkdirAll(outside, 0o755))
+	tassert.CheckFatal(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o644))
+	tassert.CheckFatal(t, os.WriteFile(filepath.Join(dir, "sub", "obj"), []byte("obj"), 0o644))
+	tassert.CheckFatal(t, os.Symlink(outside, filepath.Join(dir, "out-dir")))
+	tassert.CheckFatal(t, os.Symlink(filepath.Join(outside, "secret"), filepath.Join(dir, "out-file")))
+	tassert.CheckFatal(t, os.Symlink(filepath.Join(outside, "none"), filepath.Join(dir, "out-dangling")))
+	tassert.CheckFatal(t, os.Symlink("sub", filepath.Join(dir, "in-dir")))
+	tassert.CheckFatal(t, os.Symlink("sub/obj", filepath.Join(dir, "in-file")))
+
+	for _, objName := range []string{"sub/obj", "new", "sub/new/new", "in-dir/obj", "in-file", "in-dir/new"} {
+		path, err := posixObjPath(dir, objName, bck)
+		tassert.Errorf(t, err == nil && path == filepath.Join(dir, objName), "%q: unexpected %q, %v", objName, path, err)
+	}
+	for _, objName := range []string{"../outside/secret", "out-dir/secret", "out-dir/new", "out-file", "out-dangling",
+		"out-dir", ""} {
+		_, err := posixObjPath(dir, objName, bck)
+		tassert.Errorf(t, err != nil, "expected %q to be rejected", objName)
+	}
+
+	// reference directory that is itself a link
+	link := filepath.Join(root, "ref-link")
+	tassert.CheckFatal(t, os.Symlink(dir, link))
+	_, err := posixObjPath(link, "in-dir/obj", bck)
+	tassert.CheckError(t, err)
+	_, err = posixObjPath(link, "out-file", bck)
+	tassert.Errorf(t, err != nil, "expected %q to be rejected", "out-file")
+}
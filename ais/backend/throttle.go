@@ -0,0 +1,324 @@
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Rate limiting and retrying of the requests to remote backends. Each request:
+// - waits for the provider's token bucket (cluster config `rate_limit.backends`) and the bucket's
+//   token bucket (bucket property `rate_limit`) - both limits are per target;
+// - when throttled by the backend (HTTP 429 and 503, S3 SlowDown, etc.), gets retried with
+//   exponential backoff and jitter, up to the provider's `max_retries` times.
+// PUT is retried only if its reader can be reopened (cos.ReadOpenCloser).
+
+type (
+	tokenBucket struct {
+		mu     sync.Mutex
+		last   time.Time
+		tokens float64
+		rate   float64 // tokens per second
+		burst  float64
+		// as configured
+		maxRPS   int
+		maxBurst int
+	}
+	throttled struct {
+		cluster.BackendProvider
+		statsT   cos.StatsTracker
+		mu       sync.Mutex
+		provider *tokenBucket            // nil: unlimited
+		buckets  map[string]*tokenBucket // by bucket uname
+	}
+	throttledRange struct {
+		*throttled
+		rr cluster.RangeReader
+	}
+)
+
+const (
+	dfltMinBackoff = 500 * time.Millisecond
+	dfltMaxBackoff = 30 * time.Second
+)
+
+// substrings of the Cloud providers' throttling errors
+var throttleErrs = []string{
+	"SlowDown", "Throttl", "RequestLimitExceeded", "TooManyRequests", // AWS
+	"rateLimitExceeded", // GCP
+	"ServerBusy",        // Azure
+}
+
+// interface guard
+var (
+	_ cluster.BackendProvider = (*throttled)(nil)
+	_ cluster.RangeReader     = (*throttledRange)(nil)
+)
+
+// NewThrottled wraps backend provider to rate-limit and retry its requests.
+func NewThrottled(bp cluster.BackendProvider, statsT cos.StatsTracker) cluster.BackendProvider {
+	tp := &throttled{BackendProvider: bp, statsT: statsT, buckets: make(map[string]*tokenBucket)}
+	if rr, ok := bp.(cluster.RangeReader); ok {
+		return &throttledRange{tp, rr}
+	}
+	return tp
+}
+
+// Unwrap returns the backend provider that is rate-limited (see NewThrottled), or the given one.
+func Unwrap(bp cluster.BackendProvider) cluster.BackendProvider {
+	switch tp := bp.(type) {
+	case *throttled:
+		return tp.BackendProvider
+	case *throttledRange:
+		return tp.BackendProvider
+	}
+	return bp
+}
+
+func isThrottled(errCode int, err error) bool {
+	if errCode == http.StatusTooManyRequests || errCode == http.StatusServiceUnavailable {
+		return true
+	}
+	msg := err.Error()
+	for _, s := range throttleErrs {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+/////////////////
+// tokenBucket //
+/////////////////
+
+func newTokenBucket(maxRPS, burst int) *tokenBucket {
+	tb := &tokenBucket{rate: float64(maxRPS), burst: float64(burst), maxRPS: maxRPS, maxBurst: burst}
+	if burst == 0 {
+		tb.burst = tb.rate
+	}
+	tb.tokens = tb.burst
+	tb.last = time.Now()
+	return tb
+}
+
+func (tb *tokenBucket) is(maxRPS, burst int) bool { return tb.maxRPS == maxRPS && tb.maxBurst == burst }
+
+// takes a token and returns the time to wait for it (the tokens can go negative
+// to reserve the future ones)
+func (tb *tokenBucket) reserve(now time.Time) (wait time.Duration) {
+	tb.mu.Lock()
+	if elapsed := now.Sub(tb.last); elapsed > 0 {
+		tb.tokens += elapsed.Seconds() * tb.rate
+		if tb.tokens > tb.burst {
+			tb.tokens = tb.burst
+		}
+		tb.last = now
+	}
+	tb.tokens--
+	if tb.tokens < 0 {
+		wait = time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+	}
+	tb.mu.Unlock()
+	return
+}
+
+///////////////
+// throttled //
+///////////////
+
+func (tp *throttled) conf() (limit cmn.BackendRateLimit) {
+	limit = cmn.GCO.Get().RateLimit.Backends[tp.Provider()]
+	if limit.MinBackoff == 0 {
+		limit.MinBackoff = cos.Duration(dfltMinBackoff)
+	}
+	if limit.MaxBackoff == 0 {
+		limit.MaxBackoff = cos.Duration(cos.MaxDuration(dfltMaxBackoff, limit.MinBackoff.D()))
+	}
+	return
+}
+
+// (re)create token buckets upon configuration changes
+func (tp *throttled) limiters(limit *cmn.BackendRateLimit, bck *cluster.Bck) (ptb, btb *tokenBucket) {
+	tp.mu.Lock()
+	if limit.MaxRPS == 0 {
+		tp.provider = nil
+	} else {
+		if tp.provider == nil || !tp.provider.is(limit.MaxRPS, limit.Burst) {
+			tp.provider = newTokenBucket(limit.MaxRPS, limit.Burst)
+		}
+		ptb = tp.provider
+	}
+	if bck != nil && bck.Props != nil {
+		uname := bck.MakeUname("")
+		if conf := &bck.Props.RateLimit; conf.Enabled && conf.MaxRPS > 0 {
+			btb = tp.buckets[uname]
+			if btb == nil || !btb.is(conf.MaxRPS, conf.Burst) {
+				btb = newTokenBucket(conf.MaxRPS, conf.Burst)
+				tp.buckets[uname] = btb
+			}
+		} else {
+			delete(tp.buckets, uname)
+		}
+	}
+	tp.mu.Unlock()
+	return
+}
+
+func (tp *throttled) wait(ctx context.Context, limit *cmn.BackendRateLimit, bck *cluster.Bck) error {
+	var (
+		d        time.Duration
+		now      = time.Now()
+		ptb, btb = tp.limiters(limit, bck)
+	)
+	if ptb != nil {
+		d = ptb.reserve(now)
+	}
+	if btb != nil {
+		d = cos.MaxDuration(d, btb.reserve(now))
+	}
+	if d <= 0 {
+		return nil
+	}
+	tp.statsT.AddMany(
+		cos.NamedVal64{Name: stats.RemoteRateWaitCount, Value: 1},
+		cos.NamedVal64{Name: stats.RemoteRateWaitLatency, Value: int64(d)},
+	)
+	return sleepCtx(ctx, d)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// executes the request (callback) with rate limiting and retries
+func (tp *throttled) do(ctx context.Context, bck *cluster.Bck, retriable bool, cb func() (int, error)) (errCode int,
+	err error) {
+	limit := tp.conf()
+	backoff := limit.MinBackoff.D()
+	for retry := 0; ; retry++ {
+		if err = tp.wait(ctx, &limit, bck); err != nil {
+			return http.StatusServiceUnavailable, err
+		}
+		errCode, err = cb()
+		if err == nil || !isThrottled(errCode, err) {
+			return
+		}
+		tp.statsT.Add(stats.RemoteThrottleCount, 1)
+		if !retriable || retry >= limit.MaxRetries {
+			return
+		}
+		// jitter (so that the targets do not retry in lockstep)
+		delay := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		if errSleep := sleepCtx(ctx, delay); errSleep != nil {
+			return
+		}
+		backoff = cos.MinDuration(2*backoff, limit.MaxBackoff.D())
+		tp.statsT.Add(stats.RemoteRetryCount, 1)
+	}
+}
+
+func (tp *throttled) CreateBucket(bck *cluster.Bck) (int, error) {
+	return tp.do(context.Background(), bck, true, func() (int, error) {
+		return tp.BackendProvider.CreateBucket(bck)
+	})
+}
+
+func (tp *throttled) ListObjects(bck *cluster.Bck, msg *cmn.ListObjsMsg) (bckList *cmn.BucketList,
+	errCode int, err error) {
+	errCode, err = tp.do(context.Background(), bck, true, func() (errCode int, err error) {
+		bckList, errCode, err = tp.BackendProvider.ListObjects(bck, msg)
+		return
+	})
+	return
+}
+
+func (tp *throttled) ListBuckets(query cmn.QueryBcks) (bcks cmn.Bcks, errCode int, err error) {
+	errCode, err = tp.do(context.Background(), nil, true, func() (errCode int, err error) {
+		bcks, errCode, err = tp.BackendProvider.ListBuckets(query)
+		return
+	})
+	return
+}
+
+func (tp *throttled) PutObj(r io.ReadCloser, lom *cluster.LOM) (int, error) {
+	roc, reopen := r.(cos.ReadOpenCloser)
+	first := true
+	return tp.do(context.Background(), lom.Bck(), reopen, func() (int, error) {
+		if !first {
+			nr, err := roc.Open()
+			if err != nil {
+				return http.StatusInternalServerError, err
+			}
+			r = nr
+		}
+		first = false
+		return tp.BackendProvider.PutObj(r, lom) // (closes the reader)
+	})
+}
+
+func (tp *throttled) DeleteObj(lom *cluster.LOM) (int, error) {
+	return tp.do(context.Background(), lom.Bck(), true, func() (int, error) {
+		return tp.BackendProvider.DeleteObj(lom)
+	})
+}
+
+func (tp *throttled) HeadBucket(ctx context.Context, bck *cluster.Bck) (bckProps cos.SimpleKVs, errCode int, err error) {
+	errCode, err = tp.do(ctx, bck, true, func() (errCode int, err error) {
+		bckProps, errCode, err = tp.BackendProvider.HeadBucket(ctx, bck)
+		return
+	})
+	return
+}
+
+func (tp *throttled) HeadObj(ctx context.Context, lom *cluster.LOM) (oa *cmn.ObjAttrs, errCode int, err error) {
+	errCode, err = tp.do(ctx, lom.Bck(), true, func() (errCode int, err error) {
+		oa, errCode, err = tp.BackendProvider.HeadObj(ctx, lom)
+		return
+	})
+	return
+}
+
+func (tp *throttled) GetObj(ctx context.Context, lom *cluster.LOM, owt cmn.OWT) (int, error) {
+	return tp.do(ctx, lom.Bck(), true, func() (int, error) {
+		return tp.BackendProvider.GetObj(ctx, lom, owt)
+	})
+}
+
+func (tp *throttled) GetObjReader(ctx context.Context, lom *cluster.LOM) (r io.ReadCloser, expectedCksum *cos.Cksum,
+	errCode int, err error) {
+	errCode, err = tp.do(ctx, lom.Bck(), true, func() (errCode int, err error) {
+		r, expectedCksum, errCode, err = tp.BackendProvider.GetObjReader(ctx, lom)
+		return
+	})
+	return
+}
+
+func (tr *throttledRange) GetObjReaderRange(ctx context.Context, lom *cluster.LOM, offset, length int64,
+	oa *cmn.ObjAttrs) (r io.ReadCloser, errCode int, err error) {
+	errCode, err = tr.do(ctx, lom.Bck(), true, func() (errCode int, err error) {
+		r, errCode, err = tr.rr.GetObjReaderRange(ctx, lom, offset, length, oa)
+		return
+	})
+	return
+}
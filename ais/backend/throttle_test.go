@@ -0,0 +1,152 @@
+// Package backend contains implementation of various backend providers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package backend
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+type (
+	// fails the first `throttle` requests with the given error
+	throttlingProvider struct {
+		dummyBackendProvider
+		errCode  int
+		err      error
+		throttle int
+		calls    int
+		reads    []string // PUT: content read by each call
+	}
+	testTracker map[string]int64
+)
+
+func (tp *throttlingProvider) Provider() string { return cmn.ProviderGoogle }
+
+func (tp *throttlingProvider) respond() (int, error) {
+	tp.calls++
+	if tp.calls <= tp.throttle {
+		return tp.errCode, tp.err
+	}
+	return 0, nil
+}
+
+func (tp *throttlingProvider) HeadBucket(_ ctx, _ *cluster.Bck) (cos.SimpleKVs, int, error) {
+	errCode, err := tp.respond()
+	return cos.SimpleKVs{}, errCode, err
+}
+
+func (tp *throttlingProvider) PutObj(r io.ReadCloser, _ *cluster.LOM) (int, error) {
+	b, _ := io.ReadAll(r)
+	r.Close()
+	tp.reads = append(tp.reads, string(b))
+	return tp.respond()
+}
+
+func (tt testTracker) Add(name string, val int64) { tt[name] += val }
+func (tt testTracker) Get(name string) int64      { return tt[name] }
+func (tt testTracker) AddMany(nvs ...cos.NamedVal64) {
+	for _, nv := range nvs {
+		tt.Add(nv.Name, nv.Value)
+	}
+}
+
+func setRateLimit(limit cmn.BackendRateLimit) {
+	config := cmn.GCO.BeginUpdate()
+	config.RateLimit.Backends = map[string]cmn.BackendRateLimit{cmn.ProviderGoogle: limit}
+	cmn.GCO.CommitUpdate(config)
+}
+
+func TestThrottledRetry(t *testing.T) {
+	setRateLimit(cmn.BackendRateLimit{MaxRetries: 3, MinBackoff: cos.Duration(time.Millisecond)})
+	defer setRateLimit(cmn.BackendRateLimit{})
+
+	tests := []struct {
+		errCode  int
+		err      error
+		throttle int
+		calls    int
+		ok       bool
+	}{
+		{http.StatusTooManyRequests, errors.New("rateLimitExceeded"), 2, 3, true},
+		{http.StatusServiceUnavailable, errors.New("SlowDown: please reduce your request rate"), 3, 4, true},
+		{http.StatusBadRequest, errors.New("ServerBusy"), 10, 4, false}, // max retries
+		{http.StatusNotFound, errors.New("not found"), 10, 1, false},    // not throttled
+	}
+	for _, test := range tests {
+		var (
+			tracker = testTracker{}
+			tp      = &throttlingProvider{errCode: test.errCode, err: test.err, throttle: test.throttle}
+			bp      = NewThrottled(tp, tracker)
+			bck     = cluster.NewBck("abc", cmn.ProviderGoogle, cmn.NsGlobal)
+		)
+		_, _, err := bp.HeadBucket(context.Background(), bck)
+		tassert.Errorf(t, (err == nil) == test.ok, "%v: expected ok=%t, got %v", test.err, test.ok, err)
+		tassert.Errorf(t, tp.calls == test.calls, "%v: expected %d calls, got %d", test.err, test.calls, tp.calls)
+		if test.calls > 1 {
+			throttled := tracker[stats.RemoteThrottleCount]
+			tassert.Errorf(t, throttled == int64(cos.Min(test.throttle, test.calls)), "throttled %d", throttled)
+			tassert.Errorf(t, tracker[stats.RemoteRetryCount] == int64(test.calls-1),
+				"expected %d retries, got %d", test.calls-1, tracker[stats.RemoteRetryCount])
+		}
+	}
+
+	// PUT is retried with the reopened reader, unless the reader cannot be reopened
+	tp := &throttlingProvider{errCode: http.StatusServiceUnavailable, err: errors.New("SlowDown"), throttle: 1}
+	bp := NewThrottled(tp, testTracker{})
+	_, err := bp.PutObj(cos.NewByteHandle([]byte("data")), &cluster.LOM{})
+	tassert.CheckError(t, err)
+	tassert.Errorf(t, len(tp.reads) == 2 && tp.reads[1] == "data", "unexpected reads %q", tp.reads)
+
+	tp = &throttlingProvider{errCode: http.StatusServiceUnavailable, err: errors.New("SlowDown"), throttle: 1}
+	bp = NewThrottled(tp, testTracker{})
+	_, err = bp.PutObj(io.NopCloser(strings.NewReader("data")), &cluster.LOM{})
+	tassert.Errorf(t, err != nil && tp.calls == 1, "expected no retries, got %d calls (err %v)", tp.calls, err)
+
+	// the provider behind
+	tassert.Errorf(t, Unwrap(bp) == tp && Unwrap(tp) == tp, "expected %T to unwrap", bp)
+}
+
+func TestThrottledRateLimit(t *testing.T) {
+	setRateLimit(cmn.BackendRateLimit{MaxRPS: 100, Burst: 10})
+	defer setRateLimit(cmn.BackendRateLimit{})
+
+	var (
+		tracker = testTracker{}
+		tp      = &throttlingProvider{}
+		bp      = NewThrottled(tp, tracker)
+		bck     = cluster.NewBck("abc", cmn.ProviderGoogle, cmn.NsGlobal, &cmn.BucketProps{})
+		started = time.Now()
+	)
+	// burst, followed by 100 requests per second
+	for i := 0; i < 30; i++ {
+		_, _, err := bp.HeadBucket(context.Background(), bck)
+		tassert.CheckFatal(t, err)
+	}
+	elapsed := time.Since(started)
+	tassert.Errorf(t, elapsed > 150*time.Millisecond, "30 requests took %v (expecting ~200ms)", elapsed)
+	tassert.Errorf(t, tracker[stats.RemoteRateWaitCount] >= 19, "waited %d times", tracker[stats.RemoteRateWaitCount])
+
+	// bucket's own (lower) limit
+	setRateLimit(cmn.BackendRateLimit{})
+	bck.Props.RateLimit = cmn.BckRateLimitConf{MaxRPS: 50, Burst: 1, Enabled: true}
+	started = time.Now()
+	for i := 0; i < 11; i++ {
+		_, _, err := bp.HeadBucket(context.Background(), bck)
+		tassert.CheckFatal(t, err)
+	}
+	elapsed = time.Since(started)
+	tassert.Errorf(t, elapsed > 150*time.Millisecond, "11 requests took %v (expecting ~200ms)", elapsed)
+}
@@ -471,6 +471,15 @@ func defaultBckProps(args bckPropsArgs) (props *cmn.BucketProps) {
 		}
 		// Use HDFS props.
 		props.Extra.HDFS = args.bck.Props.Extra.HDFS
+	case args.bck.IsPOSIX():
+		props.Versioning.Enabled = false
+		if args.hdr != nil {
+			props = mergeRemoteBckProps(props, args.hdr)
+		}
+		if args.bck.Props == nil {
+			return // (ditto)
+		}
+		props.Extra.POSIX = args.bck.Props.Extra.POSIX
 	case args.bck.IsRemote():
 		debug.Assert(args.hdr != nil)
 		props.Versioning.Enabled = false
@@ -1464,7 +1464,9 @@ func (h *httprunner) httpdaeget(w http.ResponseWriter, r *http.Request) {
 	)
 	switch what {
 	case cmn.GetWhatConfig:
-		body = cmn.GCO.Get()
+		config := *cmn.GCO.Get()
+		config.Backend = config.Backend.Redacted()
+		body = &config
 	case cmn.GetWhatSmap:
 		body = h.owner.smap.get()
 	case cmn.GetWhatBMD:
@@ -352,7 +352,7 @@ func (p *proxyrunner) parseReqAndTry(w http.ResponseWriter, r *http.Request, bck
 	if err = p.parseReq(w, r, &request); err != nil {
 		return
 	}
-	bckArgs.bck, bckArgs.query = request.bck, request.query
+	bckArgs.bck, bckArgs.query, bckArgs.objName = request.bck, request.query, request.items[1]
 	// both immmediate caller (ais package) _and_ user (via cmn.URLParamDontLookupRemoteBck)
 	bckArgs.lookupRemote = bckArgs.lookupRemote && !dontLookupRemote(request.query)
 
@@ -641,6 +641,9 @@ func (p *proxyrunner) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		return
 	}
+	if p.checkBypassGovernance(w, r, bck) != nil {
+		return
+	}
 
 	smap := p.owner.smap.get()
 	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
@@ -698,8 +701,8 @@ func (p *proxyrunner) httpbckdelete(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		keepMD := cos.IsParseBool(request.query.Get(cmn.URLParamKeepBckMD))
-		// HDFS buckets will always keep metadata so they can re-register later
-		if bck.IsHDFS() || keepMD {
+		// HDFS and POSIX buckets will always keep metadata so they can re-register later
+		if bck.IsHDFS() || bck.IsPOSIX() || keepMD {
 			if err := p.destroyBucketData(&msg, bck); err != nil {
 				p.writeErr(w, r, err)
 			}
@@ -739,6 +742,14 @@ func (p *proxyrunner) httpbckdelete(w http.ResponseWriter, r *http.Request) {
 			p.writeErrf(w, r, fmtNotRemote, bck.Name)
 			return
 		}
+		lrMsg := &cmn.ListRangeMsg{}
+		if err := cos.MorphMarshal(msg.Value, lrMsg); err != nil {
+			p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
+			return
+		}
+		if err := p.checkListRangeACL(w, r, bck, lrMsg, "", cmn.AceObjDELETE); err != nil {
+			return
+		}
 		if xactID, err = p.doListRange(r.Method, bck.Name, &msg, request.query); err != nil {
 			p.writeErr(w, r, err)
 			return
@@ -942,6 +953,15 @@ func (p *proxyrunner) httpbckput(w http.ResponseWriter, r *http.Request) {
 			p.writeErr(w, r, err)
 			return
 		}
+		if err := p.checkACL(w, r, bckFrom, cmn.AceGET); err != nil {
+			return
+		}
+		if err := p.checkListRangeACL(w, r, bckFrom, &archMsg.ListRangeMsg, "", cmn.AceGET); err != nil {
+			return
+		}
+		if err := p.checkObjACL(w, r, bckTo, archMsg.ArchName, cmn.AcePUT); err != nil {
+			return
+		}
 		xactID, err := p.createArchMultiObj(bckFrom, bckTo, msg)
 		if err == nil {
 			w.Write([]byte(xactID))
@@ -1122,6 +1142,18 @@ func (p *proxyrunner) hpostBucket(w http.ResponseWriter, r *http.Request, msg *c
 			p.writeErrf(w, r, "cannot %s to HTTP bucket %q", msg.Action, bckTo)
 			return
 		}
+		if err = p.checkACL(w, r, bck, cmn.AceGET); err != nil {
+			return
+		}
+		if err = p.checkListRangeACL(w, r, bck, &tcoMsg.ListRangeMsg, "", cmn.AceGET); err != nil {
+			return
+		}
+		if err = p.checkACL(w, r, bckTo, cmn.AcePUT); err != nil {
+			return
+		}
+		if err = p.checkListRangeACL(w, r, bckTo, &tcoMsg.ListRangeMsg, tcoMsg.Prefix, cmn.AcePUT); err != nil {
+			return
+		}
 		glog.Infof("multi-obj %s %s => %s", msg.Action, bck, bckTo)
 		if xactID, err = p.tcobjs(bck, bckTo, msg); err != nil {
 			p.writeErr(w, r, err)
@@ -1147,6 +1179,22 @@ func (p *proxyrunner) hpostBucket(w http.ResponseWriter, r *http.Request, msg *c
 			p.writeErrf(w, r, fmtNotRemote, bucket)
 			return
 		}
+		prfMsg := &cmn.PrefetchMsg{}
+		if err := cos.MorphMarshal(msg.Value, prfMsg); err != nil {
+			p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
+			return
+		}
+		if err := prfMsg.Validate(); err != nil {
+			p.writeErr(w, r, err)
+			return
+		}
+		lrMsg := &prfMsg.ListRangeMsg
+		if prfMsg.Manifest != "" {
+			lrMsg = &cmn.ListRangeMsg{} // (object names cannot be checked upfront)
+		}
+		if err := p.checkListRangeACL(w, r, bck, lrMsg, "", cmn.AceGET); err != nil {
+			return
+		}
 		var xactID string
 		if xactID, err = p.doListRange(r.Method, bucket, msg, query); err != nil {
 			p.writeErr(w, r, err)
@@ -1195,6 +1243,15 @@ func (p *proxyrunner) hpostCreateBucket(w http.ResponseWriter, r *http.Request,
 			errors.New("property 'extra.hdfs.ref_directory' must be specified when creating HDFS bucket"))
 		return
 	}
+	if bck.IsPOSIX() && msg.Value == nil {
+		p.writeErr(w, r,
+			errors.New("property 'extra.posix.ref_directory' must be specified when creating POSIX bucket"))
+		return
+	}
+	if bck.IsHTTP() && msg.Value == nil {
+		msg.Value = &cmn.BucketPropsToUpdate{} // (see lookupHTTPBucket below)
+	}
+	var remoteHdr http.Header
 	if msg.Value != nil {
 		propsToUpdate := cmn.BucketPropsToUpdate{}
 		if err := cos.MorphMarshal(msg.Value, &propsToUpdate); err != nil {
@@ -1202,7 +1259,22 @@ func (p *proxyrunner) hpostCreateBucket(w http.ResponseWriter, r *http.Request,
 			return
 		}
 		// Make and validate new bucket props.
-		bck.Props = defaultBckProps(bckPropsArgs{bck: bck})
+		if bck.IsCloud() || bck.IsHTTP() {
+			var code int
+			if bck.IsHTTP() {
+				remoteHdr, code, err = p.lookupHTTPBucket(bck, r.URL.Query(), &propsToUpdate)
+			} else {
+				remoteHdr, code, err = p.lookupS3Bucket(bck, &propsToUpdate)
+			}
+			if err != nil {
+				p.writeErr(w, r, err, code)
+				return
+			}
+			msg.Action = cmn.ActAddRemoteBck
+			bck.Props = defaultBckProps(bckPropsArgs{bck: bck, hdr: remoteHdr})
+		} else {
+			bck.Props = defaultBckProps(bckPropsArgs{bck: bck})
+		}
 		bck.Props, err = p.makeNewBckProps(bck, &propsToUpdate, true /*creating*/)
 		if err != nil {
 			p.writeErr(w, r, err)
@@ -1231,7 +1303,7 @@ func (p *proxyrunner) hpostCreateBucket(w http.ResponseWriter, r *http.Request,
 		// Send full props to the target. Required for HDFS provider.
 		msg.Value = bck.Props
 	}
-	if err := p.createBucket(msg, bck); err != nil {
+	if err := p.createBucket(msg, bck, remoteHdr); err != nil {
 		errCode := http.StatusInternalServerError
 		if _, ok := err.(*cmn.ErrBucketAlreadyExists); ok {
 			errCode = http.StatusConflict
@@ -1240,6 +1312,70 @@ func (p *proxyrunner) hpostCreateBucket(w http.ResponseWriter, r *http.Request,
 	}
 }
 
+// Cloud buckets cannot be created - with one exception: an existing bucket at an S3-compatible
+// endpoint (property `extra.aws.endpoint`) gets looked up and added to the BMD
+func (p *proxyrunner) lookupS3Bucket(bck *cluster.Bck, propsToUpdate *cmn.BucketPropsToUpdate) (hdr http.Header,
+	code int, err error) {
+	var endpoint, profile string
+	if bck.Provider == cmn.ProviderAmazon && propsToUpdate.Extra != nil && propsToUpdate.Extra.AWS != nil {
+		if v := propsToUpdate.Extra.AWS.Endpoint; v != nil {
+			endpoint = *v
+		}
+		if v := propsToUpdate.Extra.AWS.Profile; v != nil {
+			profile = *v
+		}
+	}
+	if endpoint == "" {
+		err = fmt.Errorf("creating a bucket for any of the cloud providers is not supported (%s)", bck)
+		return hdr, http.StatusBadRequest, err
+	}
+	if _, err = cmn.GCO.Get().Backend.S3Endpoint(endpoint); err != nil {
+		return hdr, http.StatusBadRequest, err
+	}
+	q := url.Values{}
+	q.Set(cmn.URLParamS3Endpoint, endpoint)
+	if profile != "" {
+		q.Set(cmn.URLParamS3Profile, profile)
+	}
+	return p.headRemoteBck(bck.Bck, q)
+}
+
+// HTTP(S) bucket (web server's URL "directory" - see ais/backend/http.go) gets looked up
+// via its original URL or, if specified, its manifest, and added to the BMD
+func (p *proxyrunner) lookupHTTPBucket(bck *cluster.Bck, query url.Values,
+	propsToUpdate *cmn.BucketPropsToUpdate) (hdr http.Header, code int, err error) {
+	origURL := query.Get(cmn.URLParamOrigURL)
+	if origURL == "" {
+		err = fmt.Errorf("cannot create %s: missing HTTP URL", bck)
+		return hdr, http.StatusBadRequest, err
+	}
+	if !strings.HasSuffix(origURL, "/") {
+		origURL += "/"
+	}
+	if name := cos.OrigURLBck2Name(origURL); name != bck.Name {
+		err = fmt.Errorf("cannot create %s: bucket name does not match HTTP URL %q (expecting %q)", bck, origURL, name)
+		return hdr, http.StatusBadRequest, err
+	}
+	lookupURL := origURL
+	if extra := propsToUpdate.Extra; extra != nil && extra.HTTP != nil && extra.HTTP.Manifest != nil {
+		var base, u *url.URL
+		if base, err = url.Parse(origURL); err == nil {
+			u, err = base.Parse(*extra.HTTP.Manifest)
+		}
+		if err != nil {
+			return hdr, http.StatusBadRequest, err
+		}
+		lookupURL = u.String()
+	}
+	q := url.Values{}
+	q.Set(cmn.URLParamOrigURL, lookupURL)
+	if hdr, code, err = p.headRemoteBck(bck.Bck, q); err != nil {
+		return
+	}
+	hdr.Set(cmn.HdrOrigURLBck, origURL)
+	return
+}
+
 func (p *proxyrunner) listObjects(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, amsg *cmn.ActionMsg, begin int64) {
 	var (
 		err     error
@@ -1261,11 +1397,15 @@ func (p *proxyrunner) listObjects(w http.ResponseWriter, r *http.Request, bck *c
 		lsmsg.AddProps(cmn.GetPropsDefault...)
 	}
 
-	// Vanilla HTTP buckets do not support remote listing.
 	// LsArchDir needs files locally to read archive content.
-	if bck.IsHTTP() || lsmsg.IsFlagSet(cmn.LsArchDir) {
+	// Same for LsVersions (retained versions are local).
+	if lsmsg.IsFlagSet(cmn.LsArchDir) || lsmsg.IsFlagSet(cmn.LsVersions) {
 		lsmsg.SetFlag(cmn.LsPresent)
 	}
+	if lsmsg.IsFlagSet(cmn.LsVersions) && lsmsg.IsFlagSet(cmn.UseListObjsCache) {
+		p.writeErrMsg(w, r, "listing object versions is not supported with list-objects cache")
+		return
+	}
 
 	locationIsAIS := bck.IsAIS() || lsmsg.IsFlagSet(cmn.LsPresent)
 	if lsmsg.UUID == "" {
@@ -1303,6 +1443,10 @@ func (p *proxyrunner) listObjects(w http.ResponseWriter, r *http.Request, bck *c
 	}
 
 	cos.Assert(bckList != nil)
+	if bckList, err = p.filterListACL(r.Header, bck, bckList); err != nil {
+		p.writeErr(w, r, err, p.aclErrToCode(err))
+		return
+	}
 
 	if strings.Contains(r.Header.Get(cmn.HdrAccept), cmn.ContentMsgPack) {
 		if !p.writeMsgPack(w, r, bckList, "list_objects") {
@@ -1427,7 +1571,7 @@ func (p *proxyrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	request := &apiRequest{after: 1, prefix: cmn.URLPathObjects.L}
-	if msg.Action == cmn.ActRenameObject {
+	if msg.Action == cmn.ActRenameObject || msg.Action == cmn.ActPresignObject {
 		request.after = 2
 	}
 	if err := p.parseReq(w, r, request); err != nil {
@@ -1442,7 +1586,10 @@ func (p *proxyrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 	}
 	switch msg.Action {
 	case cmn.ActRenameObject:
-		if err := p.checkACL(w, r, bck, cmn.AceObjMOVE); err != nil {
+		if err := p.checkObjACL(w, r, bck, request.items[1], cmn.AceObjMOVE); err != nil {
+			return
+		}
+		if err := p.checkObjACL(w, r, bck, msg.Name, cmn.AceObjMOVE); err != nil {
 			return
 		}
 		if bck.IsRemote() {
@@ -1455,6 +1602,9 @@ func (p *proxyrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 		}
 		p.objMv(w, r, bck, request.items[1], &msg)
 		return
+	case cmn.ActPresignObject:
+		p.presignObj(w, r, bck, request.items[1], &msg)
+		return
 	case cmn.ActPromote:
 		if err := p.checkACL(w, r, bck, cmn.AcePromote); err != nil {
 			return
@@ -1590,6 +1740,21 @@ func (p *proxyrunner) httpbckpatch(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if nprops.Replication.Enabled {
+		// replication destination must exist
+		dst, _ := nprops.Replication.DstBck() // (validated)
+		if dst.Equal(bck.Bck) {
+			p.writeErrf(w, r, "bucket %s cannot be replicated to itself", bck)
+			return
+		}
+		dstBck := cluster.NewBckEmbed(dst)
+		args := bckInitArgs{p: p, w: w, r: r, bck: dstBck, msg: msg}
+		args.createAIS = false
+		args.lookupRemote = true
+		if _, err = args.initAndTry(dstBck.Name); err != nil {
+			return
+		}
+	}
 	if xactID, err = p.setBucketProps(msg, bck, nprops); err != nil {
 		p.writeErr(w, r, err)
 		return
@@ -1644,6 +1809,9 @@ func (p *proxyrunner) httpobjpatch(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		return
 	}
+	if p.checkBypassGovernance(w, r, bck) != nil {
+		return
+	}
 	smap := p.owner.smap.get()
 	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -1797,7 +1965,7 @@ func (p *proxyrunner) reverseReqRemote(w http.ResponseWriter, r *http.Request, m
 func (p *proxyrunner) listBuckets(w http.ResponseWriter, r *http.Request, query cmn.QueryBcks, msg *cmn.ActionMsg) {
 	bmd := p.owner.bmd.get()
 	if query.Provider != "" {
-		if query.IsAIS() || query.IsHDFS() {
+		if query.IsAIS() || query.IsHDFS() || query.IsPOSIX() {
 			bcks := selectBMDBuckets(bmd, query)
 			p.writeJSON(w, r, bcks, listBuckets)
 			return
@@ -2677,8 +2845,10 @@ func (p *proxyrunner) tokenHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodDelete:
 		p.httpTokenDelete(w, r)
+	case http.MethodPut:
+		p.httpTokenPut(w, r)
 	default:
-		cmn.WriteErr405(w, r, http.MethodDelete)
+		cmn.WriteErr405(w, r, http.MethodDelete, http.MethodPut)
 	}
 }
 
@@ -5,21 +5,34 @@
 package ais
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais/s3compat"
 	"github.com/NVIDIA/aistore/authn"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
 )
 
 func (p *proxyrunner) httpTokenDelete(w http.ResponseWriter, r *http.Request) {
+	p.updateTokens(w, r, "revoke token")
+}
+
+// PUT /v1/tokens - AuthN pushes S3 access keys
+func (p *proxyrunner) httpTokenPut(w http.ResponseWriter, r *http.Request) {
+	p.updateTokens(w, r, "update S3 keys")
+}
+
+func (p *proxyrunner) updateTokens(w http.ResponseWriter, r *http.Request, tag string) {
 	tokenList := &tokenList{}
 	if _, err := p.checkRESTItems(w, r, 0, false, cmn.URLPathTokens.L); err != nil {
 		return
 	}
-	if p.forwardCP(w, r, nil, "revoke token") {
+	if p.forwardCP(w, r, nil, tag) {
 		return
 	}
 	if err := cmn.ReadJSON(w, r, tokenList); err != nil {
@@ -34,7 +47,9 @@ func (p *proxyrunner) httpTokenDelete(w http.ResponseWriter, r *http.Request) {
 
 // Read a token from request header and validates it
 // Header format:
-//		'Authorization: Bearer <token>'
+//
+//	'Authorization: Bearer <token>'
+//
 // Returns: is auth enabled, decoded token, error
 func (p *proxyrunner) validateToken(hdr http.Header) (*authn.Token, error) {
 	authToken := hdr.Get(cmn.HdrAuthorization)
@@ -58,13 +73,21 @@ func (p *proxyrunner) validateToken(hdr http.Header) (*authn.Token, error) {
 // When AuthN is on, accessing a bucket requires two permissions:
 //   - access to the bucket is granted to a user
 //   - bucket ACL allows the required operation
-//   Exception: a superuser can always PATCH the bucket/Set ACL
+//     Exception: a superuser can always PATCH the bucket/Set ACL
+//
 // If AuthN is off, only bucket permissions are checked.
-//   Exceptions:
-//   - read-only access to a bucket is always granted
-//   - PATCH cannot be forbidden
+//
+//	Exceptions:
+//	- read-only access to a bucket is always granted
+//	- PATCH cannot be forbidden
 func (p *proxyrunner) checkACL(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, ace cmn.AccessAttrs) error {
-	err := p._checkACL(r.Header, bck, ace)
+	return p.checkObjACL(w, r, bck, "", ace)
+}
+
+// same as above for a given object - to enforce prefix-scoped permissions
+func (p *proxyrunner) checkObjACL(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, objName string,
+	ace cmn.AccessAttrs) error {
+	err := p._checkACL(r.Header, bck, objName, ace)
 	if err == nil {
 		return nil
 	}
@@ -83,7 +106,7 @@ func (*proxyrunner) aclErrToCode(err error) int {
 	}
 }
 
-func (p *proxyrunner) _checkACL(hdr http.Header, bck *cluster.Bck, ace cmn.AccessAttrs) error {
+func (p *proxyrunner) _checkACL(hdr http.Header, bck *cluster.Bck, objName string, ace cmn.AccessAttrs) error {
 	if p.isIntraCall(hdr) {
 		return nil
 	}
@@ -103,7 +126,7 @@ func (p *proxyrunner) _checkACL(hdr http.Header, bck *cluster.Bck, ace cmn.Acces
 		if bck != nil {
 			bucket = &bck.Bck
 		}
-		if err := token.CheckPermissions(uid, bucket, ace); err != nil {
+		if err := token.CheckObjPermissions(uid, bucket, objName, ace); err != nil {
 			return err
 		}
 	}
@@ -126,3 +149,128 @@ func (p *proxyrunner) _checkACL(hdr http.Header, bck *cluster.Bck, ace cmn.Acces
 	}
 	return bck.Allow(ace)
 }
+
+// With prefix-scoped permissions, the listing contains only the objects that
+// the user is allowed to list. (Note that, as a result, a page may contain fewer
+// entries than requested.)
+func (p *proxyrunner) filterListACL(hdr http.Header, bck *cluster.Bck, bckList *cmn.BucketList) (*cmn.BucketList, error) {
+	if !cmn.GCO.Get().Auth.Enabled || p.isIntraCall(hdr) {
+		return bckList, nil
+	}
+	token, err := p.validateToken(hdr)
+	if err != nil {
+		return nil, err
+	}
+	uid := p.owner.smap.Get().UUID
+	if !token.HasPrefixACLs(uid, &bck.Bck) {
+		return bckList, nil
+	}
+	// NOTE: not modifying in place - the list may be cached
+	filtered := *bckList
+	filtered.Entries = make([]*cmn.BucketEntry, 0, len(bckList.Entries))
+	for _, entry := range bckList.Entries {
+		if token.CheckObjPermissions(uid, &bck.Bck, entry.Name, cmn.AceObjLIST) == nil {
+			filtered.Entries = append(filtered.Entries, entry)
+		}
+	}
+	return &filtered, nil
+}
+
+// Multi-object operations (list or range): with prefix-scoped permissions, each object
+// in the list (named `namePrefix` + object name, when copied) must be allowed.
+// Ranges (templates), as well as the entire bucket, cannot be checked upfront and
+// are not permitted. The bucket-level ACL is the caller's responsibility.
+func (p *proxyrunner) checkListRangeACL(w http.ResponseWriter, r *http.Request, bck *cluster.Bck,
+	lrMsg *cmn.ListRangeMsg, namePrefix string, ace cmn.AccessAttrs) error {
+	if !cmn.GCO.Get().Auth.Enabled || p.isIntraCall(r.Header) {
+		return nil
+	}
+	token, err := p.validateToken(r.Header)
+	if err != nil {
+		p.writeErr(w, r, err, p.aclErrToCode(err))
+		return err
+	}
+	uid := p.owner.smap.Get().UUID
+	if !token.HasPrefixACLs(uid, &bck.Bck) {
+		return nil
+	}
+	if !lrMsg.IsList() {
+		err = fmt.Errorf("%s: range operations are not permitted with prefix-scoped permissions (use a list of objects)",
+			bck)
+		p.writeErr(w, r, err, http.StatusForbidden)
+		return err
+	}
+	for _, objName := range lrMsg.ObjNames {
+		if err = token.CheckObjPermissions(uid, &bck.Bck, namePrefix+objName, ace); err != nil {
+			p.writeErr(w, r, fmt.Errorf("%s/%s: %w", bck, namePrefix+objName, err), http.StatusForbidden)
+			return err
+		}
+	}
+	return nil
+}
+
+// bypassing object retention in governance mode requires admin access
+func (p *proxyrunner) checkBypassGovernance(w http.ResponseWriter, r *http.Request, bck *cluster.Bck) error {
+	if !cos.IsParseBool(r.URL.Query().Get(cmn.URLParamBypassGovernance)) &&
+		!s3compat.BypassGovernance(r.Header) {
+		return nil
+	}
+	return p.checkACL(w, r, bck, cmn.AceAdmin)
+}
+
+// S3 API: authenticate the request signed with (AWS signature V4) AuthN-issued S3 key.
+// The request then carries the token of the key's owner - to check permissions
+// as usual and, when forwarded, to be authenticated by the primary.
+// Requests that are not signed must have the token.
+func (p *proxyrunner) s3Auth(r *http.Request) error {
+	cfg := cmn.GCO.Get()
+	if !cfg.Auth.Enabled || p.isIntraCall(r.Header) {
+		return nil
+	}
+	if isPresigned(r.URL.Query()) {
+		return nil // (see checkS3ACL)
+	}
+	sig, err := s3compat.ParseSigV4(r)
+	if err != nil {
+		return err
+	}
+	if sig == nil {
+		_, err = p.validateToken(r.Header)
+		return err
+	}
+	token, err := p.authn.s3Token(sig.AccessKey)
+	if err != nil {
+		return err
+	}
+	if err := sig.Verify(r, authn.S3SecretKey(cfg.Auth.Secret, sig.AccessKey), time.Now()); err != nil {
+		return err
+	}
+	// the payload, if any, gets verified while being read (here or, when redirected, by the target)
+	s3compat.VerifyPayload(r)
+	r.Header.Set(cmn.HdrAuthorization, cmn.AuthenticationTypeBearer+" "+token)
+	return nil
+}
+
+// S3 API: same as checkObjACL but, without AuthN, only the bucket's access
+// attributes are checked
+func (p *proxyrunner) checkS3ACL(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, objName string,
+	ace cmn.AccessAttrs) error {
+	if objName != "" && isPresigned(r.URL.Query()) {
+		err := p.checkPresigned(r, bck, objName, ace)
+		if err != nil {
+			p.writeErr(w, r, err, p.aclErrToCode(err))
+		}
+		return err
+	}
+	if cmn.GCO.Get().Auth.Enabled {
+		return p.checkObjACL(w, r, bck, objName, ace)
+	}
+	if bck == nil {
+		return nil
+	}
+	err := bck.Allow(ace)
+	if err != nil {
+		p.writeErr(w, r, err, http.StatusForbidden)
+	}
+	return err
+}
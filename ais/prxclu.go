@@ -94,6 +94,9 @@ func (p *proxyrunner) httpcluget(w http.ResponseWriter, r *http.Request) {
 			p.writeErr(w, r, err)
 			return
 		}
+		if config != nil {
+			config.Backend = config.Backend.Redacted()
+		}
 		p.writeJSON(w, r, config, what)
 	case cmn.GetWhatBMD, cmn.GetWhatSmapVote, cmn.GetWhatSnode, cmn.GetWhatSmap:
 		p.httprunner.httpdaeget(w, r)
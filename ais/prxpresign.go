@@ -0,0 +1,147 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+)
+
+// Presigned URLs:
+// - a user that has access to the object mints (cmn.ActPresignObject) a URL that grants
+//   the same access - to a given object, via a given HTTP method - to anyone, without a token,
+//   until the URL expires;
+// - the URL is signed (HMAC-SHA256) with the cluster's AuthN secret and is validated by the
+//   proxy (instead of the token) prior to redirecting the request to the target;
+// - the signature covers the method, the object, and the entire query string, so that
+//   no query parameters (e.g., version, archpath, or ETL) can be added to the URL;
+// - native API and S3 API URLs share the signature (see cmn.URLParamSignature).
+
+const (
+	dfltPresignExpires = time.Hour
+	maxPresignExpires  = 7 * 24 * time.Hour
+)
+
+var (
+	errPresignExpired = errors.New("presigned URL has expired")
+	errPresignInvalid = errors.New("invalid presigned URL")
+)
+
+func presignAce(method string) cmn.AccessAttrs {
+	switch method {
+	case http.MethodGet:
+		return cmn.AceGET
+	case http.MethodHead:
+		return cmn.AceObjHEAD
+	case http.MethodPut:
+		return cmn.AcePUT
+	default:
+		return 0
+	}
+}
+
+func isPresigned(query url.Values) bool { return query.Has(cmn.URLParamSignature) }
+
+// signs the query parameters (except the signature itself) in their canonical (sorted) form
+func presignSig(method string, bck *cluster.Bck, objName string, query url.Values) string {
+	q := make(url.Values, len(query))
+	for k, v := range query {
+		if k != cmn.URLParamSignature {
+			q[k] = v
+		}
+	}
+	mac := hmac.New(sha256.New, []byte(cmn.GCO.Get().Auth.Secret))
+	mac.Write([]byte(method + "\n" + bck.MakeUname(objName) + "\n" + q.Encode()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// POST {action: presign-obj} /v1/objects/bucket-name/object-name
+func (p *proxyrunner) presignObj(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, objName string,
+	msg *cmn.ActionMsg) {
+	args := cmn.ActValPresign{}
+	if err := cos.MorphMarshal(msg.Value, &args); err != nil {
+		p.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, p.si, msg.Action, msg.Value, err)
+		return
+	}
+	if args.Method == "" {
+		args.Method = http.MethodGet
+	}
+	args.Method = strings.ToUpper(args.Method)
+	ace := presignAce(args.Method)
+	if ace == 0 {
+		p.writeErrf(w, r, "%s: unsupported method %q (expecting GET, HEAD, or PUT)", msg.Action, args.Method)
+		return
+	}
+	expires := args.Expires.D()
+	if expires == 0 {
+		expires = dfltPresignExpires
+	}
+	if expires < 0 || expires > maxPresignExpires {
+		p.writeErrf(w, r, "%s: invalid expiration %v (expecting up to %v)", msg.Action, expires, maxPresignExpires)
+		return
+	}
+	if args.S3 && (!bck.IsAIS() || !bck.Ns.IsGlobal()) {
+		p.writeErrf(w, r, "%s: S3 API supports only ais buckets in the global namespace (%s)", msg.Action, bck)
+		return
+	}
+	if err := p.checkObjACL(w, r, bck, objName, ace); err != nil {
+		return
+	}
+	var (
+		exp = time.Now().Add(expires).Unix()
+		u   = &url.URL{}
+		q   url.Values
+	)
+	if args.S3 {
+		u.Path = cmn.URLPathS3.Join(bck.Name, objName)
+		q = url.Values{}
+	} else {
+		u.Path = cmn.URLPathObjects.Join(bck.Name, objName)
+		q = cmn.AddBckToQuery(nil, bck.Bck)
+	}
+	q.Set(cmn.URLParamExpires, strconv.FormatInt(exp, 10))
+	q.Set(cmn.URLParamSignature, presignSig(args.Method, bck, objName, q))
+	u.RawQuery = q.Encode()
+	w.Write([]byte(u.String()))
+}
+
+// checkPresigned validates presigned URL - in place of the token
+func (p *proxyrunner) checkPresigned(r *http.Request, bck *cluster.Bck, objName string, ace cmn.AccessAttrs) error {
+	query := r.URL.Query()
+	exp, err := strconv.ParseInt(query.Get(cmn.URLParamExpires), 10, 64)
+	if err != nil || objName == "" || ace != presignAce(r.Method) {
+		return errPresignInvalid
+	}
+	sig := presignSig(r.Method, bck, objName, query)
+	if !hmac.Equal([]byte(sig), []byte(query.Get(cmn.URLParamSignature))) {
+		return errPresignInvalid
+	}
+	if time.Now().Unix() > exp {
+		return errPresignExpired
+	}
+	if !cmn.GCO.Get().Auth.Enabled {
+		// same as token-based access (see _checkACL)
+		ace &^= cmn.AccessRO
+	}
+	if ace == 0 {
+		return nil
+	}
+	if err := bck.Allow(ace); err != nil {
+		return fmt.Errorf("%v (presigned URL)", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestPresignCheck(t *testing.T) {
+	var (
+		p   = &proxyrunner{}
+		bck = cluster.NewBck("bck", cmn.ProviderAIS, cmn.NsGlobal, &cmn.BucketProps{Access: cmn.AccessRW})
+		exp = time.Now().Add(time.Minute).Unix()
+	)
+	presigned := func(method, objName string, exp int64) *http.Request {
+		q := url.Values{}
+		q.Set(cmn.URLParamExpires, strconv.FormatInt(exp, 10))
+		q.Set(cmn.URLParamSignature, presignSig(method, bck, objName, q))
+		u := &url.URL{Path: cmn.URLPathObjects.Join(bck.Name, objName), RawQuery: q.Encode()}
+		return &http.Request{Method: method, URL: u}
+	}
+
+	r := presigned(http.MethodGet, "obj", exp)
+	tassert.CheckError(t, p.checkPresigned(r, bck, "obj", cmn.AceGET))
+
+	// another object, method, or permission
+	err := p.checkPresigned(r, bck, "obj2", cmn.AceGET)
+	tassert.Errorf(t, err == errPresignInvalid, "expected invalid (object), got %v", err)
+	r.Method = http.MethodPut
+	err = p.checkPresigned(r, bck, "obj", cmn.AcePUT)
+	tassert.Errorf(t, err == errPresignInvalid, "expected invalid (method), got %v", err)
+	r = presigned(http.MethodPut, "obj", exp)
+	tassert.CheckError(t, p.checkPresigned(r, bck, "obj", cmn.AcePUT))
+	err = p.checkPresigned(r, bck, "obj", cmn.AceAPPEND)
+	tassert.Errorf(t, err == errPresignInvalid, "expected invalid (append), got %v", err)
+
+	// added or modified query parameters
+	r = presigned(http.MethodGet, "obj", exp)
+	r.URL.RawQuery += "&" + cmn.URLParamArchpath + "=file.txt"
+	err = p.checkPresigned(r, bck, "obj", cmn.AceGET)
+	tassert.Errorf(t, err == errPresignInvalid, "expected invalid (added parameter), got %v", err)
+	q := presigned(http.MethodGet, "obj", exp).URL.Query()
+	q.Set(cmn.URLParamExpires, strconv.FormatInt(exp+3600, 10))
+	r.URL.RawQuery = q.Encode()
+	err = p.checkPresigned(r, bck, "obj", cmn.AceGET)
+	tassert.Errorf(t, err == errPresignInvalid, "expected invalid (modified expiration), got %v", err)
+
+	// expired
+	r = presigned(http.MethodGet, "obj", time.Now().Add(-time.Second).Unix())
+	err = p.checkPresigned(r, bck, "obj", cmn.AceGET)
+	tassert.Errorf(t, err == errPresignExpired, "expected expired, got %v", err)
+
+	// the bucket's access attributes still apply
+	bck.Props.Access = cmn.AccessRO
+	r = presigned(http.MethodPut, "obj", exp)
+	err = p.checkPresigned(r, bck, "obj", cmn.AcePUT)
+	tassert.Errorf(t, err != nil, "expected PUT to be forbidden by the bucket's access attributes")
+}
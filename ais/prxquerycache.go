@@ -205,19 +205,20 @@ func (b *queryBuffer) get(token string, size uint) (entries []*cmn.BucketEntry,
 	})
 	entries = b.currentBuff[idx:]
 
-	if size > uint(len(entries)) {
+	// NOTE: noncurrent versions (cmn.LsVersions) do not count
+	n, full := cmn.PageLen(entries, size)
+	if !full {
 		// In case we don't have enough entries and we haven't filled anything then
 		// we must request more (if filled then we don't have enough because it's end).
 		if !filled {
 			return nil, false
 		}
-		size = uint(len(entries))
 	}
 
 	// Move buffer after returned entries.
-	b.currentBuff = entries[size:]
+	b.currentBuff = entries[n:]
 	// Select only the entries that need to be returned to user.
-	entries = entries[:size]
+	entries = entries[:n]
 	if len(entries) > 0 {
 		b.nextToken = entries[len(entries)-1].Name
 	}
@@ -228,9 +229,10 @@ func (b *queryBuffer) set(id string, entries []*cmn.BucketEntry, size uint) {
 	if b.leftovers == nil {
 		b.leftovers = make(map[string]*queryBufferTarget, 5)
 	}
+	_, full := cmn.PageLen(entries, size)
 	b.leftovers[id] = &queryBufferTarget{
 		entries: entries,
-		done:    uint(len(entries)) < size,
+		done:    !full,
 	}
 	b.lastAccess.Store(mono.NanoTime())
 }
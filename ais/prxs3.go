@@ -34,6 +34,10 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 		glog.Infof("S3Request: %s - %s", r.Method, r.URL)
 	}
 
+	if err := p.s3Auth(r); err != nil {
+		p.writeErr(w, r, err, p.aclErrToCode(err))
+		return
+	}
 	// TODO: Fix the hack, https://github.com/tensorflow/tensorflow/issues/41798
 	cos.ReparseQuery(r)
 	apiItems, err := p.checkRESTItems(w, r, 0, true, cmn.URLPathS3.L)
@@ -55,15 +59,14 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		if len(apiItems) == 0 {
 			// nothing  - list all the buckets
-			p.bckNamesToS3(w)
+			p.bckNamesToS3(w, r)
 			return
 		}
 		q := r.URL.Query()
-		_, lifecycle := q[s3compat.URLParamLifecycle]
 		_, policy := q[s3compat.URLParamPolicy]
 		_, cors := q[s3compat.URLParamCORS]
 		_, acl := q[s3compat.URLParamACL]
-		if lifecycle || policy || cors || acl {
+		if policy || cors || acl {
 			p.unsupported(w, r, apiItems[0])
 			return
 		}
@@ -73,10 +76,23 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 				p.getBckVersioningS3(w, r, apiItems[0])
 				return
 			}
+			if q.Has(s3compat.URLParamLifecycle) {
+				p.getBckLifecycleS3(w, r, apiItems[0])
+				return
+			}
+			if q.Has(s3compat.URLParamVersions) {
+				p.listObjVersionsS3(w, r, apiItems[0])
+				return
+			}
 			// only bucket name - list objects in the bucket
 			p.bckListS3(w, r, apiItems[0])
 			return
 		}
+		if q.Has(s3compat.URLParamUploadID) {
+			// list parts of a multipart upload
+			p.mptS3(w, r, apiItems)
+			return
+		}
 		// object data otherwise
 		p.getObjS3(w, r, apiItems)
 	case http.MethodPut:
@@ -91,16 +107,25 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 				p.putBckVersioningS3(w, r, apiItems[0])
 				return
 			}
+			if q.Has(s3compat.URLParamLifecycle) {
+				p.putBckLifecycleS3(w, r, apiItems[0])
+				return
+			}
 			p.putBckS3(w, r, apiItems[0])
 			return
 		}
 		p.putObjS3(w, r, apiItems)
 	case http.MethodPost:
+		q := r.URL.Query()
+		if len(apiItems) > 1 && (q.Has(s3compat.URLParamUploads) || q.Has(s3compat.URLParamUploadID)) {
+			// start or complete multipart upload
+			p.mptS3(w, r, apiItems)
+			return
+		}
 		if len(apiItems) != 1 {
 			p.writeErr(w, r, errS3Req)
 			return
 		}
-		q := r.URL.Query()
 		if _, multiple := q[s3compat.URLParamMultiDelete]; !multiple {
 			p.writeErr(w, r, errS3Req)
 			return
@@ -118,9 +143,18 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 				p.delMultipleObjs(w, r, apiItems[0])
 				return
 			}
+			if q.Has(s3compat.URLParamLifecycle) {
+				p.delBckLifecycleS3(w, r, apiItems[0])
+				return
+			}
 			p.delBckS3(w, r, apiItems[0])
 			return
 		}
+		if r.URL.Query().Has(s3compat.URLParamUploadID) {
+			// abort multipart upload
+			p.mptS3(w, r, apiItems)
+			return
+		}
 		p.delObjS3(w, r, apiItems)
 	default:
 		cmn.WriteErr405(w, r, http.MethodDelete, http.MethodGet, http.MethodHead,
@@ -129,7 +163,10 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 }
 
 // GET s3/
-func (p *proxyrunner) bckNamesToS3(w http.ResponseWriter) {
+func (p *proxyrunner) bckNamesToS3(w http.ResponseWriter, r *http.Request) {
+	if err := p.checkACL(w, r, nil, cmn.AceListBuckets); err != nil {
+		return
+	}
 	var (
 		bmd   = p.owner.bmd.get()
 		query = cmn.QueryBcks{Provider: cmn.ProviderAIS}
@@ -156,6 +193,9 @@ func (p *proxyrunner) putBckS3(w http.ResponseWriter, r *http.Request, bucket st
 		p.writeErr(w, r, err)
 		return
 	}
+	if err := p.checkACL(w, r, nil, cmn.AceCreateBucket); err != nil {
+		return
+	}
 	msg := cmn.ActionMsg{Action: cmn.ActCreateBck}
 	if p.forwardCP(w, r, nil, msg.Action+"-"+bucket) {
 		return
@@ -178,8 +218,7 @@ func (p *proxyrunner) delBckS3(w http.ResponseWriter, r *http.Request, bucket st
 		p.writeErr(w, r, err, http.StatusNotFound)
 		return
 	}
-	if err := bck.Allow(cmn.AceDestroyBucket); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err := p.checkS3ACL(w, r, bck, "", cmn.AceDestroyBucket); err != nil {
 		return
 	}
 	if p.forwardCP(w, r, nil, msg.Action+"-"+bucket) {
@@ -204,8 +243,7 @@ func (p *proxyrunner) delMultipleObjs(w http.ResponseWriter, r *http.Request, bu
 		p.writeErr(w, r, err, http.StatusNotFound)
 		return
 	}
-	if err := bck.Allow(cmn.AceObjDELETE); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err := p.checkS3ACL(w, r, bck, "", cmn.AceObjDELETE); err != nil {
 		return
 	}
 	decoder := xml.NewDecoder(r.Body)
@@ -217,6 +255,11 @@ func (p *proxyrunner) delMultipleObjs(w http.ResponseWriter, r *http.Request, bu
 	if len(objList.Object) == 0 {
 		return
 	}
+	for _, obj := range objList.Object {
+		if err := p.checkS3ACL(w, r, bck, obj.Key, cmn.AceObjDELETE); err != nil {
+			return
+		}
+	}
 	msg := cmn.ActionMsg{Action: cmn.ActDeleteObjects}
 	query := make(url.Values)
 	query.Set(cmn.URLParamProvider, cmn.ProviderAIS)
@@ -248,8 +291,7 @@ func (p *proxyrunner) headBckS3(w http.ResponseWriter, r *http.Request, bucket s
 		p.writeErr(w, r, err, http.StatusNotFound)
 		return
 	}
-	if err := bck.Allow(cmn.AceBckHEAD); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err := p.checkS3ACL(w, r, bck, "", cmn.AceBckHEAD); err != nil {
 		return
 	}
 	// From AWS docs:
@@ -271,6 +313,9 @@ func (p *proxyrunner) bckListS3(w http.ResponseWriter, r *http.Request, bucket s
 		p.writeErr(w, r, err)
 		return
 	}
+	if err := p.checkACL(w, r, bck, cmn.AceObjLIST); err != nil {
+		return
+	}
 	lsmsg := cmn.ListObjsMsg{UUID: cos.GenUUID(), TimeFormat: time.RFC3339}
 	lsmsg.AddProps(cmn.GetPropsSize, cmn.GetPropsChecksum, cmn.GetPropsAtime, cmn.GetPropsVersion)
 	s3compat.FillMsgFromS3Query(r.URL.Query(), &lsmsg)
@@ -285,6 +330,9 @@ func (p *proxyrunner) bckListS3(w http.ResponseWriter, r *http.Request, bucket s
 	} else {
 		objList, err = p.listObjectsRemote(bck, lsmsg)
 	}
+	if err == nil {
+		objList, err = p.filterListACL(r.Header, bck, objList)
+	}
 	if err != nil {
 		p.writeErr(w, r, err)
 		return
@@ -300,6 +348,40 @@ func (p *proxyrunner) bckListS3(w http.ResponseWriter, r *http.Request, bucket s
 	sgl.Free()
 }
 
+// GET s3/bckName?versions
+func (p *proxyrunner) listObjVersionsS3(w http.ResponseWriter, r *http.Request, bucket string) {
+	bck := cluster.NewBck(bucket, cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd); err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	if !bck.Props.Versioning.Retain {
+		p.writeErrf(w, r, "bucket %s does not retain object versions (see versioning.retain)", bck)
+		return
+	}
+	if err := p.checkS3ACL(w, r, bck, "", cmn.AceObjLIST); err != nil {
+		return
+	}
+	lsmsg := cmn.ListObjsMsg{UUID: cos.GenUUID(), TimeFormat: time.RFC3339}
+	lsmsg.AddProps(cmn.GetPropsSize, cmn.GetPropsChecksum, cmn.GetPropsAtime, cmn.GetPropsVersion)
+	s3compat.FillMsgFromS3VersionsQuery(r.URL.Query(), &lsmsg)
+	objList, err := p.listObjectsAIS(bck, lsmsg)
+	if err == nil {
+		objList, err = p.filterListACL(r.Header, bck, objList)
+	}
+	if err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	resp := s3compat.NewListVersionsResult(bucket, &lsmsg)
+	resp.FillFromAisBckList(objList, &lsmsg)
+	sgl := memsys.PageMM().NewSGL(0)
+	resp.MustMarshal(sgl)
+	w.Header().Set(cmn.HdrContentType, cmn.ContentXML)
+	sgl.WriteTo(w)
+	sgl.Free()
+}
+
 // PUT s3/bckName/objName - with HeaderObjSrc in request header - a source
 func (p *proxyrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items []string) {
 	started := time.Now()
@@ -315,8 +397,8 @@ func (p *proxyrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items []
 		p.writeErr(w, r, err)
 		return
 	}
-	if err := bckSrc.Allow(cmn.AceGET); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	objName := strings.Trim(parts[1], "/")
+	if err := p.checkS3ACL(w, r, bckSrc, objName, cmn.AceGET); err != nil {
 		return
 	}
 	bckDst := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
@@ -333,11 +415,9 @@ func (p *proxyrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items []
 		smap = p.owner.smap.get()
 		err  error
 	)
-	if err = bckDst.Allow(cmn.AcePUT); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err = p.checkS3ACL(w, r, bckDst, path.Join(items[1:]...), cmn.AcePUT); err != nil {
 		return
 	}
-	objName := strings.Trim(parts[1], "/")
 	si, err = cluster.HrwTarget(bckSrc.MakeUname(objName), &smap.Smap)
 	if err != nil {
 		p.writeErr(w, r, err)
@@ -367,11 +447,10 @@ func (p *proxyrunner) directPutObjS3(w http.ResponseWriter, r *http.Request, ite
 		smap = p.owner.smap.get()
 		err  error
 	)
-	if err = bck.Allow(cmn.AcePUT); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	objName := path.Join(items[1:]...)
+	if err = p.checkS3ACL(w, r, bck, objName, cmn.AcePUT); err != nil {
 		return
 	}
-	objName := path.Join(items[1:]...)
 	si, err = cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
 		p.writeErr(w, r, err)
@@ -410,11 +489,10 @@ func (p *proxyrunner) getObjS3(w http.ResponseWriter, r *http.Request, items []s
 		smap = p.owner.smap.get()
 		err  error
 	)
-	if err = bck.Allow(cmn.AceGET); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	objName := path.Join(items[1:]...)
+	if err = p.checkS3ACL(w, r, bck, objName, cmn.AceGET); err != nil {
 		return
 	}
-	objName := path.Join(items[1:]...)
 
 	si, err = cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -439,8 +517,7 @@ func (p *proxyrunner) headObjS3(w http.ResponseWriter, r *http.Request, items []
 		p.writeErr(w, r, err)
 		return
 	}
-	if err := bck.Allow(cmn.AceObjHEAD); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err := p.checkS3ACL(w, r, bck, objName, cmn.AceObjHEAD); err != nil {
 		return
 	}
 	smap := p.owner.smap.get()
@@ -473,11 +550,15 @@ func (p *proxyrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []s
 		smap = p.owner.smap.get()
 		err  error
 	)
-	if err = bck.Allow(cmn.AceObjDELETE); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	objName := path.Join(items[1:]...)
+	if err = p.checkS3ACL(w, r, bck, objName, cmn.AceObjDELETE); err != nil {
 		return
 	}
-	objName := path.Join(items[1:]...)
+	if s3compat.BypassGovernance(r.Header) {
+		if err = p.checkS3ACL(w, r, bck, "", cmn.AceAdmin); err != nil {
+			return
+		}
+	}
 	si, err = cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
 		p.writeErr(w, r, err)
@@ -490,6 +571,44 @@ func (p *proxyrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []s
 	p.s3Redirect(w, r, si, redirectURL, bck.Name)
 }
 
+// POST s3/bckName/objName?uploads
+// PUT s3/bckName/objName?partNumber=<n>&uploadId=<id> (see directPutObjS3)
+// POST|GET|DELETE s3/bckName/objName?uploadId=<id>
+// Multipart upload: all requests are redirected to the target that owns the object
+func (p *proxyrunner) mptS3(w http.ResponseWriter, r *http.Request, items []string) {
+	started := time.Now()
+	bck := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd); err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	var (
+		smap    = p.owner.smap.get()
+		objName = path.Join(items[1:]...)
+	)
+	// list parts (GET) is a read, abort (DELETE) - a delete; start and complete (POST) - a write
+	perms := cmn.AcePUT
+	switch r.Method {
+	case http.MethodGet:
+		perms = cmn.AceGET
+	case http.MethodDelete:
+		perms = cmn.AceObjDELETE
+	}
+	if err := p.checkS3ACL(w, r, bck, objName, perms); err != nil {
+		return
+	}
+	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
+	if err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("AISS3 multipart: %s %s/%s => %s", r.Method, bck, objName, si)
+	}
+	redirectURL := p.redirectURL(r, si, started, cmn.NetworkIntraData)
+	p.s3Redirect(w, r, si, redirectURL, bck.Name)
+}
+
 // GET s3/bk-name?versioning
 func (p *proxyrunner) getBckVersioningS3(w http.ResponseWriter, r *http.Request, bucket string) {
 	bck := cluster.NewBck(bucket, cmn.ProviderAIS, cmn.NsGlobal)
@@ -497,6 +616,9 @@ func (p *proxyrunner) getBckVersioningS3(w http.ResponseWriter, r *http.Request,
 		p.writeErr(w, r, err)
 		return
 	}
+	if err := p.checkACL(w, r, bck, cmn.AceBckHEAD); err != nil {
+		return
+	}
 	resp := s3compat.NewVersioningConfiguration(bck.Props.Versioning.Enabled)
 	sgl := memsys.PageMM().NewSGL(0)
 	resp.MustMarshal(sgl)
@@ -505,7 +627,7 @@ func (p *proxyrunner) getBckVersioningS3(w http.ResponseWriter, r *http.Request,
 	sgl.Free()
 }
 
-// GET s3/bk-name?lifecycle|cors|policy|acl
+// GET s3/bk-name?cors|policy|acl
 func (p *proxyrunner) unsupported(w http.ResponseWriter, r *http.Request, bucket string) {
 	bck := cluster.NewBck(bucket, cmn.ProviderAIS, cmn.NsGlobal)
 	if err := bck.Init(p.owner.bmd); err != nil {
@@ -531,6 +653,9 @@ func (p *proxyrunner) putBckVersioningS3(w http.ResponseWriter, r *http.Request,
 		p.writeErrMsg(w, r, "bucket does not exist", http.StatusNotFound)
 		return
 	}
+	if err := p.checkACL(w, r, bck, cmn.AcePATCH); err != nil {
+		return
+	}
 	decoder := xml.NewDecoder(r.Body)
 	defer cos.Close(r.Body)
 	vconf := &s3compat.VersioningConfiguration{}
@@ -552,3 +677,83 @@ func (p *proxyrunner) putBckVersioningS3(w http.ResponseWriter, r *http.Request,
 		p.writeErr(w, r, err)
 	}
 }
+
+// GET s3/bk-name?lifecycle
+func (p *proxyrunner) getBckLifecycleS3(w http.ResponseWriter, r *http.Request, bucket string) {
+	bck := cluster.NewBck(bucket, cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd); err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	if err := p.checkACL(w, r, bck, cmn.AceBckHEAD); err != nil {
+		return
+	}
+	if len(bck.Props.Lifecycle.Rules) == 0 {
+		p.writeErr(w, r, s3compat.NewErrNoLifecycle(bucket))
+		return
+	}
+	resp := s3compat.NewLifecycleConfiguration(&bck.Props.Lifecycle)
+	sgl := memsys.PageMM().NewSGL(0)
+	resp.MustMarshal(sgl)
+	w.Header().Set(cmn.HdrContentType, cmn.ContentXML)
+	sgl.WriteTo(w)
+	sgl.Free()
+}
+
+// PUT s3/bk-name?lifecycle
+func (p *proxyrunner) putBckLifecycleS3(w http.ResponseWriter, r *http.Request, bucket string) {
+	msg := &cmn.ActionMsg{Action: cmn.ActSetBprops}
+	if p.forwardCP(w, r, nil, msg.Action+"-"+bucket) {
+		return
+	}
+	lconf := &s3compat.LifecycleConfiguration{}
+	err := xml.NewDecoder(r.Body).Decode(lconf)
+	cos.Close(r.Body)
+	if err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	conf, err := lconf.ToLifecycleConf()
+	if err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	p.setBckLifecycleS3(w, r, msg, bucket, conf)
+}
+
+// DELETE s3/bk-name?lifecycle
+func (p *proxyrunner) delBckLifecycleS3(w http.ResponseWriter, r *http.Request, bucket string) {
+	msg := &cmn.ActionMsg{Action: cmn.ActSetBprops}
+	if p.forwardCP(w, r, nil, msg.Action+"-"+bucket) {
+		return
+	}
+	if p.setBckLifecycleS3(w, r, msg, bucket, &cmn.LifecycleConf{}) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (p *proxyrunner) setBckLifecycleS3(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg, bucket string,
+	conf *cmn.LifecycleConf) bool {
+	bck := cluster.NewBck(bucket, cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd); err != nil {
+		p.writeErr(w, r, err)
+		return false
+	}
+	if err := p.checkACL(w, r, bck, cmn.AcePATCH); err != nil {
+		return false
+	}
+	propsToUpdate := cmn.BucketPropsToUpdate{
+		Lifecycle: &cmn.LifecycleConfToUpdate{Rules: &conf.Rules, Enabled: &conf.Enabled},
+	}
+	// make and validate new props
+	nprops, err := p.makeNewBckProps(bck, &propsToUpdate)
+	if err != nil {
+		p.writeErr(w, r, err)
+		return false
+	}
+	if _, err := p.setBucketProps(msg, bck, nprops); err != nil {
+		p.writeErr(w, r, err)
+		return false
+	}
+	return true
+}
@@ -28,6 +28,7 @@ type bckInitArgs struct {
 
 	origURLBck string
 	bck        *cluster.Bck
+	objName    string // when accessing a given object (prefix-scoped permissions)
 	msg        *cmn.ActionMsg
 
 	skipBackend  bool // initialize bucket via `bck.InitNoBackend`
@@ -124,8 +125,8 @@ func (args *bckInitArgs) _checkRemoteBckPermissions() (err error) {
 		goto retErr
 	}
 
-	// HDFS buckets are allowed to be deleted.
-	if args.bck.IsHDFS() {
+	// HDFS and POSIX buckets are allowed to be deleted.
+	if args.bck.IsHDFS() || args.bck.IsPOSIX() {
 		return
 	}
 
@@ -155,7 +156,11 @@ func (args *bckInitArgs) _requiresPermission(perm cmn.AccessAttrs) bool {
 }
 
 func (args *bckInitArgs) _checkACL(bck *cluster.Bck) (errCode int, err error) {
-	err = args.p._checkACL(args.r.Header, bck, args.perms)
+	if args.objName != "" && isPresigned(args.r.URL.Query()) {
+		err = args.p.checkPresigned(args.r, bck, args.objName, args.perms)
+		return args.p.aclErrToCode(err), err
+	}
+	err = args.p._checkACL(args.r.Header, bck, args.objName, args.perms)
 	return args.p.aclErrToCode(err), err
 }
 
@@ -213,9 +218,9 @@ func (args *bckInitArgs) _try() (bck *cluster.Bck, errCode int, err error) {
 		return
 	}
 
-	// In case of HDFS if the bucket does not exist in BMD there is no point
+	// In case of HDFS and POSIX if the bucket does not exist in BMD there is no point
 	// in checking if it exists remotely if we don't have `ref_directory`.
-	if args.bck.IsHDFS() {
+	if args.bck.IsHDFS() || args.bck.IsPOSIX() {
 		err = cmn.NewErrBckNotFound(args.bck.Bck)
 		errCode = http.StatusNotFound
 		return
@@ -276,6 +276,9 @@ func (p *proxyrunner) setBucketProps(msg *cmn.ActionMsg, bck *cluster.Bck, nprop
 			}
 		}
 		nprops = defaultBckProps(bckPropsArgs{bck: bck, hdr: remoteBckProps})
+		if err = bprops.Retention.ValidateUpdate(&nprops.Retention); err != nil {
+			return
+		}
 	default:
 		cos.Assert(false)
 	}
@@ -1011,6 +1014,19 @@ func (p *proxyrunner) makeNewBckProps(bck *cluster.Bck, propsToUpdate *cmn.Bucke
 	)
 	nprops = bprops.Clone()
 	nprops.Apply(propsToUpdate)
+	if err = bprops.Retention.ValidateUpdate(&nprops.Retention); err != nil {
+		return
+	}
+	if bprops.Extra.AWS.Endpoint != nprops.Extra.AWS.Endpoint {
+		if len(creating) == 0 {
+			err = fmt.Errorf("%s: cannot modify S3 endpoint of an existing bucket %s (%q)",
+				p.si, bck, bprops.Extra.AWS.Endpoint)
+			return
+		}
+		if _, err = cfg.Backend.S3Endpoint(nprops.Extra.AWS.Endpoint); err != nil {
+			return
+		}
+	}
 	if bck.IsCloud() {
 		bv, nv := bck.VersionConf().Enabled, nprops.Versioning.Enabled
 		if bv != nv {
@@ -1022,6 +1038,13 @@ func (p *proxyrunner) makeNewBckProps(bck *cluster.Bck, propsToUpdate *cmn.Bucke
 	} else if bck.IsHDFS() {
 		nprops.Versioning.Enabled = false
 		// TODO: Check if the `RefDirectory` does not overlap with other buckets.
+	} else if bck.IsPOSIX() {
+		nprops.Versioning.Enabled = false
+		if len(creating) == 0 && bprops.Extra.POSIX.RefDirectory != nprops.Extra.POSIX.RefDirectory {
+			err = fmt.Errorf("%s: cannot modify reference directory of an existing bucket %s (%q)",
+				p.si, bck, bprops.Extra.POSIX.RefDirectory)
+			return
+		}
 	}
 	if bprops.EC.Enabled && nprops.EC.Enabled {
 		sameSlices := bprops.EC.DataSlices == nprops.EC.DataSlices && bprops.EC.ParitySlices == nprops.EC.ParitySlices
@@ -16,6 +16,18 @@ const (
 	URLParamACL         = "acl"
 	URLParamMultiDelete = "delete"
 
+	// object versions
+	URLParamVersions  = "versions"
+	URLParamVersionID = "versionId"
+	nullVersionID     = "null"
+
+	// multipart upload
+	URLParamUploads          = "uploads"
+	URLParamUploadID         = "uploadId"
+	URLParamPartNum          = "partNumber"
+	URLParamMaxParts         = "max-parts"
+	URLParamPartNumberMarker = "part-number-marker"
+
 	versioningEnabled  = "Enabled"
 	versioningDisabled = "Suspended"
 
@@ -24,4 +36,7 @@ const (
 	// Headers
 	headerETag   = "ETag"
 	HeaderObjSrc = "x-amz-copy-source"
+
+	HeaderVersionID    = "x-amz-version-id"
+	HeaderDeleteMarker = "x-amz-delete-marker"
 )
@@ -0,0 +1,145 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// S3 bucket lifecycle configuration is mapped onto AIS bucket property `lifecycle`:
+//   - Expiration/Days                           => expire_days
+//   - NoncurrentVersionExpiration/NoncurrentDays => noncurrent_days
+//   - Transition/Days (any storage class)        => evict_days (i.e., the object remains
+//     in the remote backend only)
+// Tag-based filters and date-based actions are not supported.
+
+const (
+	lifecycleEnabled  = "Enabled"
+	lifecycleDisabled = "Disabled"
+)
+
+type (
+	LifecycleConfiguration struct {
+		XMLName xml.Name         `xml:"LifecycleConfiguration"`
+		Ns      string           `xml:"xmlns,attr,omitempty"`
+		Rules   []*LifecycleRule `xml:"Rule"`
+	}
+	LifecycleRule struct {
+		ID                          string                       `xml:"ID,omitempty"`
+		Prefix                      *string                      `xml:"Prefix,omitempty"` // deprecated (V1) form
+		Filter                      *LifecycleFilter             `xml:"Filter,omitempty"`
+		Status                      string                       `xml:"Status"`
+		Expiration                  *LifecycleExpiration         `xml:"Expiration,omitempty"`
+		Transition                  *LifecycleTransition         `xml:"Transition,omitempty"`
+		NoncurrentVersionExpiration *NoncurrentVersionExpiration `xml:"NoncurrentVersionExpiration,omitempty"`
+	}
+	LifecycleFilter struct {
+		Prefix string    `xml:"Prefix"`
+		Tag    *struct{} `xml:"Tag,omitempty"`
+		And    *struct{} `xml:"And,omitempty"`
+	}
+	LifecycleExpiration struct {
+		Days int64  `xml:"Days,omitempty"`
+		Date string `xml:"Date,omitempty"`
+	}
+	LifecycleTransition struct {
+		Days         int64  `xml:"Days,omitempty"`
+		Date         string `xml:"Date,omitempty"`
+		StorageClass string `xml:"StorageClass,omitempty"`
+	}
+	NoncurrentVersionExpiration struct {
+		NoncurrentDays int64 `xml:"NoncurrentDays"`
+	}
+)
+
+func NewLifecycleConfiguration(conf *cmn.LifecycleConf) *LifecycleConfiguration {
+	res := &LifecycleConfiguration{Ns: s3Namespace, Rules: make([]*LifecycleRule, 0, len(conf.Rules))}
+	for i := range conf.Rules {
+		rule := &conf.Rules[i]
+		s3rule := &LifecycleRule{
+			ID:     rule.ID,
+			Filter: &LifecycleFilter{Prefix: rule.Prefix},
+			Status: lifecycleEnabled,
+		}
+		if rule.Disabled || !conf.Enabled {
+			s3rule.Status = lifecycleDisabled
+		}
+		if rule.ExpireDays > 0 {
+			s3rule.Expiration = &LifecycleExpiration{Days: rule.ExpireDays}
+		}
+		if rule.EvictDays > 0 {
+			s3rule.Transition = &LifecycleTransition{Days: rule.EvictDays}
+		}
+		if rule.NoncurrentDays > 0 {
+			s3rule.NoncurrentVersionExpiration = &NoncurrentVersionExpiration{NoncurrentDays: rule.NoncurrentDays}
+		}
+		res.Rules = append(res.Rules, s3rule)
+	}
+	return res
+}
+
+// ToLifecycleConf converts S3 lifecycle configuration to AIS bucket property.
+func (r *LifecycleConfiguration) ToLifecycleConf() (*cmn.LifecycleConf, error) {
+	conf := &cmn.LifecycleConf{Rules: make([]cmn.LifecycleRule, 0, len(r.Rules))}
+	for _, s3rule := range r.Rules {
+		rule := cmn.LifecycleRule{ID: s3rule.ID}
+		switch s3rule.Status {
+		case lifecycleEnabled:
+		case lifecycleDisabled:
+			rule.Disabled = true
+		default:
+			return nil, fmt.Errorf("lifecycle rule %q: invalid status %q", s3rule.ID, s3rule.Status)
+		}
+		if s3rule.Filter != nil {
+			if s3rule.Filter.Tag != nil || s3rule.Filter.And != nil {
+				return nil, fmt.Errorf("lifecycle rule %q: tag-based filters are not supported", s3rule.ID)
+			}
+			rule.Prefix = s3rule.Filter.Prefix
+		} else if s3rule.Prefix != nil {
+			rule.Prefix = *s3rule.Prefix
+		}
+		if exp := s3rule.Expiration; exp != nil {
+			if exp.Date != "" {
+				return nil, errDateNotSupported(s3rule.ID)
+			}
+			rule.ExpireDays = exp.Days
+		}
+		if tr := s3rule.Transition; tr != nil {
+			if tr.Date != "" {
+				return nil, errDateNotSupported(s3rule.ID)
+			}
+			rule.EvictDays = tr.Days
+		}
+		if nc := s3rule.NoncurrentVersionExpiration; nc != nil {
+			rule.NoncurrentDays = nc.NoncurrentDays
+		}
+		conf.Rules = append(conf.Rules, rule)
+	}
+	if len(conf.Rules) == 0 {
+		return nil, errors.New("lifecycle configuration must contain at least one rule")
+	}
+	conf.Enabled = true
+	return conf, nil
+}
+
+func (r *LifecycleConfiguration) MustMarshal(sgl *memsys.SGL) {
+	sgl.Write([]byte(xml.Header))
+	err := xml.NewEncoder(sgl).Encode(r)
+	cos.AssertNoErr(err)
+}
+
+func NewErrNoLifecycle(bucket string) error {
+	return cmn.NewErrNotFound("lifecycle configuration of bucket %q", bucket)
+}
+
+func errDateNotSupported(id string) error {
+	return fmt.Errorf("lifecycle rule %q: date-based actions are not supported", id)
+}
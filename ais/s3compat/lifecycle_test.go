@@ -0,0 +1,57 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestLifecycleConf(t *testing.T) {
+	const body = `<LifecycleConfiguration>
+	<Rule>
+		<ID>tmp</ID>
+		<Filter><Prefix>tmp/</Prefix></Filter>
+		<Status>Enabled</Status>
+		<Expiration><Days>7</Days></Expiration>
+	</Rule>
+	<Rule>
+		<ID>cold</ID>
+		<Prefix>logs/</Prefix>
+		<Status>Disabled</Status>
+		<Transition><Days>30</Days><StorageClass>GLACIER</StorageClass></Transition>
+		<NoncurrentVersionExpiration><NoncurrentDays>3</NoncurrentDays></NoncurrentVersionExpiration>
+	</Rule>
+</LifecycleConfiguration>`
+	lconf := &LifecycleConfiguration{}
+	tassert.CheckFatal(t, xml.Unmarshal([]byte(body), lconf))
+	conf, err := lconf.ToLifecycleConf()
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, conf.Enabled && len(conf.Rules) == 2, "unexpected lifecycle: %+v", conf)
+
+	tmp, cold := conf.Rules[0], conf.Rules[1]
+	tassert.Errorf(t, tmp.Prefix == "tmp/" && tmp.ExpireDays == 7 && !tmp.Disabled, "unexpected rule %s", tmp)
+	tassert.Errorf(t, cold.Prefix == "logs/" && cold.EvictDays == 30 && cold.NoncurrentDays == 3 && cold.Disabled,
+		"unexpected rule %s", cold)
+	tassert.Errorf(t, len(conf.Match("tmp/a")) == 1 && len(conf.Match("logs/a")) == 0,
+		"unexpected match (disabled rules must not match)")
+
+	// and back
+	res := NewLifecycleConfiguration(conf)
+	tassert.Fatalf(t, len(res.Rules) == 2, "expected 2 rules, got %d", len(res.Rules))
+	tassert.Errorf(t, res.Rules[0].Filter.Prefix == "tmp/" && res.Rules[0].Expiration.Days == 7,
+		"unexpected rule %+v", res.Rules[0])
+	tassert.Errorf(t, res.Rules[1].Status == lifecycleDisabled && res.Rules[1].Transition.Days == 30,
+		"unexpected rule %+v", res.Rules[1])
+
+	// not supported
+	lconf.Rules[0].Expiration.Date = "2030-01-01T00:00:00Z"
+	_, err = lconf.ToLifecycleConf()
+	tassert.Errorf(t, err != nil, "expected error on date-based expiration")
+	_, err = (&LifecycleConfiguration{}).ToLifecycleConf()
+	tassert.Errorf(t, err != nil, "expected error on empty configuration")
+}
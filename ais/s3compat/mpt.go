@@ -0,0 +1,377 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/memsys"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// NOTE: multipart uploads are tracked by the target that "owns" the object
+// (HRW-wise). Each upload is bound to its bucket and object, and gets persisted
+// in the target's DB (see LoadUploads) - the uploads, and their parts (workfiles),
+// survive restarts until completed, aborted, or expired.
+//
+// While being completed, the upload is marked as such: its list of parts does not
+// change until the completion succeeds (FinishUpload) or fails (EndComplete).
+
+const (
+	// S3 limits: https://docs.aws.amazon.com/AmazonS3/latest/userguide/qfacts.html
+	MaxPartNum   = 10000
+	maxListParts = 1000
+
+	mptCollection = "s3-mpt"
+)
+
+type (
+	// (internal) part of a multipart upload
+	MptPart struct {
+		MD5  string `json:"md5"`  // MD5 of the part, aka ETag
+		FQN  string `json:"fqn"`  // FQN of the corresponding workfile
+		Size int64  `json:"size"` // part size in bytes
+		Num  int64  `json:"num"`  // part number
+	}
+	mpt struct {
+		BckName    string     `json:"bck"`
+		ObjName    string     `json:"obj"`
+		Parts      []*MptPart `json:"parts"` // sorted by part number
+		Ctime      time.Time  `json:"ctime"` // InitUpload time
+		completing bool       // (not persisted)
+	}
+	uploads struct {
+		sync.RWMutex
+		m  map[string]*mpt // by upload ID
+		db dbdriver.Driver
+	}
+
+	// Initiate multipart upload response
+	InitiateMptUploadResult struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Ns       string   `xml:"xmlns,attr"`
+		Bucket   string   `xml:"Bucket"`
+		Key      string   `xml:"Key"`
+		UploadID string   `xml:"UploadId"`
+	}
+
+	// Complete multipart upload request
+	CompleteMptUpload struct {
+		Parts []*PartInfo `xml:"Part"`
+	}
+	PartInfo struct {
+		ETag       string `xml:"ETag"`
+		PartNumber int64  `xml:"PartNumber"`
+		Size       int64  `xml:"Size,omitempty"`
+	}
+
+	// Complete multipart upload response
+	CompleteMptUploadResult struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Ns      string   `xml:"xmlns,attr"`
+		Bucket  string   `xml:"Bucket"`
+		Key     string   `xml:"Key"`
+		ETag    string   `xml:"ETag"`
+	}
+
+	// List parts response
+	ListPartsResult struct {
+		XMLName              xml.Name    `xml:"ListPartsResult"`
+		Ns                   string      `xml:"xmlns,attr"`
+		Bucket               string      `xml:"Bucket"`
+		Key                  string      `xml:"Key"`
+		UploadID             string      `xml:"UploadId"`
+		PartNumberMarker     int64       `xml:"PartNumberMarker"`
+		NextPartNumberMarker int64       `xml:"NextPartNumberMarker"`
+		MaxParts             int         `xml:"MaxParts"`
+		IsTruncated          bool        `xml:"IsTruncated"`
+		Parts                []*PartInfo `xml:"Part"`
+	}
+)
+
+var (
+	ups = &uploads{m: make(map[string]*mpt, 8)}
+
+	ErrMptCompleting = errors.New("multipart upload is being completed")
+)
+
+/////////////
+// uploads //
+/////////////
+
+// LoadUploads makes the registry persistent and loads the uploads
+// that were in progress prior to the target's restart.
+func LoadUploads(db dbdriver.Driver) (n int, err error) {
+	ups.Lock()
+	defer ups.Unlock()
+	ups.db = db
+	recs, err := db.GetAll(mptCollection, "")
+	if err != nil {
+		return 0, err
+	}
+	for id, val := range recs {
+		up := &mpt{}
+		if err := jsoniter.UnmarshalFromString(val, up); err != nil {
+			return n, fmt.Errorf("invalid multipart upload record %q: %v", id, err)
+		}
+		ups.m[id] = up
+		n++
+	}
+	return
+}
+
+// (under lock)
+func (u *uploads) get(id, bckName, objName string) (*mpt, error) {
+	up, ok := u.m[id]
+	if !ok || up.BckName != bckName || up.ObjName != objName {
+		return nil, NewErrNoSuchUpload(id)
+	}
+	return up, nil
+}
+
+// (under lock)
+func (u *uploads) persist(id string, up *mpt) error {
+	if u.db == nil {
+		return nil
+	}
+	return u.db.Set(mptCollection, id, up)
+}
+
+// (under lock)
+func (u *uploads) del(id string) {
+	delete(u.m, id)
+	if u.db == nil {
+		return
+	}
+	if err := u.db.Delete(mptCollection, id); err != nil && !dbdriver.IsErrNotFound(err) {
+		glog.Errorf("failed to delete multipart upload %q record: %v", id, err)
+	}
+}
+
+// InitUpload registers a new multipart upload.
+func InitUpload(id, bckName, objName string) error {
+	up := &mpt{
+		BckName: bckName,
+		ObjName: objName,
+		Parts:   make([]*MptPart, 0, 8),
+		Ctime:   time.Now(),
+	}
+	ups.Lock()
+	defer ups.Unlock()
+	if err := ups.persist(id, up); err != nil {
+		return err
+	}
+	ups.m[id] = up
+	return nil
+}
+
+// AddPart adds (or replaces) a part of the upload. When replaced, the workfile
+// of the previous part with the same number is returned to be removed.
+func AddPart(id, bckName, objName string, npart *MptPart) (prevFQN string, err error) {
+	ups.Lock()
+	defer ups.Unlock()
+	up, err := ups.get(id, bckName, objName)
+	if err != nil {
+		return "", err
+	}
+	if up.completing {
+		return "", fmt.Errorf("%w (upload %q)", ErrMptCompleting, id)
+	}
+	var (
+		parts = make([]*MptPart, 0, len(up.Parts)+1)
+		idx   = sort.Search(len(up.Parts), func(i int) bool { return up.Parts[i].Num >= npart.Num })
+	)
+	parts = append(parts, up.Parts[:idx]...)
+	parts = append(parts, npart)
+	if idx < len(up.Parts) && up.Parts[idx].Num == npart.Num {
+		prevFQN = up.Parts[idx].FQN
+		idx++
+	}
+	parts = append(parts, up.Parts[idx:]...)
+
+	nup := *up
+	nup.Parts = parts
+	if err = ups.persist(id, &nup); err != nil {
+		return "", err
+	}
+	up.Parts = parts
+	return
+}
+
+// BeginComplete validates the parts listed in the complete-upload request against
+// the uploaded ones and returns the latter in the requested order. The upload gets
+// marked as "completing": no parts can be added (or replaced) and no other completion
+// can start until the caller either finishes the upload (FinishUpload) or fails (EndComplete).
+func BeginComplete(id, bckName, objName string, parts []*PartInfo) (res []*MptPart, err error) {
+	ups.Lock()
+	defer ups.Unlock()
+	up, err := ups.get(id, bckName, objName)
+	if err != nil {
+		return nil, err
+	}
+	if up.completing {
+		return nil, fmt.Errorf("%w (upload %q)", ErrMptCompleting, id)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("upload %q: no parts specified", id)
+	}
+	res = make([]*MptPart, 0, len(parts))
+	for i, part := range parts {
+		if i > 0 && part.PartNumber <= parts[i-1].PartNumber {
+			return nil, fmt.Errorf("upload %q: invalid part order (%d after %d)",
+				id, part.PartNumber, parts[i-1].PartNumber)
+		}
+		idx := sort.Search(len(up.Parts), func(i int) bool { return up.Parts[i].Num >= part.PartNumber })
+		if idx >= len(up.Parts) || up.Parts[idx].Num != part.PartNumber {
+			return nil, fmt.Errorf("upload %q: part %d not found", id, part.PartNumber)
+		}
+		mptPart := up.Parts[idx]
+		if etag := UnquoteETag(part.ETag); etag != "" && etag != mptPart.MD5 {
+			return nil, fmt.Errorf("upload %q: part %d ETag mismatch (%q vs %q)",
+				id, part.PartNumber, etag, mptPart.MD5)
+		}
+		res = append(res, mptPart)
+	}
+	up.completing = true
+	return
+}
+
+// EndComplete clears the "completing" mark when the completion fails.
+func EndComplete(id string) {
+	ups.Lock()
+	if up, ok := ups.m[id]; ok {
+		up.completing = false
+	}
+	ups.Unlock()
+}
+
+// FinishUpload removes the upload from the registry and returns all its parts,
+// including those that were uploaded but not referenced by the completion request.
+// Uploads that are being completed can only be finished by the completion itself.
+func FinishUpload(id, bckName, objName string, completed bool) (parts []*MptPart, err error) {
+	ups.Lock()
+	defer ups.Unlock()
+	up, err := ups.get(id, bckName, objName)
+	if err != nil {
+		return nil, err
+	}
+	if up.completing != completed {
+		return nil, fmt.Errorf("%w (upload %q)", ErrMptCompleting, id)
+	}
+	ups.del(id)
+	return up.Parts, nil
+}
+
+// ListParts returns up to `maxParts` parts of the upload with part numbers
+// greater than `marker`.
+func ListParts(id, bckName, objName string, marker int64, maxParts int) (result *ListPartsResult, err error) {
+	ups.RLock()
+	defer ups.RUnlock()
+	up, err := ups.get(id, bckName, objName)
+	if err != nil {
+		return nil, err
+	}
+	if maxParts <= 0 || maxParts > maxListParts {
+		maxParts = maxListParts
+	}
+	result = &ListPartsResult{
+		Ns:               s3Namespace,
+		Bucket:           up.BckName,
+		Key:              up.ObjName,
+		UploadID:         id,
+		PartNumberMarker: marker,
+		MaxParts:         maxParts,
+		Parts:            make([]*PartInfo, 0, cos.Min(len(up.Parts), maxParts)),
+	}
+	for _, part := range up.Parts {
+		if part.Num <= marker {
+			continue
+		}
+		if len(result.Parts) == maxParts {
+			result.IsTruncated = true
+			break
+		}
+		result.Parts = append(result.Parts, &PartInfo{ETag: part.MD5, PartNumber: part.Num, Size: part.Size})
+		result.NextPartNumberMarker = part.Num
+	}
+	return
+}
+
+// CleanupExpired removes and returns uploads that were initiated more than
+// `maxAge` ago - the caller is expected to remove their workfiles.
+func CleanupExpired(maxAge time.Duration) (parts []*MptPart) {
+	now := time.Now()
+	ups.Lock()
+	for id, up := range ups.m {
+		if now.Sub(up.Ctime) > maxAge && !up.completing {
+			parts = append(parts, up.Parts...)
+			ups.del(id)
+		}
+	}
+	ups.Unlock()
+	return
+}
+
+// ParsePartNum parses and validates the `partNumber` query parameter.
+func ParsePartNum(s string) (int64, error) {
+	partNum, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || partNum < 1 || partNum > MaxPartNum {
+		return 0, fmt.Errorf("invalid part number %q (must be in [1, %d] range)", s, MaxPartNum)
+	}
+	return partNum, nil
+}
+
+// MptETag computes the ETag of a multipart-uploaded object the same way S3 does:
+// MD5 of the concatenated (binary) MD5s of the parts, followed by "-<number of parts>".
+func MptETag(parts []*MptPart) (string, error) {
+	h := md5.New()
+	for _, part := range parts {
+		b, err := hex.DecodeString(part.MD5)
+		if err != nil {
+			return "", fmt.Errorf("part %d: invalid MD5 %q: %v", part.Num, part.MD5, err)
+		}
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil)) + "-" + strconv.Itoa(len(parts)), nil
+}
+
+func UnquoteETag(etag string) string { return strings.Trim(etag, "\"") }
+
+func NewErrNoSuchUpload(id string) error {
+	return cmn.NewErrNotFound("multipart upload %q", id)
+}
+
+func (r *InitiateMptUploadResult) MustMarshal(sgl *memsys.SGL) {
+	r.Ns = s3Namespace
+	sgl.Write([]byte(xml.Header))
+	err := xml.NewEncoder(sgl).Encode(r)
+	cos.AssertNoErr(err)
+}
+
+func (r *CompleteMptUploadResult) MustMarshal(sgl *memsys.SGL) {
+	r.Ns = s3Namespace
+	sgl.Write([]byte(xml.Header))
+	err := xml.NewEncoder(sgl).Encode(r)
+	cos.AssertNoErr(err)
+}
+
+func (r *ListPartsResult) MustMarshal(sgl *memsys.SGL) {
+	sgl.Write([]byte(xml.Header))
+	err := xml.NewEncoder(sgl).Encode(r)
+	cos.AssertNoErr(err)
+}
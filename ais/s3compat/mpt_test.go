@@ -0,0 +1,137 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func md5hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestMptETag(t *testing.T) {
+	var (
+		parts = []*MptPart{{Num: 1, MD5: md5hex("hello")}, {Num: 2, MD5: md5hex("world")}}
+		h     = md5.New()
+	)
+	for _, part := range parts {
+		b, _ := hex.DecodeString(part.MD5)
+		h.Write(b)
+	}
+	expected := hex.EncodeToString(h.Sum(nil)) + "-2"
+	etag, err := MptETag(parts)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, etag == expected, "expected ETag %q, got %q", expected, etag)
+
+	_, err = MptETag([]*MptPart{{Num: 1, MD5: "not-hex"}})
+	tassert.Errorf(t, err != nil, "expected error on invalid MD5")
+}
+
+func TestMptParts(t *testing.T) {
+	const id = "test-upload"
+	tassert.CheckFatal(t, InitUpload(id, "bck", "obj"))
+	defer FinishUpload(id, "bck", "obj", false)
+
+	for _, num := range []int64{3, 1, 2} {
+		prev, err := AddPart(id, "bck", "obj", &MptPart{Num: num, MD5: md5hex("a"), FQN: "fqn"})
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, prev == "", "unexpected replaced part %q", prev)
+	}
+	// re-upload part #2
+	prev, err := AddPart(id, "bck", "obj", &MptPart{Num: 2, MD5: md5hex("b"), FQN: "fqn2"})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, prev == "fqn", "expected replaced part %q, got %q", "fqn", prev)
+
+	// the upload is bound to its bucket and object
+	_, err = AddPart(id, "bck", "other", &MptPart{Num: 4, MD5: md5hex("a"), FQN: "fqn"})
+	tassert.Errorf(t, cmn.IsErrNotFound(err), "expected no-such-upload, got %v", err)
+	_, err = ListParts(id, "other", "obj", 0, 0)
+	tassert.Errorf(t, cmn.IsErrNotFound(err), "expected no-such-upload, got %v", err)
+
+	result, err := ListParts(id, "bck", "obj", 1, 1)
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(result.Parts) == 1 && result.Parts[0].PartNumber == 2, "unexpected parts: %+v", result.Parts)
+	tassert.Errorf(t, result.IsTruncated, "expected truncated listing")
+
+	// wrong ETag
+	_, err = BeginComplete(id, "bck", "obj",
+		[]*PartInfo{{PartNumber: 1, ETag: md5hex("a")}, {PartNumber: 2, ETag: md5hex("a")}})
+	tassert.Errorf(t, err != nil, "expected ETag mismatch")
+	// wrong order
+	_, err = BeginComplete(id, "bck", "obj", []*PartInfo{{PartNumber: 2}, {PartNumber: 1}})
+	tassert.Errorf(t, err != nil, "expected invalid order")
+
+	parts, err := BeginComplete(id, "bck", "obj",
+		[]*PartInfo{{PartNumber: 1}, {PartNumber: 2, ETag: "\"" + md5hex("b") + "\""}})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(parts) == 2 && parts[1].FQN == "fqn2", "unexpected parts: %+v", parts)
+
+	// while completing: no new parts, no other completions, no abort
+	_, err = AddPart(id, "bck", "obj", &MptPart{Num: 1, MD5: md5hex("c"), FQN: "fqn3"})
+	tassert.Errorf(t, errors.Is(err, ErrMptCompleting), "expected %v, got %v", ErrMptCompleting, err)
+	_, err = BeginComplete(id, "bck", "obj", []*PartInfo{{PartNumber: 1}})
+	tassert.Errorf(t, errors.Is(err, ErrMptCompleting), "expected %v, got %v", ErrMptCompleting, err)
+	_, err = FinishUpload(id, "bck", "obj", false)
+	tassert.Errorf(t, errors.Is(err, ErrMptCompleting), "expected %v, got %v", ErrMptCompleting, err)
+
+	// failed completion can be retried
+	EndComplete(id)
+	_, err = BeginComplete(id, "bck", "obj", []*PartInfo{{PartNumber: 1}, {PartNumber: 3}})
+	tassert.CheckFatal(t, err)
+
+	all, err := FinishUpload(id, "bck", "obj", true)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(all) == 3, "expected 3 parts, got %d", len(all))
+	_, err = AddPart(id, "bck", "obj", &MptPart{Num: 1})
+	tassert.Errorf(t, err != nil, "expected error adding part to finished upload")
+}
+
+func TestMptPersist(t *testing.T) {
+	db, err := dbdriver.NewBuntDB(filepath.Join(t.TempDir(), "test.db"))
+	tassert.CheckFatal(t, err)
+	defer func() {
+		ups.Lock()
+		ups.db = nil
+		ups.Unlock()
+		db.Close()
+	}()
+	_, err = LoadUploads(db)
+	tassert.CheckFatal(t, err)
+
+	const id = "persisted-upload"
+	tassert.CheckFatal(t, InitUpload(id, "bck", "obj"))
+	_, err = AddPart(id, "bck", "obj", &MptPart{Num: 1, MD5: md5hex("a"), FQN: "fqn1", Size: 1})
+	tassert.CheckFatal(t, err)
+	_, err = AddPart(id, "bck", "obj", &MptPart{Num: 2, MD5: md5hex("b"), FQN: "fqn2", Size: 2})
+	tassert.CheckFatal(t, err)
+
+	// restart
+	ups.Lock()
+	ups.m = make(map[string]*mpt, 8)
+	ups.Unlock()
+	n, err := LoadUploads(db)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, n == 1, "expected 1 upload, got %d", n)
+
+	result, err := ListParts(id, "bck", "obj", 0, 0)
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(result.Parts) == 2 && result.Parts[1].Size == 2, "unexpected parts: %+v", result.Parts)
+
+	_, err = FinishUpload(id, "bck", "obj", false)
+	tassert.CheckFatal(t, err)
+	recs, err := db.GetAll(mptCollection, "")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(recs) == 0, "expected no records, got %d", len(recs))
+}
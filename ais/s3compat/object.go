@@ -114,12 +114,18 @@ func FormatTime(t time.Time) string {
 }
 
 func lomMD5(lom *cluster.LOM) string {
+	if lom.Bck().IsAIS() {
+		// multipart upload
+		if v, exists := lom.GetCustomKey(cmn.ETag); exists {
+			return v
+		}
+	}
 	if v, exists := lom.GetCustomKey(cmn.SourceObjMD); exists && v == cmn.ProviderAmazon {
 		if v, exists := lom.GetCustomKey(cmn.MD5ObjMD); exists {
 			return v
 		}
 	}
-	if cksum := lom.Checksum(); cksum.Type() == cos.ChecksumMD5 {
+	if cksum := lom.PlainAttrs().Cksum; cksum.Type() == cos.ChecksumMD5 {
 		return cksum.Value()
 	}
 	return ""
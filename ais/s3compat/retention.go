@@ -0,0 +1,37 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn/cos"
+)
+
+// Object lock: https://docs.aws.amazon.com/AmazonS3/latest/userguide/object-lock.html
+// Retention is configured via bucket property `retention` (see cluster/lretention.go);
+// S3 clients can view the object's retention and bypass it in governance mode.
+const (
+	HeaderObjLockMode        = "x-amz-object-lock-mode"
+	HeaderObjLockRetainUntil = "x-amz-object-lock-retain-until-date"
+	HeaderObjLockLegalHold   = "x-amz-object-lock-legal-hold"
+	HeaderBypassGovernance   = "x-amz-bypass-governance-retention"
+)
+
+// SetRetentionHeaders adds object lock response headers if the object is retained.
+func SetRetentionHeaders(header http.Header, lom *cluster.LOM) {
+	if mode, until := lom.Retention(); !until.IsZero() {
+		header.Set(HeaderObjLockMode, strings.ToUpper(mode))
+		header.Set(HeaderObjLockRetainUntil, until.UTC().Format(time.RFC3339))
+	}
+	if lom.LegalHold() {
+		header.Set(HeaderObjLockLegalHold, "ON")
+	}
+}
+
+func BypassGovernance(hdr http.Header) bool { return cos.IsParseBool(hdr.Get(HeaderBypassGovernance)) }
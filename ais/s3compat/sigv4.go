@@ -0,0 +1,366 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AWS signature V4: https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
+// Both variants are supported: signature in the `Authorization` header and presigned URL
+// (signature in the query). The former must carry the hash of the payload (or, explicitly,
+// UNSIGNED-PAYLOAD) in `x-amz-content-sha256` - the hash is then verified upon reading
+// the payload (see VerifyPayload); chunked (STREAMING-*) payloads are not supported.
+
+const (
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+	sigV4Request   = "aws4_request"
+	sigV4Time      = "20060102T150405Z"
+
+	authV4Prefix = sigV4Algorithm + " "
+	authV2Prefix = "AWS "
+
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+
+	headerAmzDate       = "X-Amz-Date"
+	headerContentSHA256 = "X-Amz-Content-Sha256"
+
+	// presigned URL
+	queryAlgorithm     = "X-Amz-Algorithm"
+	queryCredential    = "X-Amz-Credential"
+	queryDate          = "X-Amz-Date"
+	queryExpires       = "X-Amz-Expires"
+	querySignedHeaders = "X-Amz-SignedHeaders"
+	querySignature     = "X-Amz-Signature"
+
+	maxPresignedExpires = 7 * 24 * time.Hour
+	maxRequestTimeSkew  = 15 * time.Minute
+)
+
+var (
+	ErrSigV4Mismatch  = errors.New("the request signature does not match the signature calculated by the server")
+	ErrSigV4Expired   = errors.New("the request signature has expired")
+	ErrSHA256Mismatch = errors.New("the provided 'x-amz-content-sha256' header does not match what was computed")
+)
+
+type SigV4 struct {
+	AccessKey     string
+	date          string // YYYYMMDD (credential scope)
+	region        string
+	service       string
+	amzDate       string // YYYYMMDDTHHMMSSZ
+	signedHeaders []string
+	payload       string // x-amz-content-sha256
+	signature     string
+	expires       time.Duration // presigned URL only
+	presigned     bool
+}
+
+// ParseSigV4 returns nil if the request is not signed.
+func ParseSigV4(r *http.Request) (*SigV4, error) {
+	query := r.URL.Query()
+	if query.Has(queryAlgorithm) {
+		return parsePresigned(query)
+	}
+	auth := r.Header.Get("Authorization")
+	switch {
+	case auth == "":
+		return nil, nil
+	case strings.HasPrefix(auth, authV4Prefix):
+		return parseAuthHeader(r, auth[len(authV4Prefix):])
+	case strings.HasPrefix(auth, authV2Prefix):
+		return nil, errors.New("AWS signature V2 is not supported")
+	default:
+		return nil, nil // (not an S3 signature - e.g., AuthN token)
+	}
+}
+
+// Authorization: AWS4-HMAC-SHA256 Credential=AKID/20130524/us-east-1/s3/aws4_request,
+// SignedHeaders=host;range;x-amz-date, Signature=fe5f80f77d5fa3beca038a248ff027d0445342fe2855ddc963176630326f1024
+func parseAuthHeader(r *http.Request, auth string) (*SigV4, error) {
+	sig := &SigV4{}
+	for _, part := range strings.Split(auth, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid authorization header %q", auth)
+		}
+		switch kv[0] {
+		case "Credential":
+			if err := sig.parseCredential(kv[1]); err != nil {
+				return nil, err
+			}
+		case "SignedHeaders":
+			sig.signedHeaders = strings.Split(kv[1], ";")
+		case "Signature":
+			sig.signature = kv[1]
+		}
+	}
+	dateHeader := "x-amz-date"
+	sig.amzDate = r.Header.Get(headerAmzDate)
+	if sig.amzDate == "" {
+		// NOTE: `Date` header would have to be in the same (ISO 8601 basic) format
+		dateHeader = "date"
+		sig.amzDate = r.Header.Get("Date")
+	}
+	if err := sig.validate(); err != nil {
+		return nil, err
+	}
+	if !sig.signs(dateHeader) {
+		return nil, fmt.Errorf("signed headers must include %q", dateHeader)
+	}
+	sig.payload = r.Header.Get(headerContentSHA256)
+	if sig.payload != unsignedPayload && !isSHA256(sig.payload) {
+		return nil, fmt.Errorf("unsupported or missing %s %q (expecting SHA-256 of the payload or %s)",
+			headerContentSHA256, sig.payload, unsignedPayload)
+	}
+	return sig, nil
+}
+
+func parsePresigned(query url.Values) (*SigV4, error) {
+	if alg := query.Get(queryAlgorithm); alg != sigV4Algorithm {
+		return nil, fmt.Errorf("unsupported signature algorithm %q", alg)
+	}
+	sig := &SigV4{
+		amzDate:       query.Get(queryDate),
+		signedHeaders: strings.Split(query.Get(querySignedHeaders), ";"),
+		signature:     query.Get(querySignature),
+		presigned:     true,
+	}
+	if err := sig.parseCredential(query.Get(queryCredential)); err != nil {
+		return nil, err
+	}
+	secs, err := strconv.ParseInt(query.Get(queryExpires), 10, 64)
+	if err != nil || secs <= 0 {
+		return nil, fmt.Errorf("invalid %s %q", queryExpires, query.Get(queryExpires))
+	}
+	sig.expires = time.Duration(secs) * time.Second
+	if sig.expires > maxPresignedExpires {
+		return nil, fmt.Errorf("%s must be less than a week (%v)", queryExpires, maxPresignedExpires)
+	}
+	return sig, sig.validate()
+}
+
+// AKID/20130524/us-east-1/s3/aws4_request
+func (sig *SigV4) parseCredential(cred string) error {
+	parts := strings.Split(cred, "/")
+	if len(parts) != 5 || parts[0] == "" || parts[4] != sigV4Request {
+		return fmt.Errorf("invalid credential %q", cred)
+	}
+	sig.AccessKey, sig.date, sig.region, sig.service = parts[0], parts[1], parts[2], parts[3]
+	return nil
+}
+
+func (sig *SigV4) validate() error {
+	if sig.AccessKey == "" || sig.signature == "" || len(sig.signedHeaders) == 0 || sig.signedHeaders[0] == "" {
+		return errors.New("incomplete signature V4")
+	}
+	if sig.amzDate == "" {
+		return errors.New("missing request date")
+	}
+	if !sig.signs("host") {
+		return errors.New("signed headers must include \"host\"")
+	}
+	return nil
+}
+
+func (sig *SigV4) signs(name string) bool {
+	for _, h := range sig.signedHeaders {
+		if h == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (sig *SigV4) scope() string {
+	return sig.date + "/" + sig.region + "/" + sig.service + "/" + sigV4Request
+}
+
+// Verify checks the signature given the secret key and current time.
+func (sig *SigV4) Verify(r *http.Request, secretKey string, now time.Time) error {
+	signed, err := time.Parse(sigV4Time, sig.amzDate)
+	if err != nil {
+		return fmt.Errorf("invalid request date %q", sig.amzDate)
+	}
+	if !strings.HasPrefix(sig.amzDate, sig.date) {
+		return fmt.Errorf("request date %q does not match credential scope %q", sig.amzDate, sig.date)
+	}
+	if sig.presigned {
+		if now.After(signed.Add(sig.expires)) {
+			return ErrSigV4Expired
+		}
+		if signed.Sub(now) > maxRequestTimeSkew {
+			return fmt.Errorf("request date %q is in the future", sig.amzDate)
+		}
+	} else if d := now.Sub(signed); d > maxRequestTimeSkew || d < -maxRequestTimeSkew {
+		return fmt.Errorf("the difference between the request time %q and the server's time is too large",
+			sig.amzDate)
+	}
+	expected := sig.Sign(r, secretKey)
+	if !hmac.Equal([]byte(expected), []byte(sig.signature)) {
+		return ErrSigV4Mismatch
+	}
+	return nil
+}
+
+// Sign computes the signature of the request.
+func (sig *SigV4) Sign(r *http.Request, secretKey string) string {
+	canonical := sig.canonicalRequest(r)
+	hash := sha256.Sum256([]byte(canonical))
+	stringToSign := sigV4Algorithm + "\n" + sig.amzDate + "\n" + sig.scope() + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), sig.date)
+	key = hmacSHA256(key, sig.region)
+	key = hmacSHA256(key, sig.service)
+	key = hmacSHA256(key, sigV4Request)
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func (sig *SigV4) canonicalRequest(r *http.Request) string {
+	var sb strings.Builder
+	sb.WriteString(r.Method)
+	sb.WriteByte('\n')
+	path := r.URL.Path
+	if path == "" {
+		path = "/"
+	}
+	sb.WriteString(uriEncode(path, false))
+	sb.WriteByte('\n')
+	sb.WriteString(sig.canonicalQuery(r.URL.Query()))
+	sb.WriteByte('\n')
+	for _, name := range sig.signedHeaders {
+		sb.WriteString(name)
+		sb.WriteByte(':')
+		sb.WriteString(canonicalHeader(r, name))
+		sb.WriteByte('\n')
+	}
+	sb.WriteByte('\n')
+	sb.WriteString(strings.Join(sig.signedHeaders, ";"))
+	sb.WriteByte('\n')
+	if sig.presigned {
+		sb.WriteString(unsignedPayload)
+	} else {
+		sb.WriteString(sig.payload)
+	}
+	return sb.String()
+}
+
+func (sig *SigV4) canonicalQuery(query url.Values) string {
+	params := make([]string, 0, len(query))
+	for k, vs := range query {
+		if sig.presigned && k == querySignature {
+			continue
+		}
+		ek := uriEncode(k, true)
+		for _, v := range vs {
+			params = append(params, ek+"="+uriEncode(v, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+func canonicalHeader(r *http.Request, name string) string {
+	if name == "host" {
+		return r.Host
+	}
+	var (
+		vals = r.Header.Values(name)
+		trim = make([]string, len(vals))
+	)
+	for i, v := range vals {
+		trim[i] = strings.Join(strings.Fields(v), " ")
+	}
+	return strings.Join(trim, ",")
+}
+
+///////////////////
+// payloadReader //
+///////////////////
+
+// payloadReader computes SHA-256 of the payload while it is being read and, once
+// the entire payload is read, compares it with the signed one
+type payloadReader struct {
+	io.ReadCloser
+	h        hash.Hash
+	expected string
+	size     int64 // Content-Length (-1 if unknown)
+	read     int64
+	done     bool
+}
+
+// VerifyPayload wraps the request's body, so that reading it fails (with ErrSHA256Mismatch)
+// if the payload does not match its signed hash (`x-amz-content-sha256`) - a no-op for
+// unsigned payloads.
+func VerifyPayload(r *http.Request) {
+	expected := r.Header.Get(headerContentSHA256)
+	if r.Body == nil || r.Body == http.NoBody || !isSHA256(expected) {
+		return
+	}
+	r.Body = &payloadReader{
+		ReadCloser: r.Body,
+		h:          sha256.New(),
+		expected:   strings.ToLower(expected),
+		size:       r.ContentLength,
+	}
+}
+
+func (pr *payloadReader) Read(p []byte) (n int, err error) {
+	n, err = pr.ReadCloser.Read(p)
+	pr.h.Write(p[:n])
+	pr.read += int64(n)
+	if pr.done || (err != io.EOF && (pr.size < 0 || pr.read < pr.size)) {
+		return
+	}
+	pr.done = true
+	if hex.EncodeToString(pr.h.Sum(nil)) != pr.expected {
+		err = ErrSHA256Mismatch
+	}
+	return
+}
+
+func isSHA256(s string) bool {
+	if len(s) != 2*sha256.Size {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// URI-encode every byte except the unreserved characters (and, optionally, slash)
+func uriEncode(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			sb.WriteByte(c)
+			continue
+		}
+		sb.WriteByte('%')
+		sb.WriteByte(hexDigits[c>>4])
+		sb.WriteByte(hexDigits[c&0xf])
+	}
+	return sb.String()
+}
@@ -0,0 +1,49 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/NVIDIA/aistore/cluster"
+)
+
+// Server-side encryption: https://docs.aws.amazon.com/AmazonS3/latest/userguide/serv-side-encryption.html
+// Both SSE-S3 ("AES256") and SSE-KMS ("aws:kms") requests are served by the cluster's KMS -
+// the former uses the bucket's (or KMS default) key, the latter - the specified key, if any.
+// Customer-provided keys (SSE-C) are not supported.
+const (
+	HeaderSSE         = "x-amz-server-side-encryption"
+	HeaderSSEKMSKeyID = "x-amz-server-side-encryption-aws-kms-key-id"
+	HeaderSSECAlg     = "x-amz-server-side-encryption-customer-algorithm"
+
+	SSEAES256 = "AES256"
+	SSEKMS    = "aws:kms"
+)
+
+// ParseSSE parses PUT request headers; returns ok == false if encryption was not requested.
+func ParseSSE(hdr http.Header) (keyID string, ok bool, err error) {
+	if hdr.Get(HeaderSSECAlg) != "" {
+		return "", false, fmt.Errorf("server-side encryption with customer-provided keys (SSE-C) is not supported")
+	}
+	switch alg := hdr.Get(HeaderSSE); alg {
+	case "":
+		return "", false, nil
+	case SSEAES256:
+		return "", true, nil
+	case SSEKMS:
+		return hdr.Get(HeaderSSEKMSKeyID), true, nil
+	default:
+		return "", false, fmt.Errorf("invalid %s value %q", HeaderSSE, alg)
+	}
+}
+
+// SetSSEHeader adds server-side encryption response header if the object is encrypted.
+func SetSSEHeader(header http.Header, lom *cluster.LOM) {
+	if lom.IsEncrypted() {
+		header.Set(HeaderSSE, SSEAES256)
+	}
+}
@@ -0,0 +1,126 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// ListObjectVersions is supported for AIS buckets with `versioning.retain`.
+// Listing pages never split versions of a given object, and so `key-marker`
+// alone suffices to resume the listing (`version-id-marker` is ignored).
+
+type (
+	ListVersionsResult struct {
+		XMLName       xml.Name           `xml:"ListVersionsResult"`
+		Ns            string             `xml:"xmlns,attr"`
+		Name          string             `xml:"Name"`
+		Prefix        string             `xml:"Prefix"`
+		KeyMarker     string             `xml:"KeyMarker"`
+		NextKeyMarker string             `xml:"NextKeyMarker,omitempty"`
+		MaxKeys       int                `xml:"MaxKeys"`
+		IsTruncated   bool               `xml:"IsTruncated"`
+		Versions      []*ObjVersion      `xml:"Version"`
+		DeleteMarkers []*DeleteMarkerEnt `xml:"DeleteMarker"`
+	}
+	ObjVersion struct {
+		Key          string `xml:"Key"`
+		VersionID    string `xml:"VersionId"`
+		IsLatest     bool   `xml:"IsLatest"`
+		LastModified string `xml:"LastModified"`
+		ETag         string `xml:"ETag"`
+		Size         int64  `xml:"Size"`
+		Class        string `xml:"StorageClass"`
+	}
+	DeleteMarkerEnt struct {
+		Key          string `xml:"Key"`
+		VersionID    string `xml:"VersionId"`
+		IsLatest     bool   `xml:"IsLatest"`
+		LastModified string `xml:"LastModified"`
+	}
+)
+
+func FillMsgFromS3VersionsQuery(query url.Values, msg *cmn.ListObjsMsg) {
+	if pageSize, err := strconv.Atoi(query.Get("max-keys")); err == nil && pageSize > 0 {
+		msg.PageSize = uint(pageSize)
+	}
+	msg.Prefix = query.Get("prefix")
+	msg.ContinuationToken = query.Get("key-marker")
+	msg.SetFlag(cmn.LsVersions)
+}
+
+// VersionID returns requested version of the object, if any
+// ("null" denotes the current version of the object).
+func VersionID(query url.Values) string {
+	if ver := query.Get(URLParamVersionID); ver != nullVersionID {
+		return ver
+	}
+	return ""
+}
+
+func NewListVersionsResult(bucket string, msg *cmn.ListObjsMsg) *ListVersionsResult {
+	return &ListVersionsResult{
+		Ns:        s3Namespace,
+		Name:      bucket,
+		Prefix:    msg.Prefix,
+		KeyMarker: msg.ContinuationToken,
+		MaxKeys:   1000,
+	}
+}
+
+// FillFromAisBckList converts AIS listing (where noncurrent versions precede
+// the current one in ascending order) to S3 (latest version first).
+func (r *ListVersionsResult) FillFromAisBckList(bckList *cmn.BucketList, lsmsg *cmn.ListObjsMsg) {
+	r.IsTruncated = bckList.ContinuationToken != ""
+	r.NextKeyMarker = bckList.ContinuationToken
+	entries := bckList.Entries
+	for start := 0; start < len(entries); {
+		end := start + 1
+		for end < len(entries) && entries[end].Name == entries[start].Name {
+			end++
+		}
+		for i := end - 1; i >= start; i-- {
+			r.add(entries[i], lsmsg)
+		}
+		start = end
+	}
+}
+
+func (r *ListVersionsResult) add(entry *cmn.BucketEntry, lsmsg *cmn.ListObjsMsg) {
+	var (
+		objInfo = entryToS3(entry, lsmsg)
+		latest  = !entry.IsNoncurrent()
+	)
+	if entry.IsDelMarker() {
+		r.DeleteMarkers = append(r.DeleteMarkers, &DeleteMarkerEnt{
+			Key:          objInfo.Key,
+			VersionID:    entry.Version,
+			IsLatest:     latest,
+			LastModified: objInfo.LastModified,
+		})
+		return
+	}
+	r.Versions = append(r.Versions, &ObjVersion{
+		Key:          objInfo.Key,
+		VersionID:    entry.Version,
+		IsLatest:     latest,
+		LastModified: objInfo.LastModified,
+		ETag:         objInfo.ETag,
+		Size:         objInfo.Size,
+		Class:        objInfo.Class,
+	})
+}
+
+func (r *ListVersionsResult) MustMarshal(sgl *memsys.SGL) {
+	sgl.Write([]byte(xml.Header))
+	err := xml.NewEncoder(sgl).Encode(r)
+	cos.AssertNoErr(err)
+}
@@ -0,0 +1,41 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestListVersionsResult(t *testing.T) {
+	var (
+		lsmsg   = &cmn.ListObjsMsg{}
+		bckList = &cmn.BucketList{
+			Entries: []*cmn.BucketEntry{
+				{Name: "a", Version: "1", Flags: cmn.EntryNoncurrent},
+				{Name: "a", Version: "2", Flags: cmn.EntryNoncurrent},
+				{Name: "a", Version: "3"},
+				{Name: "b", Version: "1", Flags: cmn.EntryNoncurrent},
+				{Name: "b", Version: "2", Flags: cmn.EntryDelMarker},
+			},
+			ContinuationToken: "b",
+		}
+		res = NewListVersionsResult("bck", lsmsg)
+	)
+	res.FillFromAisBckList(bckList, lsmsg)
+	tassert.Errorf(t, res.IsTruncated && res.NextKeyMarker == "b", "expected truncated listing with next key marker %q", "b")
+	tassert.Fatalf(t, len(res.Versions) == 4, "expected 4 versions, got %d", len(res.Versions))
+	tassert.Fatalf(t, len(res.DeleteMarkers) == 1, "expected 1 delete marker, got %d", len(res.DeleteMarkers))
+
+	expected := []string{"a/3", "a/2", "a/1", "b/1"}
+	for i, v := range res.Versions {
+		tassert.Errorf(t, v.Key+"/"+v.VersionID == expected[i], "expected %q, got %q", expected[i], v.Key+"/"+v.VersionID)
+		tassert.Errorf(t, v.IsLatest == (i == 0), "%s/%s: unexpected IsLatest %t", v.Key, v.VersionID, v.IsLatest)
+	}
+	dm := res.DeleteMarkers[0]
+	tassert.Errorf(t, dm.Key == "b" && dm.VersionID == "2" && dm.IsLatest, "unexpected delete marker %+v", dm)
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/NVIDIA/aistore/etl"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/health"
+	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/nl"
@@ -44,6 +45,7 @@ import (
 	"github.com/NVIDIA/aistore/volume"
 	"github.com/NVIDIA/aistore/xaction"
 	"github.com/NVIDIA/aistore/xreg"
+	"github.com/NVIDIA/aistore/xs"
 )
 
 const dbName = "ais.db"
@@ -66,6 +68,8 @@ type (
 		res          *res.Res
 		db           dbdriver.Driver
 		transactions transactions
+		quotas       quotas
+		partials     partials
 		regstate     regstate // the state of being registered with the primary, can be (en/dis)abled via API
 	}
 )
@@ -81,7 +85,7 @@ func (b backends) init(t *targetrunner, starting bool) {
 	backend.Init()
 
 	ais := backend.NewAIS(t)
-	b[cmn.ProviderAIS] = ais // ais cloud is always present
+	b[cmn.ProviderAIS] = backend.NewThrottled(ais, t.statsT) // ais cloud is always present
 
 	config := cmn.GCO.Get()
 	if aisConf, ok := config.Backend.ProviderConf(cmn.ProviderAIS); ok {
@@ -90,7 +94,8 @@ func (b backends) init(t *targetrunner, starting bool) {
 		}
 	}
 
-	b[cmn.ProviderHTTP], _ = backend.NewHTTP(t, config)
+	hp, _ := backend.NewHTTP(t, config)
+	b[cmn.ProviderHTTP] = backend.NewThrottled(hp, t.statsT)
 	if err := b.initExt(t, starting); err != nil {
 		cos.ExitLogf("%v", err)
 	}
@@ -135,6 +140,11 @@ func (b backends) initExt(t *targetrunner, starting bool) (err error) {
 				b[provider], err = backend.NewHDFS(t)
 				add = provider
 			}
+		case cmn.ProviderPOSIX:
+			if _, ok := b[provider]; !ok {
+				b[provider], err = backend.NewPOSIX(t)
+				add = provider
+			}
 		default:
 			err = fmt.Errorf(cmn.FmtErrUnknown, t.si, "backend provider", provider)
 		}
@@ -144,8 +154,13 @@ func (b backends) initExt(t *targetrunner, starting bool) (err error) {
 		if err != nil {
 			return
 		}
-		if add != "" && !starting {
-			glog.Errorf("Warning: %s: add %q backend", t.si, add)
+		if add != "" {
+			if b[provider] != nil {
+				b[provider] = backend.NewThrottled(b[provider], t.statsT) // see `rate_limit`
+			}
+			if !starting {
+				glog.Errorf("Warning: %s: add %q backend", t.si, add)
+			}
 		}
 	}
 	return
@@ -280,6 +295,12 @@ func (t *targetrunner) Run() error {
 
 	cluster.Init(t)
 	cluster.RegLomCacheWithHK(t)
+	hk.Reg("s3-mpt.gc", t.gcMpt, hk.DayInterval)
+	hk.Reg(cmn.ActLifecycle, t.lifecycleHK, lifecycleInterval)
+	t.quotas.init(t)
+	hk.Reg("quotas", t.quotas.housekeep, dfltQuotaSyncTime)
+	t.partials.init(t)
+	hk.Reg("partials", t.partials.housekeep, partialIdleTime/4)
 
 	// metrics, disks first
 	tstats := t.statsT.(*stats.Trunner)
@@ -301,13 +322,19 @@ func (t *targetrunner) Run() error {
 		glog.Errorln("")
 	}
 
-	// register object type and workfile type
+	// register object type, workfile type, retained object versions, and cached ETL results
 	if err := fs.CSM.Reg(fs.ObjectType, &fs.ObjectContentResolver{}); err != nil {
 		cos.ExitLogf("%v", err)
 	}
 	if err := fs.CSM.Reg(fs.WorkfileType, &fs.WorkfileContentResolver{}); err != nil {
 		cos.ExitLogf("%v", err)
 	}
+	if err := fs.CSM.Reg(fs.ObjVersionType, &fs.ObjVersionContentResolver{}); err != nil {
+		cos.ExitLogf("%v", err)
+	}
+	if err := fs.CSM.Reg(fs.ETLCacheType, &fs.ETLCacheContentResolver{}); err != nil {
+		cos.ExitLogf("%v", err)
+	}
 
 	// Init meta-owners and load local instances
 	t.owner.bmd.init()
@@ -371,6 +398,9 @@ func (t *targetrunner) Run() error {
 	}
 	t.db = db
 	defer cos.Close(db)
+	t.loadMpt()
+	hk.Reg(cmn.ActWriteBack, t.wbHousekeep, wbHousekeepInterval)
+	hk.Reg(cmn.ActReplicate, t.rpHousekeep, rpHousekeepInterval)
 
 	// transactions
 	t.transactions.init(t)
@@ -639,8 +669,8 @@ func (t *targetrunner) httpbckdelete(w http.ResponseWriter, r *http.Request) {
 	switch msg.Action {
 	case cmn.ActEvictRemoteBck:
 		keepMD := cos.IsParseBool(request.query.Get(cmn.URLParamKeepBckMD))
-		// HDFS buckets will always keep metadata so they can re-register later
-		if request.bck.IsHDFS() || keepMD {
+		// HDFS and POSIX buckets will always keep metadata so they can re-register later
+		if request.bck.IsHDFS() || request.bck.IsPOSIX() || keepMD {
 			nlp := request.bck.GetNameLockPair()
 			nlp.Lock()
 			defer nlp.Unlock()
@@ -710,19 +740,23 @@ func (t *targetrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 	switch msg.Action {
 	case cmn.ActPrefetchObjects:
 		var (
-			err   error
-			lrMsg = &cmn.ListRangeMsg{}
+			err    error
+			prfMsg = &cmn.PrefetchMsg{}
 		)
 		if !request.bck.IsRemote() {
 			t.writeErrf(w, r, "%s: expecting remote bucket, got %s, action=%s",
 				t.si, request.bck, msg.Action)
 			return
 		}
-		if err = cos.MorphMarshal(msg.Value, lrMsg); err != nil {
+		if err = cos.MorphMarshal(msg.Value, prfMsg); err != nil {
 			t.writeErrf(w, r, cmn.FmtErrMorphUnmarshal, t.si, msg.Action, msg.Value, err)
 			return
 		}
-		rns := xreg.RenewPrefetch(msg.UUID, t, request.bck, lrMsg)
+		if err = prfMsg.Validate(); err != nil {
+			t.writeErr(w, r, err)
+			return
+		}
+		rns := xreg.RenewPrefetch(msg.UUID, t, request.bck, prfMsg)
 		xact := rns.Entry.Get()
 		go xact.Run(nil)
 	default:
@@ -767,6 +801,15 @@ func (t *targetrunner) httpbckhead(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if !inBMD && request.bck.Provider == cmn.ProviderAmazon {
+		// S3-compatible endpoint of the bucket that is yet to be added (see proxy's lookupS3Bucket)
+		if endpoint := request.query.Get(cmn.URLParamS3Endpoint); endpoint != "" {
+			props := &cmn.BucketProps{Provider: cmn.ProviderAmazon}
+			props.Extra.AWS.Endpoint = endpoint
+			props.Extra.AWS.Profile = request.query.Get(cmn.URLParamS3Profile)
+			request.bck.Props = props
+		}
+	}
 	// + cloud
 	bucketProps, code, err = t.Backend(request.bck).HeadBucket(ctx, request.bck)
 	if err != nil {
@@ -786,7 +829,7 @@ func (t *targetrunner) httpbckhead(w http.ResponseWriter, r *http.Request) {
 		bucketProps[cmn.HdrRemoteOffline] = strconv.FormatBool(request.bck.IsRemote())
 	}
 	for k, v := range bucketProps {
-		if k == cmn.HdrBucketVerEnabled && request.bck.Props != nil {
+		if k == cmn.HdrBucketVerEnabled && inBMD {
 			if curr := strconv.FormatBool(request.bck.VersionConf().Enabled); curr != v {
 				// e.g., change via vendor-provided CLI and similar
 				glog.Errorf("%s: %s versioning got out of sync: %s != %s", t.si, request.bck, v, curr)
@@ -853,9 +896,20 @@ func (t *targetrunner) getObject(w http.ResponseWriter, r *http.Request, query u
 		}
 	}
 	if isETLRequest(query) {
-		t.doETL(w, r, query.Get(cmn.URLParamUUID), bck, lom.ObjName)
+		if pipeline := query.Get(cmn.URLParamETLPipeline); pipeline != "" {
+			t.doETLPipeline(w, r, pipeline, bck, lom.ObjName)
+		} else {
+			t.doETL(w, r, query.Get(cmn.URLParamUUID), query.Get(cmn.URLParamETLArgs), bck, lom.ObjName)
+		}
 		return
 	}
+	if ver := query.Get(cmn.URLParamVersion); ver != "" {
+		t.getObjVersion(w, r, lom, ver)
+		return
+	}
+	if bck.IsRemote() {
+		xs.ReadAhead(lom, readerSession(r))
+	}
 	filename := query.Get(cmn.URLParamArchpath)
 	if strings.HasPrefix(filename, lom.ObjName) {
 		if rel, err := filepath.Rel(lom.ObjName, filename); err == nil {
@@ -882,10 +936,27 @@ func (t *targetrunner) getObject(w http.ResponseWriter, r *http.Request, query u
 		originalURL := query.Get(cmn.URLParamOrigURL)
 		goi.ctx = context.WithValue(goi.ctx, cos.CtxOriginalURL, originalURL)
 	}
-	if errCode, err := goi.getObject(); err != nil && err != errSendingResp {
+	errCode, err := goi.getObject()
+	freeGetObjInfo(goi)
+	switch err {
+	case nil, errSendingResp:
+	case errAbortResp:
+		panic(http.ErrAbortHandler) // streaming cold GET failed mid-transfer (see coldTee)
+	default:
 		t.writeErr(w, r, err, errCode)
 	}
-	freeGetObjInfo(goi)
+}
+
+// reader session to prefetch ahead of (see xs/readahead.go)
+func readerSession(r *http.Request) string {
+	if session := r.Header.Get(cmn.HdrReaderSession); session != "" {
+		return session
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
 }
 
 // PUT /v1/objects/bucket-name/object-name
@@ -955,7 +1026,7 @@ func (t *targetrunner) httpobjput(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	} else {
-		errCode, err = t.doPut(r, lom, started, request.query, skipVC)
+		errCode, err = t.doPut(r, lom, started, request.query, skipVC, nil /*sse*/)
 	}
 	if err != nil {
 		t.fsErr(err, lom.FQN)
@@ -980,7 +1051,10 @@ func (t *targetrunner) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	evict := msg.Action == cmn.ActEvictObjects
+	var (
+		evict  = msg.Action == cmn.ActEvictObjects
+		bypass = cos.IsParseBool(request.query.Get(cmn.URLParamBypassGovernance))
+	)
 	lom := cluster.AllocLOM(request.items[1])
 	defer cluster.FreeLOM(lom)
 	if err := lom.Init(request.bck.Bck); err != nil {
@@ -988,7 +1062,15 @@ func (t *targetrunner) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	errCode, err := t.DeleteObject(lom, evict)
+	var (
+		errCode int
+		err     error
+	)
+	if ver := request.query.Get(cmn.URLParamVersion); ver != "" && !evict {
+		errCode, err = t.delObjVersion(lom, ver, bypass)
+	} else {
+		errCode, err = t.deleteObject(lom, evict, bypass)
+	}
 	if err != nil {
 		if errCode == http.StatusNotFound {
 			t.writeErrSilentf(w, r, http.StatusNotFound, "object %s/%s doesn't exist",
@@ -1111,9 +1193,9 @@ func (t *targetrunner) headObject(w http.ResponseWriter, r *http.Request, query
 			invalidHandler(w, r, err, http.StatusNotFound)
 			return
 		}
-		op.ObjAttrs = *lom.ObjAttrs()
+		op.ObjAttrs = *lom.PlainAttrs()
 	} else if exists {
-		op.ObjAttrs = *lom.ObjAttrs()
+		op.ObjAttrs = *lom.PlainAttrs()
 	} else {
 		// cold HEAD
 		objAttrs, errCode, err := t.Backend(lom.Bck()).HeadObj(context.Background(), lom)
@@ -1193,11 +1275,27 @@ func (t *targetrunner) httpobjpatch(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	delOldSetNew := cos.IsParseBool(request.query.Get(cmn.URLParamNewCustom))
+	var (
+		delOldSetNew = cos.IsParseBool(request.query.Get(cmn.URLParamNewCustom))
+		bypass       = cos.IsParseBool(request.query.Get(cmn.URLParamBypassGovernance))
+	)
 	if glog.FastV(4, glog.SmoduleAIS) {
 		glog.Infof("%s: %s, custom=%+v, del-old-set-new=%t", t.si, lom, msg.Value, delOldSetNew)
 	}
+	if err := checkCustomMD(lom, custom, bypass); err != nil {
+		t.writeErr(w, r, err, http.StatusForbidden)
+		return
+	}
 	if delOldSetNew {
+		// keep system (encryption and retention) metadata
+		for _, key := range append(cluster.RetentionKeys(), cmn.SSEObjMD) {
+			if _, ok := custom[key]; ok {
+				continue
+			}
+			if v, ok := lom.GetCustomKey(key); ok {
+				custom[key] = v
+			}
+		}
 		lom.SetCustomMD(custom)
 	} else {
 		for key, val := range custom {
@@ -1295,8 +1393,8 @@ func (t *targetrunner) CompareObjects(ctx context.Context, lom *cluster.LOM) (eq
 		err = fmt.Errorf(cmn.FmtErrFailed, t.si, "head metadata of", lom, err)
 		return
 	}
-	if lom.Bck().IsHDFS() {
-		equal = true // no versioning in HDFS
+	if lom.Bck().IsHDFS() || lom.Bck().IsPOSIX() {
+		equal = true // no versioning in HDFS and POSIX
 		return
 	}
 	equal = lom.Equal(objAttrs)
@@ -1340,8 +1438,8 @@ func (t *targetrunner) listBuckets(w http.ResponseWriter, r *http.Request, query
 
 func (t *targetrunner) _listBcks(query cmn.QueryBcks, cfg *cmn.Config) (names cmn.Bcks, errCode int, err error) {
 	_, ok := cfg.Backend.Providers[query.Provider]
-	// HDFS doesn't support listing remote buckets (there are no remote buckets).
-	if (!ok && !query.IsRemoteAIS()) || query.IsHDFS() {
+	// HDFS and POSIX don't support listing remote buckets (there are no remote buckets).
+	if (!ok && !query.IsRemoteAIS()) || query.IsHDFS() || query.IsPOSIX() {
 		names = selectBMDBuckets(t.owner.bmd.get(), query)
 	} else {
 		bck := cluster.NewBck("", query.Provider, query.Ns)
@@ -1391,11 +1489,17 @@ func (t *targetrunner) doAppend(r *http.Request, lom *cluster.LOM, started time.
 //  - returned version ID is the version
 // In both cases, new checksum is also generated and stored along with the new version.
 func (t *targetrunner) doPut(r *http.Request, lom *cluster.LOM, started time.Time, query url.Values,
-	skipVC bool) (errCode int, err error) {
+	skipVC bool, sse *sseReq) (errCode int, err error) {
 	var (
 		header = r.Header
 		owt    = query.Get(cmn.URLParamOWT)
 	)
+	if lom.Bck().IsAIS() {
+		// ETag of a multipart-uploaded object (if any) does not apply to the new content
+		lom.ObjAttrs().DelCustomKeys(cmn.ETag)
+	}
+	// the new content is a replica only if it comes as such (see xs/replicate.go)
+	lom.ObjAttrs().DelCustomKeys(cmn.ReplicaObjMD)
 	// TODO: oa.Size vs "Content-Length" vs actual, similar to checksum
 	cksumToUse := lom.ObjAttrs().FromHeader(header)
 	poi := allocPutObjInfo()
@@ -1409,6 +1513,9 @@ func (t *targetrunner) doPut(r *http.Request, lom *cluster.LOM, started time.Tim
 		poi.owt = cmn.OwtPut
 		poi.skipVC = skipVC
 	}
+	if sse != nil {
+		poi.sse, poi.sseKeyID = true, sse.keyID
+	}
 	if owt != "" {
 		n, err := strconv.Atoi(owt)
 		debug.AssertNoErr(err)
@@ -1486,6 +1593,10 @@ func (t *targetrunner) putMirror(lom *cluster.LOM) {
 }
 
 func (t *targetrunner) DeleteObject(lom *cluster.LOM, evict bool) (int, error) {
+	return t.deleteObject(lom, evict, false /*bypass governance*/)
+}
+
+func (t *targetrunner) deleteObject(lom *cluster.LOM, evict, bypassGovernance bool) (int, error) {
 	var (
 		aisErr, backendErr         error
 		aisErrCode, backendErrCode int
@@ -1494,8 +1605,19 @@ func (t *targetrunner) DeleteObject(lom *cluster.LOM, evict bool) (int, error) {
 	lom.Lock(true)
 	defer lom.Unlock(true)
 
+	if !evict {
+		if errCode, err := checkRetention(lom, bypassGovernance); err != nil {
+			return errCode, err
+		}
+	}
+	if !evict && lom.VersionConf().Retain {
+		return delMarker(lom)
+	}
 	delFromBackend = lom.Bck().IsRemote() && !evict
 	if err := lom.Load(false /*cache it*/, true /*locked*/); err == nil {
+		if evict && lom.WriteBackPending() {
+			return http.StatusConflict, fmt.Errorf("cannot evict %s: not yet written to the remote backend", lom)
+		}
 		delFromAIS = true
 	} else if !cmn.IsObjNotExist(err) {
 		return 0, err
@@ -1510,11 +1632,16 @@ func (t *targetrunner) DeleteObject(lom *cluster.LOM, evict bool) (int, error) {
 		backendErrCode, backendErr = t.Backend(lom.Bck()).DeleteObj(lom)
 		if backendErr == nil {
 			t.statsT.Add(stats.DeleteCount, 1)
+		} else if delFromAIS && lom.WriteBackPending() && backendErrCode == http.StatusNotFound {
+			backendErr = nil // was never written (see write-back)
 		}
 	}
 	if delFromAIS {
 		size := lom.SizeBytes()
 		aisErr = lom.Remove()
+		if aisErr == nil {
+			t.quotas.add(lom.Bck(), -size, -1)
+		}
 		if aisErr != nil {
 			if !os.IsNotExist(aisErr) {
 				if backendErr != nil {
@@ -1530,6 +1657,9 @@ func (t *targetrunner) DeleteObject(lom *cluster.LOM, evict bool) (int, error) {
 			)
 		}
 	}
+	if !evict && ((delFromAIS && aisErr == nil) || (delFromBackend && backendErr == nil)) {
+		t.replicateDelete(lom)
+	}
 	if backendErr != nil {
 		return backendErrCode, backendErr
 	}
@@ -1565,6 +1695,13 @@ func (t *targetrunner) objMv(w http.ResponseWriter, r *http.Request, msg *cmn.Ac
 		t.writeErrf(w, r, "%s: cannot rename/move object %s onto itself", t.si, lom)
 		return
 	}
+	lom.Lock(false)
+	errCode, err := checkRetention(lom, false /*bypass*/)
+	lom.Unlock(false)
+	if err != nil {
+		t.writeErr(w, r, err, errCode)
+		return
+	}
 	buf, slab := t.gmm.Alloc()
 	coi := allocCopyObjInfo()
 	{
@@ -1573,7 +1710,7 @@ func (t *targetrunner) objMv(w http.ResponseWriter, r *http.Request, msg *cmn.Ac
 		coi.localOnly = false
 		coi.finalize = true
 	}
-	_, err := coi.copyObject(lom, msg.Name /* new object name */)
+	_, err = coi.copyObject(lom, msg.Name /* new object name */)
 	slab.Free(buf)
 	freeCopyObjInfo(coi)
 	if err != nil {
@@ -0,0 +1,195 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Streaming cold GET (bucket property `cold_get.streaming`):
+// - instead of downloading the remote object in its entirety prior to sending it back
+//   (see GetCold), read the backend and, at the same time, write the object to the client
+//   and to the workfile;
+// - upon success, the object gets finalized (cached); otherwise, the workfile is discarded -
+//   in particular, when the client disconnects or the checksum does not match;
+// - in the latter case, the response is aborted; to make sure the client never receives
+//   a seemingly complete (as per Content-Length) but invalid object, the last byte is held
+//   back until the object is finalized.
+
+type coldTee struct {
+	r    io.ReadCloser // backend
+	goi  *getObjInfo
+	size int64 // Content-Length or -1 (unknown)
+	off  int64 // read so far
+	sent int64 // written to the client
+	werr error // client-side error
+	last [1]byte
+	held bool
+}
+
+var errAbortResp = errors.New("abort-resp") // (see also errSendingResp)
+
+// interface guard
+var _ io.ReadCloser = (*coldTee)(nil)
+
+func (goi *getObjInfo) coldStreamable() bool {
+	if _, ok := goi.w.(http.ResponseWriter); !ok {
+		return false
+	}
+	return goi.ranges.Range == "" && goi.archive.filename == "" && !goi.isGFN && goi.lom.Bck().IsRemote() &&
+		goi.lom.Bprops().ColdGet.Streaming
+}
+
+// Returns done == false if the object has been cold-GET by another goroutine, in which case
+// the caller must proceed to read it locally (and remains holding the read lock).
+// Otherwise, the lock is released.
+func (goi *getObjInfo) coldStream() (done bool, errCode int, err error) {
+	var (
+		lom = goi.lom
+		t   = goi.t
+		tee = &coldTee{goi: goi, size: -1}
+	)
+	for lom.UpgradeLock() {
+		// (same as GetCold)
+		if err := lom.Load(true /*cache it*/, true /*locked*/); err != nil {
+			glog.Errorf("%s: %s load err: %v - retrying...", t.si, lom, err)
+			continue
+		}
+		return
+	}
+	done = true
+	ctx := context.WithValue(goi.ctx, cos.CtxSetSize, cos.SetSizeFunc(func(size int64) { tee.size = size }))
+	r, expCksum, errCode, err := t.Backend(lom.Bck()).GetObjReader(ctx, lom)
+	if err != nil {
+		lom.Unlock(true)
+		glog.Errorf("%s: failed to GET remote %s: %v(%d)", t.si, lom.FullName(), err, errCode)
+		return
+	}
+	tee.r = r
+	params := cluster.PutObjectParams{
+		Tag:    fs.WorkfileColdget,
+		Reader: tee,
+		OWT:    cmn.OwtGet,
+		Cksum:  expCksum,
+		Atime:  goi.started,
+	}
+	if err = t.PutObject(lom, params); err == nil {
+		err = tee.flush()
+	}
+	if err != nil {
+		lom.Unlock(true)
+		switch {
+		case tee.werr != nil:
+			glog.Errorf(cmn.FmtErrFailed, t.si, "GET", lom, tee.werr)
+			err = errSendingResp
+		case tee.sent > 0 || tee.held:
+			glog.Errorf("%s: failed to cold-GET %s (aborting response): %v", t.si, lom.FullName(), err)
+			err = errAbortResp
+		default:
+			tee.goi.w.(http.ResponseWriter).Header().Del(cmn.HdrContentLength)
+			errCode = http.StatusInternalServerError
+		}
+		return
+	}
+	if err = lom.Load(true /*cache it*/, true /*locked*/); err != nil {
+		glog.Errorf("%s: unexpected failure to load %s: %v", t.si, lom.FullName(), err)
+		err = nil // (sent)
+	}
+	lom.Unlock(true)
+
+	delta := mono.SinceNano(goi.nanotim)
+	t.statsT.AddMany(
+		cos.NamedVal64{Name: stats.GetColdCount, Value: 1},
+		cos.NamedVal64{Name: stats.GetColdSize, Value: tee.off},
+		cos.NamedVal64{Name: stats.GetThroughput, Value: tee.sent},
+		cos.NamedVal64{Name: stats.GetLatency, Value: delta},
+		cos.NamedVal64{Name: stats.GetCount, Value: 1},
+	)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("cold GET %s (streaming): %s, %v", lom, cos.B2S(tee.off, 2), time.Duration(delta))
+	}
+	return
+}
+
+/////////////
+// coldTee //
+/////////////
+
+func (tee *coldTee) Read(p []byte) (n int, err error) {
+	n, err = tee.r.Read(p)
+	if n > 0 {
+		if werr := tee.write(p[:n]); werr != nil {
+			return n, werr
+		}
+	}
+	return
+}
+
+func (tee *coldTee) Close() error { return tee.r.Close() }
+
+func (tee *coldTee) write(b []byte) error {
+	if tee.off == 0 {
+		tee.header()
+	}
+	tee.off += int64(len(b))
+	if tee.size >= 0 && tee.off >= tee.size {
+		if tee.off > tee.size || tee.held {
+			return fmt.Errorf("size mismatch: expected %d, got %d (or more)", tee.size, tee.off)
+		}
+		// hold back the last byte
+		n := len(b) - 1
+		tee.last[0], tee.held = b[n], true
+		b = b[:n]
+	}
+	return tee.send(b)
+}
+
+func (tee *coldTee) send(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	n, err := tee.goi.w.Write(b)
+	tee.sent += int64(n)
+	if err != nil {
+		tee.werr = err
+	}
+	return err
+}
+
+func (tee *coldTee) header() {
+	hdr := tee.goi.w.(http.ResponseWriter).Header()
+	tee.goi.lom.ObjAttrs().ToHeader(hdr)
+	if tee.size >= 0 {
+		hdr.Set(cmn.HdrContentLength, strconv.FormatInt(tee.size, 10))
+	} else {
+		hdr.Del(cmn.HdrContentLength) // chunked
+	}
+}
+
+// upon success: send the last byte or, if the object is empty, the header
+func (tee *coldTee) flush() error {
+	if tee.held {
+		return tee.send(tee.last[:])
+	}
+	if tee.off == 0 {
+		tee.header()
+		tee.goi.w.(http.ResponseWriter).WriteHeader(http.StatusOK)
+	}
+	return nil
+}
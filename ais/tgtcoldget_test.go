@@ -0,0 +1,50 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestColdTee(t *testing.T) {
+	const content = "The quick brown fox jumps over the lazy dog"
+	newTee := func(size int64) (*coldTee, *httptest.ResponseRecorder) {
+		var (
+			rec = httptest.NewRecorder()
+			goi = &getObjInfo{lom: &cluster.LOM{ObjName: "obj"}, w: rec}
+		)
+		return &coldTee{r: io.NopCloser(strings.NewReader(content)), goi: goi, size: size}, rec
+	}
+
+	// known size: the last byte is held back until flushed
+	tee, rec := newTee(int64(len(content)))
+	_, err := io.Copy(io.Discard, tee)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, rec.Body.String() == content[:len(content)-1], "expected all but the last byte, got %q", rec.Body)
+	tassert.CheckFatal(t, tee.flush())
+	tassert.Errorf(t, rec.Body.String() == content, "expected %q, got %q", content, rec.Body)
+	tassert.Errorf(t, rec.Header().Get(cmn.HdrContentLength) != "", "expected Content-Length")
+
+	// unknown size: chunked
+	tee, rec = newTee(-1)
+	_, err = io.Copy(io.Discard, tee)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, tee.flush())
+	tassert.Errorf(t, rec.Body.String() == content, "expected %q, got %q", content, rec.Body)
+	tassert.Errorf(t, rec.Header().Get(cmn.HdrContentLength) == "", "unexpected Content-Length")
+
+	// size mismatch
+	tee, rec = newTee(int64(len(content)) - 1)
+	_, err = io.Copy(io.Discard, tee)
+	tassert.Errorf(t, err != nil, "expected size mismatch")
+	tassert.Errorf(t, rec.Body.Len() < len(content)-1, "expected incomplete response, got %q", rec.Body)
+}
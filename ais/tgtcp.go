@@ -267,6 +267,8 @@ func (t *targetrunner) httpdaeget(w http.ResponseWriter, r *http.Request) {
 		tstats := t.statsT.(*stats.Trunner)
 		msg.Capacity = tstats.MPCap
 		t.writeJSON(w, r, msg, httpdaeWhat)
+	case cmn.GetWhatQuotaUsage:
+		t.writeJSON(w, r, t.quotas.localUsage(), httpdaeWhat)
 	case cmn.GetWhatDiskStats:
 		diskStats := make(ios.AllDiskStats)
 		fs.FillDiskStats(diskStats)
@@ -279,7 +281,7 @@ func (t *targetrunner) httpdaeget(w http.ResponseWriter, r *http.Request) {
 		}
 		clusterConf, ok := conf.(cmn.BackendConfAIS)
 		cos.Assert(ok)
-		aisCloud := t.backend[cmn.ProviderAIS].(*backend.AISBackendProvider)
+		aisCloud := backend.Unwrap(t.backend[cmn.ProviderAIS]).(*backend.AISBackendProvider)
 		t.writeJSON(w, r, aisCloud.GetInfo(clusterConf), httpdaeWhat)
 	default:
 		t.httprunner.httpdaeget(w, r)
@@ -879,7 +881,7 @@ func (t *targetrunner) receiveConfig(newConfig *globalConfig, msg *aisMsg, paylo
 		// NOTE: apply the entire config: add new and _refresh_ existing
 		aisConf, ok := newConfig.Backend.ProviderConf(cmn.ProviderAIS)
 		cos.Assert(ok)
-		aisCloud := t.backend[cmn.ProviderAIS].(*backend.AISBackendProvider)
+		aisCloud := backend.Unwrap(t.backend[cmn.ProviderAIS]).(*backend.AISBackendProvider)
 		err = aisCloud.Apply(aisConf, msg.Action)
 		if err != nil {
 			glog.Errorf("%s: %v - proceeding anyway...", t.si, err)
@@ -0,0 +1,57 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/dbdriver"
+)
+
+// durable queues of the objects to be written elsewhere: write-back and replication (see xs/dqueue.go)
+
+type dqLoader interface {
+	Load() error
+}
+
+// Renews the xactions for the buckets that have queued objects, and loads the objects that are
+// due to be written. The records of the buckets that no longer exist get dropped - same as
+// the records of the buckets for which `renew` returns nil.
+func (t *targetrunner) dqHousekeep(buckets func(dbdriver.Driver) ([]cmn.Bck, error),
+	drop func(dbdriver.Driver, cmn.Bck) (int, error), renew func(*cluster.Bck) (dqLoader, error)) {
+	bcks, err := buckets(t.db)
+	if err != nil {
+		glog.Errorf("%s: %v", t.si, err)
+		return
+	}
+	for i := range bcks {
+		bck := cluster.NewBckEmbed(bcks[i])
+		if err := bck.Init(t.owner.bmd); err != nil {
+			if cmn.IsErrBckNotFound(err) || cmn.IsErrRemoteBckNotFound(err) {
+				n, errDrop := drop(t.db, bck.Bck)
+				glog.Errorf("%s: %s does not exist, dropped %d queued object%s (err: %v)",
+					t.si, bck, n, cos.Plural(n), errDrop)
+			} else {
+				glog.Errorf("%s: %v", t.si, err)
+			}
+			continue
+		}
+		xq, err := renew(bck)
+		if err != nil {
+			glog.Errorf("%s: %s: %v", t.si, bck, err)
+			continue
+		}
+		if xq == nil {
+			n, errDrop := drop(t.db, bck.Bck)
+			glog.Warningf("%s: %s: dropped %d queued object%s (err: %v)", t.si, bck, n, cos.Plural(n), errDrop)
+			continue
+		}
+		if err := xq.Load(); err != nil {
+			glog.Errorf("%s: %v", xq, err)
+		}
+	}
+}
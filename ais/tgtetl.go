@@ -11,16 +11,13 @@ import (
 
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
-	"github.com/NVIDIA/aistore/cmn/k8s"
 	"github.com/NVIDIA/aistore/etl"
+	jsoniter "github.com/json-iterator/go"
 )
 
 // [METHOD] /v1/etl
+// (K8s, unless the ETL runs locally or in-process, is checked upon init - see etl.InitSpec)
 func (t *targetrunner) etlHandler(w http.ResponseWriter, r *http.Request) {
-	if err := k8s.Detect(); err != nil {
-		t.writeErrSilent(w, r, err)
-		return
-	}
 	switch {
 	case r.Method == http.MethodPost:
 		apiItems, err := t.checkRESTItems(w, r, 1, false, cmn.URLPathETL.L)
@@ -109,11 +106,17 @@ func (t *targetrunner) stopETL(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (t *targetrunner) doETL(w http.ResponseWriter, r *http.Request, uuid string, bck *cluster.Bck, objName string) {
+// GET /v1/objects/<bucket>/<object>?uuid=<ETL ID>[&etl_args=<arguments>]
+func (t *targetrunner) doETL(w http.ResponseWriter, r *http.Request, uuid, args string, bck *cluster.Bck,
+	objName string) {
 	var (
 		comm etl.Communicator
 		err  error
 	)
+	if err = cmn.ValidateETLArgs(args); err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
 	comm, err = etl.GetCommunicator(uuid, t.si)
 	if err != nil {
 		if cmn.IsErrNotFound(err) {
@@ -127,7 +130,7 @@ func (t *targetrunner) doETL(w http.ResponseWriter, r *http.Request, uuid string
 		t.writeErr(w, r, err)
 		return
 	}
-	if err := comm.OnlineTransform(w, r, bck, objName); err != nil {
+	if err := etl.Transform(t, t.statsT, w, r, comm, bck, objName, args); err != nil {
 		t.writeErr(w, r, cmn.NewErrETL(&cmn.ETLErrorContext{
 			UUID:    uuid,
 			PodName: comm.PodName(),
@@ -136,6 +139,32 @@ func (t *targetrunner) doETL(w http.ResponseWriter, r *http.Request, uuid string
 	}
 }
 
+// GET /v1/objects/<bucket>/<object>?etl_pipeline=<JSON-encoded cmn.ETLPipeline>
+func (t *targetrunner) doETLPipeline(w http.ResponseWriter, r *http.Request, value string, bck *cluster.Bck,
+	objName string) {
+	var stages cmn.ETLPipeline
+	if r.URL.Query().Get(cmn.URLParamETLArgs) != "" {
+		t.writeErrf(w, r, "%s: arguments must be specified for each stage of the ETL pipeline separately",
+			cmn.URLParamETLArgs)
+		return
+	}
+	if err := jsoniter.UnmarshalFromString(value, &stages); err != nil {
+		t.writeErrf(w, r, "invalid %s=%q: %v", cmn.URLParamETLPipeline, value, err)
+		return
+	}
+	if err := stages.Validate(); err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	if err := etl.TransformPipeline(t, w, stages, bck, objName); err != nil {
+		if cmn.IsErrNotFound(err) {
+			t.writeErr(w, r, err, http.StatusNotFound)
+			return
+		}
+		t.writeErr(w, r, cmn.NewErrETL(&cmn.ETLErrorContext{ETLName: stages.String()}, err.Error()))
+	}
+}
+
 func (t *targetrunner) listETL(w http.ResponseWriter, r *http.Request) {
 	if _, err := t.checkRESTItems(w, r, 0, false, cmn.URLPathETLList.L); err != nil {
 		return
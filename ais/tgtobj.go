@@ -30,6 +30,7 @@ import (
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/reb"
+	"github.com/NVIDIA/aistore/sse"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/xreg"
@@ -51,6 +52,11 @@ type (
 		owt        cmn.OWT       // object write transaction enum { OwtPut, ..., OwtGet* }
 		skipEC     bool          // true: do not erasure-encode when finalizing
 		skipVC     bool          // true: do not try to load existing version and do not compare checksums
+		sse        bool          // true: encrypt regardless of the bucket's `encryption` (e.g., S3 SSE request)
+		sseKeyID   string        // (sse) master key to use; empty: KMS default
+		encrypted  bool          // workFQN contains ciphertext (see tgtsse.go)
+		wb         bool          // write-back: the remote backend is written asynchronously (see tgtwriteback.go)
+		rp         bool          // the change is logged to be replicated (see tgtreplicate.go)
 	}
 
 	getObjInfo struct {
@@ -170,22 +176,37 @@ func (poi *putObjInfo) finalize() (errCode int, err error) {
 		}
 	}
 	poi.t.putMirror(poi.lom)
+	if poi.wb {
+		poi.t.writeBack(poi.lom)
+	}
+	if poi.rp {
+		poi.t.replicate(poi.lom)
+	}
 	return
 }
 
 // poi.workFQN => LOM
 func (poi *putObjInfo) tryFinalize() (errCode int, err error) {
 	var (
-		lom = poi.lom
-		bck = lom.Bck()
-		bmd = poi.t.owner.bmd.Get()
+		lom     = poi.lom
+		bck     = lom.Bck()
+		bmd     = poi.t.owner.bmd.Get()
+		remoted bool
 	)
 	// remote versioning
 	if bck.IsRemote() && (poi.owt == cmn.OwtPut || poi.owt == cmn.OwtFinalize) {
-		errCode, err = poi.putRemote()
-		if err != nil {
-			glog.Errorf("PUT %s: %v", lom, err)
-			return
+		if lom.Bprops().WritePolicy.IsWriteBack() {
+			poi.wb = true
+		} else {
+			// quotas: check before writing to the remote backend (and only account once written)
+			if _, errCode, err = poi.quota(false /*locked*/, true /*limit*/); err != nil {
+				return
+			}
+			if errCode, err = poi.putRemote(); err != nil {
+				glog.Errorf("PUT %s: %v", lom, err)
+				return
+			}
+			remoted = true
 		}
 	}
 	if _, present := bmd.Get(bck); !present {
@@ -211,8 +232,24 @@ func (poi *putObjInfo) tryFinalize() (errCode int, err error) {
 		defer lom.Unlock(true)
 	}
 
+	// retention (WORM)
+	if errCode, err = poi.retention(); err != nil {
+		return
+	}
+	// quotas
+	var prevSize int64
+	if prevSize, errCode, err = poi.quota(true /*locked*/, !remoted /*limit*/); err != nil {
+		return
+	}
+
 	// ais versioning
-	if bck.IsAIS() && lom.VersionConf().Enabled {
+	if bck.IsAIS() && lom.VersionConf().Retain && poi.owt == cmn.OwtPut {
+		// keep the current version (see cluster/lversion.go)
+		if err = lom.Retain(); err != nil {
+			err = fmt.Errorf(cmn.FmtErrFailed, poi.t.si, "retain prior version of", lom, err)
+			return
+		}
+	} else if bck.IsAIS() && lom.VersionConf().Enabled {
 		if poi.owt == cmn.OwtPut {
 			if poi.skipVC {
 				err = lom.IncVersion()
@@ -224,6 +261,10 @@ func (poi *putObjInfo) tryFinalize() (errCode int, err error) {
 			}
 		}
 	}
+	if err = poi.sseWorkfile(); err != nil {
+		err = fmt.Errorf(cmn.FmtErrFailed, poi.t.si, "encrypt", lom, err)
+		return
+	}
 	if err = cos.Rename(poi.workFQN, lom.FQN); err != nil {
 		err = fmt.Errorf(cmn.FmtErrFailed, poi.t.si, "rename", lom, err)
 		return
@@ -234,11 +275,33 @@ func (poi *putObjInfo) tryFinalize() (errCode int, err error) {
 			glog.Errorf("PUT %s: failed to delete old copies [%v], proceeding to PUT anyway...", lom, errdc)
 		}
 	}
+	lom.RemoveETLCache()
 	if lom.AtimeUnix() == 0 {
 		lom.SetAtimeUnix(poi.atime.UnixNano())
 		debug.Assert(lom.AtimeUnix() != 0)
 	}
-	err = lom.Persist()
+	if poi.owt != cmn.OwtMigrate {
+		lom.SetLastModified(time.Now()) // (migrated objects keep their original time of writing)
+	}
+	if poi.owt == cmn.OwtMigrate && lom.WriteBackPending() {
+		poi.wb = true // (queued on the target that has sent it - see xs/writeback.go)
+	}
+	if poi.wb {
+		if err = poi.queueWriteBack(); err != nil {
+			err = fmt.Errorf(cmn.FmtErrFailed, poi.t.si, "queue write-back of", lom, err)
+			return
+		}
+	}
+	if op := poi.rpOp(); op != "" {
+		if err = poi.logReplication(op); err != nil {
+			err = fmt.Errorf(cmn.FmtErrFailed, poi.t.si, "log replication of", lom, err)
+			return
+		}
+		poi.rp = true
+	}
+	if err = lom.Persist(); err == nil {
+		poi.t.quotas.put(lom, prevSize)
+	}
 	return
 }
 
@@ -278,9 +341,20 @@ func (poi *putObjInfo) writeToFile() (err error) {
 			store *cos.CksumHash // store with LOM
 			given *cos.CksumHash // compute additionally
 			expct *cos.Cksum     // and validate against `expct` if required/available
+			plain *cos.CksumHash // (encryption) plaintext checksum
 		}{}
-		conf = poi.lom.CksumConf()
+		conf   = poi.lom.CksumConf()
+		sseMD  *sse.ObjMD
+		sseKey []byte
+		encw   *sse.Writer
 	)
+	// encrypt while writing unless the object is destined for a remote backend (see tgtsse.go)
+	if !poi.lom.Bck().IsRemote() || (poi.owt != cmn.OwtPut && poi.owt != cmn.OwtFinalize) {
+		if sseMD, sseKey, err = poi.sseInit(); err != nil {
+			cos.Close(poi.r)
+			return
+		}
+	}
 	if lmfh, err = poi.lom.CreateFile(poi.workFQN); err != nil {
 		return
 	}
@@ -332,6 +406,20 @@ func (poi *putObjInfo) writeToFile() (err error) {
 		writers = append(writers, cksums.given.H)
 	}
 write:
+	if sseMD != nil {
+		// checksums (above) are computed over the plaintext, while the one that
+		// is stored with LOM must be the checksum of the ciphertext
+		var cw io.Writer = writer
+		if cksums.store != nil {
+			cksums.plain = cksums.store
+			cksums.store = cos.NewCksumHash(conf.Type)
+			cw = cos.NewWriterMulti(cksums.store.H, writer)
+		}
+		if encw, err = sse.NewWriter(cw, sseKey); err != nil {
+			return
+		}
+		writer = encw
+	}
 	if len(writers) == 0 {
 		written, err = io.CopyBuffer(writer, reader, buf)
 	} else {
@@ -353,6 +441,13 @@ write:
 			return
 		}
 	}
+	if encw != nil {
+		if err = encw.Close(); err != nil {
+			return
+		}
+		poi.sseFinalize(sseMD, cksums.plain, written)
+		written = encw.Written()
+	}
 	cos.Close(lmfh)
 	lmfh = nil
 	// ok
@@ -416,7 +511,8 @@ do:
 	}
 	// exists && remote|cloud: check ver if requested
 	if !coldGet && goi.lom.Bck().IsRemote() {
-		if goi.lom.VersionConf().ValidateWarmGet {
+		// (objects pending write-back are yet to be written remotely - nothing to compare with)
+		if goi.lom.VersionConf().ValidateWarmGet && !goi.lom.WriteBackPending() {
 			var equal bool
 			goi.lom.Unlock(false)
 			if equal, errCode, err = goi.t.CompareObjects(goi.ctx, goi.lom); err != nil {
@@ -457,10 +553,22 @@ do:
 			return
 		}
 		goi.lom.SetAtimeUnix(goi.started.UnixNano())
+		if goi.rangeCacheable() {
+			return goi.rangeGet()
+		}
 		// (will upgrade rlock => wlock)
-		if errCode, err = goi.t.GetCold(goi.ctx, goi.lom, cmn.OwtGet); err != nil {
+		if goi.coldStreamable() {
+			var done bool
+			if done, errCode, err = goi.coldStream(); done {
+				if err == nil {
+					goi.t.partials.del(goi.lom.Uname())
+				}
+				return
+			}
+		} else if errCode, err = goi.t.GetCold(goi.ctx, goi.lom, cmn.OwtGet); err != nil {
 			return
 		}
+		goi.t.partials.del(goi.lom.Uname())
 	}
 
 	// read locally and stream back
@@ -738,10 +846,21 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, errCode int, err erro
 
 	var (
 		rrange     *cmn.HTTPRange
-		size       = goi.lom.SizeBytes()
-		cksumConf  = goi.lom.CksumConf()
+		src        objReader = lmfh
+		oa                   = goi.lom.ObjAttrs()
+		cksumConf            = goi.lom.CksumConf()
 		cksumRange bool
 	)
+	// decrypt unless GFN (that migrates encrypted objects as is)
+	if goi.lom.IsEncrypted() && !goi.isGFN {
+		if src, err = goi.lom.NewSSEReader(lmfh); err != nil {
+			err = fmt.Errorf(cmn.FmtErrFailed, goi.t.si, "decrypt", goi.lom, err)
+			errCode = http.StatusInternalServerError
+			return
+		}
+		oa = goi.lom.PlainAttrs()
+	}
+	size := oa.Size
 	// parse, validate, set response header
 	if hdr != nil {
 		// read range
@@ -758,15 +877,15 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, errCode int, err erro
 				size = rrange.Length // Content-Length
 			}
 		}
-		goi.lom.ObjAttrs().ToHeader(hdr)
+		oa.ToHeader(hdr)
 	}
 
 	// set reader
 	w := goi.w
 	if rrange == nil {
-		reader = lmfh
+		reader = src
 		if goi.archive.filename != "" {
-			csl, err = goi.freadArch(lmfh)
+			csl, err = goi.freadArch(src, size)
 			if err != nil {
 				if cmn.IsErrNotFound(err) {
 					errCode = http.StatusNotFound
@@ -785,10 +904,12 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, errCode int, err erro
 			// NOTE: hide `ReadFrom` of the `http.ResponseWriter` (in re: sendfile)
 			w = cos.WriterOnly{Writer: goi.w}
 			buf, slab = goi.t.gmm.AllocSize(size)
+		} else if reader != io.Reader(lmfh) {
+			buf, slab = goi.t.gmm.AllocSize(size) // no sendfile
 		}
 	} else {
 		buf, slab = goi.t.gmm.AllocSize(rrange.Length)
-		reader = io.NewSectionReader(lmfh, rrange.Start, rrange.Length)
+		reader = io.NewSectionReader(src, rrange.Start, rrange.Length)
 		if cksumRange {
 			var (
 				cksum *cos.CksumHash
@@ -886,6 +1007,10 @@ func (aoi *appendObjInfo) appendObject() (newHandle string, errCode int, err err
 	filePath := aoi.hi.filePath
 	switch aoi.op {
 	case cmn.AppendOp:
+		if err = aoi.t.quotas.check(aoi.lom.Bck(), aoi.size, 0); err != nil {
+			errCode = http.StatusInsufficientStorage
+			return
+		}
 		var f *os.File
 		if filePath == "" {
 			filePath = fs.CSM.Gen(aoi.lom, fs.WorkfileType, fs.WorkfileAppend)
@@ -1070,6 +1195,7 @@ func (coi *copyObjInfo) copyObject(src *cluster.LOM, objNameTo string) (size int
 	}
 
 	// unless overwriting the source w-lock the destination (see `exclusive`)
+	prevSize := int64(-1)
 	if src.Uname() != dst.Uname() {
 		dst.Lock(true)
 		defer dst.Unlock(true)
@@ -1077,12 +1203,26 @@ func (coi *copyObjInfo) copyObject(src *cluster.LOM, objNameTo string) (size int
 			if src.EqCksum(dst.Checksum()) {
 				return
 			}
+			if err = dst.CheckRetention(false /*bypass*/); err != nil {
+				return
+			}
+			prevSize = dst.SizeBytes()
 		} else if cmn.IsErrBucketNought(err) {
 			return
 		}
+		growth, objs := src.SizeBytes(), int64(1)
+		if prevSize >= 0 {
+			growth, objs = growth-prevSize, 0
+		}
+		if err = coi.t.quotas.check(dst.Bck(), growth, objs); err != nil {
+			return
+		}
 	}
 	dst2, err2 := src.Copy2FQN(dst.FQN, coi.Buf)
 	if err2 == nil {
+		if src.Uname() != dst.Uname() {
+			coi.t.quotas.put(dst2, prevSize)
+		}
 		size = src.SizeBytes()
 		if coi.finalize {
 			coi.t.putMirror(dst2)
@@ -1204,7 +1344,10 @@ func (coi *copyObjInfo) putRemote(lom *cluster.LOM, params *cluster.SendToParams
 		// NOTE: not the best way to determine whether `lom` is an object or just
 		// a regular file. Ideally, the parameter shouldn't be `lom` at all,
 		// rather something more general like `cluster.CT`.
-		var reader cos.ReadOpenCloser
+		var (
+			reader cos.ReadOpenCloser
+			oah    cmn.ObjAttrsHolder = lom
+		)
 		if !coi.promoteFile {
 			lom.Lock(false)
 			if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
@@ -1215,12 +1358,23 @@ func (coi *copyObjInfo) putRemote(lom *cluster.LOM, params *cluster.SendToParams
 				lom.Unlock(false)
 				return lom.SizeBytes(), nil
 			}
-			fh, err := cos.NewFileHandle(lom.FQN)
+			var (
+				fh  cos.ReadOpenCloser
+				err error
+			)
+			if params.DM != nil && params.DM.OWT() != cmn.OwtMigrate {
+				// not migrating: the destination stores a new object and
+				// encrypts it in accordance with its own bucket's props
+				fh, err = lom.OpenPlain()
+				oah = lom.PlainAttrs()
+			} else {
+				fh, err = cos.NewFileHandle(lom.FQN)
+			}
 			if err != nil {
 				lom.Unlock(false)
 				return 0, fmt.Errorf(cmn.FmtErrFailed, coi.t.Snode(), "open", lom.FQN, err)
 			}
-			size = lom.SizeBytes()
+			size = oah.SizeBytes()
 			reader = cos.NewDeferROC(fh, func() { lom.Unlock(false) })
 		} else {
 			debug.Assert(!coi.DryRun)
@@ -1237,7 +1391,7 @@ func (coi *copyObjInfo) putRemote(lom *cluster.LOM, params *cluster.SendToParams
 			reader = fh
 		}
 		params.Reader = reader
-		params.ObjAttrs = lom
+		params.ObjAttrs = oah
 	} else {
 		if params.Reader, params.ObjAttrs, err = coi.DP.Reader(lom); err != nil {
 			return
@@ -1356,12 +1510,23 @@ func (aaoi *appendArchObjInfo) appendObject() (errCode int, err error) {
 	if aaoi.mime != cos.ExtTar {
 		return http.StatusBadRequest, fmt.Errorf("append is supported only for %s archives", cos.ExtTar)
 	}
+	if aaoi.lom.IsEncrypted() {
+		return http.StatusBadRequest, fmt.Errorf(cmn.FmtErrUnsupported, aaoi.t.Snode(), "appending to encrypted archives")
+	}
+	if err := aaoi.lom.CheckRetention(false /*bypass*/); err != nil {
+		return http.StatusForbidden, err
+	}
+	if err := aaoi.t.quotas.check(aaoi.lom.Bck(), aaoi.size, 0); err != nil {
+		return http.StatusInsufficientStorage, err
+	}
+	prevSize := aaoi.lom.SizeBytes()
 	workFQN, err := aaoi.begin()
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 	if err = aaoi.appendToArch(workFQN); err == nil {
 		if err = aaoi.finalize(workFQN); err == nil {
+			aaoi.t.quotas.add(aaoi.lom.Bck(), aaoi.lom.SizeBytes()-prevSize, 0)
 			return 0, nil
 		}
 	}
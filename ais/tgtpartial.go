@@ -0,0 +1,572 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Range-based partial caching (bucket property `cold_get.range_caching`):
+// - range GET of a remote object that is not cached reads from the backend (cluster.RangeReader)
+//   only those parts of the range that were not read before, and stores them as workfiles
+//   (extents) - aligned to partialAlign, to reduce fragmentation;
+// - the extents belong to the version of the object (as per HEAD) that is stored along with
+//   them and pinned when reading the backend; once the object changes, all extents get removed;
+// - the partial object is locked only to look up, reserve, and add extents - never while
+//   reading the backend or writing to the client; concurrent range reads wait for
+//   the extents that are being fetched rather than fetching them again;
+// - once the extents cover the entire object, they get assembled into the object which is
+//   then finalized (cached) - same as cold GET;
+// - extents are tracked in memory by the target that "owns" the object. Those that were not
+//   accessed for partialIdleTime get removed, as do the extents of objects that were cold-GET
+//   in their entirety. Extents that were not removed prior to the target's restart get
+//   eventually removed by the space cleanup (as belonging to a different PID).
+
+const (
+	partialAlign    = cos.MiB
+	partialIdleTime = time.Hour
+	maxPartialObjs  = 4096
+	maxPartialRetry = 3
+)
+
+type (
+	extent struct {
+		ready   chan struct{} // closed once fetched (or failed)
+		fqn     string
+		off     int64
+		size    int64
+		done    bool // fetched
+		dropped bool // removed while being fetched
+	}
+	partialObj struct {
+		oa      cmn.ObjAttrs // remote object (as per HEAD) - the version the extents belong to
+		extents []*extent    // sorted by offset, non-overlapping (including those being fetched)
+		cached  int64        // total size of the fetched extents
+		atime   atomic.Int64 // last access (mono)
+		mu      sync.Mutex
+		headed  bool
+		removed bool
+	}
+	partials struct {
+		t  *targetrunner
+		m  map[string]*partialObj // by object uname
+		mu sync.Mutex
+	}
+)
+
+// the object has changed, or its extents have been removed, while reading
+var errPartialChanged = errors.New("partially cached object has changed")
+
+func (goi *getObjInfo) rangeCacheable() bool {
+	if _, ok := goi.w.(http.ResponseWriter); !ok {
+		return false
+	}
+	if goi.ranges.Range == "" || goi.archive.filename != "" || goi.isGFN || !goi.lom.Bck().IsRemote() {
+		return false
+	}
+	// cached extents are plaintext workfiles
+	if !goi.lom.Bprops().ColdGet.RangeCaching || goi.lom.Bprops().Encryption.Enabled {
+		return false
+	}
+	_, ok := goi.t.Backend(goi.lom.Bck()).(cluster.RangeReader)
+	return ok
+}
+
+// Executes range GET of the remote object via cached extents and, if need be, the backend.
+// The caller must hold the read lock that gets released upon return.
+func (goi *getObjInfo) rangeGet() (errCode int, err error) {
+	var (
+		p       *partialObj
+		covered bool
+		lom     = goi.lom
+	)
+	for i := 0; ; i++ {
+		p, covered, errCode, err = goi._rangeGet()
+		if err != errPartialChanged || i >= maxPartialRetry {
+			break
+		}
+	}
+	if err == errPartialChanged {
+		errCode = http.StatusServiceUnavailable
+	}
+	if covered {
+		goi.assemble(p) // (releases the lock)
+		return
+	}
+	lom.Unlock(false)
+	return
+}
+
+func (goi *getObjInfo) _rangeGet() (p *partialObj, covered bool, errCode int, err error) {
+	var (
+		rrange *cmn.HTTPRange
+		lom    = goi.lom
+		t      = goi.t
+		hdr    = goi.w.(http.ResponseWriter).Header()
+	)
+	if p, errCode, err = t.partials.get(goi.ctx, lom); err != nil {
+		return
+	}
+	p.mu.Lock()
+	oa := p.oa
+	p.mu.Unlock()
+	if rrange, errCode, err = goi.parseRange(hdr, oa.Size); err != nil {
+		return
+	}
+	if rrange == nil {
+		rrange = &cmn.HTTPRange{Start: 0, Length: oa.Size}
+	}
+	fetched, errCode, err := p.fetch(goi.ctx, t, lom, rrange, &oa)
+	if fetched > 0 {
+		t.statsT.Add(stats.GetColdSize, fetched)
+	}
+	if err != nil {
+		if errCode == 0 {
+			errCode = http.StatusInternalServerError
+		}
+		return
+	}
+	p.mu.Lock()
+	r, err := p.open(rrange, &oa)
+	p.mu.Unlock()
+	if err != nil {
+		if err != errPartialChanged {
+			t.fsErr(err, lom.FQN)
+			errCode = http.StatusInternalServerError
+		}
+		return
+	}
+	// transmit
+	oa.ToHeader(hdr)
+	hdr.Set(cmn.HdrContentLength, strconv.FormatInt(rrange.Length, 10))
+	buf, slab := t.gmm.AllocSize(rrange.Length)
+	written, err := io.CopyBuffer(cos.WriterOnly{Writer: goi.w}, r, buf)
+	slab.Free(buf)
+	r.Close()
+
+	p.mu.Lock()
+	if covered = !p.removed && p.cached == p.oa.Size; covered {
+		p.removed = true
+		t.partials.unlink(lom.Uname(), p)
+	}
+	p.mu.Unlock()
+
+	if err != nil {
+		glog.Errorf(cmn.FmtErrFailed, t.si, "GET", lom, err)
+		err = errSendingResp
+	} else {
+		t.statsT.AddMany(
+			cos.NamedVal64{Name: stats.GetThroughput, Value: written},
+			cos.NamedVal64{Name: stats.GetLatency, Value: mono.SinceNano(goi.nanotim)},
+			cos.NamedVal64{Name: stats.GetCount, Value: 1},
+		)
+	}
+	return
+}
+
+// extents => object
+func (goi *getObjInfo) assemble(p *partialObj) {
+	var (
+		lom = goi.lom
+		t   = goi.t
+	)
+	defer p.rm()
+	for lom.UpgradeLock() {
+		// cold-GET by another goroutine
+		if err := lom.Load(true /*cache it*/, true /*locked*/); err != nil {
+			glog.Errorf("%s: %s load err: %v - retrying...", t.si, lom, err)
+			continue
+		}
+		lom.Unlock(false)
+		return
+	}
+	p.mu.Lock()
+	r, err := p.open(&cmn.HTTPRange{Start: 0, Length: p.oa.Size}, &p.oa)
+	p.mu.Unlock()
+	if err != nil {
+		lom.Unlock(true)
+		glog.Errorf(cmn.FmtErrFailed, t.si, "assemble", lom, err)
+		return
+	}
+	lom.CopyAttrs(&p.oa, true /*skip cksum*/)
+	params := cluster.PutObjectParams{
+		Tag:    fs.WorkfileColdget,
+		Reader: r,
+		OWT:    cmn.OwtGet,
+		Atime:  goi.started,
+	}
+	if v, ok := p.oa.GetCustomKey(cmn.MD5ObjMD); ok {
+		params.Cksum = cos.NewCksum(cos.ChecksumMD5, v)
+	}
+	if err = t.PutObject(lom, params); err != nil {
+		lom.Unlock(true)
+		glog.Errorf(cmn.FmtErrFailed, t.si, "assemble", lom, err)
+		return
+	}
+	if err = lom.Load(true /*cache it*/, true /*locked*/); err != nil {
+		glog.Errorf("%s: unexpected failure to load %s: %v", t.si, lom.FullName(), err)
+	}
+	lom.Unlock(true)
+	t.statsT.Add(stats.GetColdCount, 1)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: assembled %s from %d extent(s)", t.si, lom, len(p.extents))
+	}
+}
+
+//////////////
+// partials //
+//////////////
+
+func (ps *partials) init(t *targetrunner) {
+	ps.t = t
+	ps.m = make(map[string]*partialObj)
+}
+
+// HEADs the remote object (if need be) outside the partial object's lock;
+// removes all extents if the object has changed
+func (ps *partials) get(ctx context.Context, lom *cluster.LOM) (p *partialObj, errCode int, err error) {
+	uname := lom.Uname()
+	ps.mu.Lock()
+	if p = ps.m[uname]; p == nil || p.isRemoved() {
+		p = &partialObj{}
+		ps.m[uname] = p
+		if len(ps.m) > maxPartialObjs {
+			ps.evict()
+		}
+	}
+	ps.mu.Unlock()
+
+	p.atime.Store(mono.NanoTime())
+	p.mu.Lock()
+	headed := p.headed
+	p.mu.Unlock()
+	if headed && !lom.VersionConf().ValidateWarmGet {
+		return
+	}
+	var oa *cmn.ObjAttrs
+	if oa, errCode, err = ps.t.Backend(lom.Bck()).HeadObj(ctx, lom); err != nil {
+		return
+	}
+	p.mu.Lock()
+	if p.headed && !p.sameObj(oa) {
+		glog.Warningf("%s: %s has changed - removing %d cached extent(s)", ps.t.si, lom, len(p.extents))
+		p.rmExtents()
+	}
+	p.oa, p.headed = *oa, true
+	p.mu.Unlock()
+	return
+}
+
+// removes the least recently accessed (under lock)
+func (ps *partials) evict() {
+	var (
+		oldest string
+		atime  int64
+	)
+	for uname, p := range ps.m {
+		if a := p.atime.Load(); oldest == "" || a < atime {
+			oldest, atime = uname, a
+		}
+	}
+	p := ps.m[oldest]
+	delete(ps.m, oldest)
+	go p.rm()
+}
+
+func (ps *partials) unlink(uname string, p *partialObj) {
+	ps.mu.Lock()
+	if ps.m[uname] == p {
+		delete(ps.m, uname)
+	}
+	ps.mu.Unlock()
+}
+
+// upon cold GET: remove extents (if any) of the object that is now cached
+func (ps *partials) del(uname string) {
+	ps.mu.Lock()
+	p, ok := ps.m[uname]
+	if ok {
+		delete(ps.m, uname)
+	}
+	ps.mu.Unlock()
+	if ok {
+		go p.rm()
+	}
+}
+
+func (ps *partials) housekeep() time.Duration {
+	var idle []*partialObj
+	ps.mu.Lock()
+	for uname, p := range ps.m {
+		if mono.Since(p.atime.Load()) > partialIdleTime {
+			idle = append(idle, p)
+			delete(ps.m, uname)
+		}
+	}
+	ps.mu.Unlock()
+	if len(idle) > 0 {
+		glog.Infof("%s: removing extents of %d idle partially cached object(s)", ps.t.si, len(idle))
+		go func() {
+			for _, p := range idle {
+				p.rm()
+			}
+		}()
+	}
+	return partialIdleTime / 4
+}
+
+////////////////
+// partialObj //
+////////////////
+
+// Reads from the backend all parts of the range that are neither cached nor being fetched,
+// and waits for those that are. The extents are reserved (and added) under lock - the reading
+// itself is done without it and is pinned to the given version of the object.
+func (p *partialObj) fetch(ctx context.Context, t *targetrunner, lom *cluster.LOM, rrange *cmn.HTTPRange,
+	oa *cmn.ObjAttrs) (fetched int64, errCode int, err error) {
+	var (
+		rr    = t.Backend(lom.Bck()).(cluster.RangeReader)
+		start = rrange.Start / partialAlign * partialAlign
+		end   = cos.MinI64(cos.CeilAlignInt64(rrange.Start+rrange.Length, partialAlign), oa.Size)
+	)
+	for {
+		var reserved, pending []*extent
+		p.mu.Lock()
+		if p.removed || !p.sameObj(oa) {
+			p.mu.Unlock()
+			return fetched, 0, errPartialChanged
+		}
+		pending = p.pending(start, end)
+		for _, gap := range p.gaps(start, end) {
+			e := &extent{off: gap.Start, size: gap.Length, ready: make(chan struct{})}
+			p.add(e)
+			reserved = append(reserved, e)
+		}
+		p.mu.Unlock()
+		if len(reserved) == 0 && len(pending) == 0 {
+			return
+		}
+
+		for _, e := range reserved {
+			if err == nil {
+				e.fqn, errCode, err = p.fetchExtent(ctx, t, rr, lom, oa, e.off, e.size)
+			}
+			p.mu.Lock()
+			if err == nil && !e.dropped {
+				e.done = true
+				p.cached += e.size
+				fetched += e.size
+			} else {
+				p.del(e)
+				if err == nil {
+					err = errPartialChanged
+				}
+			}
+			p.mu.Unlock()
+			close(e.ready)
+		}
+		if errCode == http.StatusPreconditionFailed {
+			glog.Warningf("%s: %s has changed - removing %d cached extent(s)", t.si, lom, len(p.extents))
+			p.mu.Lock()
+			p.rmExtents()
+			p.headed = false
+			p.mu.Unlock()
+			return fetched, 0, errPartialChanged
+		}
+		if err != nil {
+			return
+		}
+		for _, e := range pending {
+			select {
+			case <-e.ready:
+			case <-ctx.Done():
+				return fetched, 0, ctx.Err()
+			}
+		}
+	}
+}
+
+func (p *partialObj) fetchExtent(ctx context.Context, t *targetrunner, rr cluster.RangeReader, lom *cluster.LOM,
+	oa *cmn.ObjAttrs, off, size int64) (fqn string, errCode int, err error) {
+	r, errCode, err := rr.GetObjReaderRange(ctx, lom, off, size, oa)
+	if err != nil {
+		return "", errCode, fmt.Errorf(cmn.FmtErrFailed, t.si, "read range of", lom.FullName(), err)
+	}
+	defer cos.Close(r)
+	fqn = fs.CSM.Gen(lom, fs.WorkfileType, fs.WorkfilePartial)
+	fh, err := lom.CreateFile(fqn)
+	if err != nil {
+		t.fsErr(err, fqn)
+		return "", 0, err
+	}
+	buf, slab := t.gmm.AllocSize(size)
+	n, err := io.CopyBuffer(fh, io.LimitReader(r, size), buf)
+	slab.Free(buf)
+	if erc := fh.Close(); erc != nil && err == nil {
+		err = erc
+	}
+	if err == nil && n != size {
+		err = fmt.Errorf("%s: read range [%d, %d) of %s: got %d bytes", t.si, off, off+size, lom.FullName(), n)
+	}
+	if err != nil {
+		if errRm := cos.RemoveFile(fqn); errRm != nil {
+			glog.Errorf("Nested (%v): failed to remove %s, err: %v", err, fqn, errRm)
+		}
+		return "", 0, err
+	}
+	return fqn, 0, nil
+}
+
+// extents within [start, end) that are still being fetched
+func (p *partialObj) pending(start, end int64) (pending []*extent) {
+	for _, e := range p.extents {
+		if !e.done && e.off+e.size > start && e.off < end {
+			pending = append(pending, e)
+		}
+	}
+	return
+}
+
+// parts of [start, end) that are not covered by the extents
+func (p *partialObj) gaps(start, end int64) (gaps []cmn.HTTPRange) {
+	off := start
+	for _, e := range p.extents {
+		if off >= end {
+			break
+		}
+		if e.off+e.size <= off {
+			continue
+		}
+		if e.off > off {
+			gaps = append(gaps, cmn.HTTPRange{Start: off, Length: cos.MinI64(e.off, end) - off})
+		}
+		off = e.off + e.size
+	}
+	if off < end {
+		gaps = append(gaps, cmn.HTTPRange{Start: off, Length: end - off})
+	}
+	return
+}
+
+// adds (fetched or reserved) extent; only the fetched ones count as cached
+func (p *partialObj) add(e *extent) {
+	i := sort.Search(len(p.extents), func(i int) bool { return p.extents[i].off > e.off })
+	p.extents = append(p.extents, nil)
+	copy(p.extents[i+1:], p.extents[i:])
+	p.extents[i] = e
+	if e.done {
+		p.cached += e.size
+	}
+}
+
+// removes extent that failed to fetch, or was dropped while being fetched
+func (p *partialObj) del(e *extent) {
+	for i, x := range p.extents {
+		if x == e {
+			p.extents = append(p.extents[:i], p.extents[i+1:]...)
+			break
+		}
+	}
+	if e.fqn != "" {
+		if err := cos.RemoveFile(e.fqn); err != nil {
+			glog.Errorf("failed to remove %s: %v", e.fqn, err)
+		}
+	}
+}
+
+// opens the extents that cover a given range (under lock) - the caller then reads them
+// without the lock, given that the extents are removed by unlinking the files
+func (p *partialObj) open(rrange *cmn.HTTPRange, oa *cmn.ObjAttrs) (io.ReadCloser, error) {
+	var (
+		end     = rrange.Start + rrange.Length
+		off     = rrange.Start
+		readers = make([]io.Reader, 0, 2)
+		mr      = &mptReader{}
+	)
+	if !p.sameObj(oa) {
+		return nil, errPartialChanged
+	}
+	for _, e := range p.extents {
+		if e.off+e.size <= rrange.Start || e.off >= end {
+			continue
+		}
+		if !e.done || e.off > off {
+			mr.Close()
+			return nil, errPartialChanged
+		}
+		fh, err := os.Open(e.fqn)
+		if err != nil {
+			mr.Close()
+			return nil, err
+		}
+		mr.files = append(mr.files, fh)
+		off = cos.MaxI64(rrange.Start, e.off)
+		readers = append(readers, io.NewSectionReader(fh, off-e.off, cos.MinI64(end, e.off+e.size)-off))
+		off = e.off + e.size
+	}
+	if off < end {
+		mr.Close()
+		return nil, errPartialChanged
+	}
+	mr.Reader = io.MultiReader(readers...)
+	return mr, nil
+}
+
+// whether the extents belong to the given version of the object
+func (p *partialObj) sameObj(oa *cmn.ObjAttrs) bool {
+	if p.oa.Size != oa.Size || p.oa.Ver != oa.Ver {
+		return false
+	}
+	etag, _ := p.oa.GetCustomKey(cmn.ETag)
+	remETag, _ := oa.GetCustomKey(cmn.ETag)
+	return etag == remETag
+}
+
+func (p *partialObj) isRemoved() bool {
+	p.mu.Lock()
+	removed := p.removed
+	p.mu.Unlock()
+	return removed
+}
+
+func (p *partialObj) rm() {
+	p.mu.Lock()
+	p.removed = true
+	p.rmExtents()
+	p.mu.Unlock()
+}
+
+// removes all extents; those that are being fetched get dropped (and removed)
+// upon completion by their respective fetchers
+func (p *partialObj) rmExtents() {
+	for _, e := range p.extents {
+		if !e.done {
+			e.dropped = true
+			continue
+		}
+		if err := cos.RemoveFile(e.fqn); err != nil {
+			glog.Errorf("failed to remove %s: %v", e.fqn, err)
+		}
+	}
+	p.extents, p.cached = nil, 0
+}
@@ -0,0 +1,96 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestPartialExtents(t *testing.T) {
+	const content = "0123456789abcdefghijklmnopqrstuvwxyz"
+	var (
+		dir = t.TempDir()
+		p   = &partialObj{}
+	)
+	p.oa.Size = int64(len(content))
+	addExtent := func(off, size int64) {
+		fqn := filepath.Join(dir, content[off:off+size])
+		tassert.CheckFatal(t, os.WriteFile(fqn, []byte(content[off:off+size]), 0o644))
+		p.add(&extent{fqn: fqn, off: off, size: size, done: true})
+	}
+	readRange := func(start, length int64) string {
+		r, err := p.open(&cmn.HTTPRange{Start: start, Length: length}, &p.oa)
+		tassert.CheckFatal(t, err)
+		defer r.Close()
+		b, err := io.ReadAll(r)
+		tassert.CheckFatal(t, err)
+		return string(b)
+	}
+
+	gaps := p.gaps(0, p.oa.Size)
+	tassert.Errorf(t, reflect.DeepEqual(gaps, []cmn.HTTPRange{{Start: 0, Length: p.oa.Size}}), "unexpected gaps %v", gaps)
+
+	addExtent(20, 5)
+	addExtent(4, 6)
+	gaps = p.gaps(2, 30)
+	expected := []cmn.HTTPRange{{Start: 2, Length: 2}, {Start: 10, Length: 10}, {Start: 25, Length: 5}}
+	tassert.Errorf(t, reflect.DeepEqual(gaps, expected), "expected gaps %v, got %v", expected, gaps)
+	gaps = p.gaps(5, 8)
+	tassert.Errorf(t, len(gaps) == 0, "expected no gaps, got %v", gaps)
+
+	// being fetched: not cached, not readable, not a gap
+	pending := &extent{off: 10, size: 10, ready: make(chan struct{})}
+	p.add(pending)
+	tassert.Errorf(t, p.cached == 11, "expected 11 cached bytes, got %d", p.cached)
+	gaps = p.gaps(2, 30)
+	expected = []cmn.HTTPRange{{Start: 2, Length: 2}, {Start: 25, Length: 5}}
+	tassert.Errorf(t, reflect.DeepEqual(gaps, expected), "expected gaps %v, got %v", expected, gaps)
+	pend := p.pending(0, 12)
+	tassert.Errorf(t, len(pend) == 1 && pend[0] == pending, "expected pending extent, got %v", pend)
+	_, err := p.open(&cmn.HTTPRange{Start: 5, Length: 10}, &p.oa)
+	tassert.Errorf(t, err == errPartialChanged, "expected %v, got %v", errPartialChanged, err)
+	_, err = p.open(&cmn.HTTPRange{Start: 0, Length: 6}, &p.oa)
+	tassert.Errorf(t, err == errPartialChanged, "expected %v (not covered), got %v", errPartialChanged, err)
+	p.del(pending)
+
+	for _, gap := range p.gaps(0, p.oa.Size) {
+		addExtent(gap.Start, gap.Length)
+	}
+	tassert.Errorf(t, p.cached == p.oa.Size, "expected fully covered, got %d/%d", p.cached, p.oa.Size)
+	for i := 1; i < len(p.extents); i++ {
+		prev := p.extents[i-1]
+		tassert.Fatalf(t, prev.off+prev.size == p.extents[i].off, "extents are not contiguous: %+v, %+v", prev, p.extents[i])
+	}
+	s := readRange(3, 20)
+	tassert.Errorf(t, s == content[3:23], "expected %q, got %q", content[3:23], s)
+	s = readRange(0, p.oa.Size)
+	tassert.Errorf(t, s == content, "expected %q, got %q", content, s)
+
+	// the extents belong to the version of the object
+	oa := p.oa
+	oa.Ver = "2"
+	tassert.Errorf(t, p.sameObj(&p.oa) && !p.sameObj(&oa), "expected version mismatch")
+	_, err = p.open(&cmn.HTTPRange{Start: 0, Length: 1}, &oa)
+	tassert.Errorf(t, err == errPartialChanged, "expected %v, got %v", errPartialChanged, err)
+	oa = p.oa
+	oa.SetCustomKey(cmn.ETag, "etag")
+	tassert.Errorf(t, !p.sameObj(&oa), "expected ETag mismatch")
+
+	// removal drops extents that are being fetched
+	pending = &extent{off: p.oa.Size, size: 1, ready: make(chan struct{})}
+	p.add(pending)
+	p.rm()
+	tassert.Errorf(t, pending.dropped, "expected pending extent to be dropped")
+	tassert.Errorf(t, p.removed && len(p.extents) == 0 && p.cached == 0, "expected removed")
+	files, _ := os.ReadDir(dir)
+	tassert.Errorf(t, len(files) == 0, "expected extents to be removed, got %d file(s)", len(files))
+}
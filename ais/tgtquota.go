@@ -0,0 +1,361 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	ratomic "sync/atomic"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/mono"
+	"github.com/NVIDIA/aistore/xreg"
+)
+
+// Bucket and namespace quotas (see cmn.QuotaConf):
+// - each target tracks the usage (total size and number of objects) of the buckets
+//   that have quotas - its own share of it - incrementally, upon writing and deleting
+//   objects; the usage of a bucket is computed by walking it (see xreg.LocalBckUsage)
+//   once, when the bucket gets a quota, and then every quotaRescanTime to correct
+//   the drift - by merging the difference, so that concurrent updates are not lost
+//   (the merge is approximate: objects written while walking may be counted twice,
+//   objects deleted while walking - subtracted without having been counted; the
+//   error is bounded by the writes during the walk and gets corrected by the next rescan);
+// - every `ns_quota.sync_time` each target collects the usage from all other targets,
+//   so that the quotas are enforced cluster-wide - with the precision of the last sync;
+// - writes (PUT, APPEND, copy, download, dSort, etc.) that would exceed a hard limit fail
+//   with cmn.ErrQuotaExceeded; exceeding soft limits is logged;
+// - until its usage is known (e.g., upon startup), writes into a bucket that has a quota
+//   are not limited (and trigger the sync).
+
+const (
+	dfltQuotaSyncTime = time.Minute
+	quotaRescanTime   = time.Hour
+)
+
+type (
+	bckUsage struct {
+		Size int64 `json:"size,string"`
+		Objs int64 `json:"objs,string"`
+	}
+	quotas struct {
+		t         *targetrunner
+		local     map[string]*bckUsage // this target: by bucket uname (updated atomically)
+		peers     map[string]bckUsage  // all other targets (totals as of the last sync)
+		rescanned int64                // mono time of the last rescan (owned by sync)
+		mu        sync.RWMutex
+		syncing   atomic.Bool
+	}
+)
+
+func (u *bckUsage) add(size, objs int64) {
+	ratomic.AddInt64(&u.Size, size)
+	ratomic.AddInt64(&u.Objs, objs)
+}
+
+func (u *bckUsage) load() bckUsage {
+	return bckUsage{Size: ratomic.LoadInt64(&u.Size), Objs: ratomic.LoadInt64(&u.Objs)}
+}
+
+func hasQuota(bck *cluster.Bck, config *cmn.Config) bool {
+	if bck.Props.Quota.Enabled {
+		return true
+	}
+	conf, ok := config.NsQuota.Namespaces[bck.Ns.String()]
+	return ok && conf.Enabled
+}
+
+// buckets that have quotas, either their own or their namespace's
+func quotaBcks(bmd *bucketMD, config *cmn.Config) (bcks []*cluster.Bck) {
+	bmd.Range(nil, nil, func(bck *cluster.Bck) bool {
+		if hasQuota(bck, config) {
+			bcks = append(bcks, bck)
+		}
+		return false
+	})
+	return
+}
+
+func (q *quotas) init(t *targetrunner) {
+	q.t = t
+	q.local = make(map[string]*bckUsage)
+	q.peers = make(map[string]bckUsage)
+}
+
+func (q *quotas) housekeep() time.Duration {
+	config := cmn.GCO.Get()
+	interval := config.NsQuota.SyncTime.D()
+	if interval == 0 {
+		interval = dfltQuotaSyncTime
+	}
+	q.kick(config)
+	return interval
+}
+
+// start the sync unless already running
+func (q *quotas) kick(config *cmn.Config) {
+	if !q.t.ClusterStarted() || !q.syncing.CAS(false, true) {
+		return
+	}
+	var rescan bool
+	if now := mono.NanoTime(); time.Duration(now-q.rescanned) >= quotaRescanTime {
+		q.rescanned, rescan = now, true
+	}
+	go q.sync(config, rescan)
+}
+
+// compute the local usage of the buckets that have no usage yet (all buckets when
+// rescanning) and collect the usage from other targets
+func (q *quotas) sync(config *cmn.Config, rescan bool) {
+	defer q.syncing.Store(false)
+	var (
+		bcks  = quotaBcks(q.t.owner.bmd.get(), config)
+		local = make(map[string]*bckUsage, len(bcks))
+	)
+	q.mu.RLock()
+	for _, bck := range bcks {
+		uname := bck.MakeUname("")
+		if u, ok := q.local[uname]; ok {
+			local[uname] = u
+		}
+	}
+	q.mu.RUnlock()
+	for _, bck := range bcks {
+		uname := bck.MakeUname("")
+		u, ok := local[uname]
+		if ok && !rescan {
+			continue
+		}
+		if !ok {
+			u = &bckUsage{}
+		}
+		prev := u.load()
+		objs, size, err := xreg.LocalBckUsage(context.Background(), q.t, bck)
+		if err != nil {
+			glog.Errorf("%s: failed to compute %s usage: %v", q.t.si, bck, err)
+			continue
+		}
+		// merge the difference - updates made while walking are kept
+		// (and those the walk has also seen are counted twice - see above)
+		u.add(size-prev.Size, objs-prev.Objs)
+		local[uname] = u
+	}
+	var peers map[string]bckUsage
+	if len(bcks) > 0 {
+		peers = q.collect()
+	}
+	q.mu.Lock()
+	q.local, q.peers = local, peers
+	q.mu.Unlock()
+
+	q.alert(bcks, config)
+}
+
+// collect the usage from all other targets
+func (q *quotas) collect() (peers map[string]bckUsage) {
+	query := url.Values{}
+	query.Set(cmn.URLParamWhat, cmn.GetWhatQuotaUsage)
+	args := allocBcastArgs()
+	args.req = cmn.ReqArgs{Method: http.MethodGet, Path: cmn.URLPathDaemon.S, Query: query}
+	args.to = cluster.Targets
+	args.fv = func() interface{} { return &map[string]bckUsage{} }
+	results := q.t.bcastGroup(args)
+	freeBcastArgs(args)
+	peers = make(map[string]bckUsage)
+	for _, res := range results {
+		if res.err != nil {
+			glog.Errorf("%s: failed to get bucket usage from %s: %v", q.t.si, res.si, res.err)
+			continue
+		}
+		for uname, u := range *res.v.(*map[string]bckUsage) {
+			total := peers[uname]
+			total.Size += u.Size
+			total.Objs += u.Objs
+			peers[uname] = total
+		}
+	}
+	freeCallResults(results)
+	return
+}
+
+// usage of all buckets on this target (GET cmn.GetWhatQuotaUsage)
+func (q *quotas) localUsage() map[string]bckUsage {
+	q.mu.RLock()
+	usage := make(map[string]bckUsage, len(q.local))
+	for uname, u := range q.local {
+		usage[uname] = u.load()
+	}
+	q.mu.RUnlock()
+	return usage
+}
+
+func (q *quotas) tracked(bck *cluster.Bck) (ok bool) {
+	q.mu.RLock()
+	_, ok = q.local[bck.MakeUname("")]
+	q.mu.RUnlock()
+	return
+}
+
+// add the object's size (or, when negative, subtract) to the local usage
+func (q *quotas) add(bck *cluster.Bck, size, objs int64) {
+	q.mu.RLock()
+	if u, ok := q.local[bck.MakeUname("")]; ok {
+		u.add(size, objs)
+	}
+	q.mu.RUnlock()
+}
+
+// cluster-wide usage of a given bucket, or of all buckets in a given namespace
+func (q *quotas) usage(bck *cluster.Bck, ns *cmn.Ns) (usage bckUsage) {
+	q.mu.RLock()
+	if ns == nil {
+		uname := bck.MakeUname("")
+		if u, ok := q.local[uname]; ok {
+			usage = u.load()
+		}
+		peer := q.peers[uname]
+		usage.Size += peer.Size
+		usage.Objs += peer.Objs
+	} else {
+		for uname, u := range q.local {
+			if b, _ := cmn.ParseUname(uname); b.Ns == *ns {
+				l := u.load()
+				usage.Size += l.Size
+				usage.Objs += l.Objs
+			}
+		}
+		for uname, peer := range q.peers {
+			if b, _ := cmn.ParseUname(uname); b.Ns == *ns {
+				usage.Size += peer.Size
+				usage.Objs += peer.Objs
+			}
+		}
+	}
+	q.mu.RUnlock()
+	return
+}
+
+// check returns cmn.ErrQuotaExceeded if adding `size` bytes and `objs` objects to the bucket
+// would exceed either the bucket's or its namespace's hard limit. While the usage of the
+// bucket is not known yet the write is allowed.
+func (q *quotas) check(bck *cluster.Bck, size, objs int64) error {
+	if size <= 0 && objs <= 0 {
+		return nil
+	}
+	config := cmn.GCO.Get()
+	if !hasQuota(bck, config) {
+		return nil
+	}
+	if !q.tracked(bck) {
+		q.kick(config)
+		return nil
+	}
+	if conf := &bck.Props.Quota; conf.Enabled {
+		u := q.usage(bck, nil)
+		if limit := conf.Exceeds(u.Size+size, u.Objs+objs); limit != "" {
+			return cmn.NewErrQuotaExceeded("bucket "+bck.String(), limit, quotaLimit(conf, limit))
+		}
+	}
+	conf, ok := config.NsQuota.Namespaces[bck.Ns.String()]
+	if ok && conf.Enabled {
+		u := q.usage(bck, &bck.Ns)
+		if limit := conf.Exceeds(u.Size+size, u.Objs+objs); limit != "" {
+			return cmn.NewErrQuotaExceeded("namespace "+bck.Ns.String(), limit, quotaLimit(&conf, limit))
+		}
+	}
+	return nil
+}
+
+// log buckets and namespaces that exceed their soft limits
+func (q *quotas) alert(bcks []*cluster.Bck, config *cmn.Config) {
+	nss := make(map[cmn.Ns]struct{})
+	for _, bck := range bcks {
+		if conf := &bck.Props.Quota; conf.Enabled {
+			u := q.usage(bck, nil)
+			if limit := conf.ExceedsSoft(u.Size, u.Objs); limit != "" {
+				glog.Warningf("bucket %s exceeds its quota (%s = %d): size %d, objects %d",
+					bck, limit, quotaLimit(conf, limit), u.Size, u.Objs)
+			}
+		}
+		nss[bck.Ns] = struct{}{}
+	}
+	for ns := range nss {
+		conf, ok := config.NsQuota.Namespaces[ns.String()]
+		if !ok || !conf.Enabled {
+			continue
+		}
+		u := q.usage(nil, &ns)
+		if limit := conf.ExceedsSoft(u.Size, u.Objs); limit != "" {
+			glog.Warningf("namespace %s exceeds its quota (%s = %d): size %d, objects %d",
+				ns, limit, quotaLimit(&conf, limit), u.Size, u.Objs)
+		}
+	}
+}
+
+func quotaLimit(conf *cmn.QuotaConf, limit string) int64 {
+	switch limit {
+	case "hard_bytes":
+		return conf.HardBytes
+	case "hard_objs":
+		return conf.HardObjs
+	case "soft_bytes":
+		return conf.SoftBytes
+	default:
+		return conf.SoftObjs
+	}
+}
+
+// quota checks whether writing the object would exceed hard limits (unless `limit` is false)
+// and returns the size of the object that is being overwritten (-1 if none) - to update
+// the usage once the new object is written. Intra-cluster migration and cold GET are
+// accounted but not limited. (Caller must take w-lock, unless `locked` is false.)
+func (poi *putObjInfo) quota(locked, limit bool) (prevSize int64, errCode int, err error) {
+	var (
+		lom = poi.lom
+		q   = &poi.t.quotas
+	)
+	prevSize = -1
+	limit = limit && (poi.owt == cmn.OwtPut || poi.owt == cmn.OwtFinalize)
+	if !q.tracked(lom.Bck()) {
+		// not limited until the usage is known
+		if config := cmn.GCO.Get(); limit && hasQuota(lom.Bck(), config) {
+			q.kick(config)
+		}
+		return
+	}
+	cur := cluster.AllocLOM(lom.ObjName)
+	defer cluster.FreeLOM(cur)
+	if err = cur.Init(lom.Bucket()); err != nil {
+		return
+	}
+	if cur.Load(false /*cache it*/, locked) == nil {
+		prevSize = cur.SizeBytes()
+	}
+	if !limit {
+		return
+	}
+	size, objs := lom.SizeBytes(), int64(1)
+	if prevSize >= 0 {
+		size, objs = size-prevSize, 0
+	}
+	if err = q.check(lom.Bck(), size, objs); err != nil {
+		errCode = http.StatusInsufficientStorage
+	}
+	return
+}
+
+func (q *quotas) put(lom *cluster.LOM, prevSize int64) {
+	if prevSize < 0 {
+		q.add(lom.Bck(), lom.SizeBytes(), 1)
+	} else {
+		q.add(lom.Bck(), lom.SizeBytes()-prevSize, 0)
+	}
+}
@@ -0,0 +1,58 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestQuotasCheck(t *testing.T) {
+	var (
+		ns    = cmn.Ns{Name: "team-a"}
+		props = &cmn.BucketProps{Quota: cmn.QuotaConf{HardBytes: 1000, HardObjs: 10, Enabled: true}}
+		bck1  = cluster.NewBck("bck1", cmn.ProviderAIS, ns, props)
+		bck2  = cluster.NewBck("bck2", cmn.ProviderAIS, ns, &cmn.BucketProps{})
+		bck3  = cluster.NewBck("bck3", cmn.ProviderAIS, cmn.NsGlobal, props)
+		q     = &quotas{
+			t: &targetrunner{},
+			local: map[string]*bckUsage{
+				bck1.MakeUname(""): {Size: 300, Objs: 3},
+				bck2.MakeUname(""): {Size: 1000, Objs: 1},
+			},
+			peers: map[string]bckUsage{
+				bck1.MakeUname(""): {Size: 600, Objs: 6},
+			},
+		}
+	)
+	tassert.CheckError(t, q.check(bck1, 100, 1))
+	err := q.check(bck1, 101, 0)
+	tassert.Errorf(t, cmn.IsErrQuotaExceeded(err), "expected hard_bytes exceeded, got %v", err)
+	err = q.check(bck1, 0, 2)
+	tassert.Errorf(t, cmn.IsErrQuotaExceeded(err), "expected hard_objs exceeded, got %v", err)
+	tassert.CheckError(t, q.check(bck1, -100, 0))
+
+	// usage not known yet: not limited
+	tassert.CheckError(t, q.check(bck3, 1001, 11))
+
+	q.add(bck1, -200, -2)
+	tassert.CheckError(t, q.check(bck1, 300, 3))
+
+	// namespace: 100 + 600 + 1000 bytes
+	config := cmn.GCO.BeginUpdate()
+	config.NsQuota.Namespaces = map[string]cmn.QuotaConf{ns.String(): {HardBytes: 1900, Enabled: true}}
+	cmn.GCO.CommitUpdate(config)
+	defer func() {
+		config := cmn.GCO.BeginUpdate()
+		config.NsQuota.Namespaces = nil
+		cmn.GCO.CommitUpdate(config)
+	}()
+	tassert.CheckError(t, q.check(bck2, 200, 1))
+	err = q.check(bck2, 201, 1)
+	tassert.Errorf(t, cmn.IsErrQuotaExceeded(err), "expected namespace hard_bytes exceeded, got %v", err)
+}
@@ -0,0 +1,106 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/xreg"
+	"github.com/NVIDIA/aistore/xs"
+)
+
+// replication (bucket property `replication`) - see xs/replicate.go
+
+const rpHousekeepInterval = time.Minute
+
+// the change to log, if any: PUT or, when the object is migrated (global rebalance, in the first
+// place), resync - the object may have a change that is still pending on the target that
+// has sent it; replicas are never replicated any further (see xs/replicate.go)
+func (poi *putObjInfo) rpOp() string {
+	lom := poi.lom
+	if !lom.Bprops().Replication.Enabled || lom.IsReplica() {
+		return ""
+	}
+	switch poi.owt {
+	case cmn.OwtPut, cmn.OwtFinalize:
+		return xs.RpPut
+	case cmn.OwtMigrate:
+		return xs.RpResync
+	default:
+		return ""
+	}
+}
+
+// log the change (the caller holds the write lock)
+func (poi *putObjInfo) logReplication(op string) error {
+	return xs.LogReplication(poi.t.db, poi.lom, op)
+}
+
+// log the DELETE (ditto) and kick the replication
+func (t *targetrunner) replicateDelete(lom *cluster.LOM) {
+	if !lom.Bprops().Replication.Enabled || lom.IsReplica() {
+		return
+	}
+	if err := xs.LogReplication(t.db, lom, xs.RpDelete); err != nil {
+		glog.Errorf("%s: failed to log replication of deleted %s: %v", t.si, lom, err)
+		return
+	}
+	t.replicate(lom)
+}
+
+// NOTE: if the xaction cannot be renewed the change remains logged
+// and gets replicated later (see rpHousekeep)
+func (t *targetrunner) replicate(lom *cluster.LOM) {
+	rns := xreg.RenewReplicate(t, lom.Bck(), t.db)
+	if rns.Err != nil {
+		glog.Errorf("%s: %s: %v", t.si, lom, rns.Err)
+		return
+	}
+	xrp := rns.Entry.Get().(*xs.XactReplicate)
+	xrp.Add(lom.ObjName)
+}
+
+// (re)start replication for the buckets that have logged changes - in particular, upon restart -
+// and requeue the changes that are due to be retried
+func (t *targetrunner) rpHousekeep() time.Duration {
+	t.dqHousekeep(xs.ReplicationBuckets, xs.DropReplication, func(bck *cluster.Bck) (dqLoader, error) {
+		if !bck.Props.Replication.Enabled {
+			glog.Warningf("%s: %s is not replicated", t.si, bck)
+			return nil, nil
+		}
+		rns := xreg.RenewReplicate(t, bck, t.db)
+		if rns.Err != nil {
+			return nil, rns.Err
+		}
+		return rns.Entry.Get().(*xs.XactReplicate), nil
+	})
+	return rpHousekeepInterval
+}
+
+// `ais job start replicate BUCKET`: retry the changes that have failed to be replicated
+func (t *targetrunner) startReplicate(bck *cluster.Bck) error {
+	if !bck.Props.Replication.Enabled {
+		return fmt.Errorf("cannot start %q: bucket %s is not replicated", cmn.ActReplicate, bck)
+	}
+	rns := xreg.RenewReplicate(t, bck, t.db)
+	if rns.Err != nil {
+		return rns.Err
+	}
+	xrp := rns.Entry.Get().(*xs.XactReplicate)
+	return xrp.RetryFailed()
+}
+
+// `ais job start resync BUCKET`: log all the bucket's objects (that are not yet replicated)
+func (t *targetrunner) startResync(uuid string, bck *cluster.Bck) error {
+	if !bck.Props.Replication.Enabled {
+		return fmt.Errorf("cannot start %q: bucket %s is not replicated", cmn.ActResync, bck)
+	}
+	args := &xreg.ResyncArgs{DB: t.db, Replicate: t.replicate}
+	return xreg.RenewResync(t, uuid, bck, args).Err
+}
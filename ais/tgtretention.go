@@ -0,0 +1,193 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Retention (WORM) of objects - see cluster/lretention.go
+
+// stops walking the bucket (see checkDestroyRetention)
+var errRetainedFound = cmn.NewErrAborted("check-retention", "retained object found", nil)
+
+// check whether the object can be deleted or modified (caller must take the lock)
+func checkRetention(lom *cluster.LOM, bypassGovernance bool) (int, error) {
+	if err := lom.LoadLatest(true /*locked*/); err != nil {
+		if cmn.IsObjNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if err := lom.CheckRetention(bypassGovernance); err != nil {
+		return http.StatusForbidden, err
+	}
+	return 0, nil
+}
+
+// same as above for a given version of the object (versioning.retain)
+func checkVersionRetention(lom *cluster.LOM, ver string, bypassGovernance bool) (int, error) {
+	err := lom.LoadLatest(true /*locked*/)
+	if err == nil && lom.Version() == ver {
+		if err = lom.CheckRetention(bypassGovernance); err != nil {
+			return http.StatusForbidden, err
+		}
+		return 0, nil
+	}
+	if err != nil && !cmn.IsObjNotExist(err) {
+		return 0, err
+	}
+	vlom, err := lom.LoadVersion(ver)
+	if err != nil {
+		return 0, nil // (not found will be handled by the caller)
+	}
+	defer cluster.FreeLOM(vlom)
+	if err = vlom.CheckRetention(bypassGovernance); err != nil {
+		return http.StatusForbidden, err
+	}
+	return 0, nil
+}
+
+// Retained objects cannot be overwritten, with a single exception: intra-cluster
+// migration of the same content. New objects inherit the bucket's retention.
+// (Caller must take w-lock.)
+func (poi *putObjInfo) retention() (int, error) {
+	switch poi.owt {
+	case cmn.OwtGetTryLock, cmn.OwtGetLock, cmn.OwtGet, cmn.OwtGetPrefetchLock:
+		return 0, nil // cold GET (remote buckets only)
+	}
+	cur := cluster.AllocLOM(poi.lom.ObjName)
+	defer cluster.FreeLOM(cur)
+	if err := cur.Init(poi.lom.Bucket()); err != nil {
+		return 0, err
+	}
+	if errCode, err := checkRetention(cur, false /*bypass*/); err != nil {
+		if poi.owt != cmn.OwtMigrate || errCode != http.StatusForbidden || !cur.EqCksum(poi.lom.Checksum()) {
+			return errCode, err
+		}
+	}
+	if poi.owt != cmn.OwtMigrate {
+		poi.lom.SetRetention(poi.atime)
+	}
+	return 0, nil
+}
+
+// Custom metadata update (PATCH) may extend the object's retention and set (or
+// remove) legal hold; retention that is in effect cannot be otherwise weakened,
+// unless in governance mode and `bypassGovernance`.
+func checkCustomMD(lom *cluster.LOM, custom cos.SimpleKVs, bypassGovernance bool) error {
+	if _, ok := custom[cmn.SSEObjMD]; ok {
+		return fmt.Errorf("%s: custom key %q is reserved", lom, cmn.SSEObjMD)
+	}
+	if v, ok := custom[cmn.LegalHoldObjMD]; ok {
+		if _, err := cos.ParseBool(v); err != nil {
+			return fmt.Errorf("%s: invalid %s %q", lom, cmn.LegalHoldObjMD, v)
+		}
+	}
+	var (
+		mode, until   = lom.Retention()
+		nmode, nuntil = mode, until
+	)
+	if v, ok := custom[cmn.RetainUntilObjMD]; ok {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return fmt.Errorf("%s: invalid %s %q (expecting RFC 3339 time)", lom, cmn.RetainUntilObjMD, v)
+		}
+		nuntil = t
+	}
+	if v, ok := custom[cmn.RetentionModeObjMD]; ok {
+		if v != cmn.RetentionGovernance && v != cmn.RetentionCompliance {
+			return fmt.Errorf("%s: invalid %s %q", lom, cmn.RetentionModeObjMD, v)
+		}
+		nmode = v
+	}
+	if !nuntil.IsZero() && nmode == "" {
+		return fmt.Errorf("%s: %s requires %s", lom, cmn.RetainUntilObjMD, cmn.RetentionModeObjMD)
+	}
+	if !time.Now().Before(until) {
+		return nil
+	}
+	weaker := nuntil.Before(until) || (mode == cmn.RetentionCompliance && nmode != cmn.RetentionCompliance)
+	if weaker && (mode == cmn.RetentionCompliance || !bypassGovernance) {
+		return cmn.NewErrObjRetained(lom.String(), mode, until, false)
+	}
+	return nil
+}
+
+// a bucket that contains retained objects cannot be destroyed
+func (t *targetrunner) checkDestroyRetention(bck *cluster.Bck) error {
+	if !bck.IsAIS() || !bck.Props.Retention.Enabled {
+		return nil
+	}
+	var retained *cluster.LOM
+	cb := func(fqn string, de fs.DirEntry) error {
+		if de.IsDir() {
+			return nil
+		}
+		parsedFQN, _, err := cluster.ResolveFQN(fqn)
+		if err != nil {
+			return nil
+		}
+		objName, ver := parsedFQN.ObjName, ""
+		if parsedFQN.ContentType == fs.ObjVersionType {
+			i := strings.LastIndex(objName, ".")
+			if i <= 0 {
+				return nil
+			}
+			objName, ver = objName[:i], objName[i+1:]
+		}
+		lom := cluster.AllocLOM(objName)
+		if err := lom.Init(bck.Bck); err != nil {
+			cluster.FreeLOM(lom)
+			return nil
+		}
+		if ver != "" {
+			vlom, err := lom.LoadVersion(ver)
+			cluster.FreeLOM(lom)
+			if err != nil {
+				return nil
+			}
+			lom = vlom
+		} else if err := lom.Load(false /*cache it*/, false /*locked*/); err != nil {
+			cluster.FreeLOM(lom)
+			return nil
+		}
+		if lom.CheckRetention(false /*bypass*/) != nil {
+			retained = lom
+			return errRetainedFound
+		}
+		cluster.FreeLOM(lom)
+		return nil
+	}
+	for _, mi := range fs.GetAvail() {
+		opts := &fs.Options{
+			Mi:       mi,
+			Bck:      bck.Bck,
+			CTs:      []string{fs.ObjectType, fs.ObjVersionType},
+			Callback: cb,
+		}
+		err := fs.Walk(opts)
+		if retained != nil {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if retained == nil {
+		return nil
+	}
+	err := fmt.Errorf("%s: cannot destroy bucket %s: %v", t.si, bck, retained.CheckRetention(false))
+	cluster.FreeLOM(retained)
+	return err
+}
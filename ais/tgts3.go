@@ -19,24 +19,51 @@ import (
 	"github.com/NVIDIA/aistore/memsys"
 )
 
-// PUT s3/bckName/objName
+// [METHOD] s3/bckName/objName
 func (t *targetrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 	apiItems, err := t.checkRESTItems(w, r, 0, true, cmn.URLPathS3.L)
 	if err != nil {
 		return
 	}
 
+	// redirected requests signed with AWS signature V4 (see p.s3Auth)
+	if cmn.GCO.Get().Auth.Enabled {
+		s3compat.VerifyPayload(r)
+	}
+	q := r.URL.Query()
 	switch r.Method {
 	case http.MethodHead:
 		t.headObjS3(w, r, apiItems)
 	case http.MethodGet:
+		if q.Has(s3compat.URLParamUploadID) {
+			t.listMptParts(w, r, apiItems, q)
+			return
+		}
 		t.getObjS3(w, r, apiItems)
 	case http.MethodPut:
+		if q.Has(s3compat.URLParamUploadID) {
+			t.putMptPart(w, r, apiItems, q)
+			return
+		}
 		t.putObjS3(w, r, apiItems)
+	case http.MethodPost:
+		switch {
+		case q.Has(s3compat.URLParamUploads):
+			t.startMpt(w, r, apiItems)
+		case q.Has(s3compat.URLParamUploadID):
+			t.completeMpt(w, r, apiItems, q)
+		default:
+			t.writeErr(w, r, errS3Req)
+		}
 	case http.MethodDelete:
+		if q.Has(s3compat.URLParamUploadID) {
+			t.abortMpt(w, r, apiItems, q)
+			return
+		}
 		t.delObjS3(w, r, apiItems)
 	default:
-		cmn.WriteErr405(w, r, http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodPut)
+		cmn.WriteErr405(w, r, http.MethodDelete, http.MethodGet, http.MethodHead,
+			http.MethodPost, http.MethodPut)
 	}
 }
 
@@ -89,7 +116,7 @@ func (t *targetrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items [
 	}
 
 	var cksumValue string
-	if cksum := lom.Checksum(); cksum.Type() == cos.ChecksumMD5 {
+	if cksum := lom.PlainAttrs().Cksum; cksum.Type() == cos.ChecksumMD5 {
 		cksumValue = cksum.Value()
 	}
 	result := s3compat.CopyObjectResult{
@@ -143,12 +170,27 @@ func (t *targetrunner) directPutObjS3(w http.ResponseWriter, r *http.Request, it
 
 	// TODO: dual checksumming, e.g. lom.SetCustom(cmn.ProviderAmazon, ...)
 
-	if errCode, err := t.doPut(r, lom, started, r.URL.Query(), false /*skipVC*/); err != nil {
+	var sse *sseReq
+	if keyID, ok, err := s3compat.ParseSSE(r.Header); err != nil {
+		t.writeErr(w, r, err)
+		return
+	} else if ok {
+		sse = &sseReq{keyID: keyID}
+	}
+	if errCode, err := t.doPut(r, lom, started, r.URL.Query(), false /*skipVC*/, sse); err != nil {
 		t.fsErr(err, lom.FQN)
 		t.writeErr(w, r, err, errCode)
 		return
 	}
 	s3compat.SetETag(w.Header(), lom)
+	if lom.IsEncrypted() {
+		w.Header().Set(s3compat.HeaderSSE, s3compat.SSEAES256)
+		if sse != nil && r.Header.Get(s3compat.HeaderSSE) == s3compat.SSEKMS {
+			md, _ := lom.SSEMD()
+			w.Header().Set(s3compat.HeaderSSE, s3compat.SSEKMS)
+			w.Header().Set(s3compat.HeaderSSEKMSKeyID, md.KeyID)
+		}
+	}
 }
 
 // PUT s3/bckName/objName
@@ -172,8 +214,15 @@ func (t *targetrunner) getObjS3(w http.ResponseWriter, r *http.Request, items []
 		return
 	}
 	lom := cluster.AllocLOM(path.Join(items[1:]...))
-	t.getObject(w, r, r.URL.Query(), bck, lom)
+	query := r.URL.Query()
+	if ver := s3compat.VersionID(query); ver != "" {
+		query.Set(cmn.URLParamVersion, ver)
+		w.Header().Set(s3compat.HeaderVersionID, ver)
+	}
+	t.getObject(w, r, query, bck, lom)
 	s3compat.SetETag(w.Header(), lom) // add etag/md5
+	s3compat.SetSSEHeader(w.Header(), lom)
+	s3compat.SetRetentionHeaders(w.Header(), lom)
 	cluster.FreeLOM(lom)
 }
 
@@ -193,6 +242,8 @@ func (t *targetrunner) headObjS3(w http.ResponseWriter, r *http.Request, items [
 	lom := cluster.AllocLOM(objName)
 	t.headObject(w, r, r.URL.Query(), bck, lom)
 	s3compat.SetETag(w.Header(), lom) // add etag/md5
+	s3compat.SetSSEHeader(w.Header(), lom)
+	s3compat.SetRetentionHeaders(w.Header(), lom)
 	cluster.FreeLOM(lom)
 }
 
@@ -214,7 +265,17 @@ func (t *targetrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []
 		t.writeErr(w, r, err)
 		return
 	}
-	errCode, err := t.DeleteObject(lom, false)
+	var (
+		errCode int
+		err     error
+		ver     = s3compat.VersionID(r.URL.Query())
+		bypass  = s3compat.BypassGovernance(r.Header)
+	)
+	if ver != "" {
+		errCode, err = t.delObjVersion(lom, ver, bypass)
+	} else {
+		errCode, err = t.deleteObject(lom, false /*evict*/, bypass)
+	}
 	if err != nil {
 		if errCode == http.StatusNotFound {
 			err := cmn.NewErrNotFound("%s: %s", t.si, lom.FullName())
@@ -224,6 +285,15 @@ func (t *targetrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []
 		}
 		return
 	}
+	if ver != "" {
+		w.Header().Set(s3compat.HeaderVersionID, ver)
+		return
+	}
+	if lom.VersionConf().Retain {
+		w.Header().Set(s3compat.HeaderDeleteMarker, "true")
+		w.Header().Set(s3compat.HeaderVersionID, lom.Version())
+		return
+	}
 	// EC cleanup if EC is enabled
 	ec.ECM.CleanupObject(lom)
 }
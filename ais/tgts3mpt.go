@@ -0,0 +1,366 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais/s3compat"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// S3 multipart upload: the parts are stored as workfiles on the target that
+// (HRW-wise) owns the object; upon completion the parts get concatenated
+// into a regular AIS object - see also ais/s3compat/mpt.go
+
+// uploads that are neither completed nor aborted for so long are removed
+const mptMaxAge = fs.MptPartMaxAge
+
+// parts are plaintext workfiles that may stay around for days
+var errMptEncrypted = errors.New("multipart upload is not supported for buckets with encryption enabled")
+
+// combines uploaded parts (workfiles) into a single reader
+type mptReader struct {
+	io.Reader
+	files []*os.File
+}
+
+func (mr *mptReader) Close() (err error) {
+	for _, fh := range mr.files {
+		if erc := fh.Close(); erc != nil && err == nil {
+			err = erc
+		}
+	}
+	return
+}
+
+func (t *targetrunner) mptLOM(w http.ResponseWriter, r *http.Request, items []string) (lom *cluster.LOM) {
+	if len(items) < 2 {
+		t.writeErr(w, r, errS3Obj)
+		return
+	}
+	bck := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(t.owner.bmd); err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	lom = cluster.AllocLOM(path.Join(items[1:]...))
+	if err := lom.Init(bck.Bck); err != nil {
+		cluster.FreeLOM(lom)
+		t.writeErr(w, r, err)
+		return nil
+	}
+	return
+}
+
+// POST s3/bckName/objName?uploads
+// Start a new multipart upload and return its ID
+func (t *targetrunner) startMpt(w http.ResponseWriter, r *http.Request, items []string) {
+	lom := t.mptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	defer cluster.FreeLOM(lom)
+	if lom.Bprops().Encryption.Enabled {
+		t.writeErr(w, r, errMptEncrypted, http.StatusNotImplemented)
+		return
+	}
+	uploadID := cos.GenUUID()
+	if err := s3compat.InitUpload(uploadID, lom.Bucket().Name, lom.ObjName); err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: started multipart upload %q => %s", t.si, uploadID, lom)
+	}
+	result := &s3compat.InitiateMptUploadResult{Bucket: lom.Bucket().Name, Key: lom.ObjName, UploadID: uploadID}
+	sgl := memsys.PageMM().NewSGL(0)
+	result.MustMarshal(sgl)
+	w.Header().Set(cmn.HdrContentType, cmn.ContentXML)
+	sgl.WriteTo(w)
+	sgl.Free()
+}
+
+// PUT s3/bckName/objName?partNumber=<n>&uploadId=<id>
+// Store the part as a workfile and return its MD5 (aka ETag)
+func (t *targetrunner) putMptPart(w http.ResponseWriter, r *http.Request, items []string, q url.Values) {
+	if cs := fs.GetCapStatus(); cs.OOS {
+		t.writeErr(w, r, cs.Err, http.StatusInsufficientStorage)
+		return
+	}
+	partNum, err := s3compat.ParsePartNum(q.Get(s3compat.URLParamPartNum))
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	lom := t.mptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	defer cluster.FreeLOM(lom)
+	if lom.Bprops().Encryption.Enabled {
+		t.writeErr(w, r, errMptEncrypted, http.StatusNotImplemented)
+		return
+	}
+
+	var (
+		uploadID = q.Get(s3compat.URLParamUploadID)
+		partFQN  = fs.CSM.Gen(lom, fs.WorkfileType, fs.WorkfileMptPart)
+	)
+	size, md5, err := t.writeMptPart(lom, partFQN, r.Body)
+	if err != nil {
+		t.fsErr(err, partFQN)
+		t.writeErr(w, r, err)
+		return
+	}
+	part := &s3compat.MptPart{MD5: md5, FQN: partFQN, Size: size, Num: partNum}
+	prevFQN, err := s3compat.AddPart(uploadID, lom.Bucket().Name, lom.ObjName, part)
+	if err != nil {
+		// the upload has been completed or aborted in the meantime (or is being completed)
+		if errRm := cos.RemoveFile(partFQN); errRm != nil {
+			glog.Errorf("Nested (%v): failed to remove %s, err: %v", err, partFQN, errRm)
+		}
+		t.writeMptErr(w, r, err)
+		return
+	}
+	if prevFQN != "" {
+		if errRm := cos.RemoveFile(prevFQN); errRm != nil {
+			glog.Errorf("%s: failed to remove replaced part %s, err: %v", t.si, prevFQN, errRm)
+		}
+	}
+	w.Header().Set(cmn.HdrETag, md5)
+}
+
+func (t *targetrunner) writeMptPart(lom *cluster.LOM, partFQN string, body io.ReadCloser) (size int64, md5 string, err error) {
+	defer cos.Close(body)
+	fh, err := lom.CreateFile(partFQN)
+	if err != nil {
+		return
+	}
+	var (
+		cksum     = cos.NewCksumHash(cos.ChecksumMD5)
+		buf, slab = t.gmm.Alloc()
+	)
+	size, err = io.CopyBuffer(cos.NewWriterMulti(cksum.H, fh), body, buf)
+	slab.Free(buf)
+	if erc := fh.Close(); erc != nil && err == nil {
+		err = erc
+	}
+	if err != nil {
+		if errRm := cos.RemoveFile(partFQN); errRm != nil {
+			glog.Errorf("Nested (%v): failed to remove %s, err: %v", err, partFQN, errRm)
+		}
+		return
+	}
+	cksum.Finalize()
+	md5 = cksum.Value()
+	return
+}
+
+// POST s3/bckName/objName?uploadId=<id>
+// Concatenate the parts listed in the request body into the destination object
+func (t *targetrunner) completeMpt(w http.ResponseWriter, r *http.Request, items []string, q url.Values) {
+	started := time.Now()
+	if cs := fs.GetCapStatus(); cs.OOS {
+		t.writeErr(w, r, cs.Err, http.StatusInsufficientStorage)
+		return
+	}
+	var (
+		uploadID = q.Get(s3compat.URLParamUploadID)
+		partList = &s3compat.CompleteMptUpload{}
+	)
+	err := xml.NewDecoder(r.Body).Decode(partList)
+	cos.Close(r.Body)
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	lom := t.mptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	defer cluster.FreeLOM(lom)
+
+	// from here on and until FinishUpload (or EndComplete) the parts do not change
+	parts, err := s3compat.BeginComplete(uploadID, lom.Bucket().Name, lom.ObjName, partList.Parts)
+	if err != nil {
+		t.writeMptErr(w, r, err)
+		return
+	}
+	etag, err := s3compat.MptETag(parts)
+	if err != nil {
+		s3compat.EndComplete(uploadID)
+		t.writeErr(w, r, err)
+		return
+	}
+	if lom.VersionConf().Enabled {
+		// load it to see the current version
+		lom.Load(true /*cache it*/, false /*locked*/)
+	}
+
+	// open all parts
+	var (
+		size    int64
+		readers = make([]io.Reader, 0, len(parts))
+		mr      = &mptReader{files: make([]*os.File, 0, len(parts))}
+	)
+	for _, part := range parts {
+		fh, err := os.Open(part.FQN)
+		if err != nil {
+			mr.Close()
+			s3compat.EndComplete(uploadID)
+			t.fsErr(err, part.FQN)
+			t.writeErr(w, r, fmt.Errorf(cmn.FmtErrFailed, t.si, "open part", part.FQN, err))
+			return
+		}
+		mr.files = append(mr.files, fh)
+		readers = append(readers, fh)
+		size += part.Size
+	}
+	mr.Reader = io.MultiReader(readers...)
+
+	// write and finalize the object
+	lom.SetAtimeUnix(started.UnixNano())
+	lom.SetCustomKey(cmn.ETag, etag)
+	poi := allocPutObjInfo()
+	{
+		poi.atime = started
+		poi.t = t
+		poi.lom = lom
+		poi.r = mr
+		poi.workFQN = fs.CSM.Gen(lom, fs.WorkfileType, fs.WorkfilePut)
+		poi.owt = cmn.OwtPut
+		poi.size = size
+	}
+	errCode, err := poi.putObject()
+	freePutObjInfo(poi)
+	if err != nil {
+		s3compat.EndComplete(uploadID)
+		t.fsErr(err, lom.FQN)
+		t.writeErr(w, r, err, errCode)
+		return
+	}
+
+	// cleanup all the parts, including those that weren't used
+	allParts, err := s3compat.FinishUpload(uploadID, lom.Bucket().Name, lom.ObjName, true /*completed*/)
+	debug.AssertNoErr(err)
+	t.rmMptParts(allParts)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: completed multipart upload %q => %s (%d parts)", t.si, uploadID, lom, len(parts))
+	}
+
+	result := &s3compat.CompleteMptUploadResult{Bucket: lom.Bucket().Name, Key: lom.ObjName, ETag: etag}
+	sgl := memsys.PageMM().NewSGL(0)
+	result.MustMarshal(sgl)
+	w.Header().Set(cmn.HdrContentType, cmn.ContentXML)
+	w.Header().Set(cmn.HdrETag, etag)
+	sgl.WriteTo(w)
+	sgl.Free()
+}
+
+// DELETE s3/bckName/objName?uploadId=<id>
+func (t *targetrunner) abortMpt(w http.ResponseWriter, r *http.Request, items []string, q url.Values) {
+	lom := t.mptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	defer cluster.FreeLOM(lom)
+	uploadID := q.Get(s3compat.URLParamUploadID)
+	parts, err := s3compat.FinishUpload(uploadID, lom.Bucket().Name, lom.ObjName, false /*completed*/)
+	if err != nil {
+		t.writeMptErr(w, r, err)
+		return
+	}
+	t.rmMptParts(parts)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET s3/bckName/objName?uploadId=<id>
+func (t *targetrunner) listMptParts(w http.ResponseWriter, r *http.Request, items []string, q url.Values) {
+	lom := t.mptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	defer cluster.FreeLOM(lom)
+	var (
+		marker   int64
+		maxParts int
+		err      error
+		uploadID = q.Get(s3compat.URLParamUploadID)
+	)
+	if s := q.Get(s3compat.URLParamPartNumberMarker); s != "" {
+		if marker, err = strconv.ParseInt(s, 10, 64); err != nil {
+			t.writeErr(w, r, err)
+			return
+		}
+	}
+	if s := q.Get(s3compat.URLParamMaxParts); s != "" {
+		if maxParts, err = strconv.Atoi(s); err != nil {
+			t.writeErr(w, r, err)
+			return
+		}
+	}
+	result, err := s3compat.ListParts(uploadID, lom.Bucket().Name, lom.ObjName, marker, maxParts)
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	sgl := memsys.PageMM().NewSGL(0)
+	result.MustMarshal(sgl)
+	w.Header().Set(cmn.HdrContentType, cmn.ContentXML)
+	sgl.WriteTo(w)
+	sgl.Free()
+}
+
+func (t *targetrunner) writeMptErr(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, s3compat.ErrMptCompleting) {
+		t.writeErr(w, r, err, http.StatusConflict)
+		return
+	}
+	t.writeErr(w, r, err)
+}
+
+func (t *targetrunner) rmMptParts(parts []*s3compat.MptPart) {
+	for _, part := range parts {
+		if err := cos.RemoveFile(part.FQN); err != nil {
+			glog.Errorf("%s: failed to remove part %s, err: %v", t.si, part.FQN, err)
+		}
+	}
+}
+
+// load (persisted) uploads that were in progress prior to restart
+func (t *targetrunner) loadMpt() {
+	n, err := s3compat.LoadUploads(t.db)
+	if err != nil {
+		glog.Errorf("%s: failed to load multipart uploads: %v", t.si, err)
+	} else if n > 0 {
+		glog.Infof("%s: loaded %d multipart upload(s)", t.si, n)
+	}
+}
+
+// housekeeping: remove abandoned uploads
+func (t *targetrunner) gcMpt() time.Duration {
+	if parts := s3compat.CleanupExpired(mptMaxAge); len(parts) > 0 {
+		glog.Infof("%s: removing %d part(s) of expired multipart uploads", t.si, len(parts))
+		t.rmMptParts(parts)
+	}
+	return hk.DayInterval
+}
@@ -6,6 +6,7 @@ package ais
 
 import (
 	"sync"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
@@ -20,6 +21,9 @@ import (
 	"github.com/NVIDIA/aistore/xreg"
 )
 
+// how often to check and enforce bucket lifecycle rules (the rules themselves are day-granular)
+const lifecycleInterval = time.Hour
+
 // triggers by an out-of-space condition or a suspicion of thereof
 func (t *targetrunner) OOS(csRefreshed *fs.CapStatus) (cs fs.CapStatus) {
 	var err error
@@ -115,3 +119,44 @@ func (t *targetrunner) runStoreCleanup(id string, wg *sync.WaitGroup, bcks ...cm
 	})
 	return space.RunCleanup(&ini)
 }
+
+func (t *targetrunner) runLifecycle(id string, wg *sync.WaitGroup, bcks ...cmn.Bck) {
+	regToIC := id == ""
+	if regToIC {
+		id = cos.GenUUID()
+	}
+	rns := xreg.RenewLifecycle(id)
+	if rns.IsRunning() {
+		if wg != nil {
+			wg.Done()
+		}
+		return
+	}
+	debug.AssertNoErr(rns.Err) // see xlc.WhenPrevIsRunning() and xreg logic
+	xlc := rns.Entry.Get()
+	if regToIC && xlc.ID() == id {
+		// pre-existing UUID: notify IC members
+		regMsg := xactRegMsg{UUID: id, Kind: cmn.ActLifecycle, Srcs: []string{t.si.ID()}}
+		msg := t.newAmsgActVal(cmn.ActRegGlobalXaction, regMsg)
+		t.bcastAsyncIC(msg)
+	}
+	ini := space.IniLifecycle{
+		T:       t,
+		Xaction: xlc.(*space.XactLifecycle),
+		Buckets: bcks,
+		WG:      wg,
+	}
+	xlc.AddNotif(&xaction.NotifXact{
+		NotifBase: nl.NotifBase{When: cluster.UponTerm, Dsts: []string{equalIC}, F: t.callerNotifyFin},
+		Xact:      xlc,
+	})
+	space.RunLifecycle(&ini)
+}
+
+// housekeeping: periodically enforce bucket lifecycle rules (if any)
+func (t *targetrunner) lifecycleHK() time.Duration {
+	if t.ClusterStarted() && len(space.LifecycleBcks(t.Bowner())) > 0 {
+		go t.runLifecycle("" /*uuid*/, nil /*wg*/)
+	}
+	return lifecycleInterval
+}
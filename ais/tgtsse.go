@@ -0,0 +1,137 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/sse"
+)
+
+// server-side encryption of objects at rest (see also cluster/lsse.go)
+
+const workfileSSE = "sse"
+
+// per-request encryption (S3 `x-amz-server-side-encryption`)
+type sseReq struct {
+	keyID string // empty: the bucket's key, if configured, or KMS default
+}
+
+// sseInit decides whether the object that is being written must be encrypted and,
+// if it must, generates the object's data key.
+// Intra-cluster migration (rebalance, GFN, EC restore) stores objects as they come:
+// ciphertext along with its encryption metadata, or plaintext. Otherwise, the
+// encryption metadata that may have arrived with the object is never trusted.
+func (poi *putObjInfo) sseInit() (md *sse.ObjMD, key []byte, err error) {
+	if poi.owt == cmn.OwtMigrate {
+		return
+	}
+	lom := poi.lom
+	lom.ObjAttrs().DelCustomKeys(cmn.SSEObjMD)
+	var (
+		conf  = &lom.Bprops().Encryption
+		keyID = conf.KeyID
+	)
+	if poi.sse {
+		if poi.sseKeyID != "" {
+			keyID = poi.sseKeyID
+		}
+	} else if !conf.Enabled {
+		return
+	}
+	kms, err := sse.GetKMS()
+	if err != nil {
+		return
+	}
+	md = &sse.ObjMD{}
+	if key, md.Wrapped, md.KeyID, err = kms.DataKey(keyID); err != nil {
+		err = fmt.Errorf("%s: failed to generate data key: %w", lom, err)
+	}
+	return
+}
+
+// sseFinalize is called upon writing the ciphertext of the object
+func (poi *putObjInfo) sseFinalize(md *sse.ObjMD, plain *cos.CksumHash, plainSize int64) {
+	md.Size = plainSize
+	if plain != nil {
+		plain.Finalize()
+		md.Cksum = plain.Value()
+	}
+	poi.lom.SetCustomKey(cmn.SSEObjMD, md.Pack())
+	poi.encrypted = true
+}
+
+// encrypt the (plaintext) work file, unless writeToFile() has already done so;
+// in particular, objects in remote buckets are encrypted only after having been
+// written to the remote backend (in plaintext)
+func (poi *putObjInfo) sseWorkfile() (err error) {
+	if poi.encrypted {
+		return
+	}
+	md, key, err := poi.sseInit()
+	if md == nil || err != nil {
+		return
+	}
+	var (
+		lom     = poi.lom
+		conf    = lom.CksumConf()
+		workFQN = fs.CSM.Gen(lom, fs.WorkfileType, workfileSSE)
+		plain   *cos.CksumHash
+		store   *cos.CksumHash
+		src     *os.File
+		dst     *os.File
+		encw    *sse.Writer
+		written int64
+	)
+	if src, err = os.Open(poi.workFQN); err != nil {
+		return
+	}
+	defer cos.Close(src)
+	if dst, err = lom.CreateFile(workFQN); err != nil {
+		return
+	}
+	var (
+		rd io.Reader = src
+		wr io.Writer = dst
+	)
+	if conf.Type != cos.ChecksumNone {
+		plain, store = cos.NewCksumHash(conf.Type), cos.NewCksumHash(conf.Type)
+		rd = io.TeeReader(src, plain.H)
+		wr = cos.NewWriterMulti(store.H, dst)
+	}
+	buf, slab := poi.t.gmm.Alloc()
+	if encw, err = sse.NewWriter(wr, key); err == nil {
+		if written, err = io.CopyBuffer(encw, rd, buf); err == nil {
+			err = encw.Close()
+		}
+	}
+	slab.Free(buf)
+	if err1 := dst.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		cos.RemoveFile(workFQN)
+		return
+	}
+	if err = cos.RemoveFile(poi.workFQN); err != nil {
+		cos.RemoveFile(workFQN)
+		return
+	}
+	poi.workFQN = workFQN
+	poi.sseFinalize(md, plain, written)
+	lom.SetSize(encw.Written())
+	if store != nil {
+		store.Finalize()
+		lom.SetCksum(&store.Cksum)
+	} else {
+		lom.SetCksum(cos.NoneCksum)
+	}
+	return
+}
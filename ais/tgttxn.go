@@ -17,7 +17,6 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
-	"github.com/NVIDIA/aistore/cmn/k8s"
 	"github.com/NVIDIA/aistore/etl"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/mirror"
@@ -465,11 +464,7 @@ func (t *targetrunner) validateBckRenTxn(bckFrom, bckTo *cluster.Bck, msg *aisMs
 }
 
 func (t *targetrunner) etlDP(msg *cmn.TCBMsg) (dp cluster.DP, err error) {
-	if err = k8s.Detect(); err != nil {
-		return
-	}
-	if msg.ID == "" {
-		err = cmn.ErrETLMissingUUID
+	if err = msg.Validate(); err != nil {
 		return
 	}
 	return etl.NewOfflineDataProvider(msg, t.si)
@@ -831,6 +826,13 @@ func (t *targetrunner) destroyBucket(c *txnServerCtx) error {
 		if !nlp.TryLock(c.timeout.netw / 2) {
 			return cmn.NewErrBckIsBusy(c.bck.Bck)
 		}
+		// (unknown bucket has no objects to retain)
+		if c.msg.Action == cmn.ActDestroyBck && c.bck.Init(t.owner.bmd) == nil {
+			if err := t.checkDestroyRetention(c.bck); err != nil {
+				nlp.Unlock()
+				return err
+			}
+		}
 		txn := newTxnBckBase("dlb", *c.bck)
 		txn.fillFromCtx(c)
 		if err := t.transactions.begin(txn); err != nil {
@@ -0,0 +1,109 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Access to prior versions of objects in buckets with `versioning.retain`
+// (see cluster/lversion.go for the on-disk layout and semantics)
+
+// replace the object with a delete marker (caller must take w-lock)
+func delMarker(lom *cluster.LOM) (int, error) {
+	if err := lom.DelMarker(); err != nil {
+		if cmn.IsObjNotExist(err) {
+			return http.StatusNotFound, err
+		}
+		return 0, err
+	}
+	return 0, nil
+}
+
+// DELETE /v1/objects/bucket-name/object-name?version=<ver>
+func (t *targetrunner) delObjVersion(lom *cluster.LOM, ver string, bypassGovernance bool) (int, error) {
+	if !lom.VersionConf().Retain {
+		return http.StatusBadRequest, fmt.Errorf("%s: bucket %s does not retain object versions", t.si, lom.Bck())
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if errCode, err := checkVersionRetention(lom, ver, bypassGovernance); err != nil {
+		return errCode, err
+	}
+	if err := lom.DelVersion(ver); err != nil {
+		if cmn.IsObjNotExist(err) {
+			return http.StatusNotFound, err
+		}
+		return 0, err
+	}
+	return 0, nil
+}
+
+// GET /v1/objects/bucket-name/object-name?version=<ver>
+// NOTE: range reads, archive extraction, and cold GET are not supported
+func (t *targetrunner) getObjVersion(w http.ResponseWriter, r *http.Request, lom *cluster.LOM, ver string) {
+	if !lom.Bck().IsAIS() {
+		t.writeErrf(w, r, "%s: cannot GET %s version %q: not an AIS bucket", t.si, lom, ver)
+		return
+	}
+	lom.Lock(false)
+	defer lom.Unlock(false)
+
+	err := lom.LoadLatest(true /*locked*/)
+	if err != nil && !cmn.IsObjNotExist(err) {
+		t.writeErr(w, r, err)
+		return
+	}
+	if err == nil && lom.Version() == ver {
+		if lom.IsDelMarker() {
+			t.writeErrStatusf(w, r, http.StatusMethodNotAllowed, "%s version %q is a delete marker", lom, ver)
+			return
+		}
+		t.sendObjVersion(w, r, lom)
+		return
+	}
+	vlom, err := lom.LoadVersion(ver)
+	if err != nil {
+		if cmn.IsObjNotExist(err) {
+			t.writeErrSilentf(w, r, http.StatusNotFound, "%s version %q does not exist", lom, ver)
+		} else {
+			t.writeErr(w, r, err)
+		}
+		return
+	}
+	t.sendObjVersion(w, r, vlom)
+	cluster.FreeLOM(vlom)
+}
+
+func (t *targetrunner) sendObjVersion(w http.ResponseWriter, r *http.Request, lom *cluster.LOM) {
+	fh, err := lom.OpenPlain() // (decrypting if need be)
+	if err != nil {
+		t.fsErr(err, lom.FQN)
+		t.writeErr(w, r, fmt.Errorf(cmn.FmtErrFailed, t.si, "open", lom.FQN, err))
+		return
+	}
+	oa := lom.PlainAttrs()
+	oa.ToHeader(w.Header())
+	buf, slab := t.gmm.AllocSize(oa.Size)
+	written, err := io.CopyBuffer(w, fh, buf)
+	slab.Free(buf)
+	cos.Close(fh)
+	if err != nil {
+		glog.Errorf(cmn.FmtErrFailed, t.si, "GET", lom.FQN, err)
+		return
+	}
+	t.statsT.AddMany(
+		cos.NamedVal64{Name: stats.GetThroughput, Value: written},
+		cos.NamedVal64{Name: stats.GetCount, Value: 1},
+	)
+}
@@ -0,0 +1,65 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/xreg"
+	"github.com/NVIDIA/aistore/xs"
+)
+
+// write-back (bucket property `write_policy`) - see xs/writeback.go
+
+const wbHousekeepInterval = time.Minute
+
+// mark the object and durably queue it (the caller holds the write lock)
+func (poi *putObjInfo) queueWriteBack() error {
+	lom := poi.lom
+	if poi.owt == cmn.OwtPut {
+		// (will be set upon writing to the remote backend)
+		lom.ObjAttrs().DelCustomKeys(cmn.SourceObjMD, cmn.CRC32CObjMD, cmn.ETag, cmn.MD5ObjMD, cmn.VersionObjMD)
+	}
+	lom.SetCustomKey(cmn.WriteBackObjMD, "true")
+	return xs.QueueWriteBack(poi.t.db, lom)
+}
+
+// NOTE: if the xaction cannot be renewed the object remains queued
+// and gets written later (see wbHousekeep)
+func (t *targetrunner) writeBack(lom *cluster.LOM) {
+	rns := xreg.RenewWriteBack(t, lom.Bck(), t.db)
+	if rns.Err != nil {
+		glog.Errorf("%s: %s: %v", t.si, lom, rns.Err)
+		return
+	}
+	xwb := rns.Entry.Get().(*xs.XactWriteBack)
+	xwb.Add(lom.ObjName)
+}
+
+// (re)start write-back for the buckets that have queued objects - in particular, upon restart -
+// and requeue the objects that are due to be retried
+func (t *targetrunner) wbHousekeep() time.Duration {
+	t.dqHousekeep(xs.WriteBackBuckets, xs.DropWriteBack, func(bck *cluster.Bck) (dqLoader, error) {
+		rns := xreg.RenewWriteBack(t, bck, t.db)
+		if rns.Err != nil {
+			return nil, rns.Err
+		}
+		return rns.Entry.Get().(*xs.XactWriteBack), nil
+	})
+	return wbHousekeepInterval
+}
+
+// `ais job start write-back BUCKET`: retry the objects that have failed to be written
+func (t *targetrunner) startWriteBack(bck *cluster.Bck) error {
+	rns := xreg.RenewWriteBack(t, bck, t.db)
+	if rns.Err != nil {
+		return rns.Err
+	}
+	xwb := rns.Entry.Get().(*xs.XactWriteBack)
+	return xwb.RetryFailed()
+}
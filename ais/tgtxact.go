@@ -155,6 +155,11 @@ func (t *targetrunner) cmdXactStart(xactMsg *xaction.QueryMsg, bck *cluster.Bck)
 		wg.Add(1)
 		go t.runStoreCleanup(xactMsg.ID, wg, xactMsg.Buckets...)
 		wg.Wait()
+	case cmn.ActLifecycle:
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		go t.runLifecycle(xactMsg.ID, wg, xactMsg.Buckets...)
+		wg.Wait()
 	case cmn.ActResilver:
 		if bck != nil {
 			glog.Errorf(erfmb, xactMsg.Kind, bck)
@@ -172,7 +177,7 @@ func (t *targetrunner) cmdXactStart(xactMsg *xaction.QueryMsg, bck *cluster.Bck)
 		wg.Wait()
 	// 2. with bucket
 	case cmn.ActPrefetchObjects:
-		args := &cmn.ListRangeMsg{}
+		args := &cmn.PrefetchMsg{}
 		rns := xreg.RenewPrefetch(xactMsg.ID, t, bck, args)
 		xact := rns.Entry.Get()
 		xact.AddNotif(&xaction.NotifXact{
@@ -186,6 +191,12 @@ func (t *targetrunner) cmdXactStart(xactMsg *xaction.QueryMsg, bck *cluster.Bck)
 		go xact.Run(nil)
 	case cmn.ActLoadLomCache:
 		return xreg.RenewBckLoadLomCache(t, xactMsg.ID, bck)
+	case cmn.ActWriteBack:
+		return t.startWriteBack(bck)
+	case cmn.ActReplicate:
+		return t.startReplicate(bck)
+	case cmn.ActResync:
+		return t.startResync(xactMsg.ID, bck)
 	// 3. cannot start
 	case cmn.ActPutCopies:
 		return fmt.Errorf("cannot start %q (is driven by PUTs into a mirrored bucket)", xactMsg)
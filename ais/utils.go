@@ -275,7 +275,7 @@ func withRetry(cond func() bool) (ok bool) {
 
 // TODO: !4455 comment
 func isETLRequest(query url.Values) bool {
-	return query.Get(cmn.URLParamUUID) != ""
+	return query.Get(cmn.URLParamUUID) != "" || query.Get(cmn.URLParamETLPipeline) != ""
 }
 
 func deploymentType() string {
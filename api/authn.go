@@ -76,6 +76,32 @@ func LoginUser(baseParams BaseParams, userID, pass string, expire *time.Duration
 	return token, nil
 }
 
+// Generate a new S3 access key for the user. The returned secret key cannot be
+// retrieved later.
+func AddS3KeyAuthN(baseParams BaseParams, userID string) (key *authn.S3Key, err error) {
+	baseParams.Method = http.MethodPost
+	err = DoHTTPReqResp(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPathUsers.Join(userID, cmn.S3Keys),
+	}, &key)
+	return
+}
+
+func GetS3KeysAuthN(baseParams BaseParams, userID string) ([]*authn.S3Key, error) {
+	keys := make([]*authn.S3Key, 0)
+	baseParams.Method = http.MethodGet
+	err := DoHTTPReqResp(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPathUsers.Join(userID, cmn.S3Keys),
+	}, &keys)
+	return keys, err
+}
+
+func DeleteS3KeyAuthN(baseParams BaseParams, userID, keyID string) error {
+	baseParams.Method = http.MethodDelete
+	return DoHTTPRequest(ReqParams{BaseParams: baseParams, Path: cmn.URLPathUsers.Join(userID, cmn.S3Keys, keyID)})
+}
+
 func RegisterClusterAuthN(baseParams BaseParams, cluSpec authn.Cluster) error {
 	msg := cos.MustMarshal(cluSpec)
 	baseParams.Method = http.MethodPost
@@ -192,6 +218,29 @@ func AddRoleAuthN(baseParams BaseParams, roleSpec *authn.Role) error {
 	})
 }
 
+func UpdateRoleAuthN(baseParams BaseParams, roleSpec *authn.Role) error {
+	msg := cos.MustMarshal(roleSpec)
+	baseParams.Method = http.MethodPut
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPathRoles.Join(roleSpec.Name),
+		Body:       msg,
+		Header:     http.Header{cmn.HdrContentType: []string{cmn.ContentJSON}},
+	})
+}
+
+// Remove per-bucket (and per-prefix) permissions of a given role
+func RemoveRoleACLsAuthN(baseParams BaseParams, role string, acls []*authn.Bucket) error {
+	msg := cos.MustMarshal(acls)
+	baseParams.Method = http.MethodDelete
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPathRoles.Join(role, cmn.Buckets),
+		Body:       msg,
+		Header:     http.Header{cmn.HdrContentType: []string{cmn.ContentJSON}},
+	})
+}
+
 func DeleteRoleAuthN(baseParams BaseParams, role string) error {
 	baseParams.Method = http.MethodDelete
 	return DoHTTPRequest(ReqParams{
@@ -163,17 +163,21 @@ func GetBucketsSummaries(baseParams BaseParams, query cmn.QueryBcks,
 //    * cmn.BucketPropsToUpdate (cmn/api.go)
 //
 // Bucket properties can be also changed at any time via SetBucketProps (above).
-func CreateBucket(baseParams BaseParams, bck cmn.Bck, props *cmn.BucketPropsToUpdate) error {
+func CreateBucket(baseParams BaseParams, bck cmn.Bck, props *cmn.BucketPropsToUpdate, query ...url.Values) error {
 	if err := bck.Validate(); err != nil {
 		return err
 	}
+	var q url.Values
+	if len(query) > 0 {
+		q = query[0]
+	}
 	baseParams.Method = http.MethodPost
 	return DoHTTPRequest(ReqParams{
 		BaseParams: baseParams,
 		Path:       cmn.URLPathBuckets.Join(bck.Name),
 		Body:       cos.MustMarshal(cmn.ActionMsg{Action: cmn.ActCreateBck, Value: props}),
 		Header:     http.Header{cmn.HdrContentType: []string{cmn.ContentJSON}},
-		Query:      cmn.AddBckToQuery(nil, bck),
+		Query:      cmn.AddBckToQuery(q, bck),
 	})
 }
 
@@ -39,6 +39,10 @@ func MDWritePolicy(v cmn.MDWritePolicy) *cmn.MDWritePolicy {
 	return &v
 }
 
+func DataWritePolicy(v cmn.DataWritePolicy) *cmn.DataWritePolicy {
+	return &v
+}
+
 // Duration returns a pointer to the time duration value passed in.
 func Duration(v time.Duration) *time.Duration {
 	return &v
@@ -63,9 +63,32 @@ func ETLStop(baseParams BaseParams, id string) (err error) {
 // TODO: "if query has UUID then the request is ETL" is not good enough. Add ETL-specific
 //       query param and change the examples/docs (!4455)
 func ETLObject(baseParams BaseParams, id string, bck cmn.Bck, objName string, w io.Writer) (err error) {
+	return ETLObjectWithArgs(baseParams, id, "", bck, objName, w)
+}
+
+// ETLObjectWithArgs transforms the object by the ETL with the given (per-request) arguments.
+func ETLObjectWithArgs(baseParams BaseParams, id, args string, bck cmn.Bck, objName string, w io.Writer) (err error) {
+	query := url.Values{cmn.URLParamUUID: []string{id}}
+	if args != "" {
+		if err = cmn.ValidateETLArgs(args); err != nil {
+			return
+		}
+		query.Set(cmn.URLParamETLArgs, args)
+	}
+	_, err = GetObject(baseParams, bck, objName, GetObjectInput{Writer: w, Query: query})
+	return
+}
+
+// ETLPipelineObject transforms the object by the ETL pipeline - the output of each stage
+// is the input of the next one.
+func ETLPipelineObject(baseParams BaseParams, pipeline cmn.ETLPipeline, bck cmn.Bck, objName string,
+	w io.Writer) (err error) {
+	if err = pipeline.Validate(); err != nil {
+		return
+	}
 	_, err = GetObject(baseParams, bck, objName, GetObjectInput{
 		Writer: w,
-		Query:  url.Values{cmn.URLParamUUID: []string{id}},
+		Query:  url.Values{cmn.URLParamETLPipeline: []string{string(cos.MustMarshal(pipeline))}},
 	})
 	return
 }
@@ -54,6 +54,13 @@ func PrefetchRange(baseParams BaseParams, bck cmn.Bck, rng string) (string, erro
 	return doListRangeRequest(baseParams, bck, cmn.ActPrefetchObjects, prefetchMsg)
 }
 
+// Prefetch sends request to prefetch a list or a range of objects from a remote bucket
+// or else the objects named in a manifest, possibly - continuously, ahead of the readers
+// (see cmn.PrefetchMsg).
+func Prefetch(baseParams BaseParams, bck cmn.Bck, msg cmn.PrefetchMsg) (string, error) {
+	return doListRangeRequest(baseParams, bck, cmn.ActPrefetchObjects, msg)
+}
+
 // EvictList sends request to evict a list of objects from a remote bucket.
 func EvictList(baseParams BaseParams, bck cmn.Bck, fileslist []string) (string, error) {
 	evictMsg := cmn.ListRangeMsg{ObjNames: fileslist}
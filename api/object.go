@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/textproto"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
@@ -42,6 +43,8 @@ type (
 		Cksum      *cos.Cksum
 		Reader     cos.ReadOpenCloser
 		Size       uint64 // optional
+		// Custom metadata of the object (optional; see also SetObjectCustomProps)
+		CustomMD cos.SimpleKVs
 		// Skip loading existing object's metadata in order to
 		// compare its Checksum and update its existing Version (if exists);
 		// can be used to reduce PUT latency when:
@@ -111,6 +114,9 @@ func (args *PutObjectArgs) put(reqArgs cmn.ReqArgs) (*http.Request, error) {
 	if args.Size != 0 {
 		req.ContentLength = int64(args.Size) // as per https://tools.ietf.org/html/rfc7230#section-3.3.2
 	}
+	for k, v := range args.CustomMD {
+		req.Header.Add(cmn.HdrObjCustomMD, k+"="+v)
+	}
 	setAuthToken(req, args.BaseParams)
 	return req, nil
 }
@@ -476,6 +482,27 @@ func RenameObject(baseParams BaseParams, bck cmn.Bck, oldName, newName string) e
 	})
 }
 
+// PresignObject returns URL that grants access to the object (via a given HTTP method)
+// without a token, until the URL expires. See cmn.ActValPresign for defaults.
+func PresignObject(baseParams BaseParams, bck cmn.Bck, objName string, args *cmn.ActValPresign) (string, error) {
+	var (
+		rel string
+		msg = cmn.ActionMsg{Action: cmn.ActPresignObject, Value: args}
+	)
+	baseParams.Method = http.MethodPost
+	err := DoHTTPReqResp(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPathObjects.Join(bck.Name, objName),
+		Body:       cos.MustMarshal(msg),
+		Header:     http.Header{cmn.HdrContentType: []string{cmn.ContentJSON}},
+		Query:      cmn.AddBckToQuery(nil, bck),
+	}, &rel)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(baseParams.URL, "/") + rel, nil
+}
+
 // PromoteFileOrDir promotes AIS-colocated files and directories to objects.
 // NOTE: advanced usage.
 func PromoteFileOrDir(args *PromoteArgs) error {
@@ -55,6 +55,9 @@ type (
 	TokenList struct {
 		Tokens  []string `json:"tokens"`
 		Version int64    `json:"version,string"`
+		// S3 access key ID => token of the key's owner; when defined (non-nil),
+		// replaces all existing S3 keys (see s3keys.go)
+		S3Keys map[string]string `json:"s3keys"`
 	}
 )
 
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/NVIDIA/aistore/cluster/mock"
+	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/dbdriver"
 	"github.com/NVIDIA/aistore/devtools/tassert"
@@ -172,3 +173,95 @@ func TestToken(t *testing.T) {
 		t.Errorf("Invalid error(must be 'token expired'): %v", err)
 	}
 }
+
+func TestPrefixACL(t *testing.T) {
+	var (
+		cluID  = "clu1"
+		shared = cmn.Bck{Name: "shared", Provider: cmn.ProviderAIS}
+		other  = cmn.Bck{Name: "other", Provider: cmn.ProviderAIS}
+		tbck   = shared
+	)
+	tbck.Ns.UUID = cluID
+	tk := &Token{
+		Clusters: []*Cluster{{ID: cluID, Access: cmn.AccessRO}},
+		Buckets: MergeBckACLs(nil, []*Bucket{
+			{Bck: tbck, Prefix: "teamA/", Access: cmn.AccessRW},
+			{Bck: tbck, Prefix: "teamA/private/", Access: cmn.AccessNone},
+			{Bck: tbck, Access: cmn.AceBckHEAD},
+		}),
+	}
+	tassert.Fatalf(t, len(tk.Buckets) == 3, "expected 3 ACLs, got %d", len(tk.Buckets))
+
+	tests := []struct {
+		bck     cmn.Bck
+		objName string
+		perms   cmn.AccessAttrs
+		ok      bool
+	}{
+		{shared, "teamA/obj", cmn.AcePUT, true},
+		{shared, "teamA/obj", cmn.AceGET | cmn.AceObjDELETE, true},
+		{shared, "teamA/private/obj", cmn.AceGET, false},
+		{shared, "teamB/obj", cmn.AceGET, false}, // per-bucket ACL overrides cluster-wide one
+		{shared, "", cmn.AceObjLIST, true},       // listing allowed by prefix, to be filtered
+		{shared, "", cmn.AceGET, false},
+		{other, "teamA/obj", cmn.AceGET, true}, // cluster-wide
+		{other, "teamA/obj", cmn.AcePUT, false},
+	}
+	for _, test := range tests {
+		err := tk.CheckObjPermissions(cluID, &test.bck, test.objName, test.perms)
+		tassert.Errorf(t, (err == nil) == test.ok, "%s/%s (%s): expected ok=%t, got %v",
+			test.bck, test.objName, test.perms.Describe(), test.ok, err)
+	}
+	tassert.Errorf(t, tk.HasPrefixACLs(cluID, &shared), "expected prefix ACLs for %s", shared)
+	tassert.Errorf(t, !tk.HasPrefixACLs(cluID, &other), "unexpected prefix ACLs for %s", other)
+	tassert.Errorf(t, !tk.HasPrefixACLs("clu2", &shared), "unexpected prefix ACLs for %s (clu2)", shared)
+
+	// update and remove
+	tk.Buckets = MergeBckACLs(tk.Buckets, []*Bucket{{Bck: tbck, Prefix: "teamA/private/", Access: cmn.AccessRO}})
+	tassert.Errorf(t, len(tk.Buckets) == 3, "expected 3 ACLs, got %d", len(tk.Buckets))
+	err := tk.CheckObjPermissions(cluID, &shared, "teamA/private/obj", cmn.AceGET)
+	tassert.Errorf(t, err == nil, "expected access to updated prefix, got %v", err)
+
+	tk.Buckets = RemoveBckACLs(tk.Buckets, []*Bucket{{Bck: tbck, Prefix: "teamA/"}, {Bck: tbck, Prefix: "teamA/private/"}})
+	tassert.Errorf(t, len(tk.Buckets) == 1, "expected 1 ACL, got %d", len(tk.Buckets))
+	tassert.Errorf(t, !tk.HasPrefixACLs(cluID, &shared), "unexpected prefix ACLs for %s", shared)
+}
+
+func TestS3Keys(t *testing.T) {
+	driver := mock.NewDBDriver()
+	mgr, err := NewUserManager(driver)
+	tassert.CheckFatal(t, err)
+	createUsers(mgr, t)
+	defer deleteUsers(mgr, true, t)
+
+	_, err = mgr.addS3Key("no-such-user")
+	tassert.Errorf(t, cmn.IsErrNotFound(err), "expected not-found error, got %v", err)
+
+	key, err := mgr.addS3Key(users[0])
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(key.ID) == s3KeyIDLen && len(key.Secret) == s3SecretLen, "invalid S3 key %+v", key)
+	tassert.Errorf(t, key.Secret == S3SecretKey(Conf.Secret(), key.ID), "secret key mismatch")
+	other, err := mgr.addS3Key(users[1])
+	tassert.CheckFatal(t, err)
+
+	keys, err := mgr.s3KeyList(users[0])
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(keys) == 1 && keys[0].ID == key.ID && keys[0].Secret == "", "unexpected keys %+v", keys)
+
+	tokens, err := mgr.genS3Tokens()
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(tokens) == 2, "expected 2 tokens, got %d", len(tokens))
+	tk, err := DecryptToken(tokens[key.ID], Conf.Secret())
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, tk.UserID == users[0] && tk.S3Key == key.ID, "unexpected token %+v", tk)
+
+	err = mgr.delS3Key(users[0], other.ID)
+	tassert.Errorf(t, cmn.IsErrNotFound(err), "deleted another user's key: %v", err)
+	tassert.CheckError(t, mgr.delS3Key(users[0], key.ID))
+
+	// deleting user deletes its keys
+	tassert.CheckFatal(t, mgr.delUser(users[1]))
+	keys, err = mgr.s3KeyList("")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(keys) == 0, "expected no keys, got %+v", keys)
+}
@@ -43,29 +43,42 @@ func (m *UserManager) broadcast(method, path string, body []byte) {
 				}
 			}
 			if err != nil {
-				glog.Errorf("Failed to sync revoked tokens with %q: %v", clu.ID, err)
+				glog.Errorf("Failed to sync tokens with %q: %v", clu.ID, err)
 			}
 		}(clu)
 	}
 	wg.Wait()
 }
 
-// Send valid and non-expired revoked token list to a cluster.
+// Send valid and non-expired revoked token list, and S3 keys, to a cluster.
 func (m *UserManager) syncTokenList(cluster *Cluster) {
 	tokenList, err := m.generateRevokedTokenList()
 	if err != nil {
 		glog.Errorf("failed to sync token list with %q: %v", cluster.ID, err)
 		return
 	}
-	if len(tokenList) == 0 {
+	if len(tokenList) != 0 {
+		body := cos.MustMarshal(TokenList{Tokens: tokenList})
+		m.syncCluster(cluster, http.MethodDelete, body, "revoked tokens")
+	}
+	s3Keys, err := m.genS3Tokens()
+	if err != nil {
+		glog.Errorf("failed to sync S3 keys with %q: %v", cluster.ID, err)
 		return
 	}
-	body := cos.MustMarshal(TokenList{Tokens: tokenList})
+	if len(s3Keys) != 0 {
+		body := cos.MustMarshal(TokenList{S3Keys: s3Keys})
+		m.syncCluster(cluster, http.MethodPut, body, "S3 keys")
+	}
+}
+
+func (m *UserManager) syncCluster(cluster *Cluster, method string, body []byte, tag string) {
+	var err error
 	for _, u := range cluster.URLs {
-		if err = m.proxyRequest(http.MethodDelete, u, cmn.Tokens, body); err == nil {
+		if err = m.proxyRequest(method, u, cmn.Tokens, body); err == nil {
 			break
 		}
-		err = fmt.Errorf("failed to sync revoked tokens with %q: %v", cluster.ID, err)
+		err = fmt.Errorf("failed to sync %s with %q: %v", tag, cluster.ID, err)
 	}
 	if err != nil {
 		glog.Error(err)
@@ -0,0 +1,160 @@
+// Package authn - authorization server for AIStore.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package authn
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/form3tech-oss/jwt-go"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// S3 credentials: AuthN issues users access keys to sign (AWS signature V4) requests
+// to AIS S3 API:
+// - the secret key is derived from the access key ID and AuthN secret (see S3SecretKey),
+//   and so it is never stored and is returned only once, when the key is created;
+// - upon any change, AuthN pushes the entire set of access keys to all registered
+//   clusters - each key with a non-expiring token that carries the key owner's
+//   permissions (see TokenList.S3Keys).
+
+const (
+	s3KeysCollection = "s3key"
+
+	s3KeyIDPrefix = "AK"
+	s3KeyIDLen    = 20 // same as AWS
+	s3SecretLen   = 40 // ditto
+)
+
+type S3Key struct {
+	ID      string    `json:"id"`
+	UserID  string    `json:"user_id"`
+	Created time.Time `json:"created"`
+	Secret  string    `json:"secret,omitempty"` // only when created
+}
+
+// S3SecretKey returns the secret key of a given access key.
+func S3SecretKey(secret, keyID string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(s3KeysCollection + "/" + keyID))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))[:s3SecretLen]
+}
+
+func genS3KeyID() string {
+	b := make([]byte, s3KeyIDLen)
+	_, err := rand.Read(b)
+	cos.AssertNoErr(err)
+	return s3KeyIDPrefix + base32.StdEncoding.EncodeToString(b)[:s3KeyIDLen-len(s3KeyIDPrefix)]
+}
+
+func (m *UserManager) addS3Key(userID string) (*S3Key, error) {
+	if _, err := m.db.GetString(usersCollection, userID); err != nil {
+		return nil, cmn.NewErrNotFound("user-manager: %s user %q", svcName, userID)
+	}
+	key := &S3Key{ID: genS3KeyID(), UserID: userID, Created: time.Now()}
+	if err := m.db.Set(s3KeysCollection, key.ID, key); err != nil {
+		return nil, err
+	}
+	go m.broadcastS3Keys(true /*force*/)
+	key.Secret = S3SecretKey(Conf.Secret(), key.ID)
+	return key, nil
+}
+
+func (m *UserManager) delS3Key(userID, keyID string) error {
+	key := &S3Key{}
+	if err := m.db.Get(s3KeysCollection, keyID, key); err != nil || key.UserID != userID {
+		return cmn.NewErrNotFound("user-manager: %s user %q S3 key %q", svcName, userID, keyID)
+	}
+	if err := m.db.Delete(s3KeysCollection, keyID); err != nil {
+		return err
+	}
+	go m.broadcastS3Keys(true /*force*/)
+	return nil
+}
+
+// all access keys or, if `userID` is defined, the keys of a given user
+func (m *UserManager) s3KeyList(userID string) ([]*S3Key, error) {
+	recs, err := m.db.GetAll(s3KeysCollection, "")
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*S3Key, 0, len(recs))
+	for _, rec := range recs {
+		key := &S3Key{}
+		if err := jsoniter.UnmarshalFromString(rec, key); err != nil {
+			glog.Errorf("invalid S3 key %q: %v", rec, err)
+			continue
+		}
+		if userID == "" || key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Created.Before(keys[j].Created) })
+	return keys, nil
+}
+
+// returns true if the user had any keys
+func (m *UserManager) delUserS3Keys(userID string) bool {
+	keys, err := m.s3KeyList(userID)
+	if err != nil || len(keys) == 0 {
+		return false
+	}
+	for _, key := range keys {
+		if err := m.db.Delete(s3KeysCollection, key.ID); err != nil {
+			glog.Errorf("failed to delete user %q S3 key %q: %v", userID, key.ID, err)
+		}
+	}
+	return true
+}
+
+// access key ID => token of the key's owner
+func (m *UserManager) genS3Tokens() (map[string]string, error) {
+	keys, err := m.s3KeyList("")
+	if err != nil {
+		return nil, err
+	}
+	tokens := make(map[string]string, len(keys))
+	Conf.RLock()
+	defer Conf.RUnlock()
+	expires := time.Now().Add(foreverTokenTime)
+	for _, key := range keys {
+		uInfo, err := m.lookupUser(key.UserID)
+		if err != nil {
+			glog.Errorf("S3 key %q: user %q not found: %v", key.ID, key.UserID, err)
+			continue
+		}
+		token, err := m.signToken(uInfo, expires, jwt.MapClaims{"s3key": key.ID})
+		if err != nil {
+			return nil, err
+		}
+		tokens[key.ID] = token
+	}
+	return tokens, nil
+}
+
+// update S3 keys on all clusters - upon adding and deleting the keys, as well as
+// modifying users and roles (the permissions of the keys' owners), unless there
+// are no keys
+func (m *UserManager) broadcastS3Keys(force bool) {
+	tokens, err := m.genS3Tokens()
+	if err != nil {
+		glog.Errorf("failed to generate S3 key list: %v", err)
+		return
+	}
+	if len(tokens) == 0 && !force {
+		return
+	}
+	body := cos.MustMarshal(TokenList{S3Keys: tokens})
+	m.broadcast(http.MethodPut, cmn.Tokens, body)
+}
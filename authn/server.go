@@ -162,6 +162,10 @@ func (a *Server) httpUserDel(w http.ResponseWriter, r *http.Request) {
 	if err = checkAuthorization(w, r); err != nil {
 		return
 	}
+	if len(apiItems) > 1 {
+		a.httpS3KeyDel(w, r, apiItems)
+		return
+	}
 	if err := a.users.delUser(apiItems[0]); err != nil {
 		glog.Errorf("Failed to delete user: %v\n", err)
 		cmn.WriteErrMsg(w, r, "Failed to delete user: "+err.Error())
@@ -173,6 +177,8 @@ func (a *Server) httpUserPost(w http.ResponseWriter, r *http.Request) {
 		return
 	} else if len(apiItems) == 0 {
 		a.userAdd(w, r)
+	} else if len(apiItems) > 1 {
+		a.httpS3KeyPost(w, r, apiItems)
 	} else {
 		a.userLogin(w, r)
 	}
@@ -231,7 +237,7 @@ func (a *Server) httpUserGet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if len(items) > 1 {
-		cmn.WriteErrMsg(w, r, "invalid request")
+		a.httpS3KeyGet(w, r, items)
 		return
 	}
 
@@ -267,6 +273,59 @@ func (a *Server) httpUserGet(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, uInfo, "user info")
 }
 
+// POST /v1/users/user-id/s3keys - generate a new S3 access key
+func (a *Server) httpS3KeyPost(w http.ResponseWriter, r *http.Request, items []string) {
+	if len(items) != 2 || items[1] != cmn.S3Keys {
+		cmn.WriteErrMsg(w, r, "invalid request")
+		return
+	}
+	if err := checkAuthorization(w, r); err != nil {
+		return
+	}
+	key, err := a.users.addS3Key(items[0])
+	if err != nil {
+		if cmn.IsErrNotFound(err) {
+			cmn.WriteErr(w, r, err, http.StatusNotFound)
+		} else {
+			cmn.WriteErr(w, r, err)
+		}
+		return
+	}
+	writeJSON(w, key, "add S3 key")
+}
+
+// GET /v1/users/user-id/s3keys - list user's S3 access keys (without secrets)
+func (a *Server) httpS3KeyGet(w http.ResponseWriter, r *http.Request, items []string) {
+	if len(items) != 2 || items[1] != cmn.S3Keys {
+		cmn.WriteErrMsg(w, r, "invalid request")
+		return
+	}
+	if err := checkAuthorization(w, r); err != nil {
+		return
+	}
+	keys, err := a.users.s3KeyList(items[0])
+	if err != nil {
+		cmn.WriteErr(w, r, err)
+		return
+	}
+	writeJSON(w, keys, "list S3 keys")
+}
+
+// DELETE /v1/users/user-id/s3keys/key-id - delete S3 access key
+func (a *Server) httpS3KeyDel(w http.ResponseWriter, r *http.Request, items []string) {
+	if len(items) != 3 || items[1] != cmn.S3Keys {
+		cmn.WriteErrMsg(w, r, "invalid request")
+		return
+	}
+	if err := a.users.delS3Key(items[0], items[2]); err != nil {
+		if cmn.IsErrNotFound(err) {
+			cmn.WriteErr(w, r, err, http.StatusNotFound)
+		} else {
+			cmn.WriteErr(w, r, err)
+		}
+	}
+}
+
 // Checks if the request header contains super-user credentials and they are
 // valid. Super-user is a user created at deployment time that cannot be
 // deleted/created via REST API
@@ -496,6 +555,25 @@ func (a *Server) httpRoleDel(w http.ResponseWriter, r *http.Request) {
 	}
 
 	roleID := apiItems[0]
+	if len(apiItems) > 1 {
+		// remove the role's per-bucket (per-prefix) permissions
+		if len(apiItems) != 2 || apiItems[1] != cmn.Buckets {
+			cmn.WriteErrMsg(w, r, "invalid request")
+			return
+		}
+		acls := make([]*Bucket, 0, 1)
+		if err := cmn.ReadJSON(w, r, &acls); err != nil {
+			return
+		}
+		if err := a.users.delRoleACLs(roleID, acls); err != nil {
+			if cmn.IsErrNotFound(err) {
+				cmn.WriteErr(w, r, err, http.StatusNotFound)
+			} else {
+				cmn.WriteErr(w, r, err)
+			}
+		}
+		return
+	}
 	if err = a.users.delRole(roleID); err != nil {
 		cmn.WriteErr(w, r, err)
 	}
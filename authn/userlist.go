@@ -219,7 +219,13 @@ func (m *UserManager) delUser(userID string) error {
 	if userID == adminID {
 		return errors.New("cannot remove built-in administrator account")
 	}
-	return m.db.Delete(usersCollection, userID)
+	if err := m.db.Delete(usersCollection, userID); err != nil {
+		return err
+	}
+	if m.delUserS3Keys(userID) {
+		go m.broadcastS3Keys(true /*force*/)
+	}
+	return nil
 }
 
 // Unregister an existing cluster
@@ -236,7 +242,11 @@ func (m *UserManager) delRole(role string) error {
 	if role == AdminRole {
 		return errors.New("cannot remove built-in administrator role")
 	}
-	return m.db.Delete(rolesCollection, role)
+	if err := m.db.Delete(rolesCollection, role); err != nil {
+		return err
+	}
+	go m.broadcastS3Keys(false)
+	return nil
 }
 
 // Updates an existing user. The function invalidates user tokens after
@@ -260,7 +270,11 @@ func (m *UserManager) updateUser(userID string, updateReq *User) error {
 	uInfo.Clusters = MergeClusterACLs(uInfo.Clusters, updateReq.Clusters)
 	uInfo.Buckets = MergeBckACLs(uInfo.Buckets, updateReq.Buckets)
 
-	return m.db.Set(usersCollection, userID, uInfo)
+	if err := m.db.Set(usersCollection, userID, uInfo); err != nil {
+		return err
+	}
+	go m.broadcastS3Keys(false)
+	return nil
 }
 
 // Updates an existing role
@@ -283,7 +297,28 @@ func (m *UserManager) updateRole(role string, updateReq *Role) error {
 	rInfo.Clusters = MergeClusterACLs(rInfo.Clusters, updateReq.Clusters)
 	rInfo.Buckets = MergeBckACLs(rInfo.Buckets, updateReq.Buckets)
 
-	return m.db.Set(rolesCollection, role, rInfo)
+	if err := m.db.Set(rolesCollection, role, rInfo); err != nil {
+		return err
+	}
+	go m.broadcastS3Keys(false)
+	return nil
+}
+
+// Removes per-bucket (and per-prefix) permissions of an existing role
+func (m *UserManager) delRoleACLs(role string, acls []*Bucket) error {
+	if role == AdminRole {
+		return errors.New("cannot modify built-in administrator role")
+	}
+	rInfo := &Role{}
+	if err := m.db.Get(rolesCollection, role, rInfo); err != nil {
+		return cmn.NewErrNotFound("user-manager: %s role %q", svcName, role)
+	}
+	rInfo.Buckets = RemoveBckACLs(rInfo.Buckets, acls)
+	if err := m.db.Set(rolesCollection, role, rInfo); err != nil {
+		return err
+	}
+	go m.broadcastS3Keys(false)
+	return nil
 }
 
 func (m *UserManager) lookupRole(roleID string) (*Role, error) {
@@ -314,7 +349,11 @@ func (m *UserManager) updateCluster(cluID string, info *Cluster) error {
 	if len(info.URLs) != 0 {
 		clu.URLs = info.URLs
 	}
-	return m.db.Set(clustersCollection, cluID, clu)
+	if err := m.db.Set(clustersCollection, cluID, clu); err != nil {
+		return err
+	}
+	go m.syncTokenList(clu)
+	return nil
 }
 
 // Before putting a list of cluster permissions to a token, cluster aliases
@@ -379,26 +418,29 @@ func (m *UserManager) issueToken(userID, pwd string, ttl *time.Duration) (string
 		expDelta = foreverTokenTime
 	}
 	expires = issued.Add(expDelta)
+	return m.signToken(uInfo, expires, nil)
+}
 
-	// put all useful info into token: who owns the token, when it was issued,
-	// when it expires and credentials to log in AWS, GCP etc.
-	// If a user is a super user, it is enough to pass only isAdmin marker
-	var t *jwt.Token
+// put all useful info into token: who owns the token, when it was issued,
+// when it expires and credentials to log in AWS, GCP etc.
+// If a user is a super user, it is enough to pass only isAdmin marker
+// (Caller must take Conf read lock.)
+func (m *UserManager) signToken(uInfo *User, expires time.Time, extra jwt.MapClaims) (string, error) {
+	claims := jwt.MapClaims{
+		"expires":  expires,
+		"username": uInfo.ID,
+	}
 	if uInfo.IsAdmin() {
-		t = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-			"expires":  expires,
-			"username": userID,
-			"admin":    true,
-		})
+		claims["admin"] = true
 	} else {
 		m.fixClusterIDs(uInfo.Clusters)
-		t = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-			"expires":  expires,
-			"username": userID,
-			"buckets":  uInfo.Buckets,
-			"clusters": uInfo.Clusters,
-		})
+		claims["buckets"] = uInfo.Buckets
+		claims["clusters"] = uInfo.Clusters
+	}
+	for k, v := range extra {
+		claims[k] = v
 	}
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := t.SignedString([]byte(Conf.Server.Secret))
 	if err != nil {
 		return "", fmt.Errorf("failed to generate token: %v", err)
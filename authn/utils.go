@@ -7,6 +7,7 @@ package authn
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
@@ -39,9 +40,11 @@ type (
 		Access cmn.AccessAttrs `json:"perm,string,omitempty"`
 		URLs   []string        `json:"urls,omitempty"`
 	}
-	// Permissions for a single bucket
+	// Permissions for a single bucket or, if Prefix is defined, for the objects
+	// in the bucket whose names start with the prefix
 	Bucket struct {
 		Bck    cmn.Bck         `json:"bck"`
+		Prefix string          `json:"prefix,omitempty"`
 		Access cmn.AccessAttrs `json:"perm,string"`
 	}
 	Role struct {
@@ -59,6 +62,7 @@ type (
 		Clusters []*Cluster `json:"clusters"`
 		Buckets  []*Bucket  `json:"buckets,omitempty"`
 		IsAdmin  bool       `json:"admin"`
+		S3Key    string     `json:"s3key,omitempty"` // S3 access key ID (see s3keys.go)
 	}
 	ClusterList struct {
 		Clusters map[string]*Cluster `json:"clusters,omitempty"`
@@ -104,28 +108,77 @@ func (tk *Token) aclForCluster(clusterID string) (perms cmn.AccessAttrs, ok bool
 	return 0, false
 }
 
+// For AuthN all buckets are external, so they have UUIDs. To correctly
+// compare with local bucket, token's bucket should be fixed.
+func (b *Bucket) match(clusterID string, bck *cmn.Bck) bool {
+	if b.Bck.Ns.UUID != clusterID {
+		return false
+	}
+	tbBck := b.Bck
+	tbBck.Ns.UUID = ""
+	return tbBck.Equal(*bck)
+}
+
+func (b *Bucket) String() string {
+	if b.Prefix == "" {
+		return b.Bck.String()
+	}
+	return b.Bck.String() + "/" + b.Prefix
+}
+
 func (tk *Token) aclForBucket(clusterID string, bck *cmn.Bck) (perms cmn.AccessAttrs, ok bool) {
 	for _, b := range tk.Buckets {
-		tbBck := b.Bck
-		if tbBck.Ns.UUID != clusterID {
-			continue
-		}
-		// For AuthN all buckets are external, so they have UUIDs. To correctly
-		// compare with local bucket, token's bucket should be fixed.
-		tbBck.Ns.UUID = ""
-		if b.Bck.Equal(*bck) {
+		if b.Prefix == "" && b.match(clusterID, bck) {
 			return b.Access, true
 		}
 	}
 	return 0, false
 }
 
+// the longest prefix that matches the object name wins
+func (tk *Token) aclForObject(clusterID string, bck *cmn.Bck, objName string) (perms cmn.AccessAttrs, ok bool) {
+	var longest int
+	for _, b := range tk.Buckets {
+		if b.Prefix == "" || len(b.Prefix) <= longest || !strings.HasPrefix(objName, b.Prefix) {
+			continue
+		}
+		if b.match(clusterID, bck) {
+			perms, ok, longest = b.Access, true, len(b.Prefix)
+		}
+	}
+	return
+}
+
+// HasPrefixACLs returns true if the token contains prefix-scoped permissions for
+// the bucket - in which case, the bucket's listing must be filtered (see CheckObjPermissions).
+func (tk *Token) HasPrefixACLs(clusterID string, bck *cmn.Bck) bool {
+	if tk.IsAdmin {
+		return false
+	}
+	for _, b := range tk.Buckets {
+		if b.Prefix != "" && b.match(clusterID, bck) {
+			return true
+		}
+	}
+	return false
+}
+
 // A user has two-level permissions: cluster-wide and on per bucket basis.
 // To be able to access data, a user must have either permission. This
 // allows creating users, e.g, with read-only access to the entire cluster,
 // and read-write access to a single bucket.
 // Per-bucket ACL overrides cluster-wide one.
 func (tk *Token) CheckPermissions(clusterID string, bck *cmn.Bck, perms cmn.AccessAttrs) error {
+	return tk.CheckObjPermissions(clusterID, bck, "", perms)
+}
+
+// Same as above, plus prefix-scoped permissions: the ACL of the longest bucket
+// prefix that the object name starts with overrides both per-bucket and
+// cluster-wide ones.
+// Without object name (bucket-level access), the bucket can be listed and HEAD-ed
+// if any of its prefixes allows it - the caller is then expected to filter the
+// listing.
+func (tk *Token) CheckObjPermissions(clusterID string, bck *cmn.Bck, objName string, perms cmn.AccessAttrs) error {
 	if tk.IsAdmin {
 		return nil
 	}
@@ -147,19 +200,32 @@ func (tk *Token) CheckPermissions(clusterID string, bck *cmn.Bck, perms cmn.Acce
 		return nil
 	}
 
-	// Check only bucket specific permissions.
+	// Check only bucket and prefix specific permissions.
 	debug.AssertMsg(bck != nil, "Requested bucket permissions without bucket")
+	if objName != "" {
+		if prefACL, ok := tk.aclForObject(clusterID, bck, objName); ok {
+			if prefACL.Has(objPerms) {
+				return nil
+			}
+			return ErrNoPermissions
+		}
+	}
 	bckACL, bckOk := tk.aclForBucket(clusterID, bck)
 	if bckOk {
 		if bckACL.Has(objPerms) {
 			return nil
 		}
-		return ErrNoPermissions
+	} else if cluOk && cluACL.Has(objPerms) {
+		return nil
 	}
-	if !cluOk || !cluACL.Has(objPerms) {
-		return ErrNoPermissions
+	if objName == "" && objPerms&^(cmn.AceObjLIST|cmn.AceBckHEAD) == 0 {
+		for _, b := range tk.Buckets {
+			if b.Prefix != "" && b.Access.Has(objPerms) && b.match(clusterID, bck) {
+				return nil
+			}
+		}
 	}
-	return nil
+	return ErrNoPermissions
 }
 
 func (uInfo *User) IsAdmin() bool {
@@ -175,19 +241,37 @@ func MergeBckACLs(oldACLs, newACLs []*Bucket) []*Bucket {
 	for _, n := range newACLs {
 		found := false
 		for _, o := range oldACLs {
-			if o.Bck.Equal(n.Bck) {
+			if o.Bck.Equal(n.Bck) && o.Prefix == n.Prefix {
 				found = true
 				o.Access = n.Access
 				break
 			}
-			if !found {
-				oldACLs = append(oldACLs, n)
-			}
+		}
+		if !found {
+			oldACLs = append(oldACLs, n)
 		}
 	}
 	return oldACLs
 }
 
+// RemoveBckACLs removes per-bucket (and per-prefix) permissions
+func RemoveBckACLs(oldACLs, delACLs []*Bucket) []*Bucket {
+	acls := oldACLs[:0]
+	for _, o := range oldACLs {
+		found := false
+		for _, d := range delACLs {
+			if o.Bck.Equal(d.Bck) && o.Prefix == d.Prefix {
+				found = true
+				break
+			}
+		}
+		if !found {
+			acls = append(acls, o)
+		}
+	}
+	return acls
+}
+
 func MergeClusterACLs(oldACLs, newACLs []*Cluster) []*Cluster {
 	for _, n := range newACLs {
 		found := false
@@ -7,6 +7,7 @@ package cluster
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cmn"
@@ -292,6 +293,15 @@ func (lom *LOM) Copy2FQN(dstFQN string, buf []byte) (dst *LOM, err error) {
 	if !dst.Bucket().Equal(lom.Bucket()) {
 		// The copy will be in a new bucket - completely separate object. Hence, we have to set initial version.
 		dst.SetVersion(lomInitialVersion)
+		dst.SetLastModified(time.Now())
+	}
+	if dst.Uname() != lom.Uname() {
+		// new object: does not inherit the source's retention (persisted below, along with the rest)
+		if dst.Bprops().Retention.Enabled {
+			dst.SetRetention(time.Now())
+		} else {
+			dst.md.DelCustomKeys(retentionKeys...)
+		}
 	}
 
 	workFQN := fs.CSM.Gen(dst, fs.WorkfileType, fs.WorkfileCopy)
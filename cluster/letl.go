@@ -0,0 +1,63 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Cached results of on-the-fly transformations (see etl/cache.go) are stored next to the
+// object (same mountpath) as <object-name>.<key> (fs.ETLCacheType). The key identifies
+// the ETL, its arguments, and the version and checksum of the object - a modified object
+// never matches the cached results of its prior content. The latter are removed when the
+// object gets overwritten or removed (otherwise, they are eventually evicted by LRU).
+
+// ETLCacheFQN returns FQN of the cached result of ETL identified by the key.
+func (lom *LOM) ETLCacheFQN(key string) string {
+	return fs.CSM.Gen(lom, fs.ETLCacheType, key)
+}
+
+// RemoveETLCache removes all cached results of ETLs (if any) of the object.
+func (lom *LOM) RemoveETLCache() {
+	// fast path: nothing was ever cached in the bucket (on this mountpath)
+	if _, err := os.Stat(lom.mpathInfo.MakePathCT(lom.Bucket(), fs.ETLCacheType)); err != nil {
+		return
+	}
+	var (
+		fqn       = lom.mpathInfo.MakePathFQN(lom.Bucket(), fs.ETLCacheType, lom.ObjName)
+		dir       = filepath.Dir(fqn)
+		prefix    = filepath.Base(fqn) + "."
+		removeErr error
+	)
+	f, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	names, err := f.Readdirnames(-1)
+	f.Close()
+	if err != nil {
+		return
+	}
+	for _, name := range names {
+		if len(name) != len(prefix)+fs.ETLCacheKeyLen || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if _, err := strconv.ParseUint(name[len(prefix):], 16, 64); err != nil {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			removeErr = err
+		}
+	}
+	if removeErr != nil {
+		glog.Errorf("%s: failed to remove cached ETL results: %v", lom, removeErr)
+	}
+}
@@ -0,0 +1,57 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Lifecycle (bucket property `lifecycle`) expires objects by the time they were
+// written and retained versions - by the time they became noncurrent. Both times
+// are stored in the object's metadata (rather than taken from the filesystem) so
+// that they survive migration, mirroring, and restoring of the object.
+//
+// Objects written prior to enabling lifecycle do not have the time of writing -
+// the modification time of their file is used instead (not the access time, which
+// keeps being updated as long as the object is read).
+
+// SetLastModified stores the time of writing if the bucket has lifecycle enabled.
+func (lom *LOM) SetLastModified(now time.Time) {
+	if !lom.Bprops().Lifecycle.Enabled {
+		lom.md.DelCustomKeys(cmn.LastModifiedObjMD)
+		return
+	}
+	lom.SetCustomKey(cmn.LastModifiedObjMD, strconv.FormatInt(now.UnixNano(), 10))
+}
+
+// LastModifiedUnix returns the time of writing or, if unknown, file modification time.
+func (lom *LOM) LastModifiedUnix() int64 {
+	if v, ok := lom.GetCustomKey(cmn.LastModifiedObjMD); ok {
+		if tu, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return tu
+		}
+	}
+	if finfo, err := os.Stat(lom.FQN); err == nil {
+		return finfo.ModTime().UnixNano()
+	}
+	if atime := lom.md.Atime; atime < 0 {
+		return -atime // (prefetch)
+	}
+	return lom.md.Atime
+}
+
+// NoncurrentUnix returns the time the retained version became noncurrent (0 if unknown).
+func (lom *LOM) NoncurrentUnix() int64 {
+	v, ok := lom.GetCustomKey(cmn.NoncurrentObjMD)
+	if !ok {
+		return 0
+	}
+	tu, _ := strconv.ParseInt(v, 10, 64)
+	return tu
+}
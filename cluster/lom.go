@@ -162,7 +162,7 @@ func (lom *LOM) LIF() (lif LIF) {
 func (lom *LOM) ObjAttrs() *cmn.ObjAttrs { return &lom.md.ObjAttrs }
 
 // LOM == remote-object equality check
-func (lom *LOM) Equal(rem cmn.ObjAttrsHolder) (equal bool) { return lom.ObjAttrs().Equal(rem) }
+func (lom *LOM) Equal(rem cmn.ObjAttrsHolder) (equal bool) { return lom.PlainAttrs().Equal(rem) }
 
 func (lom *LOM) CopyAttrs(oah cmn.ObjAttrsHolder, skipCksum bool) {
 	lom.md.ObjAttrs.CopyFrom(oah, skipCksum)
@@ -242,6 +242,18 @@ func (lom *LOM) WritePolicy() (p cmn.MDWritePolicy) {
 	return
 }
 
+// (write-back) the object is yet to be written to its remote backend
+func (lom *LOM) WriteBackPending() bool {
+	_, ok := lom.md.GetCustomKey(cmn.WriteBackObjMD)
+	return ok
+}
+
+// (replication) the object is a replica of another bucket's object
+func (lom *LOM) IsReplica() bool {
+	_, ok := lom.md.GetCustomKey(cmn.ReplicaObjMD)
+	return ok
+}
+
 func (lom *LOM) loaded() bool { return lom.md.bckID != 0 }
 
 func (lom *LOM) HrwTarget(smap *Smap) (tsi *Snode, local bool, err error) {
@@ -575,6 +587,9 @@ func (lom *LOM) Load(cacheit, locked bool) (err error) {
 	if err != nil {
 		return
 	}
+	if lom.IsDelMarker() {
+		return errDelMarker(lom) // see lversion.go
+	}
 	if cacheit && lcache != nil {
 		md := lom.md
 		lcache.Store(lom.md.uname, &md)
@@ -690,6 +705,7 @@ func (lom *LOM) Remove() (err error) {
 			err = erc
 		}
 	}
+	lom.RemoveETLCache()
 	lom.md.bckID = 0
 	return
 }
@@ -28,12 +28,12 @@ func (*LDP) Reader(lom *LOM) (cos.ReadOpenCloser, cmn.ObjAttrsHolder, error) {
 
 	lom.Lock(false)
 	if lomLoadErr = lom.Load(false /*cache it*/, true /*locked*/); lomLoadErr == nil {
-		var file *cos.FileHandle
-		if file, err = cos.NewFileHandle(lom.FQN); err != nil {
+		var file cos.ReadOpenCloser
+		if file, err = lom.OpenPlain(); err != nil { // (decrypting if need be - see lsse.go)
 			lom.Unlock(false)
 			return nil, nil, fmt.Errorf(cmn.FmtErrFailed, "LOMReader", "open", lom.FQN, err)
 		}
-		return cos.NewDeferROC(file, func() { lom.Unlock(false) }), lom, nil
+		return cos.NewDeferROC(file, func() { lom.Unlock(false) }), lom.PlainAttrs(), nil
 	}
 
 	// LOM loading error has occurred
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/NVIDIA/aistore/cluster"
@@ -70,6 +71,7 @@ var _ = Describe("LOM", func() {
 
 	_ = fs.CSM.Reg(fs.ObjectType, &fs.ObjectContentResolver{})
 	_ = fs.CSM.Reg(fs.WorkfileType, &fs.WorkfileContentResolver{})
+	_ = fs.CSM.Reg(fs.ObjVersionType, &fs.ObjVersionContentResolver{})
 
 	bmd := cluster.NewBaseBownerMock(
 		cluster.NewBck(
@@ -78,7 +80,12 @@ var _ = Describe("LOM", func() {
 		),
 		cluster.NewBck(
 			bucketLocalB, cmn.ProviderAIS, cmn.NsGlobal,
-			&cmn.BucketProps{Cksum: cmn.CksumConf{Type: cos.ChecksumXXHash}, LRU: cmn.LRUConf{Enabled: true}, BID: 2},
+			&cmn.BucketProps{
+				Cksum:     cmn.CksumConf{Type: cos.ChecksumXXHash},
+				LRU:       cmn.LRUConf{Enabled: true},
+				Lifecycle: cmn.LifecycleConf{Enabled: true},
+				BID:       2,
+			},
 		),
 		cluster.NewBck(
 			bucketLocalC, cmn.ProviderAIS, cmn.NsGlobal,
@@ -323,6 +330,36 @@ var _ = Describe("LOM", func() {
 			})
 		})
 
+		Describe("LastModified", func() {
+			var (
+				written = time.Unix(1500000000, 0)
+				atime   = time.Unix(1600000000, 0)
+			)
+			testObjectName := "foldr/test-obj.ext"
+
+			It("should use file mtime (not atime) if lifecycle is disabled", func() {
+				var (
+					mtime = time.Unix(1400000000, 0)
+					lom   = filePut(mis[0].MakePathFQN(localBckA, fs.ObjectType, testObjectName), 0)
+				)
+				lom.SetLastModified(written)
+				lom.SetAtimeUnix(atime.UnixNano())
+				Expect(persist(lom)).NotTo(HaveOccurred())
+				Expect(os.Chtimes(lom.FQN, atime, mtime)).NotTo(HaveOccurred())
+				Expect(lom.Load(false, false)).NotTo(HaveOccurred())
+				Expect(lom.LastModifiedUnix()).To(Equal(mtime.UnixNano()))
+			})
+			It("should persist time of writing if lifecycle is enabled", func() {
+				lom := filePut(mis[0].MakePathFQN(localBckB, fs.ObjectType, testObjectName), 0)
+				lom.SetLastModified(written)
+				lom.SetAtimeUnix(atime.UnixNano())
+				Expect(persist(lom)).NotTo(HaveOccurred())
+				lom.Uncache(false)
+				Expect(lom.Load(false, false)).NotTo(HaveOccurred())
+				Expect(lom.LastModifiedUnix()).To(Equal(written.UnixNano()))
+			})
+		})
+
 		Describe("checksum", func() {
 			testFileSize := 456
 			testObjectName := "cksum-foldr/test-obj.ext"
@@ -557,6 +594,31 @@ var _ = Describe("LOM", func() {
 				Expect(err).ToNot(HaveOccurred())
 				Expect(lom.Version()).To(BeEquivalentTo(desiredVersion))
 			})
+
+			It("should store received retained version", func() {
+				var (
+					lom        = filePut(localFQN, 0)
+					noncurrent = time.Unix(1500000000, 0)
+					oa         = &cmn.ObjAttrs{Size: 5, Ver: "3"}
+				)
+				oa.SetCustomKey(cmn.NoncurrentObjMD, strconv.FormatInt(noncurrent.UnixNano(), 10))
+				Expect(lom.PutVersion(strings.NewReader("hello"), oa, nil)).NotTo(HaveOccurred())
+
+				vers, err := lom.Versions()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(vers).To(Equal([]string{"3"}))
+				vlom, err := lom.LoadVersion("3")
+				Expect(err).NotTo(HaveOccurred())
+				defer cluster.FreeLOM(vlom)
+				Expect(vlom.SizeBytes()).To(BeEquivalentTo(5))
+				Expect(vlom.NoncurrentUnix()).To(Equal(noncurrent.UnixNano()))
+				finfo, err := os.Stat(vlom.FQN)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(finfo.ModTime().Equal(noncurrent)).To(BeTrue())
+
+				err = lom.PutVersion(strings.NewReader("x"), &cmn.ObjAttrs{Size: 1, Ver: "x"}, nil)
+				Expect(err).To(HaveOccurred())
+			})
 		})
 
 		Describe("CustomMD", func() {
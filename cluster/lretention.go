@@ -0,0 +1,106 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+)
+
+// Retention (WORM) of objects (bucket property `retention`):
+// - when written, the object inherits the bucket's retention mode and gets
+//   retain-until = now + retention period; both are stored as custom metadata
+//   (see cmn.RetentionModeObjMD et al.), so that changing (or disabling) the bucket's
+//   retention does not affect objects that are already stored;
+// - legal hold is independent of the retention period and stays on until removed;
+// - retained objects cannot be deleted, overwritten, renamed, or appended to;
+//   in governance mode (only) the restriction can be bypassed.
+
+const retentionSepa = "|"
+
+var retentionKeys = []string{cmn.RetentionModeObjMD, cmn.RetainUntilObjMD, cmn.LegalHoldObjMD}
+
+// RetentionKeys returns custom metadata keys that are reserved for retention.
+func RetentionKeys() []string { return retentionKeys }
+
+func (lom *LOM) LegalHold() bool {
+	v, ok := lom.md.GetCustomKey(cmn.LegalHoldObjMD)
+	return ok && cos.IsParseBool(v)
+}
+
+// Retention returns the object's retention mode and retain-until time (zero if none).
+func (lom *LOM) Retention() (mode string, until time.Time) {
+	v, ok := lom.md.GetCustomKey(cmn.RetainUntilObjMD)
+	if !ok {
+		return
+	}
+	until, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		// NOTE: retain forever rather than never
+		until = time.Unix(1<<62, 0)
+	}
+	mode, _ = lom.md.GetCustomKey(cmn.RetentionModeObjMD)
+	return
+}
+
+// CheckRetention returns cmn.ErrObjRetained if the object cannot be modified (or deleted).
+func (lom *LOM) CheckRetention(bypassGovernance bool) error {
+	if lom.LegalHold() {
+		return cmn.NewErrObjRetained(lom.String(), "", time.Time{}, true)
+	}
+	mode, until := lom.Retention()
+	if until.IsZero() || time.Now().After(until) {
+		return nil
+	}
+	if mode == cmn.RetentionGovernance && bypassGovernance {
+		return nil
+	}
+	return cmn.NewErrObjRetained(lom.String(), mode, until, false)
+}
+
+// RetentionMD returns the object's retention metadata packed as a single string
+// (empty if none) - to be restored by SetRetentionMD (e.g., when erasure coding).
+func (lom *LOM) RetentionMD() string {
+	var (
+		vals = make([]string, len(retentionKeys))
+		ok   bool
+	)
+	for i, key := range retentionKeys {
+		if v, exists := lom.md.GetCustomKey(key); exists {
+			vals[i], ok = v, true
+		}
+	}
+	if !ok {
+		return ""
+	}
+	return strings.Join(vals, retentionSepa)
+}
+
+func (lom *LOM) SetRetentionMD(s string) {
+	lom.md.DelCustomKeys(retentionKeys...)
+	if s == "" {
+		return
+	}
+	for i, v := range strings.SplitN(s, retentionSepa, len(retentionKeys)) {
+		if v != "" {
+			lom.SetCustomKey(retentionKeys[i], v)
+		}
+	}
+}
+
+// SetRetention removes retention metadata that the object may have come with and,
+// if the bucket's retention is enabled, applies the latter.
+func (lom *LOM) SetRetention(now time.Time) {
+	lom.md.DelCustomKeys(retentionKeys...)
+	conf := &lom.Bprops().Retention
+	if !conf.Enabled || conf.Period == 0 {
+		return
+	}
+	lom.SetCustomKey(cmn.RetentionModeObjMD, conf.Mode)
+	lom.SetCustomKey(cmn.RetainUntilObjMD, now.Add(conf.Period.D()).UTC().Format(time.RFC3339Nano))
+}
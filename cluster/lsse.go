@@ -0,0 +1,111 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"io"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/sse"
+)
+
+// Server-side encryption at rest (bucket property `encryption`):
+// - the file of an encrypted object contains ciphertext (see sse package), and the
+//   object's size and checksum (as in: lom.SizeBytes() and lom.Checksum()) describe
+//   the ciphertext as well - which is why mirroring, erasure coding, rebalancing,
+//   and copying (between the buckets of this cluster) work with encrypted objects as is;
+// - the plaintext size and checksum, and the wrapped data key are stored as
+//   custom metadata (cmn.SSEObjMD); the latter, and not the bucket configuration,
+//   determines whether a given object must be decrypted;
+// - user-facing attributes of the object are PlainAttrs().
+
+type sseROC struct {
+	*sse.Reader
+	fh *cos.FileHandle
+}
+
+// interface guard
+var _ cos.ReadOpenCloser = (*sseROC)(nil)
+
+func (lom *LOM) IsEncrypted() bool {
+	_, ok := lom.md.GetCustomKey(cmn.SSEObjMD)
+	return ok
+}
+
+// SSEMD returns encryption metadata of the object or nil if the object is not encrypted.
+func (lom *LOM) SSEMD() (*sse.ObjMD, error) {
+	v, ok := lom.md.GetCustomKey(cmn.SSEObjMD)
+	if !ok {
+		return nil, nil
+	}
+	return sse.UnpackObjMD(v)
+}
+
+// PlainAttrs returns user-facing attributes of the object: same as ObjAttrs() unless
+// the object is encrypted, in which case the returned attributes are a copy that carries
+// the plaintext size and checksum.
+func (lom *LOM) PlainAttrs() *cmn.ObjAttrs {
+	if !lom.IsEncrypted() {
+		return lom.ObjAttrs()
+	}
+	oa := &cmn.ObjAttrs{}
+	oa.CopyFrom(lom, true /*skip cksum*/)
+	oa.DelCustomKeys(cmn.SSEObjMD)
+	md, err := lom.SSEMD()
+	if err != nil {
+		glog.Errorf("%s: %v", lom, err)
+		return oa
+	}
+	oa.Size = md.Size
+	oa.Cksum = cos.NoneCksum
+	if cksum := lom.Checksum(); cksum != nil && md.Cksum != "" {
+		oa.Cksum = cos.NewCksum(cksum.Ty(), md.Cksum)
+	}
+	return oa
+}
+
+// NewSSEReader returns a reader of the decrypted content of the (encrypted) object.
+func (lom *LOM) NewSSEReader(r io.ReaderAt) (*sse.Reader, error) {
+	md, err := lom.SSEMD()
+	if err != nil {
+		return nil, err
+	}
+	key, err := md.Key()
+	if err != nil {
+		return nil, err
+	}
+	return sse.NewReader(r, key, md.Size)
+}
+
+// OpenPlain opens the object for reading: the returned reader decrypts the object's
+// content if need be.
+func (lom *LOM) OpenPlain() (cos.ReadOpenCloser, error) {
+	fh, err := cos.NewFileHandle(lom.FQN)
+	if err != nil {
+		return nil, err
+	}
+	if !lom.IsEncrypted() {
+		return fh, nil
+	}
+	r, err := lom.NewSSEReader(fh)
+	if err != nil {
+		fh.Close()
+		return nil, err
+	}
+	return &sseROC{Reader: r, fh: fh}, nil
+}
+
+func (r *sseROC) Close() error { return r.fh.Close() }
+
+func (r *sseROC) Open() (cos.ReadOpenCloser, error) {
+	fh, err := r.fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	nfh := fh.(*cos.FileHandle)
+	return &sseROC{Reader: r.Reader.Clone(nfh), fh: nfh}, nil
+}
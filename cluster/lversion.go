@@ -0,0 +1,260 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Retaining prior versions of objects (bucket property `versioning.retain`):
+// - prior to being overwritten or deleted, the current version of the object
+//   is moved aside and stored as <object-name>.<version> (fs.ObjVersionType);
+//   the time it became noncurrent is stored in its metadata (cmn.NoncurrentObjMD);
+// - deletion replaces the object with a zero-size delete marker that carries
+//   the next version; Load() reports delete markers as nonexistent objects;
+// - objects that were written prior to enabling versioning are retained
+//   as version "0" (`unversioned`);
+// - retained versions are not mirrored or erasure coded; global rebalance
+//   migrates them along with (and ahead of) the current version (see PutVersion).
+// All methods in this file must be called under the object's w-lock.
+
+const unversioned = "0"
+
+func validVersion(ver string) bool {
+	_, err := strconv.ParseUint(ver, 10, 64)
+	return err == nil
+}
+
+func errDelMarker(lom *LOM) error { return cmn.NewErrNotFound("%s (delete marker)", lom) }
+
+func (lom *LOM) IsDelMarker() bool {
+	_, ok := lom.md.GetCustomKey(cmn.DelMarkerObjMD)
+	return ok
+}
+
+// VersionFQN returns FQN of the given retained version of the object.
+func (lom *LOM) VersionFQN(ver string) string {
+	return fs.CSM.Gen(lom, fs.ObjVersionType, ver)
+}
+
+// LoadLatest loads the current version of the object, which may as well be
+// a delete marker (see IsDelMarker). Does not add LOM to cache.
+func (lom *LOM) LoadLatest(locked bool) (err error) {
+	if err = lom.Load(false /*cache it*/, locked); err != nil && lom.IsDelMarker() {
+		err = nil
+	}
+	return
+}
+
+// Retain moves aside the current version of the object (or its delete marker)
+// that is about to be overwritten, and assigns the next version to `lom`.
+func (lom *LOM) Retain() error {
+	cur := AllocLOM(lom.ObjName)
+	defer FreeLOM(cur)
+	if err := cur.Init(lom.Bucket()); err != nil {
+		return err
+	}
+	if err := cur.LoadLatest(true /*locked*/); err != nil {
+		if !cmn.IsObjNotExist(err) {
+			return err
+		}
+		lom.SetVersion(lomInitialVersion)
+		return nil
+	}
+	if err := cur.retain(); err != nil {
+		return err
+	}
+	lom.SetVersion(cur.md.Ver)
+	return lom.IncVersion()
+}
+
+// DelMarker retains the current version of the object and replaces the latter
+// with a delete marker.
+func (lom *LOM) DelMarker() error {
+	if err := lom.LoadLatest(true /*locked*/); err != nil {
+		return err
+	}
+	if lom.IsDelMarker() {
+		return errDelMarker(lom)
+	}
+	if err := lom.retain(); err != nil {
+		return err
+	}
+	var (
+		ver = lom.md.Ver
+		bid = lom.md.bckID
+	)
+	lom.md = lmeta{uname: lom.md.uname, bckID: bid}
+	lom.SetVersion(ver)
+	if err := lom.IncVersion(); err != nil {
+		return err
+	}
+	lom.SetAtimeUnix(time.Now().UnixNano())
+	lom.SetCustomKey(cmn.DelMarkerObjMD, "true")
+
+	fh, err := lom.CreateFile(lom.FQN)
+	if err != nil {
+		return err
+	}
+	cos.Close(fh)
+	buf, mm := lom.marshal()
+	err = fs.SetXattr(lom.FQN, XattrLOM, buf)
+	mm.Free(buf)
+	if err != nil {
+		if errRm := cos.RemoveFile(lom.FQN); errRm != nil {
+			glog.Errorf(fmtNestedErr, errRm)
+		}
+	}
+	return err
+}
+
+// DelVersion permanently removes the given version of the object. Removing
+// the current version (or delete marker) makes the latest retained version current.
+func (lom *LOM) DelVersion(ver string) error {
+	if !validVersion(ver) {
+		return fmt.Errorf("%s: invalid version %q", lom, ver)
+	}
+	err := lom.LoadLatest(true /*locked*/)
+	if err != nil && !cmn.IsObjNotExist(err) {
+		return err
+	}
+	if err == nil && lom.md.Ver == ver {
+		if err = lom.Remove(); err != nil {
+			return err
+		}
+		return lom.promote()
+	}
+	if err = os.Remove(lom.VersionFQN(ver)); err != nil && os.IsNotExist(err) {
+		err = cmn.NewErrNotFound("%s version %q", lom, ver)
+	}
+	return err
+}
+
+// LoadVersion loads metadata of the given retained version of the object.
+// The caller must free the returned LOM (FreeLOM).
+func (lom *LOM) LoadVersion(ver string) (vlom *LOM, err error) {
+	if !validVersion(ver) {
+		return nil, fmt.Errorf("%s: invalid version %q", lom, ver)
+	}
+	vlom = lom.Clone(lom.VersionFQN(ver))
+	vlom.md = lmeta{uname: lom.md.uname}
+	if err = vlom.FromFS(); err != nil {
+		FreeLOM(vlom)
+		vlom = nil
+	}
+	return
+}
+
+// PutVersion stores the given retained version of the object received from
+// another target (global rebalance); `oa` carries its metadata, including the version.
+func (lom *LOM) PutVersion(r io.Reader, oa cmn.ObjAttrsHolder, buf []byte) (err error) {
+	ver := oa.Version()
+	if !validVersion(ver) {
+		return fmt.Errorf("%s: invalid version %q", lom, ver)
+	}
+	var (
+		vlom    = lom.Clone(lom.VersionFQN(ver))
+		workFQN = fs.CSM.Gen(vlom, fs.WorkfileType, fs.WorkfilePut)
+	)
+	defer FreeLOM(vlom)
+	vlom.md = lmeta{uname: lom.md.uname}
+	vlom.CopyAttrs(oa, oa.Checksum() == nil /*skip-checksum*/)
+	if _, err = cos.SaveReaderSafe(workFQN, vlom.FQN, r, buf, cos.ChecksumNone, oa.SizeBytes(), ""); err != nil {
+		return
+	}
+	bufm, mm := vlom.marshal()
+	err = fs.SetXattr(vlom.FQN, XattrLOM, bufm)
+	mm.Free(bufm)
+	if err != nil {
+		if errRm := cos.RemoveFile(vlom.FQN); errRm != nil {
+			glog.Errorf(fmtNestedErr, errRm)
+		}
+		return
+	}
+	if tu := vlom.NoncurrentUnix(); tu != 0 {
+		mtime := time.Unix(0, tu)
+		err = os.Chtimes(vlom.FQN, mtime, mtime)
+	}
+	return
+}
+
+// Versions returns retained versions of the object in ascending order.
+func (lom *LOM) Versions() (vers []string, err error) {
+	dir, prefix := filepath.Split(lom.VersionFQN(""))
+	dentries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	for _, de := range dentries {
+		name := de.Name()
+		if de.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if ver := name[len(prefix):]; validVersion(ver) {
+			vers = append(vers, ver)
+		}
+	}
+	sort.Slice(vers, func(i, j int) bool {
+		if len(vers[i]) != len(vers[j]) {
+			return len(vers[i]) < len(vers[j])
+		}
+		return vers[i] < vers[j]
+	})
+	return
+}
+
+// move the current version aside
+func (lom *LOM) retain() (err error) {
+	if lom.md.Ver == "" {
+		lom.md.Ver = unversioned
+	}
+	verFQN := lom.VersionFQN(lom.md.Ver)
+	lom.Uncache(true /*delDirty*/)
+	if err = cos.Rename(lom.FQN, verFQN); err != nil {
+		return
+	}
+	for copyFQN := range lom.md.copies {
+		if copyFQN == lom.FQN {
+			continue
+		}
+		if errRm := cos.RemoveFile(copyFQN); errRm != nil {
+			glog.Errorf("%s: failed to remove copy %q: %v", lom, copyFQN, errRm)
+		}
+	}
+	lom.md.copies = nil
+	now := time.Now()
+	lom.SetCustomKey(cmn.NoncurrentObjMD, strconv.FormatInt(now.UnixNano(), 10))
+	buf, mm := lom.marshal()
+	err = fs.SetXattr(verFQN, XattrLOM, buf)
+	mm.Free(buf)
+	if err != nil {
+		return
+	}
+	return os.Chtimes(verFQN, now, now)
+}
+
+// make the latest retained version current
+func (lom *LOM) promote() error {
+	vers, err := lom.Versions()
+	if err != nil || len(vers) == 0 {
+		return err
+	}
+	return cos.Rename(lom.VersionFQN(vers[len(vers)-1]), lom.FQN)
+}
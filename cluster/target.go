@@ -40,6 +40,13 @@ type (
 		GetObj(ctx context.Context, lom *LOM, owt cmn.OWT) (errCode int, err error)
 		GetObjReader(ctx context.Context, lom *LOM) (r io.ReadCloser, expectedCksum *cos.Cksum, errCode int, err error)
 	}
+	// optional: backends that support reading byte ranges of remote objects;
+	// `oa` (as per HeadObj) pins the version of the object - if the latter has changed,
+	// the read either fails with http.StatusPreconditionFailed or returns the pinned version
+	RangeReader interface {
+		GetObjReaderRange(ctx context.Context, lom *LOM, offset, length int64, oa *cmn.ObjAttrs) (r io.ReadCloser,
+			errCode int, err error)
+	}
 
 	// Callback called by EC PUT jogger after the object is processed and
 	// all its slices/replicas are sent to other targets.
@@ -87,17 +87,20 @@ func genShardsHandler(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	var (
-		ext      = filepath.Ext(object)
-		template = strings.TrimSuffix(object, ext)
-	)
+	ext := filepath.Ext(object)
+	for _, e := range cos.ArchExtensions {
+		if strings.HasSuffix(object, e) && len(e) > len(ext) {
+			ext = e // compound extension, e.g. ".tar.lz4"
+		}
+	}
+	template := strings.TrimSuffix(object, ext)
 
 	fileSize, err := parseByteFlagToInt(c, fileSizeFlag)
 	if err != nil {
 		return err
 	}
 
-	supportedExts := []string{".tar", ".tgz"}
+	supportedExts := []string{cos.ExtTar, cos.ExtTgz, cos.ExtTarTgz, cos.ExtTarLz4, cos.ExtTarZst}
 	if !cos.StringInSlice(ext, supportedExts) {
 		return fmt.Errorf("extension %q is invalid, should be one of %q", ext, strings.Join(supportedExts, ", "))
 	}
@@ -79,6 +79,13 @@ var (
 				ArgsUsage: showUserListArgument,
 				Action:    wrapAuthN(showUserHandler),
 			},
+			{
+				Name:         subcmdAuthS3Key,
+				Usage:        "show user's S3 access keys",
+				ArgsUsage:    showS3KeyArgument,
+				Action:       wrapAuthN(showS3KeyHandler),
+				BashComplete: oneUserCompletions,
+			},
 			{
 				Name:   subcmdAuthConfig,
 				Usage:  "show AuthN server configuration",
@@ -119,6 +126,20 @@ var (
 						Action:       wrapAuthN(addAuthRoleHandler),
 						BashComplete: roleCluPermCompletions,
 					},
+					{
+						Name:         subcmdAuthACL,
+						Usage:        "grant a role permissions for a bucket or for the objects with a given prefix",
+						ArgsUsage:    addAuthACLArgument,
+						Action:       wrapAuthN(addAuthACLHandler),
+						BashComplete: oneRoleCompletions,
+					},
+					{
+						Name:         subcmdAuthS3Key,
+						Usage:        "generate a new S3 access key for a user",
+						ArgsUsage:    addS3KeyArgument,
+						Action:       wrapAuthN(addS3KeyHandler),
+						BashComplete: oneUserCompletions,
+					},
 				},
 			},
 			{
@@ -146,6 +167,20 @@ var (
 						Action:       wrapAuthN(deleteRoleHandler),
 						BashComplete: oneRoleCompletions,
 					},
+					{
+						Name:         subcmdAuthACL,
+						Usage:        "revoke a role's permissions for a bucket or for the objects with a given prefix",
+						ArgsUsage:    deleteAuthACLArgument,
+						Action:       wrapAuthN(deleteAuthACLHandler),
+						BashComplete: oneRoleCompletions,
+					},
+					{
+						Name:         subcmdAuthS3Key,
+						Usage:        "delete a user's S3 access key",
+						ArgsUsage:    deleteS3KeyArgument,
+						Action:       wrapAuthN(deleteS3KeyHandler),
+						BashComplete: oneUserCompletions,
+					},
 					{
 						Name:      subcmdAuthToken,
 						Usage:     "revoke an authorization token",
@@ -466,36 +501,13 @@ func addAuthRoleHandler(c *cli.Context) (err error) {
 		return missingArgumentsError(c, "permissions")
 	}
 
-	cluster := args.Get(1)
-	alias := ""
-	cluList, err := api.GetClusterAuthN(authParams, authn.Cluster{})
+	cluster, alias, err := lookupAuthCluster(args.Get(1))
 	if err != nil {
 		return
 	}
-	found := false
-	for _, clu := range cluList {
-		if cluster == clu.Alias {
-			alias = cluster
-			cluster = clu.ID
-			found = true
-			break
-		}
-		if cluster == clu.ID {
-			found = true
-			break
-		}
-	}
-	if !found {
-		return fmt.Errorf("cluster %q not found", cluster)
-	}
-
-	perms := cmn.AccessNone
-	for i := 2; i < c.NArg(); i++ {
-		p, err := cmn.StrToAccess(args.Get(i))
-		if err != nil {
-			return err
-		}
-		perms |= p
+	perms, err := parseAuthPerms(args[2:])
+	if err != nil {
+		return
 	}
 
 	cluPerms := []*authn.Cluster{
@@ -513,6 +525,124 @@ func addAuthRoleHandler(c *cli.Context) (err error) {
 	return api.AddRoleAuthN(authParams, rInfo)
 }
 
+func addAuthACLHandler(c *cli.Context) (err error) {
+	role, acl, err := parseAuthACL(c)
+	if err != nil {
+		return
+	}
+	if c.NArg() < 4 {
+		return missingArgumentsError(c, "permissions")
+	}
+	if acl.Access, err = parseAuthPerms(c.Args()[3:]); err != nil {
+		return
+	}
+	rInfo := &authn.Role{Name: role, Buckets: []*authn.Bucket{acl}}
+	return api.UpdateRoleAuthN(authParams, rInfo)
+}
+
+func deleteAuthACLHandler(c *cli.Context) (err error) {
+	role, acl, err := parseAuthACL(c)
+	if err != nil {
+		return
+	}
+	return api.RemoveRoleACLsAuthN(authParams, role, []*authn.Bucket{acl})
+}
+
+func addS3KeyHandler(c *cli.Context) (err error) {
+	userID := c.Args().First()
+	if userID == "" {
+		return missingArgumentsError(c, "user name")
+	}
+	key, err := api.AddS3KeyAuthN(authParams, userID)
+	if err != nil {
+		return err
+	}
+	if err = templates.DisplayOutput(key, c.App.Writer, templates.AuthNS3KeyNewTmpl); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.App.Writer, "NOTE: the secret key cannot be retrieved later - store it now")
+	return nil
+}
+
+func deleteS3KeyHandler(c *cli.Context) (err error) {
+	userID, keyID := c.Args().Get(0), c.Args().Get(1)
+	if userID == "" {
+		return missingArgumentsError(c, "user name")
+	}
+	if keyID == "" {
+		return missingArgumentsError(c, "access key")
+	}
+	return api.DeleteS3KeyAuthN(authParams, userID, keyID)
+}
+
+func showS3KeyHandler(c *cli.Context) (err error) {
+	userID := c.Args().First()
+	if userID == "" {
+		return missingArgumentsError(c, "user name")
+	}
+	list, err := api.GetS3KeysAuthN(authParams, userID)
+	if err != nil {
+		return err
+	}
+	return templates.DisplayOutput(list, c.App.Writer, templates.AuthNS3KeyTmpl)
+}
+
+// ROLE CLUSTER_ID BUCKET[/PREFIX]
+func parseAuthACL(c *cli.Context) (role string, acl *authn.Bucket, err error) {
+	args := c.Args()
+	if role = args.First(); role == "" {
+		err = missingArgumentsError(c, "role name")
+		return
+	}
+	if c.NArg() < 2 {
+		err = missingArgumentsError(c, "cluster ID")
+		return
+	} else if c.NArg() < 3 {
+		err = missingArgumentsError(c, "bucket name")
+		return
+	}
+	cluster, _, err := lookupAuthCluster(args.Get(1))
+	if err != nil {
+		return
+	}
+	bck, prefix, err := parseBckObjectURI(c, args.Get(2), true /*optional prefix*/)
+	if err != nil {
+		return
+	}
+	// AuthN buckets are always qualified by their cluster's ID
+	bck.Ns.UUID = cluster
+	acl = &authn.Bucket{Bck: bck, Prefix: prefix}
+	return
+}
+
+// given cluster ID or alias, return both
+func lookupAuthCluster(cluster string) (id, alias string, err error) {
+	cluList, err := api.GetClusterAuthN(authParams, authn.Cluster{})
+	if err != nil {
+		return
+	}
+	for _, clu := range cluList {
+		if cluster == clu.Alias {
+			return clu.ID, cluster, nil
+		}
+		if cluster == clu.ID {
+			return cluster, "", nil
+		}
+	}
+	return "", "", fmt.Errorf("cluster %q not found", cluster)
+}
+
+func parseAuthPerms(args []string) (perms cmn.AccessAttrs, err error) {
+	for _, arg := range args {
+		p, err := cmn.StrToAccess(arg)
+		if err != nil {
+			return perms, err
+		}
+		perms |= p
+	}
+	return perms, nil
+}
+
 func parseAuthUser(c *cli.Context, omitEmpty bool) *authn.User {
 	username := cliAuthnUserName(c)
 	userpass := cliAuthnUserPassword(c, omitEmpty)
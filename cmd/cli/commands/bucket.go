@@ -8,6 +8,7 @@ package commands
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
 	"sort"
 	"strings"
@@ -36,8 +37,8 @@ const (
 )
 
 // Creates new ais bucket
-func createBucket(c *cli.Context, bck cmn.Bck, props *cmn.BucketPropsToUpdate) (err error) {
-	if err = api.CreateBucket(defaultAPIParams, bck, props); err != nil {
+func createBucket(c *cli.Context, bck cmn.Bck, props *cmn.BucketPropsToUpdate, query ...url.Values) (err error) {
+	if err = api.CreateBucket(defaultAPIParams, bck, props, query...); err != nil {
 		if herr, ok := err.(*cmn.ErrHTTP); ok {
 			if herr.Status == http.StatusConflict {
 				desc := fmt.Sprintf("Bucket %q already exists", bck)
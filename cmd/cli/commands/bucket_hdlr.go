@@ -7,6 +7,7 @@ package commands
 
 import (
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -205,8 +206,13 @@ func createBucketHandler(c *cli.Context) (err error) {
 	if err != nil {
 		return err
 	}
-	for _, bck := range buckets {
-		if err := createBucket(c, bck, props); err != nil {
+	for i, bck := range buckets {
+		var query url.Values
+		if uri := c.Args().Get(i); bck.IsHTTP() && isWebURL(uri) {
+			// HTTP(S) bucket: web server's URL "directory"
+			query = url.Values{cmn.URLParamOrigURL: []string{uri}}
+		}
+		if err := createBucket(c, bck, props, query); err != nil {
 			return err
 		}
 	}
@@ -354,7 +360,10 @@ func multiObjBckCopy(c *cli.Context, fromBck, toBck cmn.Bck, listObjs, tmplObjs
 	msg.ContinueOnError = flagIsSet(c, continueOnErrorFlag)
 	var xactID string
 	if len(etlID) != 0 {
-		msg.ID = etlID[0]
+		if msg.Pipeline = parseETLPipeline(etlID[0]); msg.Pipeline == nil {
+			msg.ID = etlID[0]
+			msg.Args = parseStrFlag(c, etlArgsFlag)
+		}
 		operation = "ETL objects"
 		xactID, err = api.ETLMultiObj(defaultAPIParams, fromBck, msg)
 	} else {
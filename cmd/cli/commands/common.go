@@ -37,6 +37,7 @@ const (
 	commandPrefetch  = "prefetch"
 	commandGet       = "get"
 	commandList      = "ls"
+	commandPresign   = "presign"
 	commandPromote   = "promote"
 	commandPut       = "put"
 	commandSetCustom = "set-custom"
@@ -162,6 +163,8 @@ const (
 	subcmdAuthRole    = "role"
 	subcmdAuthCluster = "cluster"
 	subcmdAuthToken   = "token"
+	subcmdAuthACL     = "acl"
+	subcmdAuthS3Key   = "s3key"
 	subcmdAuthConfig  = subcmdConfig
 
 	// Warm up subcommands
@@ -252,7 +255,12 @@ const (
 	showAuthRoleArgument      = "[ROLE]"
 	showUserListArgument      = "[USER_NAME]"
 	addAuthRoleArgument       = "ROLE [CLUSTER_ID PERMISSION ...]"
+	addAuthACLArgument        = "ROLE CLUSTER_ID BUCKET[/PREFIX] PERMISSION [PERMISSION...]"
+	deleteAuthACLArgument     = "ROLE CLUSTER_ID BUCKET[/PREFIX]"
 	deleteRoleArgument        = "ROLE"
+	addS3KeyArgument          = "USER_NAME"
+	deleteS3KeyArgument       = "USER_NAME ACCESS_KEY"
+	showS3KeyArgument         = "USER_NAME"
 	deleteTokenArgument       = "TOKEN | TOKEN_FILE"
 
 	// Alias
@@ -383,6 +391,19 @@ var (
 	listFlag     = cli.StringFlag{Name: "list", Usage: "comma-separated list of object names, e.g.: 'o1,o2,o3'"}
 	templateFlag = cli.StringFlag{Name: "template", Usage: "template for matching object names, e.g.: 'shard-{900..999}.tar'"}
 
+	// prefetch
+	manifestFlag = cli.StringFlag{
+		Name: "manifest",
+		Usage: "object that names the objects to prefetch, one name or template per line, " +
+			"e.g.: 'ais://manifests/train.txt' or 'train.txt' (same bucket)",
+	}
+	numWorkersFlag = cli.IntFlag{Name: "num-workers", Usage: "number of objects each target prefetches concurrently"}
+	rateFlag       = cli.IntFlag{Name: "rate", Usage: "max number of objects each target prefetches per second (0: unlimited)"}
+	readAheadFlag  = cli.IntFlag{
+		Name:  "read-ahead",
+		Usage: "prefetch continuously, the specified number of manifest entries ahead of each reader",
+	}
+
 	// Object
 	offsetFlag   = cli.StringFlag{Name: "offset", Usage: "object read offset " + sizeUnits}
 	lengthFlag   = cli.StringFlag{Name: "length", Usage: "object read length " + sizeUnits}
@@ -429,6 +450,15 @@ var (
 
 	sourceBckFlag = cli.StringFlag{Name: "source-bck", Usage: "source bucket"}
 
+	// Presigned URL
+	presignExpiresFlag = cli.DurationFlag{
+		Name:  "expires",
+		Usage: "presigned URL expiration time (up to 7 days), valid time units: 's', 'm', and 'h'",
+		Value: time.Hour,
+	}
+	presignMethodFlag = cli.StringFlag{Name: "method", Usage: "HTTP method the URL grants: GET, HEAD, or PUT", Value: "GET"}
+	presignS3Flag     = cli.BoolFlag{Name: "s3", Usage: "generate S3 API URL (/s3/BUCKET/OBJECT_NAME)"}
+
 	// AuthN
 	tokenFileFlag = cli.StringFlag{Name: "file,f", Value: "", Usage: "path to file"}
 	passwordFlag  = cli.StringFlag{Name: "password,p", Value: "", Usage: "user password"}
@@ -446,6 +476,7 @@ var (
 
 	// ETL
 	etlExtFlag              = cli.StringFlag{Name: "ext", Usage: "mapping from old to new extensions of transformed objects' names"}
+	etlArgsFlag             = cli.StringFlag{Name: "args", Usage: "arguments for the ETL, e.g. 'size=224x224 format=png'"}
 	etlUUID                 = cli.StringFlag{Name: "name", Usage: "unique ETL name (leaving this field empty will have unique ID auto-generated)"}
 	etlBucketRequestTimeout = cli.DurationFlag{Name: "request-timeout", Usage: "timeout for a transformation of a single object"}
 	fromFileFlag            = cli.StringFlag{Name: "from-file", Usage: "absolute path to the file with the code for ETL", Required: true}
@@ -457,6 +488,18 @@ var (
 		Name:  "runtime",
 		Usage: "runtime which should be used when running the provided code", Required: true,
 	}
+	etlMemLimitFlag = cli.StringFlag{
+		Name:  "mem-limit",
+		Usage: "(wasi runtime only) max memory of a single transformation " + sizeUnits,
+	}
+	etlCPULimitFlag = cli.DurationFlag{
+		Name:  "cpu-limit",
+		Usage: "(wasi runtime only) max execution time of a single transformation",
+	}
+	etlOutputLimitFlag = cli.StringFlag{
+		Name:  "output-limit",
+		Usage: "(io:// with wasi or local runtime) max output size of a single transformation " + sizeUnits,
+	}
 	commTypeFlag = cli.StringFlag{
 		Name:  "comm-type",
 		Usage: "communication type which should be used when running the provided code",
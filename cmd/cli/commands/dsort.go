@@ -7,7 +7,6 @@ package commands
 
 import (
 	"archive/tar"
-	"compress/gzip"
 	"encoding/hex"
 	jsonStd "encoding/json"
 	"fmt"
@@ -41,17 +40,19 @@ func phaseToBarText(phase string) string {
 
 func createTar(w io.Writer, ext string, start, end, fileCnt int, fileSize int64) error {
 	var (
-		gzw       *gzip.Writer
 		tw        *tar.Writer
 		random    = cos.NowRand()
 		buf       = make([]byte, fileSize)
 		randBytes = make([]byte, 10)
 	)
 
-	if ext == ".tgz" {
-		gzw = gzip.NewWriter(w)
-		tw = tar.NewWriter(gzw)
-		defer gzw.Close()
+	if cos.IsCompressedTar(ext) {
+		cw, err := cos.NewTarCompressor(w, ext, false /*fast*/)
+		if err != nil {
+			return err
+		}
+		tw = tar.NewWriter(cw)
+		defer cw.Close()
 	} else {
 		tw = tar.NewWriter(w)
 	}
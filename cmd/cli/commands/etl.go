@@ -30,13 +30,20 @@ var (
 			depsFileFlag,
 			runtimeFlag,
 			commTypeFlag,
+			etlMemLimitFlag,
+			etlCPULimitFlag,
+			etlOutputLimitFlag,
 			waitTimeoutFlag,
 			etlUUID,
 		},
 		subcmdStop: {
 			allETLStopFlag,
 		},
+		subcmdObject: {
+			etlArgsFlag,
+		},
 		subcmdBucket: {
+			etlArgsFlag,
 			etlExtFlag,
 			cpBckPrefixFlag,
 			cpBckDryRunFlag,
@@ -80,14 +87,15 @@ var (
 	objCmdETL = cli.Command{
 		Name:         subcmdObject,
 		Usage:        "transform an object",
-		ArgsUsage:    "ETL_ID BUCKET/OBJECT_NAME OUTPUT",
+		ArgsUsage:    "ETL_ID[,ETL_ID...] BUCKET/OBJECT_NAME OUTPUT",
 		Action:       etlObjectHandler,
+		Flags:        etlSubcmdsFlags[subcmdObject],
 		BashComplete: etlIDCompletions,
 	}
 	bckCmdETL = cli.Command{
 		Name:         subcmdBucket,
 		Usage:        "transform bucket and put results into another bucket",
-		ArgsUsage:    "ETL_ID SRC_BUCKET DST_BUCKET",
+		ArgsUsage:    "ETL_ID[,ETL_ID...] SRC_BUCKET DST_BUCKET",
 		Action:       etlBucketHandler,
 		Flags:        etlSubcmdsFlags[subcmdBucket],
 		BashComplete: manyBucketsCompletions([]cli.BashCompleteFunc{etlIDCompletions}, 1, 2),
@@ -212,6 +220,17 @@ func etlInitCodeHandler(c *cli.Context) (err error) {
 		}
 	}
 	msg.WaitTimeout = cos.Duration(parseDurationFlag(c, waitTimeoutFlag))
+	if flagIsSet(c, etlMemLimitFlag) {
+		if msg.MemLimit, err = parseByteFlagToInt(c, etlMemLimitFlag); err != nil {
+			return
+		}
+	}
+	msg.CPULimit = cos.Duration(parseDurationFlag(c, etlCPULimitFlag))
+	if flagIsSet(c, etlOutputLimitFlag) {
+		if msg.OutputLimit, err = parseByteFlagToInt(c, etlOutputLimitFlag); err != nil {
+			return
+		}
+	}
 
 	if err := msg.Validate(); err != nil {
 		return err
@@ -330,7 +349,14 @@ func etlObjectHandler(c *cli.Context) (err error) {
 		defer f.Close()
 	}
 
-	return handleETLHTTPError(api.ETLObject(defaultAPIParams, id, bck, objName, w), id)
+	args := parseStrFlag(c, etlArgsFlag)
+	if pipeline := parseETLPipeline(id); pipeline != nil {
+		if args != "" {
+			return fmt.Errorf("flag %q is not supported with ETL pipeline", etlArgsFlag.Name)
+		}
+		return api.ETLPipelineObject(defaultAPIParams, pipeline, bck, objName, w)
+	}
+	return handleETLHTTPError(api.ETLObjectWithArgs(defaultAPIParams, id, args, bck, objName, w), id)
 }
 
 func etlBucketHandler(c *cli.Context) (err error) {
@@ -358,12 +384,19 @@ func etlBucketHandler(c *cli.Context) (err error) {
 	}
 
 	msg := &cmn.TCBMsg{
-		ID: id,
+		ID:   id,
+		Args: parseStrFlag(c, etlArgsFlag),
 		CopyBckMsg: cmn.CopyBckMsg{
 			Prefix: parseStrFlag(c, cpBckPrefixFlag),
 			DryRun: flagIsSet(c, cpBckDryRunFlag),
 		},
 	}
+	if msg.Pipeline = parseETLPipeline(id); msg.Pipeline != nil {
+		if msg.Args != "" {
+			return fmt.Errorf("flag %q is not supported with ETL pipeline", etlArgsFlag.Name)
+		}
+		msg.ID = ""
+	}
 
 	if flagIsSet(c, etlExtFlag) {
 		mapStr := parseStrFlag(c, etlExtFlag)
@@ -411,6 +444,17 @@ func etlBucketHandler(c *cli.Context) (err error) {
 	return nil
 }
 
+// comma-separated ETL IDs denote ETL pipeline (nil when there is a single ETL)
+func parseETLPipeline(ids string) (pipeline cmn.ETLPipeline) {
+	if !strings.Contains(ids, ",") {
+		return nil
+	}
+	for _, id := range strings.Split(ids, ",") {
+		pipeline = append(pipeline, cmn.ETLStage{ID: strings.TrimSpace(id)})
+	}
+	return
+}
+
 func handleETLHTTPError(err error, etlID string) error {
 	if httpErr, ok := err.(*cmn.ErrHTTP); ok {
 		// TODO: How to find out if it's transformation not found, and not object not found?
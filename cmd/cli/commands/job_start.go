@@ -51,6 +51,10 @@ var (
 		commandPrefetch: append(
 			baseLstRngFlags,
 			dryRunFlag,
+			manifestFlag,
+			numWorkersFlag,
+			rateFlag,
+			readAheadFlag,
 		),
 		subcmdLRU: {
 			listBucketsFlag,
@@ -550,9 +554,46 @@ func startPrefetchHandler(c *cli.Context) (err error) {
 		return
 	}
 
+	if flagIsSet(c, manifestFlag) {
+		return prefetchManifest(c, bck)
+	}
 	if flagIsSet(c, listFlag) || flagIsSet(c, templateFlag) {
 		return listOrRangeOp(c, commandPrefetch, bck)
 	}
 
-	return missingArgumentsError(c, "object list or range")
+	return missingArgumentsError(c, "object list, range, or manifest")
+}
+
+func prefetchManifest(c *cli.Context, bck cmn.Bck) (err error) {
+	if flagIsSet(c, listFlag) || flagIsSet(c, templateFlag) {
+		return incorrectUsageMsg(c, "flag %q cannot be used together with %q or %q",
+			manifestFlag.Name, listFlag.Name, templateFlag.Name)
+	}
+	msg := prefetchMsg(c, cmn.ListRangeMsg{})
+	if uri := parseStrFlag(c, manifestFlag); strings.Contains(uri, cmn.BckProviderSeparator) {
+		if msg.ManifestBck, msg.Manifest, err = parseBckObjectURI(c, uri); err != nil {
+			return
+		}
+	} else {
+		msg.Manifest = uri
+	}
+	if flagIsSet(c, dryRunFlag) {
+		fmt.Fprintf(c.App.Writer, "PREFETCH %s objects named in %q\n", bck, msg.Manifest)
+		return
+	}
+	xactID, err := api.Prefetch(defaultAPIParams, bck, msg)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(c.App.Writer, "prefetching %s objects named in %q, %s\n", bck, msg.Manifest, xactProgressMsg(xactID))
+	return
+}
+
+func prefetchMsg(c *cli.Context, lrMsg cmn.ListRangeMsg) cmn.PrefetchMsg {
+	return cmn.PrefetchMsg{
+		ListRangeMsg: lrMsg,
+		NumWorkers:   parseIntFlag(c, numWorkersFlag),
+		Rate:         parseIntFlag(c, rateFlag),
+		ReadAhead:    parseIntFlag(c, readAheadFlag),
+	}
 }
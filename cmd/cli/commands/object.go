@@ -111,7 +111,7 @@ func getObject(c *cli.Context, outFile string, silent bool) (err error) {
 		objArgs = api.GetObjectInput{Writer: file, Header: hdr}
 	}
 
-	if bck.IsHTTP() {
+	if bck.IsHTTP() && isWebURL(uri) {
 		objArgs.Query = make(url.Values, 2)
 		objArgs.Query.Set(cmn.URLParamOrigURL, uri)
 	}
@@ -811,7 +811,7 @@ func listOp(c *cli.Context, command string, bck cmn.Bck) (err error) {
 		if err = ensureHasProvider(bck, command); err != nil {
 			return
 		}
-		xactID, err = api.PrefetchList(defaultAPIParams, bck, fileList)
+		xactID, err = api.Prefetch(defaultAPIParams, bck, prefetchMsg(c, cmn.ListRangeMsg{ObjNames: fileList}))
 		command += "ed"
 	case commandEvict:
 		if err = ensureHasProvider(bck, command); err != nil {
@@ -864,7 +864,7 @@ func rangeOp(c *cli.Context, command string, bck cmn.Bck) (err error) {
 		if err = ensureHasProvider(bck, command); err != nil {
 			return
 		}
-		xactID, err = api.PrefetchRange(defaultAPIParams, bck, rangeStr)
+		xactID, err = api.Prefetch(defaultAPIParams, bck, prefetchMsg(c, cmn.ListRangeMsg{Template: rangeStr}))
 		command += "ed"
 	case commandEvict:
 		if err = ensureHasProvider(bck, command); err != nil {
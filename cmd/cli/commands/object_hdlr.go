@@ -12,6 +12,7 @@ import (
 
 	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/urfave/cli"
 )
 
@@ -61,6 +62,11 @@ var (
 			recursiveFlag,
 			progressBarFlag,
 		},
+		commandPresign: {
+			presignExpiresFlag,
+			presignMethodFlag,
+			presignS3Flag,
+		},
 		commandCat: {
 			offsetFlag,
 			lengthFlag,
@@ -139,6 +145,14 @@ var (
 				Flags:     objectCmdsFlags[commandConcat],
 				Action:    concatHandler,
 			},
+			{
+				Name:         commandPresign,
+				Usage:        "generate a URL that grants time-limited access to the object without a token",
+				ArgsUsage:    objectArgument,
+				Flags:        objectCmdsFlags[commandPresign],
+				Action:       presignHandler,
+				BashComplete: bucketCompletions(bckCompletionsOpts{separator: true}),
+			},
 			{
 				Name:         commandCat,
 				Usage:        "cat an object - print the contents to STDOUT",
@@ -409,6 +423,30 @@ func setCustomPropsHandler(c *cli.Context) (err error) {
 	return setCustomProps(c, bck, objName)
 }
 
+func presignHandler(c *cli.Context) (err error) {
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "object name in the form bucket/object")
+	}
+	bck, objName, err := parseBckObjectURI(c, c.Args().First())
+	if err != nil {
+		return
+	}
+	if objName == "" {
+		return incorrectUsageMsg(c, "no object specified in %q", c.Args().First())
+	}
+	args := &cmn.ActValPresign{
+		Method:  parseStrFlag(c, presignMethodFlag),
+		Expires: cos.Duration(parseDurationFlag(c, presignExpiresFlag)),
+		S3:      flagIsSet(c, presignS3Flag),
+	}
+	u, err := api.PresignObject(defaultAPIParams, bck, objName, args)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(c.App.Writer, u)
+	return
+}
+
 func catHandler(c *cli.Context) (err error) {
 	return getObject(c, fileStdIO, true /*silent*/)
 }
@@ -40,6 +40,7 @@ var (
 		cmn.PropBucketAccessAttrs:             cmn.SupportedPermissions(),
 		cmn.HdrObjCksumType:                   cos.SupportedChecksums(),
 		"md_write":                            cmn.SupportedWritePolicy,
+		"write_policy":                        cmn.SupportedDataWritePolicy,
 		"ec.compression":                      cmn.SupportedCompression,
 		"compression.checksum":                cmn.SupportedCompression,
 		"rebalance.compression":               cmn.SupportedCompression,
@@ -60,6 +61,7 @@ var (
 		"rebalance.enabled":                   supportedBool,
 		"resilver.enabled":                    supportedBool,
 		"versioning.enabled":                  supportedBool,
+		"versioning.retain":                   supportedBool,
 		"replication.on_cold_get":             supportedBool,
 		"replication.on_lru_eviction":         supportedBool,
 		"replication.on_put":                  supportedBool,
@@ -303,6 +303,7 @@ func TestParseQueryBckURI(t *testing.T) {
 		{uri: "ais://@uuid#ns", bck: cmn.QueryBcks{Provider: cmn.ProviderAIS, Ns: cmn.Ns{Name: "ns", UUID: "uuid"}}},
 		{uri: "ais://bucket", bck: cmn.QueryBcks{Provider: cmn.ProviderAIS, Name: "bucket"}},
 		{uri: "hdfs://bucket", bck: cmn.QueryBcks{Provider: cmn.ProviderHDFS, Name: "bucket"}},
+		{uri: "file://bucket", bck: cmn.QueryBcks{Provider: cmn.ProviderPOSIX, Name: "bucket"}},
 		{uri: "ais://#ns/bucket", bck: cmn.QueryBcks{Provider: cmn.ProviderAIS, Name: "bucket", Ns: cmn.Ns{Name: "ns"}}},
 		{uri: "ais://@uuid#ns/bucket", bck: cmn.QueryBcks{Provider: cmn.ProviderAIS, Name: "bucket", Ns: cmn.Ns{Name: "ns", UUID: "uuid"}}},
 		{uri: "http://web.url/dataset", bck: cmn.QueryBcks{Provider: cmn.ProviderHTTP, Name: "ZWUyYWFiOGEzYjEwMTJkNw"}},
@@ -339,6 +340,8 @@ func TestParseBckURI(t *testing.T) {
 	}{
 		{uri: "ais://bucket", bck: cmn.Bck{Provider: cmn.ProviderAIS, Name: "bucket"}},
 		{uri: "hdfs://bucket", bck: cmn.Bck{Provider: cmn.ProviderHDFS, Name: "bucket"}},
+		{uri: "posix://bucket", bck: cmn.Bck{Provider: cmn.ProviderPOSIX, Name: "bucket"}},
+		{uri: "file://bucket", bck: cmn.Bck{Provider: cmn.ProviderPOSIX, Name: "bucket"}},
 		{uri: "ais://#ns/bucket", bck: cmn.Bck{Provider: cmn.ProviderAIS, Name: "bucket", Ns: cmn.Ns{Name: "ns"}}},
 		{uri: "ais://@uuid#ns/bucket", bck: cmn.Bck{Provider: cmn.ProviderAIS, Name: "bucket", Ns: cmn.Ns{Name: "ns", UUID: "uuid"}}},
 		{uri: "http://web.url/dataset", bck: cmn.Bck{Provider: cmn.ProviderHTTP, Name: "ZWUyYWFiOGEzYjEwMTJkNw"}},
@@ -219,6 +219,14 @@ const (
 		"{{ $user.ID }}\t{{ JoinList $user.Roles }}\n" +
 		"{{end}}"
 
+	AuthNS3KeyTmpl = "ACCESS KEY\tUSER\tCREATED\n" +
+		"{{ range $key := . }}" +
+		"{{ $key.ID }}\t{{ $key.UserID }}\t{{ FormatTime $key.Created }}\n" +
+		"{{end}}"
+
+	AuthNS3KeyNewTmpl = "Access key\t{{ .ID }}\n" +
+		"Secret key\t{{ .Secret }}\n"
+
 	AuthNUserVerboseTmpl = "Name\t{{ .ID }}\n" +
 		"Roles\t{{ JoinList .Roles }}\n" +
 		"{{ if ne (len .Clusters) 0 }}" +
@@ -6,9 +6,12 @@
 package cmn
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/OneOfOne/xxhash"
 )
 
 // copy & (offline) transform bucket to bucket
@@ -25,10 +28,28 @@ type (
 		Ext cos.SimpleKVs `json:"ext"`
 
 		ID             string       `json:"id,omitempty"`              // optional, ETL only
+		Args           string       `json:"args,omitempty"`            // optional, ETL only (arguments for the ETL)
+		Pipeline       ETLPipeline  `json:"pipeline,omitempty"`        // optional, ETL only (instead of ID)
 		RequestTimeout cos.Duration `json:"request_timeout,omitempty"` // optional, ETL only
 
 		CopyBckMsg
 	}
+
+	// ETL pipeline: ordered list of ETLs, each transforming the output of the previous one
+	// (see also URLParamETLPipeline)
+	ETLPipeline []ETLStage
+	ETLStage    struct {
+		ID   string `json:"id"`
+		Args string `json:"args,omitempty"` // forwarded to the transformer (see HdrETLArgs)
+	}
+)
+
+// max length of ETL arguments
+const (
+	MaxETLArgsLen = 4 * cos.KiB
+	// ETL arguments longer than this (or containing other than [A-Za-z0-9-_.]) are hashed
+	// when included in the names of the transformed objects (see TCBMsg.ToName)
+	maxETLArgsTagLen = 32
 )
 
 // control message to generate bucket summary or summaries
@@ -54,13 +75,32 @@ type (
 ////////////
 
 func (msg *TCBMsg) Validate() error {
+	if len(msg.Pipeline) > 0 {
+		if msg.ID != "" {
+			return fmt.Errorf("ETL ID (%q) and pipeline (%s) are mutually exclusive", msg.ID, msg.Pipeline)
+		}
+		if msg.Args != "" {
+			return fmt.Errorf("ETL pipeline %s: arguments must be specified for each stage separately", msg.Pipeline)
+		}
+		return msg.Pipeline.Validate()
+	}
 	if msg.ID == "" {
 		return ErrETLMissingUUID
 	}
-	return nil
+	return ValidateETLArgs(msg.Args)
+}
+
+// Stages returns the pipeline, or the single ETL (ID) as one-stage pipeline.
+func (msg *TCBMsg) Stages() ETLPipeline {
+	if len(msg.Pipeline) > 0 {
+		return msg.Pipeline
+	}
+	return ETLPipeline{{ID: msg.ID, Args: msg.Args}}
 }
 
 // Replace extension and add suffix if provided.
+// ETL arguments, if any, are appended to the base name, e.g.: "a/b.jpg" => "a/b_224x224.jpg"
+// (so that transforming with different arguments does not produce the same objects).
 func (msg *TCBMsg) ToName(name string) string {
 	if msg.Ext != nil {
 		if idx := strings.LastIndexByte(name, '.'); idx >= 0 {
@@ -70,12 +110,90 @@ func (msg *TCBMsg) ToName(name string) string {
 			}
 		}
 	}
+	if tag := msg.argsTag(); tag != "" {
+		if idx := strings.LastIndexByte(name, '.'); idx > strings.LastIndexByte(name, '/')+1 {
+			name = name[:idx] + "_" + tag + name[idx:]
+		} else {
+			name += "_" + tag
+		}
+	}
 	if msg.Prefix != "" {
 		name = msg.Prefix + name
 	}
 	return name
 }
 
+func (msg *TCBMsg) argsTag() string {
+	if msg.Args != "" {
+		return ETLArgsTag(msg.Args)
+	}
+	var tags []string
+	for _, stage := range msg.Pipeline {
+		if stage.Args != "" {
+			tags = append(tags, ETLArgsTag(stage.Args))
+		}
+	}
+	return strings.Join(tags, "_")
+}
+
+/////////////////
+// ETLPipeline //
+/////////////////
+
+func (p ETLPipeline) Validate() error {
+	if len(p) == 0 {
+		return fmt.Errorf("ETL pipeline is empty")
+	}
+	for i, stage := range p {
+		if stage.ID == "" {
+			return fmt.Errorf("ETL pipeline %s: stage #%d: %v", p, i+1, ErrETLMissingUUID)
+		}
+		if err := ValidateETLArgs(stage.Args); err != nil {
+			return fmt.Errorf("ETL pipeline %s: stage #%d: %v", p, i+1, err)
+		}
+	}
+	return nil
+}
+
+func (p ETLPipeline) String() string {
+	ids := make([]string, 0, len(p))
+	for _, stage := range p {
+		ids = append(ids, stage.ID)
+	}
+	return "[" + strings.Join(ids, " => ") + "]"
+}
+
+// ETL arguments are forwarded to the transformer as HTTP header (and environment variable)
+// and, therefore, cannot contain control characters
+func ValidateETLArgs(args string) error {
+	if len(args) > MaxETLArgsLen {
+		return fmt.Errorf("ETL arguments are too long (%d > %d)", len(args), MaxETLArgsLen)
+	}
+	for i := 0; i < len(args); i++ {
+		if c := args[i]; c < ' ' || c == 0x7f {
+			return fmt.Errorf("ETL arguments %q contain invalid (control) character", args)
+		}
+	}
+	return nil
+}
+
+// ETLArgsTag returns object name-friendly representation of the ETL arguments:
+// the arguments themselves or, if too long or containing special characters, their hash.
+func ETLArgsTag(args string) string {
+	if len(args) <= maxETLArgsTagLen {
+		safe := true
+		for i := 0; i < len(args) && safe; i++ {
+			c := args[i]
+			safe = (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
+				c == '-' || c == '_' || c == '.'
+		}
+		if safe {
+			return args
+		}
+	}
+	return strconv.FormatUint(xxhash.ChecksumString64S(args, cos.MLCG32), 36)
+}
+
 //////////////////////
 // BucketsSummary(ies)
 //////////////////////
@@ -6,7 +6,9 @@
 package cmn
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
 	"reflect"
 	"strings"
 
@@ -41,12 +43,37 @@ type (
 		// LRU is the embedded struct of the same name
 		LRU LRUConf `json:"lru"`
 
+		// Lifecycle defines time-based expiration (and eviction) of the bucket's objects
+		Lifecycle LifecycleConf `json:"lifecycle"`
+
+		// Encryption defines server-side encryption (at rest) of the bucket's objects
+		Encryption EncryptionConf `json:"encryption"`
+
+		// Retention makes objects immutable (WORM) for a period of time after they were written
+		Retention RetentionConf `json:"retention"`
+
+		// Quota limits the bucket's (cluster-wide) size and number of objects
+		Quota QuotaConf `json:"quota"`
+
 		// Mirror defines local-mirroring policy for the bucket
 		Mirror MirrorConf `json:"mirror"`
 
+		// Replication defines asynchronous replication of the bucket's PUTs and DELETEs
+		// to a bucket on a remote AIS cluster or in the Cloud
+		Replication BckReplicationConf `json:"replication"`
+
+		// RateLimit limits the rate of requests to the remote backend (in addition to cluster config `rate_limit`)
+		RateLimit BckRateLimitConf `json:"rate_limit"`
+
+		// ColdGet defines how remote objects that are not present in the cluster get read (and cached)
+		ColdGet ColdGetConf `json:"cold_get"`
+
 		// Metadata write policy
 		MDWrite MDWritePolicy `json:"md_write"`
 
+		// Data write policy: write-through (default) or write-back (buckets with Cloud backends only)
+		WritePolicy DataWritePolicy `json:"write_policy"`
+
 		// EC defines erasure coding setting for the bucket
 		EC ECConf `json:"ec"`
 
@@ -67,23 +94,154 @@ type (
 		Renamed string `list:"omit"`
 	}
 
+	// LifecycleConf: unlike capacity-driven LRU, lifecycle rules are enforced regardless
+	// of the used capacity by the periodic (target-side) lifecycle xaction - see space/lifecycle.go
+	LifecycleConf struct {
+		Rules   []LifecycleRule `json:"rules"`
+		Enabled bool            `json:"enabled"`
+	}
+	LifecycleRule struct {
+		// Optional rule ID (e.g., S3 lifecycle rule ID)
+		ID string `json:"id,omitempty"`
+
+		// The rule applies to the objects with names starting with the prefix (all objects if empty)
+		Prefix string `json:"prefix,omitempty"`
+
+		// Remove objects that were last modified more than ExpireDays ago; for remote buckets
+		// this amounts to eviction: the objects are removed from the cluster but not from the backend
+		ExpireDays int64 `json:"expire_days,omitempty"`
+
+		// Remove non-current (i.e., overwritten) versions of objects that are older than NoncurrentDays
+		NoncurrentDays int64 `json:"noncurrent_days,omitempty"`
+
+		// Evict cached remote objects that were not accessed for more than EvictDays
+		EvictDays int64 `json:"evict_days,omitempty"`
+
+		// Disabled rules are kept but not enforced
+		Disabled bool `json:"disabled,omitempty"`
+	}
+	LifecycleConfToUpdate struct {
+		Rules   *[]LifecycleRule `json:"rules"`
+		Enabled *bool            `json:"enabled"`
+	}
+
+	// EncryptionConf: when enabled, new objects are encrypted with AES-256-GCM using per-object
+	// data keys wrapped by the KeyID master key of the cluster's KMS (see `kms` config and sse package).
+	// Objects that are already stored are not affected either way.
+	EncryptionConf struct {
+		KeyID   string `json:"key_id"` // empty: KMS default key
+		Enabled bool   `json:"enabled"`
+	}
+	EncryptionConfToUpdate struct {
+		KeyID   *string `json:"key_id"`
+		Enabled *bool   `json:"enabled"`
+	}
+
+	// RetentionConf: objects that are written while retention is enabled cannot be deleted,
+	// overwritten, renamed, or appended to for the duration of the Period (each object carries
+	// its own retention - see cluster/lretention.go). In governance mode, admins can bypass
+	// the retention and modify (or disable) the configuration. In compliance mode, nobody can:
+	// the configuration can be only made stricter.
+	RetentionConf struct {
+		Mode    string       `json:"mode"`   // RetentionGovernance | RetentionCompliance
+		Period  cos.Duration `json:"period"` // zero: legal holds only
+		Enabled bool         `json:"enabled"`
+	}
+	RetentionConfToUpdate struct {
+		Mode    *string       `json:"mode"`
+		Period  *cos.Duration `json:"period"`
+		Enabled *bool         `json:"enabled"`
+	}
+
+	// QuotaConf limits the total size and the number of objects of a bucket (bucket property
+	// `quota`) or a namespace (cluster config `ns_quota`). Writes that would exceed hard limits
+	// fail with ErrQuotaExceeded; exceeding soft limits is only logged. Zero means no limit.
+	QuotaConf struct {
+		SoftBytes int64 `json:"soft_bytes"`
+		HardBytes int64 `json:"hard_bytes"`
+		SoftObjs  int64 `json:"soft_objs"`
+		HardObjs  int64 `json:"hard_objs"`
+		Enabled   bool  `json:"enabled"`
+	}
+	QuotaConfToUpdate struct {
+		SoftBytes *int64 `json:"soft_bytes"`
+		HardBytes *int64 `json:"hard_bytes"`
+		SoftObjs  *int64 `json:"soft_objs"`
+		HardObjs  *int64 `json:"hard_objs"`
+		Enabled   *bool  `json:"enabled"`
+	}
+
+	// BckReplicationConf: when enabled, targets durably log the changes (PUTs and DELETEs) of the
+	// bucket's objects and asynchronously apply them to the destination bucket - see xs/replicate.go
+	BckReplicationConf struct {
+		Dst     string `json:"dst"` // destination bucket, e.g. "ais://@remais/abc" or "s3://abc"
+		Enabled bool   `json:"enabled"`
+	}
+	BckReplicationConfToUpdate struct {
+		Dst     *string `json:"dst"`
+		Enabled *bool   `json:"enabled"`
+	}
+
+	// BckRateLimitConf: per-bucket (and per-target) token bucket that throttles the requests
+	// to the bucket's remote backend - see ais/backend/throttle.go
+	BckRateLimitConf struct {
+		MaxRPS  int  `json:"max_rps"` // max requests per second, per target
+		Burst   int  `json:"burst"`   // max burst (0: max_rps)
+		Enabled bool `json:"enabled"`
+	}
+	BckRateLimitConfToUpdate struct {
+		MaxRPS  *int  `json:"max_rps"`
+		Burst   *int  `json:"burst"`
+		Enabled *bool `json:"enabled"`
+	}
+
+	// ColdGetConf: cold GET of remote objects - see ais/tgtcoldget.go and ais/tgtpartial.go
+	ColdGetConf struct {
+		Streaming    bool `json:"streaming"`     // stream the object to the client while caching it
+		RangeCaching bool `json:"range_caching"` // range read caches only the ranges that were read
+	}
+	ColdGetConfToUpdate struct {
+		Streaming    *bool `json:"streaming"`
+		RangeCaching *bool `json:"range_caching"`
+	}
+
 	ExtraProps struct {
-		AWS  ExtraPropsAWS  `json:"aws,omitempty" list:"omitempty"`
-		HTTP ExtraPropsHTTP `json:"http,omitempty" list:"omitempty"`
-		HDFS ExtraPropsHDFS `json:"hdfs,omitempty" list:"omitempty"`
+		AWS   ExtraPropsAWS   `json:"aws,omitempty" list:"omitempty"`
+		HTTP  ExtraPropsHTTP  `json:"http,omitempty" list:"omitempty"`
+		HDFS  ExtraPropsHDFS  `json:"hdfs,omitempty" list:"omitempty"`
+		POSIX ExtraPropsPOSIX `json:"posix,omitempty" list:"omitempty"`
 	}
 	ExtraToUpdate struct {
-		HDFS *ExtraPropsHDFSToUpdate `json:"hdfs"`
+		AWS   *ExtraPropsAWSToUpdate   `json:"aws"`
+		HTTP  *ExtraPropsHTTPToUpdate  `json:"http"`
+		HDFS  *ExtraPropsHDFSToUpdate  `json:"hdfs"`
+		POSIX *ExtraPropsPOSIXToUpdate `json:"posix"`
 	}
 
 	ExtraPropsAWS struct {
 		// Region where AWS bucket is located.
 		CloudRegion string `json:"cloud_region,omitempty" list:"readonly"`
+		// S3-compatible endpoint (MinIO, Ceph, etc.): one of the named endpoints
+		// in the cluster configuration (see BackendConfAWS) or URL; empty - AWS.
+		Endpoint string `json:"endpoint,omitempty"`
+		// Named profile (shared credentials and config files) - takes precedence
+		// over the endpoint's credentials.
+		Profile string `json:"profile,omitempty"`
+	}
+	ExtraPropsAWSToUpdate struct {
+		Endpoint *string `json:"endpoint"`
+		Profile  *string `json:"profile"`
 	}
 
 	ExtraPropsHTTP struct {
 		// Original URL prior to hashing.
 		OrigURLBck string `json:"original_url,omitempty" list:"readonly"`
+		// URL (absolute or relative to the original URL) of the manifest that lists
+		// the bucket's objects, one per line; empty - list index pages (autoindex).
+		Manifest string `json:"manifest,omitempty"`
+	}
+	ExtraPropsHTTPToUpdate struct {
+		Manifest *string `json:"manifest"`
 	}
 
 	ExtraPropsHDFS struct {
@@ -94,20 +252,36 @@ type (
 		RefDirectory *string `json:"ref_directory"`
 	}
 
+	ExtraPropsPOSIX struct {
+		// Reference directory (absolute path) - typically, NFS mount that is visible on all targets.
+		RefDirectory string `json:"ref_directory,omitempty"`
+	}
+	ExtraPropsPOSIXToUpdate struct {
+		RefDirectory *string `json:"ref_directory"`
+	}
+
 	// Once validated, BucketPropsToUpdate are copied to BucketProps.
 	// The struct may have extra fields that do not exist in BucketProps.
 	// Add tag 'copy:"skip"' to ignore those fields when copying values.
 	BucketPropsToUpdate struct {
-		BackendBck *BckToUpdate         `json:"backend_bck"`
-		Versioning *VersionConfToUpdate `json:"versioning"`
-		Cksum      *CksumConfToUpdate   `json:"checksum"`
-		LRU        *LRUConfToUpdate     `json:"lru"`
-		Mirror     *MirrorConfToUpdate  `json:"mirror"`
-		EC         *ECConfToUpdate      `json:"ec"`
-		Access     *AccessAttrs         `json:"access,string"`
-		MDWrite    *MDWritePolicy       `json:"md_write"`
-		Extra      *ExtraToUpdate       `json:"extra"`
-		Force      bool                 `json:"force" copy:"skip" list:"omit"`
+		BackendBck  *BckToUpdate                `json:"backend_bck"`
+		Versioning  *VersionConfToUpdate        `json:"versioning"`
+		Cksum       *CksumConfToUpdate          `json:"checksum"`
+		LRU         *LRUConfToUpdate            `json:"lru"`
+		Lifecycle   *LifecycleConfToUpdate      `json:"lifecycle"`
+		Encryption  *EncryptionConfToUpdate     `json:"encryption"`
+		Retention   *RetentionConfToUpdate      `json:"retention"`
+		Quota       *QuotaConfToUpdate          `json:"quota"`
+		Mirror      *MirrorConfToUpdate         `json:"mirror"`
+		Replication *BckReplicationConfToUpdate `json:"replication"`
+		RateLimit   *BckRateLimitConfToUpdate   `json:"rate_limit"`
+		ColdGet     *ColdGetConfToUpdate        `json:"cold_get"`
+		EC          *ECConfToUpdate             `json:"ec"`
+		Access      *AccessAttrs                `json:"access,string"`
+		MDWrite     *MDWritePolicy              `json:"md_write"`
+		WritePolicy *DataWritePolicy            `json:"write_policy"`
+		Extra       *ExtraToUpdate              `json:"extra"`
+		Force       bool                        `json:"force" copy:"skip" list:"omit"`
 	}
 
 	BckToUpdate struct {
@@ -139,6 +313,8 @@ func DefaultBckProps(bck Bck, cs ...*Config) *BucketProps {
 	}
 	if bck.IsAIS() {
 		c.LRU.Enabled = false
+	} else {
+		c.Versioning.Retain = false
 	}
 	return &BucketProps{
 		Cksum:      c.Cksum,
@@ -191,7 +367,10 @@ func (bp *BucketProps) Validate(targetCnt int) error {
 	var (
 		softErr        error
 		validationArgs = &ValidationArgs{Provider: bp.Provider, TargetCnt: targetCnt}
-		validators     = []PropsValidator{&bp.Cksum, &bp.LRU, &bp.Mirror, &bp.EC, &bp.Extra, bp.MDWrite}
+		validators     = []PropsValidator{
+			&bp.Cksum, &bp.Versioning, &bp.LRU, &bp.Lifecycle, &bp.Encryption, &bp.Retention, &bp.Quota,
+			&bp.Mirror, &bp.Replication, &bp.RateLimit, &bp.EC, &bp.Extra, bp.MDWrite, bp.WritePolicy,
+		}
 	)
 	for _, validator := range validators {
 		if err := validator.ValidateAsProps(validationArgs); err != nil {
@@ -205,6 +384,27 @@ func (bp *BucketProps) Validate(targetCnt int) error {
 	if bp.Mirror.Enabled && bp.EC.Enabled {
 		return fmt.Errorf("cannot enable mirroring and ec at the same time for the same bucket")
 	}
+	if bp.Versioning.Retain && !bp.BackendBck.IsEmpty() {
+		return fmt.Errorf("versioning.retain is not supported for buckets with remote backend (%q)", bp.BackendBck)
+	}
+	if bp.Retention.Enabled && !bp.BackendBck.IsEmpty() {
+		return fmt.Errorf("retention is not supported for buckets with remote backend (%q)", bp.BackendBck)
+	}
+	if bp.Replication.Enabled {
+		dst, _ := bp.Replication.DstBck()
+		if dst.Equal(bp.BackendBck) {
+			return fmt.Errorf("replication destination %q cannot be the bucket's backend", dst)
+		}
+	}
+	if bp.RateLimit.Enabled && bp.Provider == ProviderAIS && (bp.BackendBck.IsEmpty() || bp.BackendBck.IsRemoteAIS()) {
+		return errors.New("rate_limit applies only to Cloud, HDFS, HTTP, and POSIX buckets (and backends)")
+	}
+	if (bp.ColdGet.Streaming || bp.ColdGet.RangeCaching) && bp.Provider == ProviderAIS && bp.BackendBck.IsEmpty() {
+		return errors.New("cold_get applies only to remote buckets (and buckets with remote backends)")
+	}
+	if bp.WritePolicy.IsWriteBack() && !IsCloudProvider(bp.Provider) && !bp.BackendBck.IsCloud() {
+		return fmt.Errorf("write_policy %q requires Cloud bucket or Cloud backend (provider %q)", bp.WritePolicy, bp.Provider)
+	}
 	return softErr
 }
 
@@ -242,10 +442,280 @@ func (c *ExtraProps) ValidateAsProps(args *ValidationArgs) error {
 		if c.HDFS.RefDirectory == "" {
 			return fmt.Errorf("reference directory must be set for a bucket with HDFS provider")
 		}
+	case ProviderPOSIX:
+		if !filepath.IsAbs(c.POSIX.RefDirectory) {
+			return fmt.Errorf("reference directory must be set (as an absolute path) for a bucket with POSIX provider")
+		}
 	case ProviderHTTP:
 		if c.HTTP.OrigURLBck == "" {
 			return fmt.Errorf("original bucket URL must be set for a bucket with HTTP provider")
 		}
 	}
+	if args.Provider != ProviderAmazon && (c.AWS.Endpoint != "" || c.AWS.Profile != "") {
+		return fmt.Errorf("S3 endpoint and profile can only be set for a bucket with %q provider", ProviderAmazon)
+	}
+	if args.Provider != ProviderHTTP && c.HTTP.Manifest != "" {
+		return fmt.Errorf("manifest can only be set for a bucket with %q provider", ProviderHTTP)
+	}
+	return nil
+}
+
+/////////////////////
+// DataWritePolicy //
+/////////////////////
+
+func (c DataWritePolicy) ValidateAsProps(_ *ValidationArgs) error {
+	if c == "" || c == WriteThrough || c == WriteBack {
+		return nil
+	}
+	return fmt.Errorf("invalid write_policy %q (expecting one of %v)", c, SupportedDataWritePolicy)
+}
+
+///////////////////
+// LifecycleConf //
+///////////////////
+
+func (c *LifecycleConf) ValidateAsProps(_ *ValidationArgs) error {
+	ids := make(cos.StringSet, len(c.Rules))
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.ExpireDays < 0 || rule.NoncurrentDays < 0 || rule.EvictDays < 0 {
+			return fmt.Errorf("invalid lifecycle rule %s: the number of days cannot be negative", rule)
+		}
+		if rule.ExpireDays == 0 && rule.NoncurrentDays == 0 && rule.EvictDays == 0 {
+			return fmt.Errorf("invalid lifecycle rule %s: no action specified", rule)
+		}
+		if rule.ID == "" {
+			continue
+		}
+		if ids.Contains(rule.ID) {
+			return fmt.Errorf("duplicate lifecycle rule ID %q", rule.ID)
+		}
+		ids.Add(rule.ID)
+	}
 	return nil
 }
+
+// Match returns enabled rules that apply to a given object.
+func (c *LifecycleConf) Match(objName string) (rules []*LifecycleRule) {
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if !rule.Disabled && strings.HasPrefix(objName, rule.Prefix) {
+			rules = append(rules, rule)
+		}
+	}
+	return
+}
+
+func (c *LifecycleConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return fmt.Sprintf("%d rule(s)", len(c.Rules))
+}
+
+func (rule LifecycleRule) String() string {
+	s := fmt.Sprintf("[id: %q, prefix: %q", rule.ID, rule.Prefix)
+	if rule.ExpireDays > 0 {
+		s += fmt.Sprintf(", expire: %dd", rule.ExpireDays)
+	}
+	if rule.NoncurrentDays > 0 {
+		s += fmt.Sprintf(", noncurrent: %dd", rule.NoncurrentDays)
+	}
+	if rule.EvictDays > 0 {
+		s += fmt.Sprintf(", evict: %dd", rule.EvictDays)
+	}
+	if rule.Disabled {
+		s += ", disabled"
+	}
+	return s + "]"
+}
+
+////////////////////
+// EncryptionConf //
+////////////////////
+
+func (c *EncryptionConf) ValidateAsProps(_ *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if kms := GCO.Get().KMS; kms.Provider == "" {
+		return errors.New("cannot enable encryption: key management service (kms) is not configured")
+	}
+	return nil
+}
+
+func (c *EncryptionConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	if c.KeyID == "" {
+		return "AES-256-GCM (default key)"
+	}
+	return fmt.Sprintf("AES-256-GCM (key %q)", c.KeyID)
+}
+
+///////////////////
+// RetentionConf //
+///////////////////
+
+// retention modes
+const (
+	RetentionGovernance = "governance"
+	RetentionCompliance = "compliance"
+)
+
+func (c *RetentionConf) ValidateAsProps(args *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if args.Provider != ProviderAIS {
+		return fmt.Errorf("retention is supported only for %q buckets (got %q)", ProviderAIS, args.Provider)
+	}
+	if c.Mode != RetentionGovernance && c.Mode != RetentionCompliance {
+		return fmt.Errorf("invalid retention mode %q (expecting %q or %q)",
+			c.Mode, RetentionGovernance, RetentionCompliance)
+	}
+	if c.Period < 0 {
+		return fmt.Errorf("invalid retention period %v", c.Period)
+	}
+	return nil
+}
+
+// ValidateUpdate checks whether the (current) configuration can be changed to `nc`:
+// in compliance mode, retention can be neither disabled nor shortened.
+func (c *RetentionConf) ValidateUpdate(nc *RetentionConf) error {
+	if !c.Enabled || c.Mode != RetentionCompliance {
+		return nil
+	}
+	if !nc.Enabled || nc.Mode != RetentionCompliance {
+		return errors.New("retention in compliance mode cannot be disabled or changed to governance mode")
+	}
+	if nc.Period < c.Period {
+		return fmt.Errorf("retention period in compliance mode cannot be shortened (%v => %v)", c.Period, nc.Period)
+	}
+	return nil
+}
+
+func (c *RetentionConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return fmt.Sprintf("%s, %v", c.Mode, c.Period)
+}
+
+///////////////
+// QuotaConf //
+///////////////
+
+func (c *QuotaConf) ValidateAsProps(*ValidationArgs) error { return c.Validate() }
+
+func (c *QuotaConf) Validate() error {
+	if c.SoftBytes < 0 || c.HardBytes < 0 || c.SoftObjs < 0 || c.HardObjs < 0 {
+		return fmt.Errorf("invalid quota %+v (limits cannot be negative)", *c)
+	}
+	if c.HardBytes != 0 && c.SoftBytes > c.HardBytes {
+		return fmt.Errorf("invalid quota: soft_bytes (%d) exceeds hard_bytes (%d)", c.SoftBytes, c.HardBytes)
+	}
+	if c.HardObjs != 0 && c.SoftObjs > c.HardObjs {
+		return fmt.Errorf("invalid quota: soft_objs (%d) exceeds hard_objs (%d)", c.SoftObjs, c.HardObjs)
+	}
+	return nil
+}
+
+// Exceeds returns the name of the first hard limit that the given usage (size, objs)
+// exceeds, or empty string.
+func (c *QuotaConf) Exceeds(size, objs int64) string {
+	switch {
+	case c.HardBytes != 0 && size > c.HardBytes:
+		return "hard_bytes"
+	case c.HardObjs != 0 && objs > c.HardObjs:
+		return "hard_objs"
+	}
+	return ""
+}
+
+// ExceedsSoft is the same as Exceeds, for the soft limits.
+func (c *QuotaConf) ExceedsSoft(size, objs int64) string {
+	switch {
+	case c.SoftBytes != 0 && size > c.SoftBytes:
+		return "soft_bytes"
+	case c.SoftObjs != 0 && objs > c.SoftObjs:
+		return "soft_objs"
+	}
+	return ""
+}
+
+func (c *QuotaConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return fmt.Sprintf("size %s/%s, objects %d/%d (soft/hard)",
+		cos.B2S(c.SoftBytes, 0), cos.B2S(c.HardBytes, 0), c.SoftObjs, c.HardObjs)
+}
+
+////////////////////////
+// BckReplicationConf //
+////////////////////////
+
+func (c *BckReplicationConf) ValidateAsProps(*ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Dst == "" {
+		return errors.New("replication destination (replication.dst) must be specified")
+	}
+	dst, err := c.DstBck()
+	if err != nil {
+		return err
+	}
+	if !dst.IsRemoteAIS() && !dst.IsCloud() {
+		return fmt.Errorf("invalid replication destination %q (expecting bucket on a remote AIS cluster or in the Cloud)", c.Dst)
+	}
+	return nil
+}
+
+// DstBck parses the destination bucket, e.g. "ais://@remais/abc" or "gs://abc".
+func (c *BckReplicationConf) DstBck() (bck Bck, err error) {
+	var objName string
+	bck, objName, err = ParseBckObjectURI(c.Dst, ParseURIOpts{})
+	if err != nil {
+		return bck, fmt.Errorf("invalid replication destination %q: %v", c.Dst, err)
+	}
+	if bck.Name == "" || objName != "" {
+		return bck, fmt.Errorf("invalid replication destination %q (expecting bucket URI)", c.Dst)
+	}
+	return
+}
+
+func (c *BckReplicationConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return "to " + c.Dst
+}
+
+//////////////////////
+// BckRateLimitConf //
+//////////////////////
+
+func (c *BckRateLimitConf) ValidateAsProps(*ValidationArgs) error {
+	if c.MaxRPS < 0 || c.Burst < 0 {
+		return fmt.Errorf("invalid rate_limit %+v (values cannot be negative)", *c)
+	}
+	if c.Enabled && c.MaxRPS == 0 {
+		return errors.New("rate_limit.max_rps must be specified")
+	}
+	return nil
+}
+
+func (c *BckRateLimitConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	burst := c.Burst
+	if burst == 0 {
+		burst = c.MaxRPS
+	}
+	return fmt.Sprintf("%d req/s (burst %d)", c.MaxRPS, burst)
+}
@@ -39,20 +39,24 @@ const (
 	ActEvictRemoteBck  = "evict-remote-bck" // evict remote bucket's data
 	ActInvalListCache  = "inval-listobj-cache"
 	ActLRU             = "lru"
+	ActLifecycle       = "lifecycle"
 	ActList            = "list"
 	ActLoadLomCache    = "load-lom-cache"
 	ActMakeNCopies     = "make-n-copies"
 	ActMoveBck         = "move-bck"
 	ActNewPrimary      = "new-primary"
 	ActPrefetchObjects = "prefetch-listrange"
+	ActPresignObject   = "presign-obj"
 	ActPromote         = "promote"
 	ActPutCopies       = "put-copies"
 	ActQueryObjects    = "query-objs"
 	ActRebalance       = "rebalance"
 	ActRenameObject    = "rename-obj"
+	ActReplicate       = "replicate"
 	ActResetBprops     = "reset-bprops"
 	ActResetConfig     = "reset-config"
 	ActResilver        = "resilver"
+	ActResync          = "resync"
 	ActResyncBprops    = "resync-bprops"
 	ActSetBprops       = "set-bprops"
 	ActSetConfig       = "set-config"
@@ -60,6 +64,7 @@ const (
 	ActStartGFN        = "start-gfn"
 	ActStoreCleanup    = "cleanup-store"
 	ActSummary         = "summary"
+	ActWriteBack       = "write-back"
 
 	ActAttachRemote = "attach"
 	ActDetachRemote = "detach"
@@ -136,6 +141,12 @@ const (
 	// Query objects handle header.
 	HdrHandle = headerPrefix + "query-handle"
 
+	// Prefetch: client (reader) session, to prefetch ahead of (see PrefetchMsg.ReadAhead).
+	HdrReaderSession = headerPrefix + "reader-session"
+
+	// ETL: arguments forwarded to the transformer (see ETLStage.Args).
+	HdrETLArgs = headerPrefix + "etl-args"
+
 	// Reverse proxy headers.
 	HdrNodeID  = headerPrefix + "node-id"
 	HdrNodeURL = headerPrefix + "node-url"
@@ -191,6 +202,11 @@ const (
 	URLParamUUID        = "uuid"
 	URLParamRegex       = "regex" // dsort/downloader regex
 
+	// GET object: JSON-encoded ETLPipeline (instead of a single ETL `uuid`)
+	URLParamETLPipeline = "etl_pipeline"
+	// arguments for the ETL (GET with URLParamUUID) - see HdrETLArgs
+	URLParamETLArgs = "etl_args"
+
 	// Bucket related query params.
 	URLParamProvider  = "provider" // backend provider
 	URLParamNamespace = "namespace"
@@ -200,6 +216,14 @@ const (
 	// Object related query params.
 	URLParamAppendType   = "append_type"
 	URLParamAppendHandle = "append_handle"
+	URLParamVersion      = "version" // GET or DELETE a given version of the object
+
+	// (retention) delete or modify objects retained in governance mode; requires admin access
+	URLParamBypassGovernance = "bypass_governance"
+
+	// presigned URL: expiration time (Unix seconds) and signature (see ActPresignObject)
+	URLParamExpires   = "expires"
+	URLParamSignature = "signature"
 
 	// HTTP bucket support.
 	URLParamOrigURL = "original_url"
@@ -246,6 +270,10 @@ const (
 	// or errors (e.g., attach invalid mountpath)
 	URLParamForce = "frc"
 
+	// S3-compatible endpoint and profile of the (not yet added) bucket to lookup (see ExtraPropsAWS)
+	URLParamS3Endpoint = "s3e"
+	URLParamS3Profile  = "s3p"
+
 	URLParamDontLookupRemoteBck = "dntlrb" // true: do not try to lookup remote buckets on the fly (overrides the default)
 	URLParamDontResilver        = "dntres" // true: do not resilver data off of mountpaths that are being disabled/detached
 
@@ -295,6 +323,7 @@ const (
 	GetWhatSysInfo       = "sysinfo"
 	GetWhatTargetIPs     = "target_ips"
 	GetWhatLog           = "log"
+	GetWhatQuotaUsage    = "quota_usage" // intra-cluster: bucket usage (quotas) by target
 )
 
 // Internal "what" values.
@@ -327,6 +356,10 @@ const (
 	// Flags
 	EntryIsCached = 1 << (EntryStatusBits + 1)
 	EntryInArch   = 1 << (EntryStatusBits + 2)
+
+	// LsVersions
+	EntryNoncurrent = 1 << (EntryStatusBits + 3) // prior (retained) version of the object
+	EntryDelMarker  = 1 << (EntryStatusBits + 4) // delete marker
 )
 
 // List objects default page size
@@ -369,6 +402,7 @@ const (
 	Voteres    = "result"
 	VoteInit   = "init"
 	Mountpaths = "mountpaths"
+	S3Keys     = "s3keys" // AuthN
 
 	// (see the corresponding action messages above)
 	Keepalive          = "keepalive"
@@ -438,7 +472,18 @@ const (
 	WriteDefault = MDWritePolicy("") // equivalent to immediate writing (WriteImmediate)
 )
 
+// data write policy (bucket property `write_policy`) - see xs/writeback.go
+type DataWritePolicy string
+
+func (dw DataWritePolicy) IsWriteBack() bool { return dw == WriteBack }
+
+const (
+	WriteThrough = DataWritePolicy("write-through") // PUT writes remote backend synchronously (default)
+	WriteBack    = DataWritePolicy("write-back")    // PUT completes locally; remote backend is written asynchronously
+)
+
 var (
-	SupportedWritePolicy = []string{string(WriteImmediate), string(WriteDelayed), string(WriteNever)}
-	SupportedCompression = []string{CompressNever, CompressAlways}
+	SupportedWritePolicy     = []string{string(WriteImmediate), string(WriteDelayed), string(WriteNever)}
+	SupportedDataWritePolicy = []string{string(WriteThrough), string(WriteBack)}
+	SupportedCompression     = []string{CompressNever, CompressAlways}
 )
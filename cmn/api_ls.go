@@ -22,6 +22,10 @@ const (
 
 	// cache list-objects results and use this cache to speed-up
 	UseListObjsCache
+
+	// include prior versions and delete markers (buckets with versioning.retain);
+	// noncurrent versions precede the current one and do not count towards the page size
+	LsVersions
 )
 
 type (
@@ -21,6 +21,12 @@ type (
 		Overwrite bool   `json:"overwrite"`
 		KeepOrig  bool   `json:"keep_original"`
 	}
+	// ActPresignObject: URL that grants access to the object without a token
+	ActValPresign struct {
+		Method  string       `json:"method"`  // GET (default), HEAD, or PUT
+		Expires cos.Duration `json:"expires"` // valid for (default: 1h; max: 7 days)
+		S3      bool         `json:"s3"`      // S3 API URL (/s3/bucket/object)
+	}
 	ActValRmNode struct {
 		DaemonID      string `json:"sid"`
 		SkipRebalance bool   `json:"skip_rebalance"`
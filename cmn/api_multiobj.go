@@ -5,7 +5,18 @@
  */
 package cmn
 
-import "path/filepath"
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// prefetch limits (per target)
+const (
+	MaxPrefetchWorkers   = 1024
+	MaxPrefetchRate      = 100_000 // objects per second
+	MaxPrefetchReadAhead = 100_000 // manifest entries
+)
 
 // used in multi-object (list|range) operations
 type (
@@ -15,6 +26,18 @@ type (
 		Template string   `json:"template"`
 	}
 
+	// PrefetchMsg is used in prefetch operations: a list or a range of objects, or else
+	// the objects named in a manifest - all at once or, when ReadAhead is specified,
+	// continuously, just ahead of the reader(s) (see also: HdrReaderSession)
+	PrefetchMsg struct {
+		ListRangeMsg
+		ManifestBck Bck    `json:"manifest_bck"` // bucket that contains the manifest (default: the bucket to prefetch)
+		Manifest    string `json:"manifest"`     // manifest: one object name (or template) per line
+		NumWorkers  int    `json:"num_workers"`  // concurrency budget, per target (default: 1)
+		Rate        int    `json:"rate"`         // max objects per second, per target (0: unlimited)
+		ReadAhead   int    `json:"read_ahead"`   // number of manifest entries to prefetch ahead of each reader session
+	}
+
 	// ArchiveMsg is used in CreateArchMultiObj operations; the message contains parameters
 	// for archiving mutiple (source) objects as one of the supported cos.ArchExtensions types
 	// at the specified (bucket) destination
@@ -47,6 +70,35 @@ type (
 func (lrm *ListRangeMsg) IsList() bool      { return len(lrm.ObjNames) > 0 }
 func (lrm *ListRangeMsg) HasTemplate() bool { return lrm.Template != "" }
 
+/////////////////
+// PrefetchMsg //
+/////////////////
+
+func (msg *PrefetchMsg) HasManifest() bool { return msg.Manifest != "" }
+
+func (msg *PrefetchMsg) Validate() error {
+	if msg.HasManifest() && (msg.IsList() || msg.HasTemplate()) {
+		return errors.New("prefetch: manifest cannot be combined with a list or a template")
+	}
+	if msg.ReadAhead > 0 && !msg.HasManifest() {
+		return errors.New("prefetch: read-ahead requires a manifest")
+	}
+	if msg.NumWorkers < 0 || msg.Rate < 0 || msg.ReadAhead < 0 {
+		return fmt.Errorf("prefetch: invalid (negative) num-workers %d, rate %d, or read-ahead %d",
+			msg.NumWorkers, msg.Rate, msg.ReadAhead)
+	}
+	if msg.NumWorkers > MaxPrefetchWorkers {
+		return fmt.Errorf("prefetch: num-workers %d exceeds the maximum %d", msg.NumWorkers, MaxPrefetchWorkers)
+	}
+	if msg.Rate > MaxPrefetchRate {
+		return fmt.Errorf("prefetch: rate %d exceeds the maximum %d objects per second", msg.Rate, MaxPrefetchRate)
+	}
+	if msg.ReadAhead > MaxPrefetchReadAhead {
+		return fmt.Errorf("prefetch: read-ahead %d exceeds the maximum %d", msg.ReadAhead, MaxPrefetchReadAhead)
+	}
+	return nil
+}
+
 ////////////////
 // ArchiveMsg //
 ////////////////
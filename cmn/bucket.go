@@ -24,8 +24,9 @@ const (
 	ProviderAzure  = "azure"
 	ProviderGoogle = "gcp"
 	ProviderHDFS   = "hdfs"
+	ProviderPOSIX  = "posix"
 	ProviderHTTP   = "ht"
-	allProviders   = "ais, aws (s3://), gcp (gs://), azure (az://), hdfs://, ht://"
+	allProviders   = "ais, aws (s3://), gcp (gs://), azure (az://), hdfs://, posix (file://), ht://"
 
 	NsUUIDPrefix = '@' // BEWARE: used by on-disk layout
 	NsNamePrefix = '#' // BEWARE: used by on-disk layout
@@ -38,6 +39,7 @@ const (
 	S3Scheme      = "s3"
 	AZScheme      = "az"
 	AISScheme     = "ais"
+	FileScheme    = "file"
 )
 
 type (
@@ -101,6 +103,7 @@ var (
 		ProviderAmazon,
 		ProviderAzure,
 		ProviderHDFS,
+		ProviderPOSIX,
 		ProviderHTTP,
 	)
 )
@@ -157,6 +160,8 @@ func NormalizeProvider(provider string) (string, error) {
 		return ProviderAzure, nil
 	case GSScheme:
 		return ProviderGoogle, nil
+	case FileScheme:
+		return ProviderPOSIX, nil
 	default:
 		if !IsNormalizedProvider(provider) {
 			return provider, NewErrorInvalidBucketProvider(Bck{Provider: provider})
@@ -423,10 +428,11 @@ func (b *Bck) RemoteBck() *Bck {
 func (b Bck) IsAIS() bool       { return b.Provider == ProviderAIS && !b.Ns.IsRemote() && !b.HasBackendBck() }
 func (b Bck) IsRemoteAIS() bool { return b.Provider == ProviderAIS && b.Ns.IsRemote() }
 func (b Bck) IsHDFS() bool      { return b.Provider == ProviderHDFS }
+func (b Bck) IsPOSIX() bool     { return b.Provider == ProviderPOSIX }
 func (b Bck) IsHTTP() bool      { return b.Provider == ProviderHTTP }
 
 func (b Bck) IsRemote() bool {
-	return b.IsCloud() || b.IsRemoteAIS() || b.IsHDFS() || b.IsHTTP() || b.HasBackendBck()
+	return b.IsCloud() || b.IsRemoteAIS() || b.IsHDFS() || b.IsPOSIX() || b.IsHTTP() || b.HasBackendBck()
 }
 
 func (b Bck) IsCloud() bool {
@@ -449,6 +455,7 @@ func (b Bck) HasProvider() bool {
 func (query QueryBcks) String() string    { return Bck(query).String() }
 func (query QueryBcks) IsAIS() bool       { return Bck(query).IsAIS() }
 func (query QueryBcks) IsHDFS() bool      { return Bck(query).IsHDFS() }
+func (query QueryBcks) IsPOSIX() bool     { return Bck(query).IsPOSIX() }
 func (query QueryBcks) IsRemoteAIS() bool { return Bck(query).IsRemoteAIS() }
 func (query QueryBcks) IsCloud() bool     { return IsCloudProvider(query.Provider) }
 
@@ -33,6 +33,8 @@ func (be *BucketEntry) SetExists()         { be.Flags |= EntryIsCached }
 func (be *BucketEntry) IsStatusOK() bool   { return be.Status() == 0 }
 func (be *BucketEntry) Status() uint16     { return be.Flags & EntryStatusMask }
 func (be *BucketEntry) IsInsideArch() bool { return be.Flags&EntryInArch != 0 }
+func (be *BucketEntry) IsNoncurrent() bool { return be.Flags&EntryNoncurrent != 0 }
+func (be *BucketEntry) IsDelMarker() bool  { return be.Flags&EntryDelMarker != 0 }
 func (be *BucketEntry) String() string     { return "{" + be.Name + "}" }
 
 func (be *BucketEntry) CopyWithProps(propsSet cos.StringSet) (ne *BucketEntry) {
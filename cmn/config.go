@@ -52,6 +52,20 @@ const (
 	MaxSliceCount = 32 // erasure coding: maximum number of data or parity slices
 )
 
+// built-in key management service (see KMSConf)
+const KMSProviderFile = "file"
+
+// shown in place of secrets (see BackendConf.Redacted)
+const RedactedSecret = "****"
+
+// ETL runtimes and the local runtime's default launcher (see ETLConf)
+const (
+	ETLRuntimeK8s   = "k8s"
+	ETLRuntimeLocal = "local"
+
+	ETLLauncherProcess = "process"
+)
+
 const (
 	IgnoreReaction = "ignore"
 	WarnReaction   = "warn"
@@ -134,6 +148,10 @@ type (
 		Downloader  DownloaderConf  `json:"downloader"`
 		DSort       DSortConf       `json:"distributed_sort"`
 		Compression CompressionConf `json:"compression"`
+		KMS         KMSConf         `json:"kms"`
+		NsQuota     NsQuotaConf     `json:"ns_quota"`
+		RateLimit   RateLimitConf   `json:"rate_limit"`
+		ETL         ETLConf         `json:"etl"`
 		MDWrite     MDWritePolicy   `json:"md_write"`
 		LastUpdated string          `json:"lastupdate_time"`
 		UUID        string          `json:"uuid"`                  // immutable
@@ -185,6 +203,10 @@ type (
 		Downloader  *DownloaderConfToUpdate  `json:"downloader,omitempty"`
 		DSort       *DSortConfToUpdate       `json:"distributed_sort,omitempty"`
 		Compression *CompressionConfToUpdate `json:"compression,omitempty"`
+		KMS         *KMSConfToUpdate         `json:"kms,omitempty"`
+		NsQuota     *NsQuotaConfToUpdate     `json:"ns_quota,omitempty"`
+		RateLimit   *RateLimitConfToUpdate   `json:"rate_limit,omitempty"`
+		ETL         *ETLConfToUpdate         `json:"etl,omitempty"`
 		MDWrite     *MDWritePolicy           `json:"md_write,omitempty"`
 		Proxy       *ProxyConfToUpdate       `json:"proxy,omitempty"`
 
@@ -214,10 +236,42 @@ type (
 	BackendConfAIS map[string][]string // cluster alias -> [urls...]
 	BackendInfoAIS map[string]*RemoteAISInfo
 
+	// named S3-compatible endpoints (MinIO, Ceph, etc.) referenced by bucket property
+	// `extra.aws.endpoint` (see ExtraPropsAWS)
+	BackendConfAWS struct {
+		Endpoints map[string]*S3Endpoint `json:"endpoints,omitempty"`
+	}
+	S3Endpoint struct {
+		URL        string `json:"url"`                  // e.g. "http://minio.local:9000"
+		Region     string `json:"region,omitempty"`     // default: us-east-1
+		Profile    string `json:"profile,omitempty"`    // named profile (shared credentials and config files)
+		AccessKey  string `json:"access_key,omitempty"` // static credentials (take precedence over the profile)
+		SecretKey  string `json:"secret_key,omitempty"` // ditto
+		PathStyle  bool   `json:"path_style"`           // path-style addressing (most S3-compatible stores)
+		SkipVerify bool   `json:"skip_verify"`          // skip HTTPS cert verification (self-signed certs)
+	}
+
 	BackendConfHDFS struct {
 		Addresses           []string `json:"addresses"`
 		User                string   `json:"user"`
 		UseDatanodeHostname bool     `json:"use_datanode_hostname"`
+		// secure (kerberized) HDFS clusters
+		Kerberos *HDFSKerberosConf `json:"kerberos,omitempty"`
+		// "authentication", "integrity", or "privacy" (cf. dfs.data.transfer.protection)
+		DataTransferProtection string `json:"data_transfer_protection,omitempty"`
+	}
+	// keytab-based Kerberos authentication
+	HDFSKerberosConf struct {
+		Principal        string `json:"principal"`           // e.g. "aistore@EXAMPLE.COM" (default realm: krb5.conf)
+		Keytab           string `json:"keytab"`              // e.g. "/etc/security/keytabs/aistore.keytab"
+		Krb5Conf         string `json:"krb5_conf,omitempty"` // default: "/etc/krb5.conf"
+		ServicePrincipal string `json:"service_principal"`   // NameNode SPN, e.g. "nn/_HOST"
+	}
+
+	// directories (typically, NFS mounts that are visible on all targets) that posix buckets
+	// are permitted to reference (see ExtraPropsPOSIX); empty - any directory
+	BackendConfPOSIX struct {
+		Roots []string `json:"roots,omitempty"`
 	}
 
 	MirrorConf struct {
@@ -430,10 +484,14 @@ type (
 
 		// Validate object version upon warm GET.
 		ValidateWarmGet bool `json:"validate_warm_get"`
+
+		// Retain prior versions of the objects (AIS buckets only).
+		Retain bool `json:"retain"`
 	}
 	VersionConfToUpdate struct {
 		Enabled         *bool `json:"enabled,omitempty"`
 		ValidateWarmGet *bool `json:"validate_warm_get,omitempty"`
+		Retain          *bool `json:"retain,omitempty"`
 	}
 
 	TestFSPConf struct {
@@ -563,6 +621,60 @@ type (
 		Checksum     *bool `json:"checksum,omitempty"`
 	}
 
+	// key management service for server-side encryption (see `encryption` bucket property)
+	KMSConf struct {
+		Provider string `json:"provider"` // "" (none), KMSProviderFile, or see sse.RegisterKMS
+		KeyFile  string `json:"key_file"` // master keys: JSON file (provider "file")
+	}
+	KMSConfToUpdate struct {
+		Provider *string `json:"provider,omitempty"`
+		KeyFile  *string `json:"key_file,omitempty"`
+	}
+
+	// namespace quotas (see also `quota` bucket property)
+	NsQuotaConf struct {
+		// by namespace (cmn.Ns.String(), e.g. "#team-a")
+		Namespaces map[string]QuotaConf `json:"namespaces,omitempty"`
+		// how often targets recompute and exchange bucket usage (bucket and namespace quotas)
+		SyncTime cos.Duration `json:"sync_time"`
+	}
+	NsQuotaConfToUpdate struct {
+		Namespaces *map[string]QuotaConf `json:"namespaces,omitempty"`
+		SyncTime   *cos.Duration         `json:"sync_time,omitempty"`
+	}
+
+	// rate limiting and retrying of the requests to remote backends, by provider (e.g. "gcp");
+	// see also bucket property `rate_limit`
+	RateLimitConf struct {
+		Backends map[string]BackendRateLimit `json:"backends,omitempty"`
+	}
+	RateLimitConfToUpdate struct {
+		Backends *map[string]BackendRateLimit `json:"backends,omitempty"`
+	}
+	BackendRateLimit struct {
+		MaxRPS int `json:"max_rps"` // max requests per second, per target (0: unlimited)
+		Burst  int `json:"burst"`   // max burst (0: max_rps)
+		// retrying throttled requests (HTTP 429 and 503, S3 SlowDown, etc.) with exponential backoff
+		MaxRetries int          `json:"max_retries"`
+		MinBackoff cos.Duration `json:"min_backoff"` // first retry (0: 500ms); doubles with each next one...
+		MaxBackoff cos.Duration `json:"max_backoff"` // ...up to this (0: 30s)
+	}
+
+	// where and how targets run ETL transformers
+	ETLConf struct {
+		Runtime string `json:"runtime"` // ETLRuntimeK8s (default) or ETLRuntimeLocal
+		// local runtime only:
+		// ETLLauncherProcess (default) - run the transformer as a subprocess of the target, or
+		// OCI container CLI (e.g. "docker", "podman", or the full path)
+		Launcher string `json:"launcher"`
+		WorkDir  string `json:"work_dir"` // transformers' volumes (default: $TMPDIR/ais-etl)
+	}
+	ETLConfToUpdate struct {
+		Runtime  *string `json:"runtime,omitempty"`
+		Launcher *string `json:"launcher,omitempty"`
+		WorkDir  *string `json:"work_dir,omitempty"`
+	}
+
 	// obsolete; TODO: remove with the next meta-version update
 	ReplicationConf struct {
 		OnColdGet     bool `json:"on_cold_get"`
@@ -731,9 +843,18 @@ var (
 	_ Validator = (*DownloaderConf)(nil)
 	_ Validator = (*DSortConf)(nil)
 	_ Validator = (*CompressionConf)(nil)
+	_ Validator = (*KMSConf)(nil)
+	_ Validator = (*NsQuotaConf)(nil)
+	_ Validator = (*RateLimitConf)(nil)
+	_ Validator = (*ETLConf)(nil)
 
 	_ PropsValidator = (*CksumConf)(nil)
 	_ PropsValidator = (*LRUConf)(nil)
+	_ PropsValidator = (*LifecycleConf)(nil)
+	_ PropsValidator = (*EncryptionConf)(nil)
+	_ PropsValidator = (*RetentionConf)(nil)
+	_ PropsValidator = (*QuotaConf)(nil)
+	_ PropsValidator = (*VersionConf)(nil)
 	_ PropsValidator = (*MirrorConf)(nil)
 	_ PropsValidator = (*ECConf)(nil)
 
@@ -860,6 +981,21 @@ func (c *BackendConf) Validate() (err error) {
 				break
 			}
 			c.Conf[provider] = aisConf
+		case ProviderAmazon:
+			var awsConf BackendConfAWS
+			if err := jsoniter.Unmarshal(b, &awsConf); err != nil {
+				return fmt.Errorf("invalid cloud specification: %v", err)
+			}
+			for name, ep := range awsConf.Endpoints {
+				if ep == nil {
+					return fmt.Errorf("S3 endpoint %q is empty", name)
+				}
+				if err := ep.Validate(); err != nil {
+					return fmt.Errorf("S3 endpoint %q: %v", name, err)
+				}
+			}
+			c.Conf[provider] = awsConf
+			c.setProvider(provider)
 		case ProviderHDFS:
 			var hdfsConf BackendConfHDFS
 			if err := jsoniter.Unmarshal(b, &hdfsConf); err != nil {
@@ -868,6 +1004,9 @@ func (c *BackendConf) Validate() (err error) {
 			if len(hdfsConf.Addresses) == 0 {
 				return fmt.Errorf("no addresses provided to HDFS NameNode")
 			}
+			if err := hdfsConf.Validate(); err != nil {
+				return err
+			}
 
 			// Check connectivity and filter out non-reachable addresses.
 			reachableAddrs := hdfsConf.Addresses[:0]
@@ -892,6 +1031,19 @@ func (c *BackendConf) Validate() (err error) {
 
 			c.Conf[provider] = hdfsConf
 			c.setProvider(provider)
+		case ProviderPOSIX:
+			var posixConf BackendConfPOSIX
+			if err := jsoniter.Unmarshal(b, &posixConf); err != nil {
+				return fmt.Errorf("invalid cloud specification: %v", err)
+			}
+			for i, root := range posixConf.Roots {
+				if !filepath.IsAbs(root) {
+					return fmt.Errorf("POSIX root %q must be an absolute path", root)
+				}
+				posixConf.Roots[i] = filepath.Clean(root)
+			}
+			c.Conf[provider] = posixConf
+			c.setProvider(provider)
 		case "":
 			continue
 		default:
@@ -904,7 +1056,7 @@ func (c *BackendConf) Validate() (err error) {
 func (c *BackendConf) setProvider(provider string) {
 	var ns Ns
 	switch provider {
-	case ProviderAmazon, ProviderAzure, ProviderGoogle, ProviderHDFS:
+	case ProviderAmazon, ProviderAzure, ProviderGoogle, ProviderHDFS, ProviderPOSIX:
 		ns = NsGlobal
 	default:
 		cos.AssertMsg(false, "unknown backend provider "+provider)
@@ -923,6 +1075,105 @@ func (c *BackendConf) ProviderConf(provider string, newConf ...interface{}) (con
 	return
 }
 
+// S3Endpoint returns one of the named S3-compatible endpoints or, if `endpoint` is a URL,
+// the endpoint with path-style addressing and default credentials
+func (c *BackendConf) S3Endpoint(endpoint string) (*S3Endpoint, error) {
+	if strings.HasPrefix(endpoint, "http://") || strings.HasPrefix(endpoint, "https://") {
+		ep := &S3Endpoint{URL: endpoint, PathStyle: true}
+		return ep, ep.Validate()
+	}
+	var awsConf BackendConfAWS
+	if conf, ok := c.Conf[ProviderAmazon]; ok {
+		if err := cos.MorphMarshal(conf, &awsConf); err != nil {
+			return nil, err
+		}
+	}
+	if ep, ok := awsConf.Endpoints[endpoint]; ok {
+		return ep, nil
+	}
+	return nil, fmt.Errorf("unknown S3 endpoint %q (expecting URL or one of the named endpoints in %q config)",
+		endpoint, "backend.aws")
+}
+
+// Redacted returns a copy of the backend config with the S3 endpoints' secret keys masked -
+// to show the config to users (see `GET /v1/cluster?what=config` and `ais show config`)
+func (c *BackendConf) Redacted() BackendConf {
+	conf, ok := c.Conf[ProviderAmazon]
+	if !ok {
+		return *c
+	}
+	var awsConf BackendConfAWS
+	if err := cos.MorphMarshal(conf, &awsConf); err != nil || len(awsConf.Endpoints) == 0 {
+		return *c
+	}
+	for _, ep := range awsConf.Endpoints { // (unmarshaled copies)
+		if ep != nil && ep.SecretKey != "" {
+			ep.SecretKey = RedactedSecret
+		}
+	}
+	redacted := *c
+	redacted.Conf = make(map[string]interface{}, len(c.Conf))
+	for provider, conf := range c.Conf {
+		redacted.Conf[provider] = conf
+	}
+	redacted.Conf[ProviderAmazon] = awsConf
+	return redacted
+}
+
+func (ep *S3Endpoint) Validate() error {
+	u, err := url.Parse(ep.URL)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL %q (expecting http(s)://host[:port])", ep.URL)
+	}
+	if (ep.AccessKey == "") != (ep.SecretKey == "") {
+		return errors.New("access key and secret key must be specified together")
+	}
+	return nil
+}
+
+func (c *BackendConfHDFS) Validate() error {
+	switch c.DataTransferProtection {
+	case "", "authentication", "integrity", "privacy":
+	default:
+		return fmt.Errorf("invalid HDFS data transfer protection %q (expecting one of: %q, %q, %q)",
+			c.DataTransferProtection, "authentication", "integrity", "privacy")
+	}
+	if c.Kerberos == nil {
+		return nil
+	}
+	if c.Kerberos.Principal == "" || c.Kerberos.Keytab == "" {
+		return errors.New("HDFS Kerberos principal and keytab must be specified together")
+	}
+	if c.Kerberos.ServicePrincipal == "" {
+		return errors.New("HDFS Kerberos service principal (of the NameNode) is required")
+	}
+	if !filepath.IsAbs(c.Kerberos.Keytab) {
+		return fmt.Errorf("HDFS Kerberos keytab %q must be an absolute path", c.Kerberos.Keytab)
+	}
+	return nil
+}
+
+// CheckRefDirectory returns an error if the directory is not permitted to be referenced
+// by a posix bucket
+func (c *BackendConfPOSIX) CheckRefDirectory(dir string) error {
+	if !filepath.IsAbs(dir) {
+		return fmt.Errorf("POSIX reference directory %q must be an absolute path", dir)
+	}
+	if len(c.Roots) == 0 {
+		return nil
+	}
+	dir = filepath.Clean(dir)
+	for _, root := range c.Roots {
+		if cos.IsSubdir(root, dir) {
+			return nil
+		}
+	}
+	return fmt.Errorf("POSIX reference directory %q is not under any of the configured roots %v", dir, c.Roots)
+}
+
 func (c *BackendConf) EqualClouds(o *BackendConf) bool {
 	if len(o.Conf) != len(c.Conf) {
 		return false
@@ -1059,6 +1310,19 @@ func (c *VersionConf) Validate() error {
 	if !c.Enabled && c.ValidateWarmGet {
 		return errors.New("versioning.validate_warm_get requires versioning to be enabled")
 	}
+	if !c.Enabled && c.Retain {
+		return errors.New("versioning.retain requires versioning to be enabled")
+	}
+	return nil
+}
+
+func (c *VersionConf) ValidateAsProps(args *ValidationArgs) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.Retain && args.Provider != ProviderAIS {
+		return fmt.Errorf("versioning.retain is supported only for AIS buckets (provider %q)", args.Provider)
+	}
 	return nil
 }
 
@@ -1073,7 +1337,9 @@ func (c *VersionConf) String() string {
 	} else {
 		text += "no"
 	}
-
+	if c.Retain {
+		text += " | Retain prior versions"
+	}
 	return text
 }
 
@@ -1458,6 +1724,66 @@ func (c *CompressionConf) Validate() (err error) {
 	return nil
 }
 
+func (c *KMSConf) Validate() (err error) {
+	if c.Provider == KMSProviderFile && c.KeyFile == "" {
+		return fmt.Errorf("invalid kms.key_file %q (must be non-empty for provider %q)", c.KeyFile, c.Provider)
+	}
+	return nil
+}
+
+func (c *NsQuotaConf) Validate() error {
+	if c.SyncTime < 0 {
+		return fmt.Errorf("invalid ns_quota.sync_time %v", c.SyncTime)
+	}
+	for name, quota := range c.Namespaces {
+		ns := ParseNsUname(name)
+		if name == "" || ns.String() != name {
+			return fmt.Errorf("invalid ns_quota namespace %q (expecting, e.g., \"#team-a\")", name)
+		}
+		if err := ns.Validate(); err != nil {
+			return err
+		}
+		if err := quota.Validate(); err != nil {
+			return fmt.Errorf("namespace %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func (c *RateLimitConf) Validate() error {
+	for provider, limit := range c.Backends {
+		if !IsNormalizedProvider(provider) || provider == ProviderAIS {
+			return fmt.Errorf("invalid rate_limit backend %q (expecting Cloud, HDFS, HTTP, or POSIX provider)", provider)
+		}
+		if limit.MaxRPS < 0 || limit.Burst < 0 || limit.MaxRetries < 0 {
+			return fmt.Errorf("invalid rate_limit %q: %+v (values cannot be negative)", provider, limit)
+		}
+		if limit.MinBackoff < 0 || limit.MaxBackoff < 0 || (limit.MaxBackoff != 0 && limit.MaxBackoff < limit.MinBackoff) {
+			return fmt.Errorf("invalid rate_limit %q: min_backoff %v, max_backoff %v",
+				provider, limit.MinBackoff, limit.MaxBackoff)
+		}
+	}
+	return nil
+}
+
+func (c *ETLConf) Validate() error {
+	switch c.Runtime {
+	case "", ETLRuntimeK8s, ETLRuntimeLocal:
+	default:
+		return fmt.Errorf("invalid etl.runtime %q (expecting %q or %q)", c.Runtime, ETLRuntimeK8s, ETLRuntimeLocal)
+	}
+	if strings.ContainsAny(c.Launcher, " \t") {
+		return fmt.Errorf("invalid etl.launcher %q (expecting %q or container CLI, e.g. \"docker\")",
+			c.Launcher, ETLLauncherProcess)
+	}
+	if c.WorkDir != "" && !filepath.IsAbs(c.WorkDir) {
+		return fmt.Errorf("invalid etl.work_dir %q (expecting absolute path)", c.WorkDir)
+	}
+	return nil
+}
+
+func (c *ETLConf) IsLocal() bool { return c.Runtime == ETLRuntimeLocal }
+
 //
 // remaining no-op validators
 //
@@ -6,10 +6,14 @@ package cos
 
 import (
 	"archive/tar"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v3"
 )
 
 // supported archive types (file extensions)
@@ -18,16 +22,76 @@ const (
 	ExtTgz    = ".tgz"
 	ExtTarTgz = ".tar.gz"
 	ExtZip    = ".zip"
+	ExtTarLz4 = ".tar.lz4"
+	ExtTarZst = ".tar.zst"
 
 	TarBlockSize = 512 // Size of each block in a tar stream
 )
 
-var ArchExtensions = []string{ExtTar, ExtTgz, ExtTarTgz, ExtZip}
+var (
+	ArchExtensions = []string{ExtTar, ExtTgz, ExtTarTgz, ExtZip, ExtTarLz4, ExtTarZst}
+
+	// compressed TARs (all contain ExtTar)
+	tarCompExtensions = []string{ExtTarTgz, ExtTarLz4, ExtTarZst}
+)
 
 func IsGzipped(filename string) bool {
 	return strings.HasSuffix(filename, ExtTgz) || strings.HasSuffix(filename, ExtTarTgz)
 }
 
+// IsCompressedTar returns true if the archive type (one of the ArchExtensions)
+// is TAR compressed with gzip, lz4, or zstd.
+func IsCompressedTar(ext string) bool {
+	switch ext {
+	case ExtTgz, ExtTarTgz, ExtTarLz4, ExtTarZst:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewTarDecompressor returns reader of the TAR that is compressed in accordance
+// with the archive type (see IsCompressedTar).
+func NewTarDecompressor(r io.Reader, ext string) (io.ReadCloser, error) {
+	switch ext {
+	case ExtTgz, ExtTarTgz:
+		return gzip.NewReader(r)
+	case ExtTarLz4:
+		return io.NopCloser(lz4.NewReader(r)), nil
+	case ExtTarZst:
+		zr, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return nil, NewUnknownMimeError(ext)
+	}
+}
+
+// NewTarCompressor returns writer that compresses TAR in accordance with the archive type;
+// `fast` trades compression ratio for speed.
+// NOTE: closing the compressor does not close the underlying writer.
+func NewTarCompressor(w io.Writer, ext string, fast bool) (io.WriteCloser, error) {
+	switch ext {
+	case ExtTgz, ExtTarTgz:
+		if fast {
+			return gzip.NewWriterLevel(w, gzip.BestSpeed)
+		}
+		return gzip.NewWriter(w), nil
+	case ExtTarLz4:
+		return lz4.NewWriter(w), nil
+	case ExtTarZst:
+		level := zstd.SpeedDefault
+		if fast {
+			level = zstd.SpeedFastest
+		}
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(level), zstd.WithEncoderConcurrency(1))
+	default:
+		return nil, NewUnknownMimeError(ext)
+	}
+}
+
 type (
 	ErrUnknownMime struct {
 		detail string
@@ -41,8 +105,10 @@ func NewUnknownMimeError(d string) *ErrUnknownMime { return &ErrUnknownMime{d} }
 func Mime(mime, filename string) (ext string, err error) {
 	// user-specified (intended) format takes precedence
 	if mime != "" {
-		if strings.Contains(mime, ExtTarTgz[1:]) { // ExtTarTgz contains ExtTar
-			return ExtTarTgz, nil
+		for _, ext := range tarCompExtensions { // (first, as they contain ExtTar)
+			if strings.Contains(mime, ext[1:]) {
+				return ext, nil
+			}
 		}
 		for _, ext := range ArchExtensions {
 			if strings.Contains(mime, ext[1:]) {
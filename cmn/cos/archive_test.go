@@ -0,0 +1,51 @@
+// Package cos provides common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package cos
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestTarCompression(t *testing.T) {
+	const name, content = "dir/file.txt", "The quick brown fox jumps over the lazy dog"
+	for _, ext := range []string{ExtTgz, ExtTarTgz, ExtTarLz4, ExtTarZst} {
+		tassert.Fatalf(t, IsCompressedTar(ext), "%s: expected compressed TAR", ext)
+		for _, fast := range []bool{false, true} {
+			buf := &bytes.Buffer{}
+			cw, err := NewTarCompressor(buf, ext, fast)
+			tassert.CheckFatal(t, err)
+			tw := tar.NewWriter(cw)
+			hdr := &tar.Header{Typeflag: tar.TypeReg, Name: name, Size: int64(len(content)), Mode: int64(PermRWR)}
+			tassert.CheckFatal(t, tw.WriteHeader(hdr))
+			_, err = tw.Write([]byte(content))
+			tassert.CheckFatal(t, err)
+			tassert.CheckFatal(t, tw.Close())
+			tassert.CheckFatal(t, cw.Close())
+
+			dr, err := NewTarDecompressor(bytes.NewReader(buf.Bytes()), ext)
+			tassert.CheckFatal(t, err)
+			tr := tar.NewReader(dr)
+			hdr, err = tr.Next()
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, hdr.Name == name, "%s: expected %q, got %q", ext, name, hdr.Name)
+			b, err := io.ReadAll(tr)
+			tassert.CheckFatal(t, err)
+			tassert.Errorf(t, string(b) == content, "%s: expected %q, got %q", ext, content, b)
+			_, err = tr.Next()
+			tassert.Errorf(t, err == io.EOF, "%s: expected EOF, got %v", ext, err)
+			tassert.CheckError(t, dr.Close())
+		}
+	}
+	for _, ext := range []string{ExtTar, ExtZip} {
+		tassert.Errorf(t, !IsCompressedTar(ext), "%s: expected not compressed TAR", ext)
+		_, err := NewTarCompressor(io.Discard, ext, false)
+		tassert.Errorf(t, err != nil, "%s: expected error", ext)
+	}
+}
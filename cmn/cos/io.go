@@ -13,6 +13,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cmn/debug"
@@ -436,6 +437,14 @@ func Rename(src, dst string) error {
 	return nil
 }
 
+// IsSubdir returns true if (cleaned-up) `path` is `dir` itself or is located under `dir`.
+func IsSubdir(dir, path string) bool {
+	if path == dir || dir == string(filepath.Separator) {
+		return true
+	}
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
 // RemoveFile removes path; returns nil upon success or if the path does not exist.
 func RemoveFile(path string) (err error) {
 	err = os.Remove(path)
@@ -93,6 +93,17 @@ type (
 		name   string // object's name
 		d1, d2 uint64 // lom.md.(bucket-ID) and lom.bck.(bucket-ID), respectively
 	}
+	ErrObjRetained struct {
+		name  string // object's name
+		until time.Time
+		mode  string
+		hold  bool // legal hold
+	}
+	ErrQuotaExceeded struct {
+		what  string // bucket or namespace
+		limit string // e.g. "hard_bytes"
+		value int64  // the limit
+	}
 	ErrAborted struct {
 		what string
 		ctx  string
@@ -392,6 +403,39 @@ func isErrObjDefunct(err error) bool {
 	return ok
 }
 
+// ErrObjRetained
+
+func NewErrObjRetained(name, mode string, until time.Time, hold bool) *ErrObjRetained {
+	return &ErrObjRetained{name: name, mode: mode, until: until, hold: hold}
+}
+
+func (e *ErrObjRetained) Error() string {
+	if e.hold {
+		return fmt.Sprintf("%s is under legal hold", e.name)
+	}
+	return fmt.Sprintf("%s is retained (%s mode) until %s", e.name, e.mode, e.until.UTC().Format(time.RFC3339))
+}
+
+func IsErrObjRetained(err error) bool {
+	var e *ErrObjRetained
+	return errors.As(err, &e)
+}
+
+// ErrQuotaExceeded
+
+func NewErrQuotaExceeded(what, limit string, value int64) *ErrQuotaExceeded {
+	return &ErrQuotaExceeded{what: what, limit: limit, value: value}
+}
+
+func (e *ErrQuotaExceeded) Error() string {
+	return fmt.Sprintf("%s: quota exceeded (%s = %d)", e.what, e.limit, e.value)
+}
+
+func IsErrQuotaExceeded(err error) bool {
+	var e *ErrQuotaExceeded
+	return errors.As(err, &e)
+}
+
 // ErrAborted
 
 func NewErrAborted(what, ctx string, err error) *ErrAborted {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	jsoniter "github.com/json-iterator/go"
 )
 
 const (
@@ -364,6 +365,11 @@ func (f *field) SetValue(src interface{}, force ...bool) error {
 				return err
 			}
 			dst.SetFloat(n)
+		case reflect.Slice, reflect.Map:
+			// e.g., lifecycle rules: JSON-encoded value
+			if err := jsoniter.UnmarshalFromString(s, dst.Addr().Interface()); err != nil {
+				return fmt.Errorf("invalid value for property %q: %v", f.name, err)
+			}
 		case reflect.Ptr:
 			dst.Set(reflect.New(dst.Type().Elem())) // set pointer to default value
 			dst = dst.Elem()                        // dereference pointer
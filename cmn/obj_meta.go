@@ -31,6 +31,29 @@ const (
 	ETag         = "ETag"
 
 	OrigURLObjMD = "orig_url"
+
+	// (versioning.retain) zero-size object that marks deletion - see cluster/lversion.go
+	DelMarkerObjMD = "delete-marker"
+
+	// (encryption) object is stored encrypted; the value is packed sse.ObjMD - see cluster/lsse.go
+	SSEObjMD = "sse"
+
+	// (retention) WORM object: retention mode, retain-until time (RFC 3339), and legal hold ("true")
+	// - see cluster/lretention.go
+	RetentionModeObjMD = "retention-mode"
+	RetainUntilObjMD   = "retain-until"
+	LegalHoldObjMD     = "legal-hold"
+
+	// (write-back) object is yet to be written to its remote backend - see xs/writeback.go
+	WriteBackObjMD = "write-back"
+
+	// (replication) object is a replica; the value is the source bucket - see xs/replicate.go
+	ReplicaObjMD = "replica-of"
+
+	// (lifecycle) time the object was written and time the retained version became noncurrent
+	// (nanoseconds since UNIX epoch) - see cluster/llifecycle.go
+	LastModifiedObjMD = "last-modified"
+	NoncurrentObjMD   = "noncurrent"
 )
 
 // provider-specific header keys
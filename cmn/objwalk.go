@@ -12,29 +12,75 @@ import (
 	"github.com/NVIDIA/aistore/cmn/cos"
 )
 
+// NOTE: noncurrent versions (LsVersions) of a given object precede the object
+// itself (or its delete marker) and are sorted by version in ascending order.
 func SortBckEntries(bckEntries []*BucketEntry) {
 	entryLess := func(i, j int) bool {
-		if bckEntries[i].Name == bckEntries[j].Name {
-			return bckEntries[i].Flags&EntryStatusMask < bckEntries[j].Flags&EntryStatusMask
+		ei, ej := bckEntries[i], bckEntries[j]
+		if ei.Name != ej.Name {
+			return ei.Name < ej.Name
 		}
-		return bckEntries[i].Name < bckEntries[j].Name
+		if ei.IsNoncurrent() != ej.IsNoncurrent() {
+			return ei.IsNoncurrent()
+		}
+		if ei.IsNoncurrent() && ei.Version != ej.Version {
+			return versionLess(ei.Version, ej.Version)
+		}
+		return ei.Flags&EntryStatusMask < ej.Flags&EntryStatusMask
 	}
 	sort.Slice(bckEntries, entryLess)
 }
 
+// numeric (AIS) versions
+func versionLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+func sameEntry(a, b *BucketEntry) bool {
+	if a.Name != b.Name || a.IsNoncurrent() != b.IsNoncurrent() {
+		return false
+	}
+	return !a.IsNoncurrent() || a.Version == b.Version
+}
+
+// PageLen returns the number of leading entries that make up (at most) `size`
+// objects, and whether there are enough entries to fill the page. Noncurrent
+// versions are not counted - they are always returned together with the object.
+func PageLen(entries []*BucketEntry, size uint) (n int, full bool) {
+	var cnt uint
+	for i, e := range entries {
+		if e.IsNoncurrent() {
+			continue
+		}
+		cnt++
+		if cnt == size {
+			return i + 1, true
+		}
+	}
+	return len(entries), false
+}
+
 func deduplicateBckEntries(bckEntries []*BucketEntry, maxSize uint) ([]*BucketEntry, string) {
 	objCount := uint(len(bckEntries))
+	_, full := PageLen(bckEntries, maxSize)
 
 	j := 0
+	cnt := uint(0)
 	token := ""
 	for _, obj := range bckEntries {
-		if j > 0 && bckEntries[j-1].Name == obj.Name {
+		if j > 0 && sameEntry(bckEntries[j-1], obj) {
 			continue
 		}
 		bckEntries[j] = obj
 		j++
-
-		if maxSize > 0 && j == int(maxSize) {
+		if obj.IsNoncurrent() {
+			continue
+		}
+		cnt++
+		if maxSize > 0 && cnt == maxSize {
 			break
 		}
 	}
@@ -44,7 +90,7 @@ func deduplicateBckEntries(bckEntries []*BucketEntry, maxSize uint) ([]*BucketEn
 	for i := j; i < int(objCount); i++ {
 		bckEntries[i] = nil
 	}
-	if maxSize > 0 && objCount >= maxSize {
+	if maxSize > 0 && full {
 		token = bckEntries[j-1].Name
 	}
 	return bckEntries[:j], token
@@ -181,4 +181,26 @@ var _ = Describe("API", func() {
 			),
 		)
 	})
+
+	Describe("PrefetchMsg", func() {
+		DescribeTable("should validate",
+			func(msg cmn.PrefetchMsg, valid bool) {
+				err := msg.Validate()
+				if valid {
+					Expect(err).NotTo(HaveOccurred())
+				} else {
+					Expect(err).To(HaveOccurred())
+				}
+			},
+			Entry("defaults", cmn.PrefetchMsg{}, true),
+			Entry("max limits", cmn.PrefetchMsg{Manifest: "train.txt", NumWorkers: cmn.MaxPrefetchWorkers,
+				Rate: cmn.MaxPrefetchRate, ReadAhead: cmn.MaxPrefetchReadAhead}, true),
+			Entry("negative rate", cmn.PrefetchMsg{Rate: -1}, false),
+			Entry("rate over 1e9", cmn.PrefetchMsg{Rate: 2_000_000_000}, false),
+			Entry("too many workers", cmn.PrefetchMsg{NumWorkers: cmn.MaxPrefetchWorkers + 1}, false),
+			Entry("read-ahead too large", cmn.PrefetchMsg{Manifest: "train.txt",
+				ReadAhead: cmn.MaxPrefetchReadAhead + 1}, false),
+			Entry("read-ahead without manifest", cmn.PrefetchMsg{ReadAhead: 10}, false),
+		)
+	})
 })
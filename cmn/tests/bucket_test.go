@@ -6,8 +6,10 @@ package tests
 
 import (
 	"testing"
+	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/devtools/tassert"
 )
 
@@ -153,3 +155,152 @@ func TestParseBckObjectURI(t *testing.T) {
 		tassert.Errorf(t, err != nil, "expected error for input: %s", test.uri)
 	}
 }
+
+func TestSortBckEntriesVersions(t *testing.T) {
+	entries := []*cmn.BucketEntry{
+		{Name: "b", Version: "3", Flags: cmn.EntryDelMarker},
+		{Name: "a", Version: "10", Flags: cmn.EntryNoncurrent},
+		{Name: "a", Version: "11"},
+		{Name: "b", Version: "2", Flags: cmn.EntryNoncurrent},
+		{Name: "a", Version: "9", Flags: cmn.EntryNoncurrent},
+	}
+	cmn.SortBckEntries(entries)
+	expected := []string{"a/9", "a/10", "a/11", "b/2", "b/3"}
+	for i, e := range entries {
+		tassert.Errorf(t, e.Name+"/"+e.Version == expected[i], "expected %q, got %q", expected[i], e.Name+"/"+e.Version)
+	}
+
+	// noncurrent versions do not count towards the page size
+	n, full := cmn.PageLen(entries, 1)
+	tassert.Errorf(t, n == 3 && full, "expected (3, true), got (%d, %t)", n, full)
+	n, full = cmn.PageLen(entries, 3)
+	tassert.Errorf(t, n == len(entries) && !full, "expected (%d, false), got (%d, %t)", len(entries), n, full)
+}
+
+func TestRetentionConfValidate(t *testing.T) {
+	var (
+		args = &cmn.ValidationArgs{Provider: cmn.ProviderAIS}
+		day  = cos.Duration(24 * time.Hour)
+	)
+	tassert.CheckError(t, (&cmn.RetentionConf{Enabled: true, Mode: cmn.RetentionGovernance, Period: day}).ValidateAsProps(args))
+	tassert.Errorf(t, (&cmn.RetentionConf{Enabled: true, Mode: "strict", Period: day}).ValidateAsProps(args) != nil,
+		"expected invalid mode error")
+	tassert.Errorf(t, (&cmn.RetentionConf{Enabled: true, Mode: cmn.RetentionCompliance, Period: -day}).ValidateAsProps(args) != nil,
+		"expected invalid period error")
+	args.Provider = cmn.ProviderAmazon
+	tassert.Errorf(t, (&cmn.RetentionConf{Enabled: true, Mode: cmn.RetentionCompliance, Period: day}).ValidateAsProps(args) != nil,
+		"expected error for a remote bucket")
+
+	var (
+		gov  = &cmn.RetentionConf{Enabled: true, Mode: cmn.RetentionGovernance, Period: day}
+		comp = &cmn.RetentionConf{Enabled: true, Mode: cmn.RetentionCompliance, Period: day}
+	)
+	tassert.CheckError(t, gov.ValidateUpdate(&cmn.RetentionConf{}))
+	tassert.CheckError(t, gov.ValidateUpdate(comp))
+	tassert.CheckError(t, comp.ValidateUpdate(&cmn.RetentionConf{Enabled: true, Mode: cmn.RetentionCompliance, Period: 2 * day}))
+	tassert.Errorf(t, comp.ValidateUpdate(&cmn.RetentionConf{}) != nil, "compliance: expected error disabling")
+	tassert.Errorf(t, comp.ValidateUpdate(gov) != nil, "compliance: expected error switching to governance")
+	tassert.Errorf(t, comp.ValidateUpdate(&cmn.RetentionConf{Enabled: true, Mode: cmn.RetentionCompliance, Period: day / 2}) != nil,
+		"compliance: expected error shortening")
+}
+
+func TestQuotaConfValidate(t *testing.T) {
+	tassert.CheckError(t, (&cmn.QuotaConf{SoftBytes: 10, HardBytes: 20, HardObjs: 5, Enabled: true}).Validate())
+	tassert.Errorf(t, (&cmn.QuotaConf{SoftBytes: 30, HardBytes: 20}).Validate() != nil, "expected soft > hard error")
+	tassert.Errorf(t, (&cmn.QuotaConf{HardObjs: -1}).Validate() != nil, "expected negative limit error")
+
+	c := &cmn.QuotaConf{SoftBytes: 10, HardBytes: 20, SoftObjs: 1, HardObjs: 2, Enabled: true}
+	tassert.Errorf(t, c.Exceeds(20, 2) == "", "expected no hard limit exceeded")
+	tassert.Errorf(t, c.Exceeds(21, 0) == "hard_bytes", "expected hard_bytes")
+	tassert.Errorf(t, c.Exceeds(0, 3) == "hard_objs", "expected hard_objs")
+	tassert.Errorf(t, c.ExceedsSoft(11, 0) == "soft_bytes", "expected soft_bytes")
+
+	ns := &cmn.NsQuotaConf{Namespaces: map[string]cmn.QuotaConf{"#team-a": *c}}
+	tassert.CheckError(t, ns.Validate())
+	ns.Namespaces = map[string]cmn.QuotaConf{"team-a": *c}
+	tassert.Errorf(t, ns.Validate() != nil, "expected invalid namespace error")
+}
+
+func TestDataWritePolicyValidate(t *testing.T) {
+	args := &cmn.ValidationArgs{Provider: cmn.ProviderAmazon}
+	for _, policy := range []cmn.DataWritePolicy{"", cmn.WriteThrough, cmn.WriteBack} {
+		tassert.CheckError(t, policy.ValidateAsProps(args))
+	}
+	tassert.Errorf(t, cmn.DataWritePolicy("write-around").ValidateAsProps(args) != nil, "expected invalid policy error")
+
+	bck := cmn.Bck{Name: "bck", Provider: cmn.ProviderAIS}
+	props := cmn.DefaultBckProps(bck, &cmn.Config{})
+	props.SetProvider(cmn.ProviderAIS)
+	props.Cksum.Type = cos.ChecksumXXHash
+	tassert.CheckFatal(t, props.Validate(1))
+	props.WritePolicy = cmn.WriteBack
+	tassert.Errorf(t, props.Validate(1) != nil, "expected error for a bucket without Cloud backend")
+	props.BackendBck = cmn.Bck{Name: "cloud", Provider: cmn.ProviderGoogle}
+	tassert.CheckError(t, props.Validate(1))
+}
+
+func TestBckReplicationConfValidate(t *testing.T) {
+	for _, dst := range []string{"ais://@remais/dst", "s3://dst", "gs://dst"} {
+		c := &cmn.BckReplicationConf{Dst: dst, Enabled: true}
+		tassert.CheckError(t, c.ValidateAsProps(nil))
+	}
+	for _, dst := range []string{"", "ais://dst", "s3://", "s3://dst/obj", "ais://@remais"} {
+		c := &cmn.BckReplicationConf{Dst: dst, Enabled: true}
+		tassert.Errorf(t, c.ValidateAsProps(nil) != nil, "expected error for %q", dst)
+	}
+	tassert.CheckError(t, (&cmn.BckReplicationConf{Dst: "ais://dst"}).ValidateAsProps(nil))
+
+	bck := cmn.Bck{Name: "bck", Provider: cmn.ProviderAIS}
+	props := cmn.DefaultBckProps(bck, &cmn.Config{})
+	props.SetProvider(cmn.ProviderAIS)
+	props.Cksum.Type = cos.ChecksumXXHash
+	props.Replication = cmn.BckReplicationConf{Dst: "gs://cloud", Enabled: true}
+	tassert.CheckError(t, props.Validate(1))
+	props.BackendBck = cmn.Bck{Name: "cloud", Provider: cmn.ProviderGoogle}
+	tassert.Errorf(t, props.Validate(1) != nil, "expected error for replicating to the backend bucket")
+}
+
+func TestBckRateLimitConfValidate(t *testing.T) {
+	tassert.CheckError(t, (&cmn.BckRateLimitConf{MaxRPS: 100, Enabled: true}).ValidateAsProps(nil))
+	tassert.CheckError(t, (&cmn.BckRateLimitConf{}).ValidateAsProps(nil))
+	for _, c := range []cmn.BckRateLimitConf{{Enabled: true}, {MaxRPS: -1}, {MaxRPS: 10, Burst: -1, Enabled: true}} {
+		tassert.Errorf(t, c.ValidateAsProps(nil) != nil, "expected error for %+v", c)
+	}
+
+	bck := cmn.Bck{Name: "bck", Provider: cmn.ProviderAIS}
+	props := cmn.DefaultBckProps(bck, &cmn.Config{})
+	props.SetProvider(cmn.ProviderAIS)
+	props.Cksum.Type = cos.ChecksumXXHash
+	props.RateLimit = cmn.BckRateLimitConf{MaxRPS: 100, Enabled: true}
+	tassert.Errorf(t, props.Validate(1) != nil, "expected error for ais bucket without backend")
+	props.BackendBck = cmn.Bck{Name: "cloud", Provider: cmn.ProviderGoogle}
+	tassert.CheckError(t, props.Validate(1))
+}
+
+func TestExtraPropsAWS(t *testing.T) {
+	extra := cmn.ExtraProps{AWS: cmn.ExtraPropsAWS{Endpoint: "minio", Profile: "prof"}}
+	tassert.CheckError(t, extra.ValidateAsProps(&cmn.ValidationArgs{Provider: cmn.ProviderAmazon}))
+	for _, provider := range []string{cmn.ProviderAIS, cmn.ProviderGoogle, cmn.ProviderAzure} {
+		err := extra.ValidateAsProps(&cmn.ValidationArgs{Provider: provider})
+		tassert.Errorf(t, err != nil, "expected error for %q provider", provider)
+	}
+}
+
+func TestExtraPropsHTTP(t *testing.T) {
+	extra := cmn.ExtraProps{HTTP: cmn.ExtraPropsHTTP{OrigURLBck: "https://example.com/data/", Manifest: "manifest.txt"}}
+	tassert.CheckError(t, extra.ValidateAsProps(&cmn.ValidationArgs{Provider: cmn.ProviderHTTP}))
+	extra.HTTP.OrigURLBck = ""
+	tassert.Errorf(t, extra.ValidateAsProps(&cmn.ValidationArgs{Provider: cmn.ProviderHTTP}) != nil, "expected error for empty URL")
+	err := extra.ValidateAsProps(&cmn.ValidationArgs{Provider: cmn.ProviderAIS})
+	tassert.Errorf(t, err != nil, "expected error for manifest of %q bucket", cmn.ProviderAIS)
+}
+
+func TestExtraPropsPOSIX(t *testing.T) {
+	args := &cmn.ValidationArgs{Provider: cmn.ProviderPOSIX}
+	extra := cmn.ExtraProps{POSIX: cmn.ExtraPropsPOSIX{RefDirectory: "/mnt/nfs/dataset"}}
+	tassert.CheckError(t, extra.ValidateAsProps(args))
+	for _, dir := range []string{"", "nfs/dataset"} {
+		extra.POSIX.RefDirectory = dir
+		tassert.Errorf(t, extra.ValidateAsProps(args) != nil, "expected error for %q", dir)
+	}
+}
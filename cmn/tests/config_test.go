@@ -5,13 +5,18 @@
 package tests
 
 import (
+	"net"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/jsp"
 	"github.com/NVIDIA/aistore/devtools/tassert"
+	jsoniter "github.com/json-iterator/go"
 )
 
 func TestConfigTestEnv(t *testing.T) {
@@ -71,3 +76,141 @@ func TestValidateMpath(t *testing.T) {
 		}
 	}
 }
+
+func TestS3Endpoint(t *testing.T) {
+	var conf cmn.BackendConf
+	err := jsoniter.Unmarshal([]byte(`{
+		"aws": {"endpoints": {
+			"minio": {"url": "http://minio.local:9000", "access_key": "ak", "secret_key": "sk", "path_style": true},
+			"ceph":  {"url": "https://rgw.local", "region": "default", "skip_verify": true}
+		}}
+	}`), &conf)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, conf.Validate())
+	_, ok := conf.Providers[cmn.ProviderAmazon]
+	tassert.Errorf(t, ok, "expected %q provider", cmn.ProviderAmazon)
+
+	ep, err := conf.S3Endpoint("minio")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, ep.URL == "http://minio.local:9000" && ep.AccessKey == "ak" && ep.PathStyle, "unexpected %+v", ep)
+	ep, err = conf.S3Endpoint("ceph")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, ep.Region == "default" && ep.SkipVerify && !ep.PathStyle, "unexpected %+v", ep)
+
+	// URL implies path-style addressing
+	ep, err = conf.S3Endpoint("http://10.0.0.1:9000")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, ep.PathStyle, "expected path-style addressing")
+
+	// secret keys are not shown
+	redacted := conf.Redacted()
+	out := string(cos.MustMarshal(&redacted))
+	tassert.Errorf(t, !strings.Contains(out, `"sk"`) && strings.Contains(out, cmn.RedactedSecret), "not redacted: %s", out)
+	ep, err = conf.S3Endpoint("minio")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, ep.SecretKey == "sk", "expected the config to remain intact, got %q", ep.SecretKey)
+
+	for _, endpoint := range []string{"unknown", "http://", "https:/x"} {
+		_, err = conf.S3Endpoint(endpoint)
+		tassert.Errorf(t, err != nil, "expected %q to fail", endpoint)
+	}
+
+	invalid := []string{
+		`{"aws": {"endpoints": {"minio": {"url": "minio.local:9000"}}}}`,
+		`{"aws": {"endpoints": {"minio": {"url": "http://minio.local", "access_key": "ak"}}}}`,
+	}
+	for _, s := range invalid {
+		conf = cmn.BackendConf{}
+		tassert.CheckFatal(t, jsoniter.Unmarshal([]byte(s), &conf))
+		tassert.Errorf(t, conf.Validate() != nil, "expected %s to fail validation", s)
+	}
+}
+
+func TestPOSIXRefDirectory(t *testing.T) {
+	var conf cmn.BackendConf
+	tassert.CheckFatal(t, jsoniter.Unmarshal([]byte(`{"posix": {"roots": ["/mnt/nfs/", "/data"]}}`), &conf))
+	tassert.CheckFatal(t, conf.Validate())
+	_, ok := conf.Providers[cmn.ProviderPOSIX]
+	tassert.Errorf(t, ok, "expected %q provider", cmn.ProviderPOSIX)
+
+	posixConf := conf.Conf[cmn.ProviderPOSIX].(cmn.BackendConfPOSIX)
+	for _, dir := range []string{"/mnt/nfs", "/mnt/nfs/imagenet", "/data/a/b/"} {
+		tassert.CheckError(t, posixConf.CheckRefDirectory(dir))
+	}
+	for _, dir := range []string{"mnt/nfs", "/mnt/nfs2", "/mnt", "/mnt/nfs/../etc", "/"} {
+		tassert.Errorf(t, posixConf.CheckRefDirectory(dir) != nil, "expected %q to fail", dir)
+	}
+	// no roots: any (absolute) directory
+	posixConf.Roots = nil
+	tassert.CheckError(t, posixConf.CheckRefDirectory("/etc"))
+
+	conf = cmn.BackendConf{}
+	tassert.CheckFatal(t, jsoniter.Unmarshal([]byte(`{"posix": {"roots": ["mnt/nfs"]}}`), &conf))
+	tassert.Errorf(t, conf.Validate() != nil, "expected relative root to fail validation")
+}
+
+func TestHDFSKerberos(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0") // NameNode must be reachable
+	tassert.CheckFatal(t, err)
+	defer ln.Close()
+	addr := ln.Addr().String()
+
+	var conf cmn.BackendConf
+	err = jsoniter.Unmarshal([]byte(`{"hdfs": {"addresses": ["`+addr+`"], "data_transfer_protection": "privacy",
+		"kerberos": {"principal": "ais@EXAMPLE.COM", "keytab": "/etc/ais.keytab", "service_principal": "nn/_HOST"}}}`), &conf)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, conf.Validate())
+	hdfsConf := conf.Conf[cmn.ProviderHDFS].(cmn.BackendConfHDFS)
+	tassert.Errorf(t, hdfsConf.Kerberos != nil && hdfsConf.Kerberos.ServicePrincipal == "nn/_HOST", "unexpected %+v", hdfsConf)
+
+	invalid := []string{
+		`{"kerberos": {"principal": "ais@EXAMPLE.COM", "service_principal": "nn/_HOST"}}`,
+		`{"kerberos": {"principal": "ais", "keytab": "/etc/ais.keytab"}}`,
+		`{"kerberos": {"principal": "ais", "keytab": "ais.keytab", "service_principal": "nn/_HOST"}}`,
+		`{"data_transfer_protection": "none"}`,
+	}
+	for _, s := range invalid {
+		var hdfsConf cmn.BackendConfHDFS
+		tassert.CheckFatal(t, jsoniter.Unmarshal([]byte(s), &hdfsConf))
+		tassert.Errorf(t, hdfsConf.Validate() != nil, "expected %s to fail validation", s)
+	}
+}
+
+func TestRateLimitConf(t *testing.T) {
+	conf := cmn.RateLimitConf{Backends: map[string]cmn.BackendRateLimit{
+		cmn.ProviderGoogle: {MaxRPS: 1000, Burst: 100, MaxRetries: 5},
+		cmn.ProviderAmazon: {MaxRetries: 3, MinBackoff: cos.Duration(time.Second), MaxBackoff: cos.Duration(time.Minute)},
+	}}
+	tassert.CheckError(t, conf.Validate())
+
+	invalid := []map[string]cmn.BackendRateLimit{
+		{"s3": {MaxRPS: 10}}, // not normalized
+		{cmn.ProviderAIS: {MaxRPS: 10}},
+		{cmn.ProviderGoogle: {MaxRPS: -1}},
+		{cmn.ProviderGoogle: {MinBackoff: cos.Duration(time.Minute), MaxBackoff: cos.Duration(time.Second)}},
+	}
+	for _, backends := range invalid {
+		conf := cmn.RateLimitConf{Backends: backends}
+		tassert.Errorf(t, conf.Validate() != nil, "expected %+v to fail validation", backends)
+	}
+}
+
+func TestETLConf(t *testing.T) {
+	valid := []cmn.ETLConf{
+		{},
+		{Runtime: cmn.ETLRuntimeK8s},
+		{Runtime: cmn.ETLRuntimeLocal},
+		{Runtime: cmn.ETLRuntimeLocal, Launcher: "podman", WorkDir: "/var/lib/ais/etl"},
+	}
+	for _, conf := range valid {
+		tassert.Errorf(t, conf.Validate() == nil, "expected %+v to pass validation", conf)
+	}
+	invalid := []cmn.ETLConf{
+		{Runtime: "docker"},
+		{Runtime: cmn.ETLRuntimeLocal, Launcher: "docker run"},
+		{Runtime: cmn.ETLRuntimeLocal, WorkDir: "etl"},
+	}
+	for _, conf := range invalid {
+		tassert.Errorf(t, conf.Validate() != nil, "expected %+v to fail validation", conf)
+	}
+}
@@ -0,0 +1,68 @@
+// Package test provides tests for common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestETLPipelineValidate(t *testing.T) {
+	tests := []struct {
+		msg cmn.TCBMsg
+		ok  bool
+	}{
+		{cmn.TCBMsg{ID: "md5"}, true},
+		{cmn.TCBMsg{ID: "resize", Args: "size=224x224 format=png"}, true},
+		{cmn.TCBMsg{ID: "resize", Args: "224\tx224"}, false},
+		{cmn.TCBMsg{Pipeline: cmn.ETLPipeline{{ID: "resize"}}, Args: "224x224"}, false},
+		{cmn.TCBMsg{Pipeline: cmn.ETLPipeline{{ID: "gunzip"}, {ID: "resize", Args: "224x224"}}}, true},
+		{cmn.TCBMsg{}, false},
+		{cmn.TCBMsg{ID: "md5", Pipeline: cmn.ETLPipeline{{ID: "gunzip"}}}, false},
+		{cmn.TCBMsg{Pipeline: cmn.ETLPipeline{{ID: "gunzip"}, {Args: "224x224"}}}, false},
+		{cmn.TCBMsg{Pipeline: cmn.ETLPipeline{{ID: "resize", Args: "224\nx224"}}}, false},
+		{cmn.TCBMsg{Pipeline: cmn.ETLPipeline{{ID: "resize", Args: strings.Repeat("a", cmn.MaxETLArgsLen+1)}}}, false},
+	}
+	for _, test := range tests {
+		err := test.msg.Validate()
+		tassert.Errorf(t, (err == nil) == test.ok, "%+v: expected ok=%t, got %v", test.msg, test.ok, err)
+	}
+
+	stages := (&cmn.TCBMsg{ID: "md5"}).Stages()
+	tassert.Errorf(t, len(stages) == 1 && stages[0].ID == "md5", "unexpected stages %s", stages)
+	stages = cmn.ETLPipeline{{ID: "gunzip"}, {ID: "md5"}}
+	tassert.Errorf(t, stages.String() == "[gunzip => md5]", "unexpected %q", stages.String())
+}
+
+func TestTCBMsgToName(t *testing.T) {
+	longArgs := strings.Repeat("a", 100)
+	tests := []struct {
+		msg      cmn.TCBMsg
+		name     string
+		expected string
+	}{
+		{cmn.TCBMsg{ID: "md5"}, "a/b.jpg", "a/b.jpg"},
+		{cmn.TCBMsg{ID: "resize", Args: "224x224"}, "a/b.jpg", "a/b_224x224.jpg"},
+		{cmn.TCBMsg{ID: "resize", Args: "224x224"}, "a.dir/b", "a.dir/b_224x224"},
+		{cmn.TCBMsg{ID: "resize", Args: "224x224"}, ".hidden", ".hidden_224x224"},
+		{cmn.TCBMsg{ID: "resize", Args: longArgs}, "b.jpg", "b_" + cmn.ETLArgsTag(longArgs) + ".jpg"},
+		{
+			cmn.TCBMsg{Pipeline: cmn.ETLPipeline{{ID: "gunzip"}, {ID: "resize", Args: "224x224"}, {ID: "convert", Args: "png"}}},
+			"b.jpg", "b_224x224_png.jpg",
+		},
+		{cmn.TCBMsg{ID: "resize", Args: "224x224", CopyBckMsg: cmn.CopyBckMsg{Prefix: "p/"}}, "b.jpg", "p/b_224x224.jpg"},
+	}
+	for _, test := range tests {
+		name := test.msg.ToName(test.name)
+		tassert.Errorf(t, name == test.expected, "%+v: expected %q, got %q", test.msg, test.expected, name)
+	}
+
+	tag := cmn.ETLArgsTag("size=224x224 format=png")
+	tassert.Errorf(t, tag != "" && !strings.ContainsAny(tag, "= "), "unexpected tag %q", tag)
+	tassert.Errorf(t, tag != cmn.ETLArgsTag("size=224x224 format=jpg"), "expecting different tags")
+}
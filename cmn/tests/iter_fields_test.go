@@ -52,6 +52,9 @@ var _ = Describe("IterFields", func() {
 						ParitySlices: 1024,
 					},
 					LRU: cmn.LRUConf{},
+					Lifecycle: cmn.LifecycleConf{
+						Rules: []cmn.LifecycleRule{{Prefix: "tmp/", ExpireDays: 7}},
+					},
 					Cksum: cmn.CksumConf{
 						Type: cos.ChecksumXXHash,
 					},
@@ -71,6 +74,16 @@ var _ = Describe("IterFields", func() {
 					"mirror.burst_buffer": 0,
 					"mirror.optimize_put": false,
 
+					"replication.dst":     "",
+					"replication.enabled": false,
+
+					"rate_limit.max_rps": 0,
+					"rate_limit.burst":   0,
+					"rate_limit.enabled": false,
+
+					"cold_get.streaming":     false,
+					"cold_get.range_caching": false,
+
 					"ec.enabled":       true,
 					"ec.parity_slices": 1024,
 					"ec.data_slices":   0,
@@ -81,6 +94,7 @@ var _ = Describe("IterFields", func() {
 
 					"versioning.enabled":           false,
 					"versioning.validate_warm_get": false,
+					"versioning.retain":            false,
 
 					"checksum.type":              cos.ChecksumXXHash,
 					"checksum.validate_warm_get": false,
@@ -95,11 +109,30 @@ var _ = Describe("IterFields", func() {
 					"lru.dont_evict_time":   cos.Duration(0),
 					"lru.capacity_upd_time": cos.Duration(0),
 
+					"lifecycle.enabled": false,
+					"lifecycle.rules":   []cmn.LifecycleRule{{Prefix: "tmp/", ExpireDays: 7}},
+
+					"encryption.enabled": false,
+					"encryption.key_id":  "",
+
+					"retention.mode":    "",
+					"retention.period":  cos.Duration(0),
+					"retention.enabled": false,
+
+					"quota.soft_bytes": int64(0),
+					"quota.hard_bytes": int64(0),
+					"quota.soft_objs":  int64(0),
+					"quota.hard_objs":  int64(0),
+					"quota.enabled":    false,
+
 					"extra.aws.cloud_region": "us-central",
+					"extra.aws.endpoint":     "",
+					"extra.aws.profile":      "",
 
-					"access":   cmn.AccessAttrs(0),
-					"md_write": cmn.MDWritePolicy(""),
-					"created":  int64(0),
+					"access":       cmn.AccessAttrs(0),
+					"md_write":     cmn.MDWritePolicy(""),
+					"write_policy": cmn.DataWritePolicy(""),
+					"created":      int64(0),
 				},
 			),
 			Entry("list BucketPropsToUpdate fields",
@@ -112,8 +145,9 @@ var _ = Describe("IterFields", func() {
 					Cksum: &cmn.CksumConfToUpdate{
 						Type: api.String(cos.ChecksumXXHash),
 					},
-					Access:  api.AccessAttrs(1024),
-					MDWrite: api.MDWritePolicy("never"),
+					Access:      api.AccessAttrs(1024),
+					MDWrite:     api.MDWritePolicy("never"),
+					WritePolicy: api.DataWritePolicy("write-back"),
 				},
 				map[string]interface{}{
 					"backend_bck.name":     (*string)(nil),
@@ -125,6 +159,16 @@ var _ = Describe("IterFields", func() {
 					"mirror.burst_buffer": (*int)(nil),
 					"mirror.optimize_put": (*bool)(nil),
 
+					"replication.dst":     (*string)(nil),
+					"replication.enabled": (*bool)(nil),
+
+					"rate_limit.max_rps": (*int)(nil),
+					"rate_limit.burst":   (*int)(nil),
+					"rate_limit.enabled": (*bool)(nil),
+
+					"cold_get.streaming":     (*bool)(nil),
+					"cold_get.range_caching": (*bool)(nil),
+
 					"ec.enabled":       api.Bool(true),
 					"ec.parity_slices": api.Int(1024),
 					"ec.data_slices":   (*int)(nil),
@@ -135,6 +179,7 @@ var _ = Describe("IterFields", func() {
 
 					"versioning.enabled":           (*bool)(nil),
 					"versioning.validate_warm_get": (*bool)(nil),
+					"versioning.retain":            (*bool)(nil),
 
 					"checksum.type":              api.String(cos.ChecksumXXHash),
 					"checksum.validate_warm_get": (*bool)(nil),
@@ -149,10 +194,31 @@ var _ = Describe("IterFields", func() {
 					"lru.capacity_upd_time": (*cos.Duration)(nil),
 					"lru.out_of_space":      (*int64)(nil),
 
-					"access":   api.AccessAttrs(1024),
-					"md_write": api.MDWritePolicy("never"),
+					"lifecycle.enabled": (*bool)(nil),
+					"lifecycle.rules":   (*[]cmn.LifecycleRule)(nil),
+
+					"encryption.enabled": (*bool)(nil),
+					"encryption.key_id":  (*string)(nil),
+
+					"retention.mode":    (*string)(nil),
+					"retention.period":  (*cos.Duration)(nil),
+					"retention.enabled": (*bool)(nil),
 
-					"extra.hdfs.ref_directory": (*string)(nil),
+					"quota.soft_bytes": (*int64)(nil),
+					"quota.hard_bytes": (*int64)(nil),
+					"quota.soft_objs":  (*int64)(nil),
+					"quota.hard_objs":  (*int64)(nil),
+					"quota.enabled":    (*bool)(nil),
+
+					"access":       api.AccessAttrs(1024),
+					"md_write":     api.MDWritePolicy("never"),
+					"write_policy": api.DataWritePolicy("write-back"),
+
+					"extra.aws.endpoint":        (*string)(nil),
+					"extra.aws.profile":         (*string)(nil),
+					"extra.http.manifest":       (*string)(nil),
+					"extra.hdfs.ref_directory":  (*string)(nil),
+					"extra.posix.ref_directory": (*string)(nil),
 				},
 			),
 			Entry("check for omit tag",
@@ -231,6 +297,8 @@ var _ = Describe("IterFields", func() {
 
 					"access":   "12", // type == uint64
 					"md_write": "never",
+
+					"lifecycle.rules": `[{"prefix": "tmp/", "expire_days": 7}]`, // JSON
 				},
 				&cmn.BucketProps{
 					Mirror: cmn.MirrorConf{
@@ -243,6 +311,9 @@ var _ = Describe("IterFields", func() {
 						ParitySlices: 1024,
 					},
 					LRU: cmn.LRUConf{},
+					Lifecycle: cmn.LifecycleConf{
+						Rules: []cmn.LifecycleRule{{Prefix: "tmp/", ExpireDays: 7}},
+					},
 					Cksum: cmn.CksumConf{
 						Type: cos.ChecksumXXHash,
 					},
@@ -91,9 +91,22 @@ cat > $AIS_CONF_FILE <<EOL
 		"block_size": ${BLOCK_SIZE:-262144},
 		"checksum":   ${CHECKSUM:-false}
 	},
+	"kms": {
+		"provider": "${AIS_KMS_PROVIDER:-}",
+		"key_file": "${AIS_KMS_KEY_FILE:-}"
+	},
+	"ns_quota": {
+		"sync_time": "1m"
+	},
+	"etl": {
+		"runtime":  "${AIS_ETL_RUNTIME:-k8s}",
+		"launcher": "${AIS_ETL_LAUNCHER:-process}",
+		"work_dir": "${AIS_ETL_WORK_DIR:-}"
+	},
 	"versioning": {
 		"enabled":           true,
-		"validate_warm_get": false
+		"validate_warm_get": false,
+		"retain":            false
 	},
 	"net": {
 		"l4": {
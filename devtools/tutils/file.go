@@ -149,6 +149,8 @@ func PrepareObjects(t *testing.T, desc ObjectsDesc) *ObjectsOut {
 	_ = fs.CSM.Reg(fs.ObjectType, &fs.ObjectContentResolver{})
 	_ = fs.CSM.Reg(fs.ECSliceType, &fs.ECSliceContentResolver{})
 	_ = fs.CSM.Reg(fs.ECMetaType, &fs.ECMetaContentResolver{})
+	_ = fs.CSM.Reg(fs.ObjVersionType, &fs.ObjVersionContentResolver{})
+	_ = fs.CSM.Reg(fs.ETLCacheType, &fs.ETLCacheContentResolver{})
 
 	dir := t.TempDir()
 
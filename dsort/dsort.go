@@ -14,7 +14,6 @@ import (
 	"math"
 	"net/http"
 	"net/url"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -201,23 +200,24 @@ func (m *Manager) extractShard(name string, metrics *LocalExtraction) func() err
 		}
 
 		lom.Lock(false)
-		f, err := os.Open(lom.FQN)
+		f, err := lom.OpenPlain() // (decrypting if need be)
 		if err != nil {
 			phaseInfo.adjuster.releaseSema(lom.MpathInfo())
 			lom.Unlock(false)
 			return errors.Errorf("unable to open local file, err: %v", err)
 		}
+		shardSize := lom.PlainAttrs().Size
 		var compressedSize int64
 		if m.extractCreator.UsingCompression() {
-			compressedSize = lom.SizeBytes()
+			compressedSize = shardSize
 		}
 
-		expectedUncompressedSize := uint64(float64(lom.SizeBytes()) / m.avgCompressionRatio())
+		expectedUncompressedSize := uint64(float64(shardSize) / m.avgCompressionRatio())
 		toDisk := m.dsorter.preShardExtraction(expectedUncompressedSize)
 
 		beforeExtraction := mono.NanoTime()
 
-		extractedSize, extractedCount, err := m.extractCreator.ExtractShard(lom, f, m.recManager, toDisk)
+		extractedSize, extractedCount, err := m.extractCreator.ExtractShard(lom, f.(cos.ReadReaderAt), m.recManager, toDisk)
 		cos.Close(f)
 
 		dur := mono.Since(beforeExtraction)
@@ -231,7 +231,7 @@ func (m *Manager) extractShard(name string, metrics *LocalExtraction) func() err
 
 		m.dsorter.postShardExtraction(expectedUncompressedSize) // schedule unreserving reserved memory on next memory update
 		if err != nil {
-			return errors.Errorf("error in ExtractShard, file: %s, err: %v", f.Name(), err)
+			return errors.Errorf("error in ExtractShard, file: %s, err: %v", lom.FQN, err)
 		}
 
 		metrics.mu.Lock()
@@ -424,16 +424,18 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 			goto exit
 		}
 
-		file, err := cos.NewFileHandle(lom.FQN)
+		// NOTE: sending plaintext - the receiving target encrypts the shard if need be
+		file, err := lom.OpenPlain()
 		if err != nil {
 			return err
 		}
 
+		oa := lom.PlainAttrs()
 		o := transport.AllocSend()
 		o.Hdr = transport.ObjHdr{
 			Bck:      lom.Bucket(),
 			ObjName:  shardName,
-			ObjAttrs: cmn.ObjAttrs{Size: lom.SizeBytes(), Cksum: lom.Checksum()},
+			ObjAttrs: cmn.ObjAttrs{Size: oa.Size, Cksum: oa.Cksum},
 		}
 
 		// Make send synchronous.
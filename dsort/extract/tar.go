@@ -148,7 +148,7 @@ func (t *tarExtractCreator) ExtractShard(lom *cluster.LOM, r cos.ReadReaderAt, e
 		tr     = tar.NewReader(r)
 	)
 
-	buf, slab := t.t.PageMM().AllocSize(lom.SizeBytes())
+	buf, slab := t.t.PageMM().AllocSize(lom.PlainAttrs().Size)
 	defer slab.Free(buf)
 
 	offset := int64(0)
@@ -6,7 +6,6 @@ package extract
 
 import (
 	"archive/tar"
-	"compress/gzip"
 	"io"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
@@ -20,11 +19,13 @@ import (
 // interface guard
 var _ Creator = (*targzExtractCreator)(nil)
 
+// compressed TAR: gzip (.tgz, .tar.gz), lz4 (.tar.lz4), or zstd (.tar.zst)
 type targzExtractCreator struct {
-	t cluster.Target
+	t   cluster.Target
+	ext string
 }
 
-// ExtractShard reads the tarball f and extracts its metadata.
+// ExtractShard reads the compressed tarball f and extracts its metadata.
 func (t *targzExtractCreator) ExtractShard(lom *cluster.LOM, r cos.ReadReaderAt, extractor RecordExtractor,
 	toDisk bool) (extractedSize int64, extractedCount int, err error) {
 	var (
@@ -33,12 +34,12 @@ func (t *targzExtractCreator) ExtractShard(lom *cluster.LOM, r cos.ReadReaderAt,
 		workFQN = fs.CSM.Gen(lom, filetype.DSortFileType, "") // tarFQN
 	)
 
-	gzr, err := gzip.NewReader(r)
+	dr, err := cos.NewTarDecompressor(r, t.ext)
 	if err != nil {
 		return 0, 0, err
 	}
-	defer cos.Close(gzr)
-	tr := tar.NewReader(gzr)
+	defer cos.Close(dr)
+	tr := tar.NewReader(dr)
 
 	// extract to .tar
 	f, err := cos.CreateFile(workFQN)
@@ -51,7 +52,7 @@ func (t *targzExtractCreator) ExtractShard(lom *cluster.LOM, r cos.ReadReaderAt,
 		cos.Close(f)
 	}()
 
-	buf, slab := t.t.PageMM().AllocSize(lom.SizeBytes())
+	buf, slab := t.t.PageMM().AllocSize(lom.PlainAttrs().Size)
 	defer slab.Free(buf)
 
 	offset := int64(0)
@@ -113,8 +114,9 @@ func (t *targzExtractCreator) ExtractShard(lom *cluster.LOM, r cos.ReadReaderAt,
 	}
 }
 
-func NewTargzExtractCreator(t cluster.Target) Creator {
-	return &targzExtractCreator{t: t}
+func NewTargzExtractCreator(t cluster.Target, ext string) Creator {
+	debug.Assert(cos.IsCompressedTar(ext))
+	return &targzExtractCreator{t: t, ext: ext}
 }
 
 // CreateShard creates a new shard locally based on the Shard.
@@ -123,9 +125,14 @@ func (t *targzExtractCreator) CreateShard(s *Shard, tarball io.Writer, loadConte
 	var (
 		n         int64
 		needFlush bool
-		gzw, _    = gzip.NewWriterLevel(tarball, gzip.BestSpeed)
-		tw        = tar.NewWriter(gzw)
-		rdReader  = newTarRecordDataReader(t.t)
+	)
+	gzw, err := cos.NewTarCompressor(tarball, t.ext, true /*fast*/)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		tw       = tar.NewWriter(gzw)
+		rdReader = newTarRecordDataReader(t.t)
 	)
 
 	defer func() {
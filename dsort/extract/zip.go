@@ -108,11 +108,11 @@ func (z *zipExtractCreator) ExtractShard(lom *cluster.LOM, r cos.ReadReaderAt, e
 		size int64
 	)
 
-	if zr, err = zip.NewReader(r, lom.SizeBytes()); err != nil {
+	if zr, err = zip.NewReader(r, lom.PlainAttrs().Size); err != nil {
 		return extractedSize, extractedCount, err
 	}
 
-	buf, slab := z.t.PageMM().AllocSize(lom.SizeBytes())
+	buf, slab := z.t.PageMM().AllocSize(lom.PlainAttrs().Size)
 	defer slab.Free(buf)
 
 	for _, f := range zr.File {
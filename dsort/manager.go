@@ -470,8 +470,8 @@ func (m *Manager) setExtractCreator() (err error) {
 	switch m.rs.Extension {
 	case cos.ExtTar:
 		extractCreator = extract.NewTarExtractCreator(m.ctx.t)
-	case cos.ExtTarTgz, cos.ExtTgz:
-		extractCreator = extract.NewTargzExtractCreator(m.ctx.t)
+	case cos.ExtTarTgz, cos.ExtTgz, cos.ExtTarLz4, cos.ExtTarZst:
+		extractCreator = extract.NewTargzExtractCreator(m.ctx.t, m.rs.Extension)
 	case cos.ExtZip:
 		extractCreator = extract.NewZipExtractCreator(m.ctx.t)
 	default:
@@ -27,7 +27,7 @@ const (
 
 var (
 	errMissingBucket            = errors.New("missing field 'bucket'")
-	errInvalidExtension         = errors.New("extension must be one of '.tar', '.tar.gz', or '.tgz'")
+	errInvalidExtension         = fmt.Errorf("extension must be one of %v", supportedExtensions)
 	errNegOutputShardSize       = errors.New("output shard size must be >= 0")
 	errEmptyOutputShardSize     = errors.New("output shard size must be set (cannot be 0)")
 	errNegativeConcurrencyLimit = fmt.Errorf("concurrency max limit must be 0 (limits will be calculated) or > 0")
@@ -374,6 +374,17 @@ func RequestECMeta(bck cmn.Bck, objName string, si *cluster.Snode, client *http.
 	return MetaFromReader(resp.Body)
 }
 
+// replicas and restored objects carry the encryption metadata of the original
+// (and are stored encrypted, as is) - see cluster/lsse.go - and its retention
+func setObjMD(lom *cluster.LOM, md *Metadata) {
+	if md.ObjSSE != "" {
+		lom.SetCustomKey(cmn.SSEObjMD, md.ObjSSE)
+	} else {
+		lom.ObjAttrs().DelCustomKeys(cmn.SSEObjMD)
+	}
+	lom.SetRetentionMD(md.ObjRetention)
+}
+
 // Saves the main replica to local drives
 func writeObject(t cluster.Target, lom *cluster.LOM, reader io.Reader, size int64) error {
 	if size > 0 {
@@ -235,6 +235,7 @@ func (c *getJogger) restoreReplicatedFromMemory(ctx *restoreCtx) error {
 	}
 
 	ctx.lom.SetSize(writer.Size())
+	setObjMD(ctx.lom, ctx.meta)
 	args := &WriteArgs{
 		Reader:     memsys.NewReader(writer),
 		MD:         ctx.meta.NewPack(),
@@ -303,6 +304,7 @@ func (c *getJogger) restoreReplicatedFromDisk(ctx *restoreCtx) error {
 	}
 
 	ctx.lom.SetAtimeUnix(time.Now().UnixNano())
+	setObjMD(ctx.lom, ctx.meta)
 	if err := ctx.lom.Persist(); err != nil {
 		return err
 	}
@@ -590,6 +592,7 @@ func (c *getJogger) restoreMainObj(ctx *restoreCtx) ([]*slice, error) {
 		ctx.lom.SetVersion(version)
 	}
 	ctx.lom.SetSize(ctx.meta.Size)
+	setObjMD(ctx.lom, ctx.meta)
 	mainMeta := *ctx.meta
 	mainMeta.SliceID = 0
 	args := &WriteArgs{
@@ -15,23 +15,29 @@ import (
 	"github.com/OneOfOne/xxhash"
 )
 
-const MDVersionLast = 1 // current version of metadata
+const (
+	MDVersionLast        = 3 // current version of metadata
+	mdVersionNoRetention = 2 // prior to `ObjRetention`
+	mdVersionNoSSE       = 1 // prior to `ObjSSE`
+)
 
 // Metadata - EC information stored in metafiles for every encoded object
 type Metadata struct {
-	Size        int64            // obj size (after EC'ing sum size of slices differs from the original)
-	Generation  int64            // Timestamp when the object was EC'ed
-	ObjCksum    string           // checksum of the original object
-	ObjVersion  string           // object version
-	CksumType   string           // slice checksum type
-	CksumValue  string           // slice checksum of the slice if EC is used
-	FullReplica string           // daemon ID where full(main) replica is
-	Daemons     cos.MapStrUint16 // Locations of all slices: DaemonID <-> SliceID
-	Data        int              // the number of data slices
-	Parity      int              // the number of parity slices
-	SliceID     int              // 0 for full replica, 1 to N for slices
-	MDVersion   uint32           // Metadata format version
-	IsCopy      bool             // object is replicated(true) or encoded(false)
+	Size         int64            // obj size (after EC'ing sum size of slices differs from the original)
+	Generation   int64            // Timestamp when the object was EC'ed
+	ObjCksum     string           // checksum of the original object
+	ObjVersion   string           // object version
+	CksumType    string           // slice checksum type
+	CksumValue   string           // slice checksum of the slice if EC is used
+	FullReplica  string           // daemon ID where full(main) replica is
+	Daemons      cos.MapStrUint16 // Locations of all slices: DaemonID <-> SliceID
+	Data         int              // the number of data slices
+	Parity       int              // the number of parity slices
+	SliceID      int              // 0 for full replica, 1 to N for slices
+	MDVersion    uint32           // Metadata format version
+	IsCopy       bool             // object is replicated(true) or encoded(false)
+	ObjSSE       string           // encryption metadata of the object, if encrypted (cmn.SSEObjMD)
+	ObjRetention string           // retention metadata of the object, if any (lom.RetentionMD)
 }
 
 // interface guard
@@ -125,7 +131,7 @@ func (md *Metadata) Unpack(unpacker *cos.ByteUnpack) (err error) {
 		return
 	}
 	switch md.MDVersion {
-	case MDVersionLast:
+	case MDVersionLast, mdVersionNoRetention, mdVersionNoSSE:
 		err = md.unpackLastVersion(unpacker)
 	default:
 		err = fmt.Errorf("unsupported metadata format version %d. Only %d supported",
@@ -142,6 +148,8 @@ func (md *Metadata) Unpack(unpacker *cos.ByteUnpack) (err error) {
 	calcCksum := xxhash.Checksum64S(b[:len(b)-cos.SizeofI64], cos.MLCG32)
 	if cksum != calcCksum {
 		err = cos.NewBadMetaCksumError(cksum, calcCksum, "EC metadata")
+	} else {
+		md.MDVersion = MDVersionLast // (upgrade)
 	}
 	return err
 }
@@ -184,7 +192,13 @@ func (md *Metadata) unpackLastVersion(unpacker *cos.ByteUnpack) (err error) {
 	if md.CksumValue, err = unpacker.ReadString(); err != nil {
 		return
 	}
-	md.Daemons, err = unpacker.ReadMapStrUint16()
+	if md.Daemons, err = unpacker.ReadMapStrUint16(); err != nil || md.MDVersion == mdVersionNoSSE {
+		return
+	}
+	if md.ObjSSE, err = unpacker.ReadString(); err != nil || md.MDVersion == mdVersionNoRetention {
+		return
+	}
+	md.ObjRetention, err = unpacker.ReadString()
 	return
 }
 
@@ -202,6 +216,8 @@ func (md *Metadata) Pack(packer *cos.BytePack) {
 	packer.WriteString(md.CksumType)
 	packer.WriteString(md.CksumValue)
 	packer.WriteMapStrUint16(md.Daemons)
+	packer.WriteString(md.ObjSSE)
+	packer.WriteString(md.ObjRetention)
 	h := xxhash.Checksum64S(packer.Bytes(), cos.MLCG32)
 	packer.WriteUint64(h)
 }
@@ -214,5 +230,6 @@ func (md *Metadata) PackedSize() int {
 	return cos.SizeofI32 + cos.SizeofI64*2 + cos.SizeofI16*3 + 1 /*isCopy*/ +
 		cos.PackedStrLen(md.ObjCksum) + cos.PackedStrLen(md.ObjVersion) +
 		cos.PackedStrLen(md.CksumType) + cos.PackedStrLen(md.CksumValue) +
-		cos.PackedStrLen(md.FullReplica) + daemonListSz + cos.SizeofI64 /*md cksum*/
+		cos.PackedStrLen(md.FullReplica) + daemonListSz + cos.PackedStrLen(md.ObjSSE) + cos.PackedStrLen(md.ObjRetention) +
+		cos.SizeofI64 /*md cksum*/
 }
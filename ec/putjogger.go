@@ -16,6 +16,7 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/cmn/mono"
@@ -240,6 +241,8 @@ func (c *putJogger) encode(req *request, lom *cluster.LOM) error {
 		FullReplica: c.parent.t.Snode().ID(),
 		Daemons:     make(cos.MapStrUint16, reqTargets),
 	}
+	meta.ObjSSE, _ = lom.GetCustomKey(cmn.SSEObjMD)
+	meta.ObjRetention = lom.RetentionMD()
 
 	c.parent.ObjsAdd(1, lom.SizeBytes())
 
@@ -215,6 +215,7 @@ func (r *XactRespond) DispatchResp(iReq intraReq, hdr *transport.ObjHdr, object
 			var lom *cluster.LOM
 			lom, err = cluster.AllocLomFromHdr(hdr)
 			if err == nil {
+				setObjMD(lom, meta)
 				args := &WriteArgs{
 					Reader:     object,
 					MD:         md,
@@ -10,12 +10,18 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/etl/runtime"
+	"github.com/NVIDIA/aistore/etl/wasm"
 )
 
 type (
 	InitMsgBase struct {
 		IDX       string `json:"id"`
 		CommTypeX string `json:"communication_type"`
+		Cache     bool   `json:"cache,omitempty"` // cache the results of on-the-fly transformations (see cache.go)
+
+		// io:// with local runtime and runtime.WASI: max size of the output of a single transformation
+		// (0: default - see local.go)
+		OutputLimit int64 `json:"output_limit,omitempty"`
 	}
 	InitSpecMsg struct {
 		InitMsgBase
@@ -29,6 +35,10 @@ type (
 		Deps        []byte       `json:"dependencies"`
 		Runtime     string       `json:"runtime"`
 		WaitTimeout cos.Duration `json:"wait_timeout"`
+
+		// runtime.WASI only: limits of a single transformation (0: defaults - see wasm.go)
+		MemLimit int64        `json:"mem_limit,omitempty"`
+		CPULimit cos.Duration `json:"cpu_limit,omitempty"`
 	}
 
 	InfoList []Info
@@ -80,9 +90,15 @@ func (m *InitCodeMsg) Validate() error {
 	if len(m.Code) == 0 {
 		return fmt.Errorf("source code is empty")
 	}
+	if m.OutputLimit < 0 {
+		return fmt.Errorf("invalid (negative) output limit %d", m.OutputLimit)
+	}
 	if m.Runtime == "" {
 		return fmt.Errorf("runtime is not specified")
 	}
+	if m.Runtime == runtime.WASI {
+		return m.validateWASI()
+	}
 	if _, ok := runtime.Runtimes[m.Runtime]; !ok {
 		return fmt.Errorf("unsupported runtime provided: %s", m.Runtime)
 	}
@@ -95,6 +111,23 @@ func (m *InitCodeMsg) Validate() error {
 	return nil
 }
 
+func (m *InitCodeMsg) validateWASI() error {
+	if len(m.Deps) > 0 {
+		return fmt.Errorf("dependencies are not supported by %q runtime", m.Runtime)
+	}
+	if m.CommTypeX == "" {
+		m.CommTypeX = IOCommType
+	}
+	if m.CommTypeX != IOCommType {
+		return fmt.Errorf("%q runtime supports only %s communication type", m.Runtime, IOCommType)
+	}
+	if m.MemLimit < 0 || m.CPULimit < 0 {
+		return fmt.Errorf("invalid (negative) limits: memory %d, CPU time %v", m.MemLimit, m.CPULimit)
+	}
+	_, err := wasm.Compile(m.Code)
+	return err
+}
+
 func (*InitCodeMsg) InitType() string {
 	return cmn.ETLInitCode
 }
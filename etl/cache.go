@@ -0,0 +1,201 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/OneOfOne/xxhash"
+)
+
+// Caching the results of on-the-fly transformations (InitMsgBase.Cache, or `cache: "true"`
+// pod annotation):
+// - the result is stored next to the source object as fs.ETLCacheType content (see
+//   cluster/letl.go) keyed by the ETL (ID and code), its arguments, and the version,
+//   checksum, and size of the source;
+// - a modified source never matches the results cached prior to the modification; the latter
+//   are removed when the source is overwritten or deleted;
+// - the cache is evicted by LRU (before the objects) - see space/lru.go.
+// Only the objects that are not encrypted at rest (see cluster/lsse.go) are cached.
+// Only the objects that are present in the cluster are cached (a remote object gets
+// cached upon the next GET, after the first one has brought it in).
+
+type cacheWriter struct {
+	w   io.Writer
+	wfh *os.File
+	err error // failed to write cache (does not fail the request)
+}
+
+// identifies the ETL and its code: re-initializing ETL with the same ID
+// but different code (or spec) does not match the previously cached results
+func (b *etlBootstraper) cacheID() string {
+	if !b.msg.Cache {
+		return ""
+	}
+	keys := make([]string, 0, len(b.env))
+	for k := range b.env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := xxhash.NewS64(cos.MLCG32)
+	h.Write(b.msg.Spec)
+	for _, k := range keys {
+		h.WriteString("\x00" + k + "=" + b.env[k])
+	}
+	return b.msg.IDX + "-" + strconv.FormatUint(h.Sum64(), 16)
+}
+
+// returns the key of the cached result, or false when the object is not present,
+// cannot be identified (no version and no checksum), or is (to be) encrypted at rest -
+// the results are stored in plaintext and, therefore, not cached for encrypted objects
+func cacheKey(cacheID, args string, lom *cluster.LOM) (string, bool) {
+	if err := lom.Load(true /*cache it*/, false /*locked*/); err != nil {
+		return "", false
+	}
+	if lom.IsEncrypted() || lom.Bprops().Encryption.Enabled {
+		return "", false
+	}
+	if lom.Version() == "" && lom.Checksum().IsEmpty() {
+		return "", false
+	}
+	ty, val := lom.Checksum().Get()
+	h := xxhash.NewS64(cos.MLCG32)
+	for _, s := range []string{cacheID, args, lom.Version(), ty, val, strconv.FormatInt(lom.SizeBytes(), 10)} {
+		h.WriteString(s)
+		h.WriteString("\x00")
+	}
+	return fmt.Sprintf("%0*x", fs.ETLCacheKeyLen, h.Sum64()), true
+}
+
+// Transform performs on-the-fly transformation of the object (GET with cmn.URLParamUUID).
+// If the ETL caches its results, the result is served from the cache or, upon miss, gets
+// stored there while being sent to the client.
+func Transform(t cluster.Target, statsT cos.StatsTracker, w http.ResponseWriter, r *http.Request, comm Communicator,
+	bck *cluster.Bck, objName, args string) error {
+	if comm.CacheID() == "" {
+		return comm.OnlineTransform(w, r, bck, objName, args)
+	}
+	lom := cluster.AllocLOM(objName)
+	defer cluster.FreeLOM(lom)
+	if err := lom.Init(bck.Bck); err != nil {
+		return err
+	}
+	key, ok := cacheKey(comm.CacheID(), args, lom)
+	if !ok {
+		return comm.OnlineTransform(w, r, bck, objName, args)
+	}
+	fqn := lom.ETLCacheFQN(key)
+	if served, err := serveCached(t, w, fqn); served {
+		statsT.Add(stats.ETLCacheHitCount, 1)
+		return err
+	}
+	statsT.Add(stats.ETLCacheMissCount, 1)
+	return transformCache(t, w, comm, lom, fqn, args)
+}
+
+func serveCached(t cluster.Target, w http.ResponseWriter, fqn string) (served bool, err error) {
+	fh, err := os.Open(fqn)
+	if err != nil {
+		return false, nil
+	}
+	defer fh.Close()
+	finfo, err := fh.Stat()
+	if err != nil {
+		return false, nil
+	}
+	// access time (LRU) - best effort
+	now := time.Now()
+	_ = os.Chtimes(fqn, now, now)
+	size := finfo.Size()
+	w.Header().Set(cmn.HdrContentLength, strconv.FormatInt(size, 10))
+	buf, slab := t.PageMM().AllocSize(size)
+	_, err = io.CopyBuffer(w, fh, buf)
+	slab.Free(buf)
+	return true, err
+}
+
+func transformCache(t cluster.Target, w http.ResponseWriter, comm Communicator, lom *cluster.LOM, fqn,
+	args string) (err error) {
+	r, err := comm.OfflineTransform(lom.Bck(), lom.ObjName, args, 0 /*timeout*/)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	var (
+		cw      = &cacheWriter{w: w}
+		workFQN = fs.CSM.Gen(lom, fs.WorkfileType, fs.WorkfileETLCache)
+		size    = r.Size()
+	)
+	if size >= 0 {
+		w.Header().Set(cmn.HdrContentLength, strconv.FormatInt(size, 10))
+	} else {
+		size = memsys.DefaultBufSize
+	}
+	if cw.wfh, cw.err = cos.CreateFile(workFQN); cw.err != nil {
+		cw.wfh = nil
+	}
+	buf, slab := t.PageMM().AllocSize(size)
+	_, err = io.CopyBuffer(cw, r, buf)
+	slab.Free(buf)
+	if cw.wfh == nil {
+		glog.Errorf("%s: failed to cache ETL result: %v", lom, cw.err)
+		return
+	}
+	if errClose := cw.wfh.Close(); cw.err == nil {
+		cw.err = errClose
+	}
+	if err == nil && cw.err == nil {
+		cw.err = commitCached(comm, lom, args, workFQN, fqn)
+	}
+	if err != nil || cw.err != nil {
+		if errRm := cos.RemoveFile(workFQN); errRm != nil {
+			glog.Errorf("%s: nested error: %v", lom, errRm)
+		}
+	}
+	if cw.err != nil {
+		glog.Errorf("%s: failed to cache ETL result: %v", lom, cw.err)
+	}
+	return
+}
+
+// the source may have been modified during the transformation - if so, discard the result
+func commitCached(comm Communicator, lom *cluster.LOM, args, workFQN, fqn string) error {
+	src := cluster.AllocLOM(lom.ObjName)
+	defer cluster.FreeLOM(src)
+	if err := src.Init(lom.Bucket()); err != nil {
+		return err
+	}
+	if key, ok := cacheKey(comm.CacheID(), args, src); !ok || src.ETLCacheFQN(key) != fqn {
+		return fmt.Errorf("source %s has changed", lom)
+	}
+	return cos.Rename(workFQN, fqn)
+}
+
+/////////////////
+// cacheWriter //
+/////////////////
+
+func (cw *cacheWriter) Write(b []byte) (n int, err error) {
+	if n, err = cw.w.Write(b); err != nil {
+		return
+	}
+	if cw.wfh != nil && cw.err == nil {
+		_, cw.err = cw.wfh.Write(b)
+	}
+	return
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"time"
@@ -32,6 +33,7 @@ var _ = Describe("CommunicatorTest", func() {
 		transformerServer *httptest.Server
 		targetServer      *httptest.Server
 		proxyServer       *httptest.Server
+		etlArgs           string // as received by the transformer
 
 		dataSize      = int64(cos.MiB * 50)
 		transformData = make([]byte, dataSize)
@@ -78,15 +80,18 @@ var _ = Describe("CommunicatorTest", func() {
 
 		// Initialize the HTTP servers.
 		transformerServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if etlArgs = r.Header.Get(cmn.HdrETLArgs); etlArgs == "" {
+				etlArgs = r.URL.Query().Get(cmn.URLParamETLArgs) // redirect
+			}
 			_, err := w.Write(transformData)
 			Expect(err).NotTo(HaveOccurred())
 		}))
 		targetServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			err := comm.OnlineTransform(w, r, clusterBck, objName)
+			err := comm.OnlineTransform(w, r, clusterBck, objName, r.URL.Query().Get(cmn.URLParamETLArgs))
 			Expect(err).NotTo(HaveOccurred())
 		}))
 		proxyServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			http.Redirect(w, r, targetServer.URL, http.StatusMovedPermanently)
+			http.Redirect(w, r, targetServer.URL+"?"+r.URL.RawQuery, http.StatusMovedPermanently)
 		}))
 	})
 
@@ -123,7 +128,8 @@ var _ = Describe("CommunicatorTest", func() {
 					xact: xact,
 				},
 			})
-			resp, err := http.Get(proxyServer.URL)
+			args := "size=224x224 format=png"
+			resp, err := http.Get(proxyServer.URL + "?" + url.Values{cmn.URLParamETLArgs: []string{args}}.Encode())
 			Expect(err).NotTo(HaveOccurred())
 			defer resp.Body.Close()
 
@@ -131,6 +137,7 @@ var _ = Describe("CommunicatorTest", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(len(b)).To(Equal(len(transformData)))
 			Expect(b).To(Equal(transformData))
+			Expect(etlArgs).To(Equal(args))
 		})
 	}
 })
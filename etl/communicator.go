@@ -6,6 +6,7 @@ package etl
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httputil"
@@ -35,17 +36,28 @@ type (
 		Name() string
 		PodName() string
 		SvcName() string
+		CommType() string
+		// CacheID is non-empty when the results of on-the-fly transformations are cached;
+		// it identifies the ETL and its code (see cache.go)
+		CacheID() string
 
 		// OnlineTransform uses one of the two ETL container endpoints:
 		//  - Method "PUT", Path "/"
 		//  - Method "GET", Path "/bucket/object"
-		OnlineTransform(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, objName string) error
+		// The (optional) args are forwarded to the transformer - see cmn.URLParamETLArgs.
+		OnlineTransform(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, objName, args string) error
 
 		// OfflineTransform interface implementations realize offline ETL.
 		// OfflineTransform is driven by `OfflineDataProvider` - not to confuse
 		// with GET requests from users (such as training models and apps)
 		// to perform on-the-fly transformation.
-		OfflineTransform(bck *cluster.Bck, objName string, timeout time.Duration) (cos.ReadCloseSizer, error)
+		// The (optional) args are forwarded to the transformer - see cmn.HdrETLArgs.
+		OfflineTransform(bck *cluster.Bck, objName, args string, timeout time.Duration) (cos.ReadCloseSizer, error)
+
+		// TransformStream transforms the output of the previous stage of ETL pipeline
+		// (and closes it). Only the communication types where the data is pushed to
+		// the transformer (hpush://, io://) support it.
+		TransformStream(r cos.ReadCloseSizer, args string, timeout time.Duration) (cos.ReadCloseSizer, error)
 
 		Stop()
 
@@ -61,8 +73,10 @@ type (
 		cluster.Slistener
 		t cluster.Target
 
-		name    string
-		podName string
+		name     string
+		podName  string
+		commType string
+		cacheID  string
 
 		xact cluster.Xact
 	}
@@ -103,14 +117,20 @@ var (
 // baseComm //
 //////////////
 
-func makeCommunicator(args commArgs) Communicator {
-	baseComm := baseComm{
+func newBaseComm(args commArgs) baseComm {
+	return baseComm{
 		Slistener: args.listener,
 		t:         args.bootstraper.t,
 		name:      args.bootstraper.originalPodName,
 		podName:   args.bootstraper.pod.Name,
+		commType:  args.bootstraper.msg.CommTypeX,
+		cacheID:   args.bootstraper.cacheID(),
 		xact:      args.bootstraper.xact,
 	}
+}
+
+func makeCommunicator(args commArgs) Communicator {
+	baseComm := newBaseComm(args)
 
 	switch args.bootstraper.msg.CommTypeX {
 	case PushCommType:
@@ -150,9 +170,11 @@ func makeCommunicator(args commArgs) Communicator {
 	return nil
 }
 
-func (c baseComm) Name() string    { return c.name }
-func (c baseComm) PodName() string { return c.podName }
-func (c baseComm) SvcName() string { return c.podName /*pod name is same as service name*/ }
+func (c baseComm) Name() string     { return c.name }
+func (c baseComm) PodName() string  { return c.podName }
+func (c baseComm) SvcName() string  { return c.podName /*pod name is same as service name*/ }
+func (c baseComm) CommType() string { return c.commType }
+func (c baseComm) CacheID() string  { return c.cacheID }
 
 func (c baseComm) ObjCount() int64 { return c.xact.Objs() }
 func (c baseComm) InBytes() int64  { return c.xact.InBytes() }
@@ -166,7 +188,8 @@ func (c *baseComm) Stop() {
 // pushComm //
 //////////////
 
-func (pc *pushComm) doRequest(bck *cluster.Bck, objName string, timeout time.Duration) (r cos.ReadCloseSizer, err error) {
+func (pc *pushComm) doRequest(bck *cluster.Bck, objName, args string, timeout time.Duration) (r cos.ReadCloseSizer,
+	err error) {
 	lom := cluster.AllocLOM(objName)
 	defer cluster.FreeLOM(lom)
 
@@ -174,18 +197,18 @@ func (pc *pushComm) doRequest(bck *cluster.Bck, objName string, timeout time.Dur
 		return nil, err
 	}
 
-	r, err = pc.tryDoRequest(lom, timeout)
+	r, err = pc.tryDoRequest(lom, args, timeout)
 	if err != nil && cmn.IsObjNotExist(err) && bck.IsRemote() {
 		_, err = pc.t.GetCold(context.Background(), lom, cmn.OwtGetLock)
 		if err != nil {
 			return nil, err
 		}
-		r, err = pc.tryDoRequest(lom, timeout)
+		r, err = pc.tryDoRequest(lom, args, timeout)
 	}
 	return
 }
 
-func (pc *pushComm) tryDoRequest(lom *cluster.LOM, timeout time.Duration) (cos.ReadCloseSizer, error) {
+func (pc *pushComm) tryDoRequest(lom *cluster.LOM, args string, timeout time.Duration) (cos.ReadCloseSizer, error) {
 	if pc.xact.Aborted() {
 		return nil, cmn.NewErrAborted(pc.xact.Name(), "try-request", nil)
 	}
@@ -196,15 +219,21 @@ func (pc *pushComm) tryDoRequest(lom *cluster.LOM, timeout time.Duration) (cos.R
 	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
 		return nil, err
 	}
-	size := lom.SizeBytes()
+	size := lom.PlainAttrs().Size
 
 	// `fh` is closed by Do(req).
-	fh, err := cos.NewFileHandle(lom.FQN)
+	fh, err := lom.OpenPlain() // (decrypting if need be)
 	if err != nil {
 		return nil, err
 	}
+	return pc.put(fh, size, args, timeout)
+}
 
+// PUTs the data to the transformer and returns its response
+func (pc *pushComm) put(body io.ReadCloser, size int64, args string, timeout time.Duration) (cos.ReadCloseSizer,
+	error) {
 	var (
+		err    error
 		req    *http.Request
 		resp   *http.Response
 		cancel func()
@@ -212,19 +241,26 @@ func (pc *pushComm) tryDoRequest(lom *cluster.LOM, timeout time.Duration) (cos.R
 	if timeout != 0 {
 		var ctx context.Context
 		ctx, cancel = context.WithTimeout(context.Background(), timeout)
-		req, err = http.NewRequestWithContext(ctx, http.MethodPut, pc.uri, fh)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPut, pc.uri, body)
 	} else {
-		req, err = http.NewRequest(http.MethodPut, pc.uri, fh)
+		req, err = http.NewRequest(http.MethodPut, pc.uri, body)
 	}
 	if err != nil {
-		cos.Close(fh)
+		cos.Close(body)
 		goto finish
 	}
 	if len(pc.command) != 0 {
+		command := strings.Join(pc.command, " ")
+		if args != "" {
+			command = argsEnvName + "=" + shellQuote(args) + " " + command
+		}
 		q := req.URL.Query()
-		q["command"] = []string{"bash", "-c", strings.Join(pc.command, " ")}
+		q["command"] = []string{"bash", "-c", command}
 		req.URL.RawQuery = q.Encode()
 	}
+	if args != "" {
+		req.Header.Set(cmn.HdrETLArgs, args)
+	}
 	req.ContentLength = size
 	req.Header.Set(cmn.HdrContentType, cmn.ContentBinary)
 	resp, err = pc.t.DataClient().Do(req) // nolint:bodyclose // Closed by the caller.
@@ -244,15 +280,15 @@ finish:
 			if cancel != nil {
 				cancel()
 			}
-			pc.xact.InObjsAdd(1, size)
+			pc.xact.InObjsAdd(1, cos.MaxI64(size, 0))
 		},
 	}), nil
 }
 
-func (pc *pushComm) OnlineTransform(w http.ResponseWriter, _ *http.Request, bck *cluster.Bck, objName string) error {
+func (pc *pushComm) OnlineTransform(w http.ResponseWriter, _ *http.Request, bck *cluster.Bck, objName, args string) error {
 	var (
 		size   int64
-		r, err = pc.doRequest(bck, objName, 0 /*timeout*/)
+		r, err = pc.doRequest(bck, objName, args, 0 /*timeout*/)
 	)
 	if err != nil {
 		return err
@@ -267,15 +303,27 @@ func (pc *pushComm) OnlineTransform(w http.ResponseWriter, _ *http.Request, bck
 	return err
 }
 
-func (pc *pushComm) OfflineTransform(bck *cluster.Bck, objName string, timeout time.Duration) (cos.ReadCloseSizer, error) {
-	return pc.doRequest(bck, objName, timeout)
+func (pc *pushComm) OfflineTransform(bck *cluster.Bck, objName, args string, timeout time.Duration) (cos.ReadCloseSizer,
+	error) {
+	return pc.doRequest(bck, objName, args, timeout)
+}
+
+func (pc *pushComm) TransformStream(r cos.ReadCloseSizer, args string, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if pc.xact.Aborted() {
+		r.Close()
+		return nil, cmn.NewErrAborted(pc.xact.Name(), "try-request", nil)
+	}
+	return pc.put(r, r.Size(), args, timeout)
 }
 
 //////////////////
 // redirectComm //
 //////////////////
 
-func (rc *redirectComm) OnlineTransform(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, objName string) error {
+// NOTE: the client that follows the redirect cannot be made to send the header - the args
+// are passed to the transformer in the query (cmn.URLParamETLArgs) instead.
+func (rc *redirectComm) OnlineTransform(w http.ResponseWriter, r *http.Request, bck *cluster.Bck,
+	objName, args string) error {
 	if rc.xact.Aborted() {
 		return cmn.NewErrAborted(rc.xact.Name(), "try-request", nil)
 	}
@@ -288,25 +336,30 @@ func (rc *redirectComm) OnlineTransform(w http.ResponseWriter, r *http.Request,
 
 	// TODO: Is there way to determine `rc.stats.outBytes`?
 	redirectURL := cos.JoinPath(rc.uri, transformerPath(bck, objName))
+	if args != "" {
+		redirectURL += "?" + url.Values{cmn.URLParamETLArgs: []string{args}}.Encode()
+	}
 	http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
 	return nil
 }
 
-func (rc *redirectComm) OfflineTransform(bck *cluster.Bck, objName string, timeout time.Duration) (cos.ReadCloseSizer, error) {
+func (rc *redirectComm) OfflineTransform(bck *cluster.Bck, objName, args string, timeout time.Duration) (cos.ReadCloseSizer,
+	error) {
 	size, err := determineSize(bck, objName)
 	if err != nil {
 		return nil, err
 	}
 
 	etlURL := cos.JoinPath(rc.uri, transformerPath(bck, objName))
-	return rc.getWithTimeout(etlURL, size, timeout)
+	return rc.getWithTimeout(etlURL, size, args, timeout)
 }
 
 //////////////////
 // revProxyComm //
 //////////////////
 
-func (pc *revProxyComm) OnlineTransform(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, objName string) error {
+func (pc *revProxyComm) OnlineTransform(w http.ResponseWriter, r *http.Request, bck *cluster.Bck,
+	objName, args string) error {
 	size, err := determineSize(bck, objName)
 	if err != nil {
 		return err
@@ -317,18 +370,24 @@ func (pc *revProxyComm) OnlineTransform(w http.ResponseWriter, r *http.Request,
 	path := transformerPath(bck, objName)
 	r.URL.Path, _ = url.PathUnescape(path) // `Path` must be unescaped otherwise it will be escaped again.
 	r.URL.RawPath = path                   // `RawPath` should be escaped version of `Path`.
+	if args != "" {
+		r.Header.Set(cmn.HdrETLArgs, args)
+	} else {
+		r.Header.Del(cmn.HdrETLArgs)
+	}
 	pc.rp.ServeHTTP(w, r)
 	return nil
 }
 
-func (pc *revProxyComm) OfflineTransform(bck *cluster.Bck, objName string, timeout time.Duration) (cos.ReadCloseSizer, error) {
+func (pc *revProxyComm) OfflineTransform(bck *cluster.Bck, objName, args string, timeout time.Duration) (cos.ReadCloseSizer,
+	error) {
 	size, err := determineSize(bck, objName)
 	if err != nil {
 		return nil, err
 	}
 
 	etlURL := cos.JoinPath(pc.uri, transformerPath(bck, objName))
-	return pc.getWithTimeout(etlURL, size, timeout)
+	return pc.getWithTimeout(etlURL, size, args, timeout)
 }
 
 //////////////
@@ -364,7 +423,14 @@ func transformerPath(bck *cluster.Bck, objName string) string {
 	return "/" + url.PathEscape(bck.MakeUname(objName))
 }
 
-func (c *baseComm) getWithTimeout(url string, size int64, timeout time.Duration) (r cos.ReadCloseSizer, err error) {
+// (the transformers that pull the data cannot transform the output of the previous pipeline stage)
+func (c *baseComm) TransformStream(r cos.ReadCloseSizer, _ string, _ time.Duration) (cos.ReadCloseSizer, error) {
+	r.Close()
+	return nil, fmt.Errorf("ETL %q (%s) cannot transform the output of another ETL", c.name, c.commType)
+}
+
+func (c *baseComm) getWithTimeout(url string, size int64, args string, timeout time.Duration) (r cos.ReadCloseSizer,
+	err error) {
 	if c.xact.Aborted() {
 		return nil, cmn.NewErrAborted(c.xact.Name(), "try-request", nil)
 	}
@@ -384,6 +450,9 @@ func (c *baseComm) getWithTimeout(url string, size int64, timeout time.Duration)
 	if err != nil {
 		goto finish
 	}
+	if args != "" {
+		req.Header.Set(cmn.HdrETLArgs, args)
+	}
 	resp, err = c.t.DataClient().Do(req) // nolint:bodyclose // Closed by the caller.
 finish:
 	if err != nil {
@@ -406,6 +475,11 @@ finish:
 	}), nil
 }
 
+// single-quoted for bash (io:// transformers run by `/server` in K8s)
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 func determineSize(bck *cluster.Bck, objName string) (int64, error) {
 	lom := cluster.AllocLOM(objName)
 	defer cluster.FreeLOM(lom)
@@ -14,7 +14,7 @@ import (
 
 type OfflineDataProvider struct {
 	tcbMsg         *cmn.TCBMsg
-	comm           Communicator
+	pipeline       *pipeline
 	requestTimeout time.Duration
 }
 
@@ -22,11 +22,11 @@ type OfflineDataProvider struct {
 var _ cluster.DP = (*OfflineDataProvider)(nil)
 
 func NewOfflineDataProvider(msg *cmn.TCBMsg, lsnode *cluster.Snode) (*OfflineDataProvider, error) {
-	comm, err := GetCommunicator(msg.ID, lsnode)
+	p, err := newPipeline(msg.Stages(), lsnode)
 	if err != nil {
 		return nil, err
 	}
-	pr := &OfflineDataProvider{tcbMsg: msg, comm: comm}
+	pr := &OfflineDataProvider{tcbMsg: msg, pipeline: p}
 	pr.requestTimeout = time.Duration(msg.RequestTimeout)
 	return pr, nil
 }
@@ -38,7 +38,7 @@ func (dp *OfflineDataProvider) Reader(lom *cluster.LOM) (cos.ReadOpenCloser, cmn
 		err error
 	)
 	call := func() (int, error) {
-		r, err = dp.comm.OfflineTransform(lom.Bck(), lom.ObjName, dp.requestTimeout)
+		r, err = dp.pipeline.transform(lom.Bck(), lom.ObjName, dp.requestTimeout)
 		return 0, err
 	}
 	// TODO: Check if ETL pod is healthy and wait some more if not (yet).
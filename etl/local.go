@@ -0,0 +1,869 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/k8s"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/sys"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Local (K8s-free) ETL runtime - cluster config `etl.runtime = "local"`.
+//
+// The ETL is still defined by the same pod spec (or init-code runtime) as in K8s.
+// Each target, instead of creating a pod and a service, emulates the pod on its own
+// machine using one of the launchers (config `etl.launcher`):
+// * "process" - runs the container's command (and args) as a subprocess of the target;
+//   the image is ignored and the transformer must listen on the port passed via `PORT`
+//   environment variable;
+// * OCI container CLI (e.g. "docker" or "podman") - runs the container's image
+//   with the container port published on a free host port; init containers run
+//   (to completion) first and the pod's volumes are mapped to the host directories
+//   under `etl.work_dir`.
+// The transformer is probed with its readinessProbe (or, if not specified, TCP dial),
+// and gets restarted, with backoff, when it exits or fails the probe
+// (`failureThreshold` times in a row).
+//
+// The io:// communication type does not require a long-running transformer - each
+// object is piped through the container's command (stdin => stdout).
+
+const (
+	localDfltReadyTimeout = time.Minute
+	localDfltProbePeriod  = 10 * time.Second
+	localDfltProbeTimeout = 5 * time.Second
+	localDfltFailures     = 3
+	localMinBackoff       = time.Second
+	localMaxBackoff       = time.Minute
+	localStopGrace        = 5 * time.Second
+
+	localMaxLogSize = cos.MiB
+
+	localDfltOutputLimit = cos.GiB // io:// (see InitMsgBase.OutputLimit)
+
+	localPortEnvName = "PORT"
+	localPathEnvName = "PATH"
+	localDfltPath    = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin" // (as in container images)
+)
+
+type (
+	// launcher runs the pod's containers on the target's machine
+	launcher interface {
+		// (ctx is only used with io:// to bound the time of a single transformation)
+		command(ctx context.Context, lp *localPod, c *corev1.Container, ra *runArgs) (*exec.Cmd, error)
+		// terminates the container that was started with the command
+		kill(name string, cmd *exec.Cmd, done <-chan struct{})
+		// pid of the container's main process (for health stats)
+		pid(name string, cmd *exec.Cmd) (int, error)
+	}
+	runArgs struct {
+		name     string   // (unique) container name
+		cmdline  []string // when non-empty, overrides container's command and args
+		hostPort int      // to publish the container port on (0: none)
+		stdin    bool     // keep stdin open (io://)
+		args     string   // ETL arguments (io://)
+	}
+	processLauncher   struct{}
+	containerLauncher struct {
+		cli string // docker, podman, etc.
+	}
+
+	// localPod emulates ETL pod on the target's machine.
+	localPod struct {
+		name     string
+		pod      *corev1.Pod
+		launcher launcher
+		dir      string            // pod's own work directory
+		volumes  map[string]string // volume name => host directory
+		uri      string            // http://host:port of the transformer (except io://)
+		host     string
+		hostPort int
+		probe    *corev1.Probe
+		logs     logBuf
+		restarts atomic.Int64
+		stopCh   *cos.StopCh
+		exited   chan error
+
+		mu   sync.Mutex
+		cmd  *exec.Cmd
+		done chan struct{} // closed when cmd exits
+	}
+
+	// last `localMaxLogSize` bytes of the containers' output
+	logBuf struct {
+		mu sync.Mutex
+		b  []byte
+	}
+
+	localCommunicator interface {
+		Communicator
+		localPod() *localPod
+	}
+	// wraps HTTP-based (push, redirect, and reverse proxy) communicators
+	localComm struct {
+		Communicator
+		lp *localPod
+	}
+	// io:// communicator: runs the command for each object
+	ioComm struct {
+		baseComm
+		mem         *memsys.MMSA
+		lp          *localPod
+		cmdline     []string
+		outputLimit int64
+	}
+
+	// io:// output that aborts the transformation once it exceeds the limit
+	limitWriter struct {
+		w        io.Writer
+		cancel   context.CancelFunc
+		n, limit int64
+		exceeded bool
+	}
+)
+
+var errOutputLimit = errors.New("output size limit exceeded")
+
+// interface guard
+var (
+	_ launcher = (*processLauncher)(nil)
+	_ launcher = (*containerLauncher)(nil)
+
+	_ localCommunicator = (*localComm)(nil)
+	_ localCommunicator = (*ioComm)(nil)
+
+	_ io.Writer = (*logBuf)(nil)
+	_ io.Writer = (*limitWriter)(nil)
+)
+
+func newLauncher(conf *cmn.ETLConf) (launcher, error) {
+	if conf.Launcher == "" || conf.Launcher == cmn.ETLLauncherProcess {
+		return &processLauncher{}, nil
+	}
+	cli, err := exec.LookPath(conf.Launcher)
+	if err != nil {
+		return nil, fmt.Errorf("invalid etl.launcher %q: %v", conf.Launcher, err)
+	}
+	return &containerLauncher{cli: cli}, nil
+}
+
+// startLocal is the local counterpart of `tryStart`.
+func startLocal(t cluster.Target, msg InitSpecMsg, opts StartOpts) (err error) {
+	var (
+		lp     *localPod
+		config = cmn.GCO.Get()
+		errCtx = &cmn.ETLErrorContext{TID: t.SID(), UUID: msg.IDX}
+		b      = &etlBootstraper{errCtx: errCtx, t: t, msg: msg, env: opts.Env}
+	)
+	if b.pod, err = ParsePodSpec(errCtx, msg.Spec); err != nil {
+		return
+	}
+	b.originalPodName = b.pod.GetName()
+	errCtx.ETLName = b.originalPodName
+	b.pod.SetName(k8s.CleanName(b.pod.GetName() + "-" + t.SID()))
+	errCtx.PodName = b.pod.GetName()
+	errCtx.SvcName = b.pod.GetName()
+	if len(b.pod.Spec.Containers) != 1 {
+		return cmn.NewErrETL(errCtx, "unsupported number of containers (%d), expected: 1", len(b.pod.Spec.Containers))
+	}
+	b.setPodEnvVariables()
+
+	if lp, err = newLocalPod(b.pod, &config.ETL); err != nil {
+		return cmn.NewErrETL(errCtx, err.Error())
+	}
+	lp.host = t.Snode().PublicNet.NodeHostname
+	defer func() {
+		if err != nil {
+			glog.Warning(cmn.NewErrETL(errCtx, "Performing cleanup after unsuccessful Start"))
+			lp.stop()
+		}
+	}()
+	if err = lp.start(msg.CommTypeX == IOCommType, time.Duration(msg.WaitTimeout)); err != nil {
+		return cmn.NewErrETL(errCtx, err.Error())
+	}
+	b.uri = lp.uri
+
+	b.setupXaction()
+	var c localCommunicator
+	if msg.CommTypeX == IOCommType {
+		c = newIOComm(commArgs{listener: newAborter(t, msg.IDX), bootstraper: b}, lp)
+	} else {
+		c = &localComm{makeCommunicator(commArgs{listener: newAborter(t, msg.IDX), bootstraper: b}), lp}
+	}
+	if err = reg.put(msg.IDX, c); err != nil {
+		c.Stop()
+		return
+	}
+	t.Sowner().Listeners().Reg(c)
+	if msg.CommTypeX != IOCommType {
+		go lp.monitor()
+	}
+	return
+}
+
+//////////////
+// localPod //
+//////////////
+
+func newLocalPod(pod *corev1.Pod, conf *cmn.ETLConf) (lp *localPod, err error) {
+	lp = &localPod{
+		name:    pod.GetName(),
+		pod:     pod,
+		volumes: make(map[string]string, len(pod.Spec.Volumes)),
+		probe:   pod.Spec.Containers[0].ReadinessProbe,
+		stopCh:  cos.NewStopCh(),
+		exited:  make(chan error, 1),
+	}
+	if lp.launcher, err = newLauncher(conf); err != nil {
+		return nil, err
+	}
+	workDir := conf.WorkDir
+	if workDir == "" {
+		workDir = filepath.Join(os.TempDir(), "ais-etl")
+	}
+	lp.dir = filepath.Join(workDir, lp.name)
+	for i := range pod.Spec.Volumes {
+		vol := &pod.Spec.Volumes[i]
+		switch {
+		case vol.EmptyDir != nil:
+			lp.volumes[vol.Name] = filepath.Join(lp.dir, vol.Name)
+		case vol.HostPath != nil:
+			lp.volumes[vol.Name] = vol.HostPath.Path
+		default:
+			return nil, fmt.Errorf("volume %q: only emptyDir and hostPath volumes are supported", vol.Name)
+		}
+	}
+	return lp, nil
+}
+
+func (lp *localPod) String() string { return "etl-local-" + lp.name }
+
+func (lp *localPod) main() *corev1.Container { return &lp.pod.Spec.Containers[0] }
+
+// (re)creates pod's directories, runs init containers, and then (except io://)
+// starts the transformer and waits for it to become ready
+func (lp *localPod) start(pipe bool, readyTimeout time.Duration) (err error) {
+	lp.launcher.kill(lp.name, nil, nil) // cleanup leftovers (if any)
+	if err = os.RemoveAll(lp.dir); err != nil {
+		return
+	}
+	if err = cos.CreateDir(lp.dir); err != nil {
+		return
+	}
+	for i := range lp.pod.Spec.Volumes {
+		if vol := &lp.pod.Spec.Volumes[i]; vol.EmptyDir != nil {
+			if err = cos.CreateDir(lp.volumes[vol.Name]); err != nil {
+				return
+			}
+		}
+	}
+	for i := range lp.pod.Spec.InitContainers {
+		if err = lp.runInit(&lp.pod.Spec.InitContainers[i]); err != nil {
+			return
+		}
+	}
+	if pipe {
+		return
+	}
+	if lp.hostPort, err = freePort(); err != nil {
+		return
+	}
+	lp.uri = "http://" + net.JoinHostPort(lp.host, strconv.Itoa(lp.hostPort))
+	if err = lp.run(); err != nil {
+		return
+	}
+	if readyTimeout == 0 {
+		readyTimeout = localDfltReadyTimeout
+	}
+	return lp.waitReady(readyTimeout)
+}
+
+func (lp *localPod) runInit(c *corev1.Container) error {
+	ra := &runArgs{name: lp.name + "-" + c.Name}
+	cmd, err := lp.launcher.command(context.Background(), lp, c, ra)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout, cmd.Stderr = &lp.logs, &lp.logs
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("init container %q failed: %v%s", c.Name, err, lp.logs.tail())
+	}
+	return nil
+}
+
+// starts the transformer (and the goroutine that waits for it to exit)
+func (lp *localPod) run() error {
+	ra := &runArgs{name: lp.name, hostPort: lp.hostPort}
+	cmd, err := lp.launcher.command(context.Background(), lp, lp.main(), ra)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout, cmd.Stderr = &lp.logs, &lp.logs
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	lp.mu.Lock()
+	lp.cmd, lp.done = cmd, done
+	lp.mu.Unlock()
+	go func() {
+		err := cmd.Wait()
+		close(done)
+		if err == nil {
+			err = fmt.Errorf("%s exited", lp)
+		}
+		select {
+		case lp.exited <- err:
+		default:
+		}
+	}()
+	return nil
+}
+
+func (lp *localPod) waitReady(timeout time.Duration) error {
+	var (
+		err      error
+		deadline = time.Now().Add(timeout)
+		ticker   = time.NewTicker(500 * time.Millisecond)
+	)
+	defer ticker.Stop()
+	for {
+		if err = lp.check(); err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s failed to become ready in %v: %v%s", lp, timeout, err, lp.logs.tail())
+		}
+		select {
+		case errExit := <-lp.exited:
+			return fmt.Errorf("%s exited before becoming ready: %v%s", lp, errExit, lp.logs.tail())
+		case <-ticker.C:
+		}
+	}
+}
+
+// readinessProbe (HTTP GET) or, if not specified, TCP dial
+func (lp *localPod) check() error {
+	timeout := localDfltProbeTimeout
+	if lp.probe != nil && lp.probe.TimeoutSeconds > 0 {
+		timeout = time.Duration(lp.probe.TimeoutSeconds) * time.Second
+	}
+	if lp.probe == nil || lp.probe.HTTPGet == nil {
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(lp.host, strconv.Itoa(lp.hostPort)), timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+	client := http.Client{Timeout: timeout}
+	resp, err := client.Get(cos.JoinPath(lp.uri, lp.probe.HTTPGet.Path))
+	if err != nil {
+		return err
+	}
+	cos.DrainReader(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("readiness probe: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// restarts the transformer when it exits or keeps failing the probe
+func (lp *localPod) monitor() {
+	var (
+		failures  int
+		backoff   = localMinBackoff
+		period    = localDfltProbePeriod
+		threshold = localDfltFailures
+	)
+	if lp.probe != nil && lp.probe.PeriodSeconds > 0 {
+		period = time.Duration(lp.probe.PeriodSeconds) * time.Second
+	}
+	if lp.probe != nil && lp.probe.FailureThreshold > 0 {
+		threshold = int(lp.probe.FailureThreshold)
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-lp.exited:
+			for {
+				glog.Errorf("%s: %v - restarting in %v%s", lp, err, backoff, lp.logs.tail())
+				select {
+				case <-lp.stopCh.Listen():
+					return
+				case <-time.After(backoff):
+				}
+				backoff = cos.MinDuration(2*backoff, localMaxBackoff)
+				if err = lp.run(); err == nil {
+					break
+				}
+			}
+			lp.restarts.Inc()
+			failures = 0
+		case <-ticker.C:
+			if err := lp.check(); err != nil {
+				if failures++; failures >= threshold {
+					glog.Errorf("%s: failed %d probes in a row (%v) - killing", lp, failures, err)
+					failures = 0
+					lp.kill()
+				}
+				continue
+			}
+			failures, backoff = 0, localMinBackoff
+		case <-lp.stopCh.Listen():
+			return
+		}
+	}
+}
+
+func (lp *localPod) kill() {
+	lp.mu.Lock()
+	cmd, done := lp.cmd, lp.done
+	lp.mu.Unlock()
+	if cmd != nil {
+		lp.launcher.kill(lp.name, cmd, done)
+	}
+}
+
+func (lp *localPod) stop() {
+	lp.stopCh.Close()
+	lp.kill()
+	if err := os.RemoveAll(lp.dir); err != nil {
+		glog.Errorf("%s: %v", lp, err)
+	}
+}
+
+func (lp *localPod) health() (cpu float64, mem int64, err error) {
+	lp.mu.Lock()
+	cmd := lp.cmd
+	lp.mu.Unlock()
+	if cmd == nil {
+		return // io://
+	}
+	pid, err := lp.launcher.pid(lp.name, cmd)
+	if err != nil {
+		return 0, 0, err
+	}
+	stats, err := sys.ProcessStats(pid)
+	if err != nil {
+		return 0, 0, err
+	}
+	return stats.CPU.Percent, int64(stats.Mem.Resident), nil
+}
+
+// container's environment (pod spec values only)
+func (lp *localPod) env(c *corev1.Container, ra *runArgs) (env []string, err error) {
+	env = make([]string, 0, len(c.Env)+1)
+	for _, ev := range c.Env {
+		if ev.ValueFrom != nil {
+			return nil, fmt.Errorf("container %q: env variable %q: valueFrom is not supported", c.Name, ev.Name)
+		}
+		env = append(env, ev.Name+"="+ev.Value)
+	}
+	if ra.hostPort != 0 {
+		env = append(env, localPortEnvName+"="+strconv.Itoa(ra.hostPort))
+	}
+	if ra.args != "" {
+		env = append(env, argsEnvName+"="+ra.args)
+	}
+	return
+}
+
+func hasEnv(c *corev1.Container, name string) bool {
+	for _, ev := range c.Env {
+		if ev.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+/////////////////////
+// processLauncher //
+/////////////////////
+
+func (*processLauncher) command(ctx context.Context, lp *localPod, c *corev1.Container, ra *runArgs) (*exec.Cmd, error) {
+	if len(c.VolumeMounts) > 0 {
+		return nil, fmt.Errorf("container %q: volume mounts require container launcher (see etl.launcher)", c.Name)
+	}
+	cmdline := ra.cmdline
+	if len(cmdline) == 0 {
+		cmdline = append(append([]string{}, c.Command...), c.Args...)
+	}
+	if len(cmdline) == 0 {
+		return nil, fmt.Errorf("container %q: command is required to run the container as a process", c.Name)
+	}
+	env, err := lp.env(c, ra)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, cmdline[0], cmdline[1:]...)
+	// not inheriting the target's environment (credentials included) - pod spec values only
+	cmd.Env = env
+	if !hasEnv(c, localPathEnvName) {
+		cmd.Env = append(cmd.Env, localPathEnvName+"="+localDfltPath)
+	}
+	cmd.Dir = c.WorkingDir
+	if cmd.Dir == "" {
+		cmd.Dir = lp.dir
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true} // to kill the entire process group
+	return cmd, nil
+}
+
+func (*processLauncher) kill(_ string, cmd *exec.Cmd, done <-chan struct{}) {
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	pgid := -cmd.Process.Pid
+	_ = syscall.Kill(pgid, syscall.SIGTERM)
+	select {
+	case <-done:
+	case <-time.After(localStopGrace):
+		_ = syscall.Kill(pgid, syscall.SIGKILL)
+	}
+}
+
+func (*processLauncher) pid(_ string, cmd *exec.Cmd) (int, error) { return cmd.Process.Pid, nil }
+
+///////////////////////
+// containerLauncher //
+///////////////////////
+
+func (cl *containerLauncher) command(ctx context.Context, lp *localPod, c *corev1.Container, ra *runArgs) (*exec.Cmd, error) {
+	env, err := lp.env(c, ra)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"run", "--rm", "--name", ra.name}
+	if ra.stdin {
+		args = append(args, "-i")
+	}
+	if ra.hostPort != 0 {
+		if len(c.Ports) == 0 {
+			return nil, fmt.Errorf("container %q: port is required", c.Name)
+		}
+		args = append(args, "-p", fmt.Sprintf("%d:%d", ra.hostPort, c.Ports[0].ContainerPort))
+	}
+	// NOTE: passing only the names, to have the values (e.g. init-code sources) inherited
+	// from the environment rather than exposed on the command line
+	for _, ev := range env {
+		args = append(args, "-e", ev[:strings.IndexByte(ev, '=')])
+	}
+	for _, m := range c.VolumeMounts {
+		dir, ok := lp.volumes[m.Name]
+		if !ok {
+			return nil, fmt.Errorf("container %q: volume %q not found", c.Name, m.Name)
+		}
+		vol := dir + ":" + m.MountPath
+		if m.ReadOnly {
+			vol += ":ro"
+		}
+		args = append(args, "-v", vol)
+	}
+	if c.WorkingDir != "" {
+		args = append(args, "-w", c.WorkingDir)
+	}
+	switch {
+	case len(ra.cmdline) > 0:
+		args = append(args, "--entrypoint", ra.cmdline[0], c.Image)
+		args = append(args, ra.cmdline[1:]...)
+	case len(c.Command) > 0:
+		args = append(args, "--entrypoint", c.Command[0], c.Image)
+		args = append(args, c.Command[1:]...)
+		args = append(args, c.Args...)
+	default:
+		args = append(args, c.Image)
+		args = append(args, c.Args...)
+	}
+	cmd := exec.CommandContext(ctx, cl.cli, args...)
+	cmd.Env = append(os.Environ(), env...) // (the CLI itself; the container gets "-e" variables only)
+	return cmd, nil
+}
+
+func (cl *containerLauncher) kill(name string, cmd *exec.Cmd, done <-chan struct{}) {
+	_ = exec.Command(cl.cli, "stop", "-t", strconv.Itoa(int(localStopGrace/time.Second)), name).Run()
+	_ = exec.Command(cl.cli, "rm", "-f", name).Run()
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+	select {
+	case <-done:
+	case <-time.After(localStopGrace):
+		_ = cmd.Process.Kill()
+	}
+}
+
+func (cl *containerLauncher) pid(name string, _ *exec.Cmd) (int, error) {
+	out, err := exec.Command(cl.cli, "inspect", "-f", "{{.State.Pid}}", name).Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect container %q: %v", name, err)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+////////////
+// logBuf //
+////////////
+
+func (l *logBuf) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	l.b = append(l.b, p...)
+	if len(l.b) > 2*localMaxLogSize {
+		l.b = append(l.b[:0], l.b[len(l.b)-localMaxLogSize:]...)
+	}
+	l.mu.Unlock()
+	return len(p), nil
+}
+
+func (l *logBuf) Bytes() []byte {
+	l.mu.Lock()
+	b := l.b
+	if len(b) > localMaxLogSize {
+		b = b[len(b)-localMaxLogSize:]
+	}
+	b = append([]byte{}, b...)
+	l.mu.Unlock()
+	return b
+}
+
+// for error messages
+func (l *logBuf) tail() string {
+	const maxTail = 1024
+	b := l.Bytes()
+	if len(b) == 0 {
+		return ""
+	}
+	if len(b) > maxTail {
+		b = b[len(b)-maxTail:]
+	}
+	return "; output:\n" + string(b)
+}
+
+/////////////////
+// limitWriter //
+/////////////////
+
+func outputLimit(msg *InitMsgBase) int64 {
+	if msg.OutputLimit > 0 {
+		return msg.OutputLimit
+	}
+	return localDfltOutputLimit
+}
+
+func (lw *limitWriter) Write(p []byte) (int, error) {
+	if lw.n+int64(len(p)) > lw.limit {
+		lw.exceeded = true
+		lw.cancel()
+		return 0, errOutputLimit
+	}
+	n, err := lw.w.Write(p)
+	lw.n += int64(n)
+	return n, err
+}
+
+///////////////
+// localComm //
+///////////////
+
+func (lc *localComm) localPod() *localPod { return lc.lp }
+
+func (lc *localComm) Stop() {
+	lc.lp.stop()
+	lc.Communicator.Stop()
+}
+
+////////////
+// ioComm //
+////////////
+
+func newIOComm(args commArgs, lp *localPod) *ioComm {
+	var (
+		b  = args.bootstraper
+		c  = lp.main()
+		bc = newBaseComm(args)
+	)
+	bc.commType = IOCommType
+	return &ioComm{
+		baseComm: bc,
+		mem:      b.t.PageMM(),
+		lp:       lp,
+		// same as in K8s (see updatePodCommand and pushComm)
+		cmdline:     []string{"sh", "-c", strings.Join(append(append([]string{}, c.Command...), c.Args...), " ")},
+		outputLimit: outputLimit(&b.msg.InitMsgBase),
+	}
+}
+
+func (ic *ioComm) localPod() *localPod { return ic.lp }
+
+func (ic *ioComm) Stop() {
+	ic.lp.stop()
+	ic.baseComm.Stop()
+}
+
+func (ic *ioComm) doRequest(bck *cluster.Bck, objName, args string, timeout time.Duration) (cos.ReadCloseSizer,
+	error) {
+	return ic.pipeObj(bck, objName, func(r io.Reader, size int64) (cos.ReadCloseSizer, error) {
+		return ic.pipe(r, size, args, timeout)
+	})
+}
+
+// pipes the data through the command; the output is buffered (up to the output limit) so that
+// a failed transformation results in error rather than truncated object
+func (ic *ioComm) pipe(r io.Reader, size int64, args string, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if timeout != 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+	var (
+		stderr logBuf
+		ra     = &runArgs{name: ic.lp.name + "-" + cos.GenTie(), cmdline: ic.cmdline, stdin: true, args: args}
+	)
+	if args != "" {
+		// (the command can refer to the args both as "$1" and "$AIS_ETL_ARGS")
+		ra.cmdline = append(ic.cmdline[:len(ic.cmdline):len(ic.cmdline)], "sh", args)
+	}
+	cmd, err := ic.lp.launcher.command(ctx, ic.lp, ic.lp.main(), ra)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		sgl    = ic.mem.NewSGL(cos.MaxI64(size, 0))
+		stdout = &limitWriter{w: sgl, cancel: cancel, limit: ic.outputLimit}
+	)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = r, stdout, io.MultiWriter(&stderr, &ic.lp.logs)
+	if err := cmd.Run(); err != nil || stdout.exceeded {
+		sgl.Free()
+		if ctx.Err() != nil {
+			done := make(chan struct{})
+			close(done)
+			ic.lp.launcher.kill(ra.name, cmd, done)
+		}
+		if stdout.exceeded {
+			return nil, fmt.Errorf("%s: %w (%d bytes)%s", ic.lp, errOutputLimit, stdout.limit, stderr.tail())
+		}
+		return nil, fmt.Errorf("%s: %v%s", ic.lp, err, stderr.tail())
+	}
+	return cos.NewReaderWithArgs(cos.ReaderArgs{
+		R:      sgl,
+		Size:   sgl.Size(),
+		ReadCb: func(i int, err error) { ic.xact.OutObjsAdd(1, int64(i)) },
+		DeferCb: func() {
+			sgl.Free()
+			ic.xact.InObjsAdd(1, cos.MaxI64(size, 0))
+		},
+	}), nil
+}
+
+func (ic *ioComm) OnlineTransform(w http.ResponseWriter, _ *http.Request, bck *cluster.Bck, objName, args string) error {
+	r, err := ic.doRequest(bck, objName, args, 0 /*timeout*/)
+	if err != nil {
+		return err
+	}
+	return writeTransformed(w, r)
+}
+
+func (ic *ioComm) OfflineTransform(bck *cluster.Bck, objName, args string, timeout time.Duration) (cos.ReadCloseSizer,
+	error) {
+	return ic.doRequest(bck, objName, args, timeout)
+}
+
+func (ic *ioComm) TransformStream(r cos.ReadCloseSizer, args string, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	defer r.Close()
+	if ic.xact.Aborted() {
+		return nil, cmn.NewErrAborted(ic.xact.Name(), "try-request", nil)
+	}
+	return ic.pipe(r, r.Size(), args, timeout)
+}
+
+///////////
+// utils //
+///////////
+
+// pipeObj pipes the object (cold GET-ting it, if need be) through the in-process transformation
+func (c *baseComm) pipeObj(bck *cluster.Bck, objName string,
+	pipe func(r io.Reader, size int64) (cos.ReadCloseSizer, error)) (r cos.ReadCloseSizer, err error) {
+	lom := cluster.AllocLOM(objName)
+	defer cluster.FreeLOM(lom)
+
+	if err := lom.Init(bck.Bck); err != nil {
+		return nil, err
+	}
+
+	r, err = c.tryPipeObj(lom, pipe)
+	if err != nil && cmn.IsObjNotExist(err) && bck.IsRemote() {
+		_, err = c.t.GetCold(context.Background(), lom, cmn.OwtGetLock)
+		if err != nil {
+			return nil, err
+		}
+		r, err = c.tryPipeObj(lom, pipe)
+	}
+	return
+}
+
+func (c *baseComm) tryPipeObj(lom *cluster.LOM, pipe func(r io.Reader, size int64) (cos.ReadCloseSizer, error)) (
+	cos.ReadCloseSizer, error) {
+	if c.xact.Aborted() {
+		return nil, cmn.NewErrAborted(c.xact.Name(), "try-request", nil)
+	}
+
+	lom.Lock(false)
+	defer lom.Unlock(false)
+
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		return nil, err
+	}
+	size := lom.PlainAttrs().Size
+	fh, err := lom.OpenPlain() // (decrypting if need be)
+	if err != nil {
+		return nil, err
+	}
+	defer cos.Close(fh)
+	return pipe(fh, size)
+}
+
+// writes the result of in-process transformation
+func writeTransformed(w http.ResponseWriter, r cos.ReadCloseSizer) error {
+	defer r.Close()
+	w.Header().Set(cmn.HdrContentLength, strconv.FormatInt(r.Size(), 10))
+	_, err := io.Copy(w, r)
+	return err
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return 0, err
+	}
+	port := l.(*net.TCPListener).Addr().(*net.TCPAddr).Port
+	return port, l.Close()
+}
@@ -0,0 +1,407 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cluster/mock"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/etl/runtime"
+	"github.com/NVIDIA/aistore/etl/wasm"
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const localTransformerEnv = "AIS_ETL_TEST_TRANSFORMER"
+
+// WASI command that uppercases stdin (see `upperBody` in etl/wasm tests)
+var upperWasm = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, 0x01, 0x10, 0x03, 0x60, 0x04, 0x7f, 0x7f, 0x7f,
+	0x7f, 0x01, 0x7f, 0x60, 0x01, 0x7f, 0x00, 0x60, 0x00, 0x00, 0x02, 0x67, 0x03, 0x16, 0x77, 0x61,
+	0x73, 0x69, 0x5f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x5f, 0x70, 0x72, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x31, 0x07, 0x66, 0x64, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x00, 0x00, 0x16, 0x77,
+	0x61, 0x73, 0x69, 0x5f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x5f, 0x70, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x31, 0x08, 0x66, 0x64, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x00, 0x00,
+	0x16, 0x77, 0x61, 0x73, 0x69, 0x5f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x5f, 0x70,
+	0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x31, 0x09, 0x70, 0x72, 0x6f, 0x63, 0x5f, 0x65, 0x78, 0x69,
+	0x74, 0x00, 0x01, 0x03, 0x02, 0x01, 0x02, 0x05, 0x03, 0x01, 0x00, 0x01, 0x07, 0x0a, 0x01, 0x06,
+	0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x00, 0x03, 0x0a, 0x75, 0x01, 0x73, 0x01, 0x02, 0x7f, 0x02,
+	0x40, 0x03, 0x40, 0x41, 0x00, 0x41, 0xc0, 0x00, 0x36, 0x02, 0x00, 0x41, 0x04, 0x41, 0x80, 0x08,
+	0x36, 0x02, 0x00, 0x41, 0x00, 0x41, 0x00, 0x41, 0x01, 0x41, 0x08, 0x10, 0x00, 0x0d, 0x01, 0x41,
+	0x08, 0x28, 0x02, 0x00, 0x22, 0x00, 0x45, 0x0d, 0x01, 0x41, 0x00, 0x21, 0x01, 0x03, 0x40, 0x20,
+	0x01, 0x2d, 0x00, 0x40, 0x41, 0xe1, 0x00, 0x6b, 0x41, 0x1a, 0x49, 0x04, 0x40, 0x20, 0x01, 0x20,
+	0x01, 0x2d, 0x00, 0x40, 0x41, 0x20, 0x6b, 0x3a, 0x00, 0x40, 0x0b, 0x20, 0x01, 0x41, 0x01, 0x6a,
+	0x22, 0x01, 0x20, 0x00, 0x49, 0x0d, 0x00, 0x0b, 0x41, 0x04, 0x20, 0x00, 0x36, 0x02, 0x00, 0x41,
+	0x01, 0x41, 0x00, 0x41, 0x01, 0x41, 0x0c, 0x10, 0x01, 0x1a, 0x0c, 0x00, 0x0b, 0x0b, 0x0b,
+}
+
+// TestLocalTransformer is not a test - it is the transformer started by the local runtime
+// (see "should restart the transformer" below): uppercases the PUT data.
+func TestLocalTransformer(t *testing.T) {
+	if os.Getenv(localTransformerEnv) == "" {
+		t.Skip("local runtime transformer")
+	}
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.Write([]byte("OK"))
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		w.Write([]byte(strings.ToUpper(string(b))))
+	})
+	http.ListenAndServe("127.0.0.1:"+os.Getenv(localPortEnvName), nil)
+	os.Exit(1)
+}
+
+var _ = Describe("LocalRuntimeTest", func() {
+	var (
+		tmpDir string
+		tMock  cluster.Target
+
+		bck        = cmn.Bck{Name: "localBck", Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
+		objName    = "localObj"
+		clusterBck = cluster.NewBck(
+			bck.Name, bck.Provider, bck.Ns,
+			&cmn.BucketProps{Cksum: cmn.CksumConf{Type: cos.ChecksumXXHash}},
+		)
+		bmdMock = cluster.NewBaseBownerMock(clusterBck)
+		content = "transform me"
+	)
+
+	newPod := func(c corev1.Container) *corev1.Pod {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{c}}}
+		pod.SetName("local-etl")
+		return pod
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		mpath := filepath.Join(tmpDir, "mpath")
+		err = cos.CreateDir(mpath)
+		Expect(err).NotTo(HaveOccurred())
+		fs.TestNew(nil)
+		fs.TestDisableValidation()
+		_, err = fs.Add(mpath, "daeID")
+		Expect(err).NotTo(HaveOccurred())
+
+		tMock = mock.NewTarget(bmdMock)
+
+		lom := &cluster.LOM{ObjName: objName}
+		err = lom.Init(clusterBck.Bck)
+		Expect(err).NotTo(HaveOccurred())
+		f, err := cos.CreateFile(lom.FQN)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.Write([]byte(content))
+		Expect(err).NotTo(HaveOccurred())
+		f.Close()
+		lom.SetAtimeUnix(time.Now().UnixNano())
+		lom.SetSize(int64(len(content)))
+		err = lom.Persist()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	It("should pipe objects through the command (io://)", func() {
+		os.Setenv("AIS_ETL_TEST_SECRET", "secret")
+		defer os.Unsetenv("AIS_ETL_TEST_SECRET")
+		conf := &cmn.ETLConf{Launcher: cmn.ETLLauncherProcess, WorkDir: tmpDir}
+		for _, test := range []struct {
+			command []string
+			args    []string
+			env     []corev1.EnvVar
+			etlArgs string
+			out     string
+		}{
+			{command: []string{"tr", "a-z"}, args: []string{"A-Z"}, out: strings.ToUpper(content)},
+			{command: []string{`cat; printf " %s|%s" "$1" "$AIS_ETL_ARGS"`}, etlArgs: "a b", out: content + " a b|a b"},
+			{command: []string{"cat; exit 3"}},
+			// pod spec environment only (not the target's)
+			{
+				command: []string{`cat; printf " %s|%s" "$AIS_ETL_TEST_SECRET" "$FOO"`},
+				env:     []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+				out:     content + " |bar",
+			},
+		} {
+			pod := newPod(corev1.Container{Name: "server", Command: test.command, Args: test.args, Env: test.env})
+			lp, err := newLocalPod(pod, conf)
+			Expect(err).NotTo(HaveOccurred())
+			err = lp.start(true /*pipe*/, 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			comm := newIOComm(commArgs{
+				bootstraper: &etlBootstraper{t: tMock, pod: pod, xact: mock.NewXact(cmn.ActETLInline)},
+			}, lp)
+			r, err := comm.OfflineTransform(clusterBck, objName, test.etlArgs, time.Minute)
+			if test.out == "" {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+				b, err := io.ReadAll(r)
+				Expect(err).NotTo(HaveOccurred())
+				r.Close()
+				Expect(string(b)).To(Equal(test.out))
+				Expect(comm.InBytes()).To(BeEquivalentTo(len(content)))
+			}
+			lp.stop()
+			Expect(lp.dir).NotTo(BeADirectory())
+		}
+	})
+
+	It("should chain transformers (pipeline)", func() {
+		var (
+			conf  = &cmn.ETLConf{Launcher: cmn.ETLLauncherProcess, WorkDir: tmpDir}
+			comms = make([]Communicator, 0, 2)
+		)
+		for _, c := range []corev1.Container{
+			{Name: "upper", Command: []string{"tr", "a-z", "A-Z"}},
+			{Name: "suffix", Command: []string{`cat; printf " %s" "$AIS_ETL_ARGS"`}},
+		} {
+			pod := newPod(c)
+			lp, err := newLocalPod(pod, conf)
+			Expect(err).NotTo(HaveOccurred())
+			err = lp.start(true /*pipe*/, 0)
+			Expect(err).NotTo(HaveOccurred())
+			defer lp.stop()
+			comms = append(comms, newIOComm(commArgs{
+				bootstraper: &etlBootstraper{t: tMock, pod: pod, xact: mock.NewXact(cmn.ActETLInline)},
+			}, lp))
+		}
+		p := &pipeline{
+			stages: cmn.ETLPipeline{{ID: "upper"}, {ID: "suffix", Args: "done"}},
+			comms:  comms,
+		}
+		r, err := p.transform(clusterBck, objName, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		b, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+		r.Close()
+		Expect(string(b)).To(Equal(strings.ToUpper(content) + " done"))
+		Expect(comms[1].InBytes()).To(BeEquivalentTo(len(content)))
+	})
+
+	It("should run WebAssembly modules (wasi)", func() {
+		msg := InitCodeMsg{Code: upperWasm, Runtime: runtime.WASI}
+		if !wasm.Enabled {
+			Expect(errors.Is(msg.Validate(), wasm.ErrNotBuilt)).To(BeTrue())
+			Skip("WebAssembly runtime requires etlwasm build tag")
+		}
+		Expect(msg.Validate()).NotTo(HaveOccurred())
+		Expect(msg.CommTypeX).To(Equal(IOCommType))
+		for _, invalid := range []InitCodeMsg{
+			{Code: upperWasm[:len(upperWasm)-1], Runtime: runtime.WASI},
+			{Code: upperWasm, Runtime: runtime.WASI, InitMsgBase: InitMsgBase{CommTypeX: PushCommType}},
+			{Code: upperWasm, Runtime: runtime.WASI, Deps: []byte("numpy")},
+		} {
+			Expect(invalid.Validate()).To(HaveOccurred())
+		}
+
+		module, err := wasm.Compile(upperWasm)
+		Expect(err).NotTo(HaveOccurred())
+		pod := newPod(corev1.Container{})
+		comm := newWasmComm(commArgs{
+			bootstraper: &etlBootstraper{t: tMock, pod: pod, xact: mock.NewXact(cmn.ActETLInline)},
+		}, module, 0, 0)
+		Expect(comm.CommType()).To(Equal(IOCommType))
+		r, err := comm.OfflineTransform(clusterBck, objName, "args", time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		b, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+		r.Close()
+		Expect(string(b)).To(Equal(strings.ToUpper(content)))
+		Expect(comm.InBytes()).To(BeEquivalentTo(len(content)))
+
+		// not enough memory
+		comm.memLimit = cos.KiB
+		_, err = comm.OfflineTransform(clusterBck, objName, "", time.Minute)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, wasm.ErrMemLimit)).To(BeTrue())
+		comm.lp.stop()
+	})
+
+	It("should abort transformations that exceed the output limit", func() {
+		msg := InitSpecMsg{InitMsgBase: InitMsgBase{OutputLimit: 4}}
+
+		// endless output
+		pod := newPod(corev1.Container{Name: "server", Command: []string{"yes"}})
+		lp, err := newLocalPod(pod, &cmn.ETLConf{Launcher: cmn.ETLLauncherProcess, WorkDir: tmpDir})
+		Expect(err).NotTo(HaveOccurred())
+		err = lp.start(true /*pipe*/, 0)
+		Expect(err).NotTo(HaveOccurred())
+		defer lp.stop()
+		comm := newIOComm(commArgs{
+			bootstraper: &etlBootstraper{t: tMock, msg: msg, pod: pod, xact: mock.NewXact(cmn.ActETLInline)},
+		}, lp)
+		started := time.Now()
+		_, err = comm.OfflineTransform(clusterBck, objName, "", time.Minute)
+		Expect(errors.Is(err, errOutputLimit)).To(BeTrue())
+		Expect(time.Since(started)).To(BeNumerically("<", 10*time.Second))
+	})
+
+	It("should abort WebAssembly transformations that exceed the output limit", func() {
+		if !wasm.Enabled {
+			Skip("WebAssembly runtime requires etlwasm build tag")
+		}
+		msg := InitSpecMsg{InitMsgBase: InitMsgBase{OutputLimit: 4}}
+		module, err := wasm.Compile(upperWasm)
+		Expect(err).NotTo(HaveOccurred())
+		wc := newWasmComm(commArgs{
+			bootstraper: &etlBootstraper{t: tMock, msg: msg, pod: newPod(corev1.Container{}),
+				xact: mock.NewXact(cmn.ActETLInline)},
+		}, module, 0, 0)
+		defer wc.lp.stop()
+		_, err = wc.OfflineTransform(clusterBck, objName, "", time.Minute)
+		Expect(errors.Is(err, errOutputLimit)).To(BeTrue())
+
+		// within the limit
+		wc.outputLimit = int64(len(content))
+		r, err := wc.OfflineTransform(clusterBck, objName, "", time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		b, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+		r.Close()
+		Expect(string(b)).To(Equal(strings.ToUpper(content)))
+	})
+
+	It("should cache the transformed objects", func() {
+		_ = fs.CSM.Reg(fs.WorkfileType, &fs.WorkfileContentResolver{})
+		_ = fs.CSM.Reg(fs.ETLCacheType, &fs.ETLCacheContentResolver{})
+
+		pod := newPod(corev1.Container{Name: "server", Command: []string{"tr", "a-z", "A-Z"}})
+		lp, err := newLocalPod(pod, &cmn.ETLConf{Launcher: cmn.ETLLauncherProcess, WorkDir: tmpDir})
+		Expect(err).NotTo(HaveOccurred())
+		err = lp.start(true /*pipe*/, 0)
+		Expect(err).NotTo(HaveOccurred())
+		defer lp.stop()
+		comm := newIOComm(commArgs{
+			bootstraper: &etlBootstraper{
+				t:    tMock,
+				msg:  InitSpecMsg{InitMsgBase: InitMsgBase{IDX: "local-etl", Cache: true}},
+				pod:  pod,
+				xact: mock.NewXact(cmn.ActETLInline),
+			},
+		}, lp)
+		Expect(comm.CacheID()).NotTo(BeEmpty())
+
+		// not cached: neither version nor checksum
+		lom := &cluster.LOM{ObjName: objName}
+		Expect(lom.Init(clusterBck.Bck)).NotTo(HaveOccurred())
+		Expect(lom.Load(false, false)).NotTo(HaveOccurred())
+		_, ok := cacheKey(comm.CacheID(), "", lom)
+		Expect(ok).To(BeFalse())
+		lom.SetVersion("1")
+		Expect(lom.Persist()).NotTo(HaveOccurred())
+		lom.Uncache(true)
+
+		var (
+			statsT    = mock.NewStatsTracker()
+			transform = func(args string) string {
+				w := httptest.NewRecorder()
+				err := Transform(tMock, statsT, w, nil, comm, clusterBck, objName, args)
+				Expect(err).NotTo(HaveOccurred())
+				return w.Body.String()
+			}
+			cached = func() []string {
+				fqn := lom.MpathInfo().MakePathFQN(clusterBck.Bck, fs.ETLCacheType, objName)
+				fqns, err := filepath.Glob(fqn + ".*")
+				Expect(err).NotTo(HaveOccurred())
+				return fqns
+			}
+		)
+		// miss, hit, and miss (different arguments)
+		Expect(transform("")).To(Equal(strings.ToUpper(content)))
+		Expect(transform("")).To(Equal(strings.ToUpper(content)))
+		Expect(comm.InBytes()).To(BeEquivalentTo(len(content)))
+		Expect(transform("args")).To(Equal(strings.ToUpper(content)))
+		Expect(comm.InBytes()).To(BeEquivalentTo(2 * len(content)))
+		Expect(cached()).To(HaveLen(2))
+
+		// modified source does not match
+		newContent := "transform me again"
+		Expect(lom.Load(false, false)).NotTo(HaveOccurred())
+		Expect(os.WriteFile(lom.FQN, []byte(newContent), cos.PermRWR)).NotTo(HaveOccurred())
+		lom.SetSize(int64(len(newContent)))
+		lom.SetVersion("2")
+		Expect(lom.Persist()).NotTo(HaveOccurred())
+		lom.Uncache(true)
+		Expect(transform("")).To(Equal(strings.ToUpper(newContent)))
+		Expect(comm.InBytes()).To(BeEquivalentTo(2*len(content) + len(newContent)))
+
+		// encrypted at rest: not cached
+		Expect(lom.Load(false, false)).NotTo(HaveOccurred())
+		lom.SetCustomKey(cmn.SSEObjMD, "md")
+		Expect(lom.Persist()).NotTo(HaveOccurred())
+		lom.Uncache(true)
+		_, ok = cacheKey(comm.CacheID(), "", lom)
+		Expect(ok).To(BeFalse())
+		lom.ObjAttrs().DelCustomKeys(cmn.SSEObjMD)
+		Expect(lom.Persist()).NotTo(HaveOccurred())
+		lom.Uncache(true)
+
+		// removed along with the source
+		lom.Lock(true)
+		Expect(lom.Remove()).NotTo(HaveOccurred())
+		lom.Unlock(true)
+		Expect(cached()).To(BeEmpty())
+	})
+
+	It("should restart the transformer", func() {
+		pod := newPod(corev1.Container{
+			Name:    "server",
+			Command: []string{os.Args[0], "-test.run=^TestLocalTransformer$"},
+			Env:     []corev1.EnvVar{{Name: localTransformerEnv, Value: "1"}},
+			ReadinessProbe: &corev1.Probe{
+				Handler:       corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/health"}},
+				PeriodSeconds: 1,
+			},
+		})
+		lp, err := newLocalPod(pod, &cmn.ETLConf{WorkDir: tmpDir})
+		Expect(err).NotTo(HaveOccurred())
+		lp.host = "127.0.0.1"
+		err = lp.start(false /*pipe*/, 0)
+		Expect(err).NotTo(HaveOccurred())
+		defer lp.stop()
+		go lp.monitor()
+
+		transform := func() string {
+			resp, err := http.Post(lp.uri, cmn.ContentBinary, strings.NewReader(content))
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+			b, err := io.ReadAll(resp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			return string(b)
+		}
+		Expect(transform()).To(Equal(strings.ToUpper(content)))
+
+		// crash
+		lp.kill()
+		Eventually(lp.restarts.Load, 10*time.Second, 100*time.Millisecond).Should(BeEquivalentTo(1))
+		Eventually(lp.check, 10*time.Second, 100*time.Millisecond).Should(Succeed())
+		Expect(transform()).To(Equal(strings.ToUpper(content)))
+
+		cpu, mem, err := lp.health()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cpu).To(BeNumerically(">=", 0))
+		Expect(mem).To(BeNumerically(">", 0))
+	})
+})
@@ -0,0 +1,85 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// ETL pipeline (cmn.ETLPipeline) is executed by streaming the output of each stage into
+// the next one - no intermediate results are stored. The first stage transforms the object
+// (any communication type), while the subsequent ones must accept the data pushed
+// to them (hpush:// or io://) - see Communicator.TransformStream.
+
+// environment variable that carries ETL arguments to io:// transformers (see also cmn.HdrETLArgs)
+const argsEnvName = "AIS_ETL_ARGS"
+
+type pipeline struct {
+	stages cmn.ETLPipeline
+	comms  []Communicator
+}
+
+func newPipeline(stages cmn.ETLPipeline, lsnode *cluster.Snode) (*pipeline, error) {
+	p := &pipeline{stages: stages, comms: make([]Communicator, 0, len(stages))}
+	for i, stage := range stages {
+		comm, err := GetCommunicator(stage.ID, lsnode)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && comm.CommType() != PushCommType && comm.CommType() != IOCommType {
+			return nil, fmt.Errorf("%s: ETL %q (%s) can only be the first stage of ETL pipeline %s",
+				lsnode, stage.ID, comm.CommType(), stages)
+		}
+		p.comms = append(p.comms, comm)
+	}
+	return p, nil
+}
+
+func (p *pipeline) transform(bck *cluster.Bck, objName string, timeout time.Duration) (r cos.ReadCloseSizer,
+	err error) {
+	if r, err = p.comms[0].OfflineTransform(bck, objName, p.stages[0].Args, timeout); err != nil {
+		return
+	}
+	for i := 1; i < len(p.comms); i++ {
+		if r, err = p.comms[i].TransformStream(r, p.stages[i].Args, timeout); err != nil {
+			return nil, fmt.Errorf("ETL pipeline %s: stage #%d (%q): %v", p.stages, i+1, p.stages[i].ID, err)
+		}
+	}
+	return
+}
+
+// TransformPipeline performs on-the-fly transformation of the object
+// by the ETL pipeline (GET with cmn.URLParamETLPipeline).
+func TransformPipeline(t cluster.Target, w http.ResponseWriter, stages cmn.ETLPipeline, bck *cluster.Bck,
+	objName string) error {
+	p, err := newPipeline(stages, t.Snode())
+	if err != nil {
+		return err
+	}
+	r, err := p.transform(bck, objName, 0 /*timeout*/)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	size := r.Size()
+	if size >= 0 {
+		w.Header().Set(cmn.HdrContentLength, strconv.FormatInt(size, 10))
+	} else {
+		size = memsys.DefaultBufSize
+	}
+	buf, slab := t.PageMM().AllocSize(size)
+	_, err = io.CopyBuffer(w, r, buf)
+	slab.Free(buf)
+	return err
+}
@@ -21,6 +21,7 @@ const (
 
 	commTypeAnnotation    = "communication_type"
 	waitTimeoutAnnotation = "wait_timeout"
+	cacheAnnotation       = "cache"
 )
 
 type etlBootstraper struct {
@@ -77,6 +78,9 @@ func ValidateSpec(spec []byte) (msg InitSpecMsg, err error) {
 	if msg.WaitTimeout, err = podTransformTimeout(errCtx, pod); err != nil {
 		return msg, err
 	}
+	if msg.Cache, err = podTransformCache(errCtx, pod); err != nil {
+		return msg, err
+	}
 
 	// Check pod specification constraints.
 	if len(pod.Spec.Containers) != 1 {
@@ -187,3 +191,14 @@ func podTransformTimeout(errCtx *cmn.ETLErrorContext, pod *corev1.Pod) (cos.Dura
 	}
 	return cos.Duration(v), nil
 }
+
+func podTransformCache(errCtx *cmn.ETLErrorContext, pod *corev1.Pod) (bool, error) {
+	if pod.Annotations == nil || pod.Annotations[cacheAnnotation] == "" {
+		return false, nil
+	}
+	v, err := cos.ParseBool(pod.Annotations[cacheAnnotation])
+	if err != nil {
+		return false, cmn.NewErrETL(errCtx, err.Error()).WithPodName(pod.Name)
+	}
+	return v, nil
+}
@@ -11,6 +11,10 @@ const (
 	Python36  = "python3.6"
 	Python38  = "python3.8"
 	Python310 = "python3.10"
+
+	// WebAssembly module (WASI command) executed in-process by targets - not a pod
+	// runtime and, therefore, not in `Runtimes` (see etl/wasm.go)
+	WASI = "wasi"
 )
 
 var Runtimes map[string]runtime
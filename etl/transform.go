@@ -145,6 +145,12 @@ func (e *Aborter) ListenSmapChanged() {
 }
 
 func InitSpec(t cluster.Target, msg InitSpecMsg, opts StartOpts) (err error) {
+	if cmn.GCO.Get().ETL.IsLocal() {
+		return startLocal(t, msg, opts)
+	}
+	if err = k8s.Detect(); err != nil {
+		return
+	}
 	errCtx, podName, svcName, err := tryStart(t, msg, opts)
 	if err != nil {
 		glog.Warning(cmn.NewErrETL(errCtx, "Performing cleanup after unsuccessful Start"))
@@ -157,6 +163,10 @@ func InitSpec(t cluster.Target, msg InitSpecMsg, opts StartOpts) (err error) {
 }
 
 func InitCode(t cluster.Target, msg InitCodeMsg) error {
+	if msg.Runtime == runtime.WASI {
+		return initWASI(t, msg)
+	}
+
 	// Initialize runtime.
 	r, exists := runtime.Runtimes[msg.Runtime]
 	cos.Assert(exists) // Runtime should be checked in proxy during validation.
@@ -183,6 +193,7 @@ func InitCode(t cluster.Target, msg InitCodeMsg) error {
 		InitMsgBase: InitMsgBase{
 			IDX:       msg.IDX,
 			CommTypeX: msg.CommTypeX,
+			Cache:     msg.Cache,
 		},
 		Spec:        []byte(podSpec),
 		WaitTimeout: msg.WaitTimeout,
@@ -376,8 +387,11 @@ func Stop(t cluster.Target, id string) error {
 	errCtx.PodName = c.PodName()
 	errCtx.SvcName = c.SvcName()
 
-	if err := cleanupEntities(errCtx, c.PodName(), c.SvcName()); err != nil {
-		return err
+	// (local communicators stop their transformers themselves - see below)
+	if _, local := c.(localCommunicator); !local {
+		if err := cleanupEntities(errCtx, c.PodName(), c.SvcName()); err != nil {
+			return err
+		}
 	}
 
 	if c := reg.removeByUUID(id); c != nil {
@@ -391,11 +405,6 @@ func Stop(t cluster.Target, id string) error {
 
 // StopAll deletes all running ETLs.
 func StopAll(t cluster.Target) {
-	// Skip if K8s isn't even available.
-	if k8s.Detect() != nil {
-		return
-	}
-
 	for _, e := range List() {
 		if err := Stop(t, e.ID); err != nil {
 			glog.Error(err)
@@ -418,6 +427,9 @@ func PodLogs(t cluster.Target, transformID string) (logs PodLogsMsg, err error)
 	if err != nil {
 		return logs, err
 	}
+	if lc, ok := c.(localCommunicator); ok {
+		return PodLogsMsg{TargetID: t.SID(), Logs: lc.localPod().logs.Bytes()}, nil
+	}
 	client, err := k8s.GetClient()
 	if err != nil {
 		return logs, err
@@ -440,6 +452,13 @@ func PodHealth(t cluster.Target, etlID string) (stats *PodHealthMsg, err error)
 	if c, err = GetCommunicator(etlID, t.Snode()); err != nil {
 		return
 	}
+	if lc, ok := c.(localCommunicator); ok {
+		stats = &PodHealthMsg{TargetID: t.SID()}
+		if stats.CPU, stats.Mem, err = lc.localPod().health(); err != nil {
+			return nil, err
+		}
+		return
+	}
 	if client, err = k8s.GetClient(); err != nil {
 		return
 	}
@@ -0,0 +1,202 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/k8s"
+	"github.com/NVIDIA/aistore/etl/wasm"
+	"github.com/NVIDIA/aistore/memsys"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WebAssembly ETL runtime - init-code with `runtime = "wasi"` (experimental: requires
+// `etlwasm` build tag - see etl/wasm/api.go).
+//
+// The code is a WebAssembly module built as WASI command (e.g., with
+// `GOOS=wasip1 GOARCH=wasm go build` or `cargo build --target wasm32-wasi`) that
+// reads the object from its stdin and writes the transformed object to its stdout.
+// Targets execute the module in-process (see package etl/wasm) - there are no pods,
+// and neither K8s nor the local runtime is required. The only communication type
+// is io://: each transformation runs a new (sandboxed) instance of the module with:
+// * ETL arguments (if any) passed as its first argument and as `AIS_ETL_ARGS`;
+// * linear memory limited by `InitCodeMsg.MemLimit` and execution time by `CPULimit`;
+// * output (buffered in memory) limited by `OutputLimit`;
+// * stderr going to the ETL logs.
+
+const (
+	wasmDfltMemLimit = 256 * cos.MiB
+	wasmDfltCPULimit = time.Minute
+)
+
+type (
+	// io:// communicator that runs WebAssembly module for each object
+	wasmComm struct {
+		baseComm
+		mem         *memsys.MMSA
+		lp          *localPod // (logs only)
+		module      *wasm.Module
+		memLimit    int64
+		cpuLimit    time.Duration
+		outputLimit int64
+	}
+)
+
+// interface guard
+var _ localCommunicator = (*wasmComm)(nil)
+
+func initWASI(t cluster.Target, msg InitCodeMsg) (err error) {
+	var (
+		module *wasm.Module
+		name   = k8s.CleanName(msg.IDX)
+		errCtx = &cmn.ETLErrorContext{TID: t.SID(), UUID: msg.IDX, ETLName: name}
+		b      = &etlBootstraper{
+			errCtx: errCtx,
+			t:      t,
+			// (the cache ID is computed from the "spec", i.e. the module)
+			msg:             InitSpecMsg{InitMsgBase: msg.InitMsgBase, Spec: msg.Code},
+			originalPodName: name,
+			pod:             &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: k8s.CleanName(name + "-" + t.SID())}},
+		}
+	)
+	errCtx.PodName = b.pod.Name
+	if module, err = wasm.Compile(msg.Code); err != nil {
+		return cmn.NewErrETL(errCtx, err.Error())
+	}
+
+	b.setupXaction()
+	c := newWasmComm(commArgs{listener: newAborter(t, msg.IDX), bootstraper: b}, module, msg.MemLimit,
+		time.Duration(msg.CPULimit))
+	if err = reg.put(msg.IDX, c); err != nil {
+		c.Stop()
+		return
+	}
+	t.Sowner().Listeners().Reg(c)
+	return
+}
+
+//////////////
+// wasmComm //
+//////////////
+
+func newWasmComm(args commArgs, module *wasm.Module, memLimit int64, cpuLimit time.Duration) *wasmComm {
+	bc := newBaseComm(args)
+	bc.commType = IOCommType
+	wc := &wasmComm{
+		baseComm:    bc,
+		mem:         args.bootstraper.t.PageMM(),
+		lp:          &localPod{name: bc.podName, stopCh: cos.NewStopCh()},
+		module:      module,
+		memLimit:    memLimit,
+		cpuLimit:    cpuLimit,
+		outputLimit: outputLimit(&args.bootstraper.msg.InitMsgBase),
+	}
+	if wc.memLimit == 0 {
+		wc.memLimit = wasmDfltMemLimit
+	}
+	if wc.cpuLimit == 0 {
+		wc.cpuLimit = wasmDfltCPULimit
+	}
+	return wc
+}
+
+func (wc *wasmComm) localPod() *localPod { return wc.lp }
+
+func (wc *wasmComm) Stop() {
+	wc.lp.stop() // (terminates running instances, if any)
+	wc.baseComm.Stop()
+}
+
+func (wc *wasmComm) doRequest(bck *cluster.Bck, objName, args string, timeout time.Duration) (cos.ReadCloseSizer,
+	error) {
+	return wc.pipeObj(bck, objName, func(r io.Reader, size int64) (cos.ReadCloseSizer, error) {
+		return wc.run(r, size, args, timeout)
+	})
+}
+
+// runs the module with the data as its stdin; same as ioComm, the output is buffered
+func (wc *wasmComm) run(r io.Reader, size int64, args string, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if timeout != 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+	go func() {
+		select {
+		case <-wc.lp.stopCh.Listen():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	var (
+		stderr logBuf
+		sgl    = wc.mem.NewSGL(cos.MaxI64(size, 0))
+		stdout = &limitWriter{w: sgl, cancel: cancel, limit: wc.outputLimit}
+		cfg    = &wasm.Config{
+			Stdin:    r,
+			Stdout:   stdout,
+			Stderr:   io.MultiWriter(&stderr, &wc.lp.logs),
+			Args:     []string{wc.name},
+			MemLimit: wc.memLimit,
+			CPULimit: wc.cpuLimit,
+		}
+	)
+	if args != "" {
+		cfg.Args = append(cfg.Args, args)
+		cfg.Env = []string{argsEnvName + "=" + args}
+	}
+	if err := wc.module.Run(ctx, cfg); err != nil || stdout.exceeded {
+		sgl.Free()
+		if stdout.exceeded {
+			return nil, fmt.Errorf("%s: %w (%d bytes)%s", wc.lp, errOutputLimit, stdout.limit, stderr.tail())
+		}
+		return nil, fmt.Errorf("%s: %w%s", wc.lp, err, stderr.tail())
+	}
+	return cos.NewReaderWithArgs(cos.ReaderArgs{
+		R:      sgl,
+		Size:   sgl.Size(),
+		ReadCb: func(i int, err error) { wc.xact.OutObjsAdd(1, int64(i)) },
+		DeferCb: func() {
+			sgl.Free()
+			wc.xact.InObjsAdd(1, cos.MaxI64(size, 0))
+		},
+	}), nil
+}
+
+func (wc *wasmComm) OnlineTransform(w http.ResponseWriter, _ *http.Request, bck *cluster.Bck, objName, args string) error {
+	r, err := wc.doRequest(bck, objName, args, 0 /*timeout*/)
+	if err != nil {
+		return err
+	}
+	return writeTransformed(w, r)
+}
+
+func (wc *wasmComm) OfflineTransform(bck *cluster.Bck, objName, args string, timeout time.Duration) (cos.ReadCloseSizer,
+	error) {
+	return wc.doRequest(bck, objName, args, timeout)
+}
+
+func (wc *wasmComm) TransformStream(r cos.ReadCloseSizer, args string, timeout time.Duration) (cos.ReadCloseSizer,
+	error) {
+	defer r.Close()
+	if wc.xact.Aborted() {
+		return nil, cmn.NewErrAborted(wc.xact.Name(), "try-request", nil)
+	}
+	return wc.run(r, r.Size(), args, timeout)
+}
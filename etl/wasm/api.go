@@ -0,0 +1,47 @@
+// Package wasm provides sandboxed, in-process execution of WebAssembly (WASI) ETL transformers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package wasm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// EXPERIMENTAL: the WebAssembly interpreter (see wasm.go) is built only with `etlwasm`
+// build tag, e.g.: `TAGS=etlwasm make node`. Without it, `Compile` fails with ErrNotBuilt
+// and, therefore, so does the initialization of ETLs with `wasi` runtime.
+
+type (
+	// Config defines a single run of the module.
+	Config struct {
+		Stdin  io.Reader
+		Stdout io.Writer
+		Stderr io.Writer // (optional)
+		Args   []string  // including the program name (args[0])
+		Env    []string  // "name=value"
+
+		// Max size of the linear memory, in bytes (0: the maximum size declared by the module,
+		// or 4GiB). The module cannot be run if its initial memory does not fit.
+		MemLimit int64
+		// Max time the module is executed, excluding the time spent reading stdin,
+		// writing stdout and stderr, and sleeping (0: unlimited).
+		CPULimit time.Duration
+	}
+
+	// ExitError is returned when the module exits (`proc_exit`) with non-zero status.
+	ExitError struct {
+		Code uint32
+	}
+)
+
+var (
+	ErrCPULimit = errors.New("CPU time limit exceeded")
+	ErrMemLimit = errors.New("memory limit exceeded")
+	ErrNotBuilt = errors.New("WebAssembly runtime is experimental and not included in this build (see `etlwasm` build tag)")
+)
+
+func (e *ExitError) Error() string { return fmt.Sprintf("exit status %d", e.Code) }
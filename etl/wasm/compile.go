@@ -0,0 +1,479 @@
+//go:build etlwasm
+// +build etlwasm
+
+// Package wasm provides sandboxed, in-process execution of WebAssembly (WASI) ETL transformers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package wasm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Function bodies are compiled into a flat sequence of instructions (`instr`), where
+// structured control flow (block, loop, if, br*) is replaced with jumps to the resolved
+// positions along with the operand stack heights to unwind to - see `brTarget`.
+// The opcodes are the WebAssembly ones, except:
+// * 0x04 (if)   - jump to `a` if the condition is zero;
+// * 0x05 (else) - unconditional jump to `a`;
+// * 0x0c (br), 0x0d (br_if) - jump to `a`, keeping the top `imm` values at height `b`;
+// * 0x0e (br_table) - `a` is the index of the table (`function.tables`);
+// * 0x0f (return) - return top `imm` values;
+// * 0x1c (select t) becomes 0x1b (select);
+// * 0xfc-prefixed ones become 0x100 + (sub-opcode);
+// block, loop, end, nop, and no-op conversions (e.g., reinterpretations) are not emitted.
+// In addition, the most frequent sequences of instructions are fused into
+// superinstructions (opcodes 0xe0 and above) - see `fuse`.
+
+const (
+	opBlock = 0x02
+	opLoop  = 0x03
+	opIf    = 0x04
+	opElse  = 0x05
+	opEnd   = 0x0b
+
+	opBr      = 0x0c
+	opBrIf    = 0x0d
+	opBrTable = 0x0e
+	opReturn  = 0x0f
+
+	opPrefixFC = 0x100
+
+	// superinstructions
+	opI64AddConst         = 0xe0 // i64.const imm; i64.add
+	opLocalI64AddConst    = 0xe1 // local.get a; i64.const imm; i64.add
+	opLocalI64AddConstSet = 0xe2 // local.get a; i64.const imm; i64.add; local.set b
+	opLocalGet2           = 0xe3 // local.get a; local.get b
+	opIfNot               = 0xe4 // i32.eqz; if (jump to `a` if the condition is non-zero)
+	opConstSet            = 0xe5 // i32.const imm; local.set a (or i64.const)
+	opLocalBrTable        = 0xe6 // local.get b; br_table a
+)
+
+type (
+	instr struct {
+		op  uint16
+		a   uint32
+		b   uint32
+		imm uint64
+	}
+	brTarget struct {
+		pc, height, arity uint32
+	}
+
+	ctlFrame struct {
+		kind    byte   // opBlock, opLoop, or opIf (the function body is a block)
+		height  uint32 // operand stack height at the entry (excluding params)
+		params  uint32
+		results uint32
+		start   uint32   // (loop)
+		ifPC    int      // (if) position of opIf to be patched with `else` (-1: patched)
+		fixups  []int    // positions of jumps to be patched with `end`
+		tfixups [][2]int // br_table entries (table, index) to be patched with `end`
+	}
+	compiler struct {
+		m      *Module
+		f      *function
+		r      *reader
+		ctl    []ctlFrame
+		height uint32
+		// unreachable code (after br, br_table, return, or unreachable) is not emitted
+		dead      bool
+		deadDepth int
+		// the last position that is a branch target (cannot be fused with the preceding instruction)
+		lastTarget int
+	}
+)
+
+func (m *Module) compile(f *function, r *reader) (err error) {
+	var nlocals uint64
+	for n := r.u32(); n > 0; n-- {
+		cnt := r.u32()
+		r.valType()
+		if nlocals += uint64(cnt); nlocals > maxLocals {
+			return errors.New("too many locals")
+		}
+	}
+	f.nlocals = int(nlocals)
+	c := &compiler{m: m, f: f, r: r}
+	c.ctl = append(c.ctl, ctlFrame{kind: opBlock, results: uint32(len(f.typ.results)), ifPC: -1})
+	for len(c.ctl) > 0 {
+		if err = c.next(); err != nil {
+			return
+		}
+	}
+	if r.off != len(r.b) {
+		return errors.New("unexpected instructions after the end of function")
+	}
+	return nil
+}
+
+func (c *compiler) emit(op uint16, a, b uint32, imm uint64) {
+	if !c.dead && !c.fuse(op, a, b, imm) {
+		c.f.code = append(c.f.code, instr{op: op, a: a, b: b, imm: imm})
+	}
+}
+
+func (c *compiler) setTarget() { c.lastTarget = len(c.f.code) }
+
+// peephole optimization: fuses the instruction with the preceding one(s)
+func (c *compiler) fuse(op uint16, a, _ uint32, imm uint64) bool {
+	var (
+		code = c.f.code
+		n    = len(code)
+	)
+	if n == 0 || c.lastTarget >= n {
+		return false
+	}
+	last := &code[n-1]
+	switch {
+	case op == 0x7c && last.op == 0x42:
+		last.op = opI64AddConst
+		if prev := n - 2; prev >= 0 && c.lastTarget < n-1 && code[prev].op == 0x20 {
+			code[prev].op, code[prev].imm = opLocalI64AddConst, last.imm
+			c.f.code = code[:n-1]
+		}
+	case op == 0x21 && last.op == opLocalI64AddConst:
+		last.op, last.b = opLocalI64AddConstSet, a
+	case op == 0x21 && (last.op == 0x41 || last.op == 0x42):
+		last.op, last.a = opConstSet, a
+	case op >= 0x28 && op <= 0x35 && last.op == 0xa7:
+		// i32.wrap_i64 is redundant - the address is i32 anyway
+		*last = instr{op: op, imm: imm}
+	case op == 0x20 && last.op == 0x20:
+		last.op, last.b = opLocalGet2, a
+	case op == opIf && last.op == 0x45:
+		*last = instr{op: opIfNot}
+	case op == opBrTable && last.op == 0x20:
+		last.op, last.a, last.b = opLocalBrTable, a, last.a
+	default:
+		return false
+	}
+	return true
+}
+
+func (c *compiler) pop(n uint32) {
+	if c.dead {
+		return
+	}
+	if c.height < n {
+		panic(errors.New("operand stack underflow"))
+	}
+	c.height -= n
+}
+
+func (c *compiler) push(n uint32) {
+	if c.dead {
+		return
+	}
+	c.height += n
+	if int(c.height) > c.f.maxStack {
+		c.f.maxStack = int(c.height)
+	}
+}
+
+func (c *compiler) setDead() {
+	c.dead, c.deadDepth = true, 0
+}
+
+func (c *compiler) blockType() (params, results uint32) {
+	switch b := c.r.b[c.r.off]; b {
+	case 0x40:
+		c.r.off++
+		return 0, 0
+	case valI32, valI64, valF32, valF64, valFuncref, valExternref:
+		c.r.off++
+		return 0, 1
+	default:
+		idx := c.r.sleb(33)
+		if idx < 0 || idx >= int64(len(c.m.types)) {
+			panic(fmt.Errorf("invalid block type %d", idx))
+		}
+		ft := &c.m.types[idx]
+		return uint32(len(ft.params)), uint32(len(ft.results))
+	}
+}
+
+func (c *compiler) frame(depth uint32) *ctlFrame {
+	if int(depth) >= len(c.ctl) {
+		panic(fmt.Errorf("invalid branch depth %d", depth))
+	}
+	return &c.ctl[len(c.ctl)-1-int(depth)]
+}
+
+// returns the branch target; unless it is loop, the position gets patched at the end of block
+func (c *compiler) target(depth uint32, fixup func(fr *ctlFrame)) brTarget {
+	fr := c.frame(depth)
+	if fr.kind == opLoop {
+		return brTarget{pc: fr.start, height: fr.height, arity: fr.params}
+	}
+	fixup(fr)
+	return brTarget{height: fr.height, arity: fr.results}
+}
+
+func (c *compiler) patch(fr *ctlFrame, pc uint32) {
+	for _, i := range fr.fixups {
+		c.f.code[i].a = pc
+	}
+	for _, tf := range fr.tfixups {
+		c.f.tables[tf[0]][tf[1]].pc = pc
+	}
+}
+
+func (c *compiler) next() error {
+	var (
+		r  = c.r
+		op = uint16(r.byte())
+	)
+	switch {
+	case op == 0x00: // unreachable
+		c.emit(op, 0, 0, 0)
+		c.setDead()
+	case op == 0x01: // nop
+	case op == opBlock || op == opLoop || op == opIf:
+		params, results := c.blockType()
+		if c.dead {
+			c.deadDepth++
+			break
+		}
+		if op == opIf {
+			c.pop(1)
+		}
+		c.pop(params)
+		fr := ctlFrame{kind: byte(op), height: c.height, params: params, results: results, ifPC: -1}
+		c.push(params)
+		switch op {
+		case opLoop:
+			fr.start = uint32(len(c.f.code))
+			c.setTarget()
+		case opIf:
+			c.emit(opIf, 0, 0, 0)
+			fr.ifPC = len(c.f.code) - 1
+		}
+		c.ctl = append(c.ctl, fr)
+	case op == opElse:
+		if c.dead && c.deadDepth > 0 {
+			break
+		}
+		fr := &c.ctl[len(c.ctl)-1]
+		if fr.kind != opIf || fr.ifPC < 0 {
+			return errors.New("unexpected else")
+		}
+		c.dead = false
+		fr.fixups = append(fr.fixups, len(c.f.code))
+		c.emit(opElse, 0, 0, 0)
+		c.f.code[fr.ifPC].a = uint32(len(c.f.code))
+		c.setTarget()
+		fr.ifPC = -1
+		c.height = fr.height + fr.params
+	case op == opEnd:
+		if c.dead && c.deadDepth > 0 {
+			c.deadDepth--
+			break
+		}
+		fr := c.ctl[len(c.ctl)-1]
+		c.ctl = c.ctl[:len(c.ctl)-1]
+		c.dead = false
+		if fr.ifPC >= 0 {
+			c.f.code[fr.ifPC].a = uint32(len(c.f.code))
+		}
+		c.patch(&fr, uint32(len(c.f.code)))
+		c.setTarget()
+		c.height = fr.height
+		c.push(fr.results)
+		if len(c.ctl) == 0 {
+			c.emit(opReturn, 0, 0, uint64(fr.results))
+		}
+	case op == opBr || op == opBrIf:
+		depth := r.u32()
+		if c.dead {
+			break
+		}
+		if op == opBrIf {
+			c.pop(1)
+		}
+		pos := len(c.f.code)
+		t := c.target(depth, func(fr *ctlFrame) { fr.fixups = append(fr.fixups, pos) })
+		c.emit(op, t.pc, t.height, uint64(t.arity))
+		if op == opBr {
+			c.setDead()
+		}
+	case op == opBrTable:
+		depths := make([]uint32, r.u32()+1)
+		for i := range depths {
+			depths[i] = r.u32()
+		}
+		if c.dead {
+			break
+		}
+		c.pop(1)
+		var (
+			tidx  = len(c.f.tables)
+			table = make([]brTarget, len(depths))
+		)
+		c.f.tables = append(c.f.tables, table)
+		for i, depth := range depths {
+			i := i
+			table[i] = c.target(depth, func(fr *ctlFrame) { fr.tfixups = append(fr.tfixups, [2]int{tidx, i}) })
+		}
+		c.emit(op, uint32(tidx), 0, 0)
+		c.setDead()
+	case op == opReturn:
+		c.emit(op, 0, 0, uint64(len(c.f.typ.results)))
+		c.setDead()
+	case op == 0x10: // call
+		idx := r.u32()
+		if int(idx) >= len(c.m.funcs) {
+			return fmt.Errorf("invalid function %d", idx)
+		}
+		ft := c.m.funcs[idx].typ
+		c.pop(uint32(len(ft.params)))
+		c.push(uint32(len(ft.results)))
+		c.emit(op, idx, uint32(len(ft.params)), uint64(len(ft.results)))
+	case op == 0x11: // call_indirect
+		tidx, table := r.u32(), r.u32()
+		ft, err := c.m.funcType(tidx)
+		if err != nil {
+			return err
+		}
+		if table != 0 || c.m.table == nil {
+			return fmt.Errorf("call_indirect: invalid table %d", table)
+		}
+		c.pop(1 + uint32(len(ft.params)))
+		c.push(uint32(len(ft.results)))
+		c.emit(op, tidx, 0, 0)
+	case op == 0x1a: // drop
+		c.pop(1)
+		c.emit(op, 0, 0, 0)
+	case op == 0x1b || op == 0x1c: // select
+		if op == 0x1c {
+			r.valTypes()
+		}
+		c.pop(2)
+		c.emit(0x1b, 0, 0, 0)
+	case op >= 0x20 && op <= 0x24: // local.get, local.set, local.tee, global.get, global.set
+		idx := r.u32()
+		switch op {
+		case 0x20, 0x21, 0x22:
+			if int(idx) >= len(c.f.typ.params)+c.f.nlocals {
+				return fmt.Errorf("invalid local %d", idx)
+			}
+		default:
+			if int(idx) >= len(c.m.globals) {
+				return fmt.Errorf("invalid global %d", idx)
+			}
+		}
+		switch op {
+		case 0x20, 0x23:
+			c.push(1)
+		case 0x21, 0x24:
+			c.pop(1)
+		}
+		c.emit(op, idx, 0, 0)
+	case op >= 0x28 && op <= 0x3e: // load and store
+		if c.m.mem == nil {
+			return errors.New("memory access without memory")
+		}
+		r.u32() // alignment
+		offset := r.u32()
+		if op >= 0x36 {
+			c.pop(2)
+		}
+		c.emit(op, 0, 0, uint64(offset))
+	case op == 0x3f || op == 0x40: // memory.size, memory.grow
+		if r.byte() != 0 || c.m.mem == nil {
+			return errors.New("invalid memory")
+		}
+		if op == 0x3f {
+			c.push(1)
+		}
+		c.emit(op, 0, 0, 0)
+	case op == 0x41: // i32.const
+		c.push(1)
+		c.emit(op, 0, 0, uint64(uint32(r.s32())))
+	case op == 0x42: // i64.const
+		c.push(1)
+		c.emit(op, 0, 0, uint64(r.s64()))
+	case op == 0x43: // f32.const
+		c.push(1)
+		c.emit(op, 0, 0, uint64(binary.LittleEndian.Uint32(r.bytes(4))))
+	case op == 0x44: // f64.const
+		c.push(1)
+		c.emit(op, 0, 0, binary.LittleEndian.Uint64(r.bytes(8)))
+	case op == 0xad || (op >= 0xbc && op <= 0xbf):
+		// i64.extend_i32_u and reinterpretations: no-op (i32 and f32 are zero-extended)
+	case isUnary(op):
+		c.emit(op, 0, 0, 0)
+	case isBinary(op):
+		c.pop(1)
+		c.emit(op, 0, 0, 0)
+	case op == 0xfc:
+		return c.nextFC()
+	default:
+		return fmt.Errorf("unsupported instruction (opcode 0x%x)", op)
+	}
+	return nil
+}
+
+func (c *compiler) nextFC() error {
+	var (
+		r   = c.r
+		sub = r.u32()
+		op  = opPrefixFC + uint16(sub)
+	)
+	switch {
+	case sub <= 7: // *.trunc_sat_*
+		c.emit(op, 0, 0, 0)
+	case sub == 8: // memory.init
+		idx := r.u32()
+		if r.byte() != 0 || c.m.mem == nil {
+			return errors.New("invalid memory")
+		}
+		c.pop(3)
+		c.emit(op, idx, 0, 0)
+	case sub == 9: // data.drop
+		c.emit(op, r.u32(), 0, 0)
+	case sub == 10 || sub == 11: // memory.copy, memory.fill
+		if sub == 10 {
+			r.byte()
+		}
+		if r.byte() != 0 || c.m.mem == nil {
+			return errors.New("invalid memory")
+		}
+		c.pop(3)
+		c.emit(op, 0, 0, 0)
+	default:
+		return fmt.Errorf("unsupported instruction (opcode 0xfc %d)", sub)
+	}
+	return nil
+}
+
+// (pop 1, push 1)
+func isUnary(op uint16) bool {
+	switch {
+	case op == 0x45 || op == 0x50: // eqz
+		return true
+	case op >= 0x67 && op <= 0x69, op >= 0x79 && op <= 0x7b: // clz, ctz, popcnt
+		return true
+	case op >= 0x8b && op <= 0x91, op >= 0x99 && op <= 0x9f: // abs, neg, ceil, floor, trunc, nearest, sqrt
+		return true
+	case op >= 0xa7 && op <= 0xc4: // conversions and sign extensions
+		return true
+	}
+	return false
+}
+
+// (pop 2, push 1)
+func isBinary(op uint16) bool {
+	switch {
+	case op >= 0x46 && op <= 0x4f, op >= 0x51 && op <= 0x66: // comparisons
+		return true
+	case op >= 0x6a && op <= 0x78, op >= 0x7c && op <= 0x8a: // integer arithmetic
+		return true
+	case op >= 0x92 && op <= 0x98, op >= 0xa0 && op <= 0xa6: // float arithmetic
+		return true
+	}
+	return false
+}
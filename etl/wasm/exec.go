@@ -0,0 +1,1032 @@
+//go:build etlwasm
+// +build etlwasm
+
+// Package wasm provides sandboxed, in-process execution of WebAssembly (WASI) ETL transformers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package wasm
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+const (
+	maxCallDepth  = 10000
+	maxStackSize  = 4 * 1024 * 1024 // values
+	dfltStackSize = 16 * 1024
+
+	// number of branches and calls between the checks of CPU time and context
+	checkInterval = 16 * 1024
+)
+
+type (
+	hostFunc func(inst *instance, stack []uint64)
+
+	// instance of the module - a single run
+	instance struct {
+		m        *Module
+		cfg      *Config
+		ctx      context.Context
+		mem      []byte
+		maxPages uint32
+		globals  []uint64
+		table    []int32
+		dropped  []bool // data segments (data.drop)
+		stack    []uint64
+		depth    int
+
+		// limits
+		budget     int
+		started    time.Time
+		blocked    time.Duration // waiting for I/O
+		memLimited bool          // memory.grow has failed due to MemLimit
+	}
+)
+
+var (
+	errOutOfBounds = newTrap("out of bounds memory access")
+	errDivByZero   = newTrap("integer divide by zero")
+	errOverflow    = newTrap("integer overflow")
+	errConversion  = newTrap("invalid conversion to integer")
+)
+
+func (m *Module) instantiate(ctx context.Context, cfg *Config) (*instance, error) {
+	inst := &instance{
+		m:       m,
+		cfg:     cfg,
+		ctx:     ctx,
+		globals: make([]uint64, len(m.globals)),
+		dropped: make([]bool, len(m.datas)),
+		stack:   make([]uint64, dfltStackSize),
+		budget:  checkInterval,
+		started: time.Now(),
+	}
+	for i := range m.globals {
+		inst.globals[i] = inst.eval(m.globals[i].init)
+	}
+	if m.mem != nil {
+		inst.maxPages = m.mem.max
+		if cfg.MemLimit > 0 && uint64(cfg.MemLimit)/pageSize < uint64(inst.maxPages) {
+			inst.maxPages = uint32(cfg.MemLimit / pageSize)
+		}
+		if m.mem.min > inst.maxPages {
+			return nil, fmt.Errorf("%w: WebAssembly module requires at least %d bytes of memory (limit: %d)",
+				ErrMemLimit, uint64(m.mem.min)*pageSize, cfg.MemLimit)
+		}
+		inst.mem = make([]byte, uint64(m.mem.min)*pageSize)
+	}
+	if m.table != nil {
+		inst.table = make([]int32, m.table.min)
+		for i := range inst.table {
+			inst.table[i] = -1
+		}
+	}
+	for i := range m.elems {
+		seg := &m.elems[i]
+		if !seg.active {
+			continue
+		}
+		offset := uint64(uint32(inst.eval(seg.offset)))
+		if offset+uint64(len(seg.funcs)) > uint64(len(inst.table)) {
+			return nil, fmt.Errorf("element segment %d does not fit the table", i)
+		}
+		copy(inst.table[offset:], seg.funcs)
+	}
+	for i := range m.datas {
+		seg := &m.datas[i]
+		if !seg.active {
+			continue
+		}
+		offset := uint64(uint32(inst.eval(seg.offset)))
+		if offset+uint64(len(seg.data)) > uint64(len(inst.mem)) {
+			return nil, fmt.Errorf("data segment %d does not fit the memory", i)
+		}
+		copy(inst.mem[offset:], seg.data)
+		inst.dropped[i] = true
+	}
+	return inst, nil
+}
+
+func (inst *instance) eval(expr constExpr) uint64 {
+	if expr.op == 0x23 { // global.get
+		return inst.globals[expr.val]
+	}
+	return expr.val
+}
+
+// checks the limits periodically (see checkInterval)
+func (inst *instance) check() {
+	inst.budget = checkInterval
+	if err := inst.ctx.Err(); err != nil {
+		panic(trap{err})
+	}
+	if limit := inst.cfg.CPULimit; limit > 0 && time.Since(inst.started)-inst.blocked > limit {
+		panic(newTrap("%w (%v)", ErrCPULimit, limit))
+	}
+}
+
+func (inst *instance) growStack(size int) {
+	if size > maxStackSize {
+		panic(newTrap("call stack exhausted"))
+	}
+	stack := make([]uint64, 2*size)
+	copy(stack, inst.stack)
+	inst.stack = stack
+}
+
+func (inst *instance) growMem(delta uint32) int32 {
+	var (
+		pages = uint32(len(inst.mem) / pageSize)
+		size  = uint64(pages) + uint64(delta)
+	)
+	if size > uint64(inst.maxPages) {
+		if size <= uint64(inst.m.mem.max) {
+			inst.memLimited = true
+		}
+		return -1
+	}
+	if delta > 0 {
+		mem := make([]byte, size*pageSize)
+		copy(mem, inst.mem)
+		inst.mem = mem
+	}
+	return int32(pages)
+}
+
+// calls the function with the arguments at stack[base:]; the results replace the arguments
+func (inst *instance) invoke(f *function, base int) {
+	if inst.budget--; inst.budget <= 0 {
+		inst.check()
+	}
+	if f.host == nil {
+		inst.exec(f, base)
+		return
+	}
+	if base+1 > len(inst.stack) {
+		inst.growStack(base + 1)
+	}
+	f.host(inst, inst.stack[base:])
+}
+
+func (inst *instance) exec(f *function, base int) {
+	if inst.depth++; inst.depth > maxCallDepth {
+		panic(newTrap("call stack exhausted"))
+	}
+	var (
+		nparams = len(f.typ.params)
+		ob      = base + nparams + f.nlocals // operand stack base
+	)
+	if ob+f.maxStack > len(inst.stack) {
+		inst.growStack(ob + f.maxStack)
+	}
+	var (
+		s    = inst.stack
+		mem  = inst.mem
+		code = f.code
+		sp   = ob
+		pc   int
+	)
+	for i := base + nparams; i < ob; i++ {
+		s[i] = 0
+	}
+	for {
+		in := &code[pc]
+		pc++
+		switch in.op {
+		case 0x00: // unreachable
+			panic(newTrap("unreachable"))
+
+		// control
+
+		case opIf:
+			sp--
+			if uint32(s[sp]) == 0 {
+				pc = int(in.a)
+			}
+		case opElse:
+			pc = int(in.a)
+		case opBr:
+			copy(s[ob+int(in.b):], s[sp-int(in.imm):sp])
+			sp = ob + int(in.b) + int(in.imm)
+			pc = int(in.a)
+			if inst.budget--; inst.budget <= 0 {
+				inst.check()
+			}
+		case opBrIf:
+			sp--
+			if uint32(s[sp]) != 0 {
+				copy(s[ob+int(in.b):], s[sp-int(in.imm):sp])
+				sp = ob + int(in.b) + int(in.imm)
+				pc = int(in.a)
+				if inst.budget--; inst.budget <= 0 {
+					inst.check()
+				}
+			}
+		case opBrTable:
+			var (
+				table = f.tables[in.a]
+				i     = uint32(s[sp-1])
+			)
+			sp--
+			if i >= uint32(len(table)) {
+				i = uint32(len(table) - 1)
+			}
+			t := &table[i]
+			copy(s[ob+int(t.height):], s[sp-int(t.arity):sp])
+			sp = ob + int(t.height) + int(t.arity)
+			pc = int(t.pc)
+			if inst.budget--; inst.budget <= 0 {
+				inst.check()
+			}
+		case opReturn:
+			copy(s[base:], s[sp-int(in.imm):sp])
+			inst.depth--
+			return
+		case 0x10: // call
+			nb := sp - int(in.b)
+			inst.invoke(inst.m.funcs[in.a], nb)
+			s, mem = inst.stack, inst.mem
+			sp = nb + int(in.imm)
+		case 0x11: // call_indirect
+			var (
+				ft = &inst.m.types[in.a]
+				i  = uint32(s[sp-1])
+			)
+			sp--
+			if i >= uint32(len(inst.table)) {
+				panic(newTrap("undefined element %d", i))
+			}
+			fi := inst.table[i]
+			if fi < 0 {
+				panic(newTrap("uninitialized element %d", i))
+			}
+			callee := inst.m.funcs[fi]
+			if callee.typ.id != ft.id {
+				panic(newTrap("indirect call type mismatch: expected %s, got %s", ft, callee.typ))
+			}
+			nb := sp - len(ft.params)
+			inst.invoke(callee, nb)
+			s, mem = inst.stack, inst.mem
+			sp = nb + len(ft.results)
+
+		// superinstructions (see compile.go)
+
+		case opI64AddConst:
+			s[sp-1] += in.imm
+		case opLocalI64AddConst:
+			s[sp] = s[base+int(in.a)] + in.imm
+			sp++
+		case opLocalI64AddConstSet:
+			s[base+int(in.b)] = s[base+int(in.a)] + in.imm
+		case opLocalGet2:
+			s[sp] = s[base+int(in.a)]
+			s[sp+1] = s[base+int(in.b)]
+			sp += 2
+		case opIfNot:
+			sp--
+			if uint32(s[sp]) != 0 {
+				pc = int(in.a)
+			}
+		case opConstSet:
+			s[base+int(in.a)] = in.imm
+		case opLocalBrTable:
+			var (
+				table = f.tables[in.a]
+				i     = uint32(s[base+int(in.b)])
+			)
+			if i >= uint32(len(table)) {
+				i = uint32(len(table) - 1)
+			}
+			t := &table[i]
+			copy(s[ob+int(t.height):], s[sp-int(t.arity):sp])
+			sp = ob + int(t.height) + int(t.arity)
+			pc = int(t.pc)
+			if inst.budget--; inst.budget <= 0 {
+				inst.check()
+			}
+
+		// parametric and variables
+
+		case 0x1a: // drop
+			sp--
+		case 0x1b: // select
+			sp -= 2
+			if uint32(s[sp+1]) == 0 {
+				s[sp-1] = s[sp]
+			}
+		case 0x20: // local.get
+			s[sp] = s[base+int(in.a)]
+			sp++
+		case 0x21: // local.set
+			sp--
+			s[base+int(in.a)] = s[sp]
+		case 0x22: // local.tee
+			s[base+int(in.a)] = s[sp-1]
+		case 0x23: // global.get
+			s[sp] = inst.globals[in.a]
+			sp++
+		case 0x24: // global.set
+			sp--
+			inst.globals[in.a] = s[sp]
+
+		// memory
+
+		case 0x28: // i32.load
+			ea := effAddr(s[sp-1], in.imm, 4, mem)
+			s[sp-1] = uint64(binary.LittleEndian.Uint32(mem[ea:]))
+		case 0x29: // i64.load
+			ea := effAddr(s[sp-1], in.imm, 8, mem)
+			s[sp-1] = binary.LittleEndian.Uint64(mem[ea:])
+		case 0x2a: // f32.load
+			ea := effAddr(s[sp-1], in.imm, 4, mem)
+			s[sp-1] = uint64(binary.LittleEndian.Uint32(mem[ea:]))
+		case 0x2b: // f64.load
+			ea := effAddr(s[sp-1], in.imm, 8, mem)
+			s[sp-1] = binary.LittleEndian.Uint64(mem[ea:])
+		case 0x2c: // i32.load8_s
+			ea := effAddr(s[sp-1], in.imm, 1, mem)
+			s[sp-1] = uint64(uint32(int32(int8(mem[ea]))))
+		case 0x2d: // i32.load8_u
+			ea := effAddr(s[sp-1], in.imm, 1, mem)
+			s[sp-1] = uint64(mem[ea])
+		case 0x2e: // i32.load16_s
+			ea := effAddr(s[sp-1], in.imm, 2, mem)
+			s[sp-1] = uint64(uint32(int32(int16(binary.LittleEndian.Uint16(mem[ea:])))))
+		case 0x2f: // i32.load16_u
+			ea := effAddr(s[sp-1], in.imm, 2, mem)
+			s[sp-1] = uint64(binary.LittleEndian.Uint16(mem[ea:]))
+		case 0x30: // i64.load8_s
+			ea := effAddr(s[sp-1], in.imm, 1, mem)
+			s[sp-1] = uint64(int64(int8(mem[ea])))
+		case 0x31: // i64.load8_u
+			ea := effAddr(s[sp-1], in.imm, 1, mem)
+			s[sp-1] = uint64(mem[ea])
+		case 0x32: // i64.load16_s
+			ea := effAddr(s[sp-1], in.imm, 2, mem)
+			s[sp-1] = uint64(int64(int16(binary.LittleEndian.Uint16(mem[ea:]))))
+		case 0x33: // i64.load16_u
+			ea := effAddr(s[sp-1], in.imm, 2, mem)
+			s[sp-1] = uint64(binary.LittleEndian.Uint16(mem[ea:]))
+		case 0x34: // i64.load32_s
+			ea := effAddr(s[sp-1], in.imm, 4, mem)
+			s[sp-1] = uint64(int64(int32(binary.LittleEndian.Uint32(mem[ea:]))))
+		case 0x35: // i64.load32_u
+			ea := effAddr(s[sp-1], in.imm, 4, mem)
+			s[sp-1] = uint64(binary.LittleEndian.Uint32(mem[ea:]))
+		case 0x36, 0x38: // i32.store, f32.store
+			sp -= 2
+			ea := effAddr(s[sp], in.imm, 4, mem)
+			binary.LittleEndian.PutUint32(mem[ea:], uint32(s[sp+1]))
+		case 0x37, 0x39: // i64.store, f64.store
+			sp -= 2
+			ea := effAddr(s[sp], in.imm, 8, mem)
+			binary.LittleEndian.PutUint64(mem[ea:], s[sp+1])
+		case 0x3a, 0x3c: // i32.store8, i64.store8
+			sp -= 2
+			ea := effAddr(s[sp], in.imm, 1, mem)
+			mem[ea] = byte(s[sp+1])
+		case 0x3b, 0x3d: // i32.store16, i64.store16
+			sp -= 2
+			ea := effAddr(s[sp], in.imm, 2, mem)
+			binary.LittleEndian.PutUint16(mem[ea:], uint16(s[sp+1]))
+		case 0x3e: // i64.store32
+			sp -= 2
+			ea := effAddr(s[sp], in.imm, 4, mem)
+			binary.LittleEndian.PutUint32(mem[ea:], uint32(s[sp+1]))
+		case 0x3f: // memory.size
+			s[sp] = uint64(len(mem) / pageSize)
+			sp++
+		case 0x40: // memory.grow
+			s[sp-1] = uint64(uint32(inst.growMem(uint32(s[sp-1]))))
+			mem = inst.mem
+
+		// constants
+
+		case 0x41, 0x42, 0x43, 0x44:
+			s[sp] = in.imm
+			sp++
+
+		// i32
+
+		case 0x45: // i32.eqz
+			s[sp-1] = b2u(uint32(s[sp-1]) == 0)
+		case 0x46: // i32.eq
+			sp--
+			s[sp-1] = b2u(uint32(s[sp-1]) == uint32(s[sp]))
+		case 0x47: // i32.ne
+			sp--
+			s[sp-1] = b2u(uint32(s[sp-1]) != uint32(s[sp]))
+		case 0x48: // i32.lt_s
+			sp--
+			s[sp-1] = b2u(int32(s[sp-1]) < int32(s[sp]))
+		case 0x49: // i32.lt_u
+			sp--
+			s[sp-1] = b2u(uint32(s[sp-1]) < uint32(s[sp]))
+		case 0x4a: // i32.gt_s
+			sp--
+			s[sp-1] = b2u(int32(s[sp-1]) > int32(s[sp]))
+		case 0x4b: // i32.gt_u
+			sp--
+			s[sp-1] = b2u(uint32(s[sp-1]) > uint32(s[sp]))
+		case 0x4c: // i32.le_s
+			sp--
+			s[sp-1] = b2u(int32(s[sp-1]) <= int32(s[sp]))
+		case 0x4d: // i32.le_u
+			sp--
+			s[sp-1] = b2u(uint32(s[sp-1]) <= uint32(s[sp]))
+		case 0x4e: // i32.ge_s
+			sp--
+			s[sp-1] = b2u(int32(s[sp-1]) >= int32(s[sp]))
+		case 0x4f: // i32.ge_u
+			sp--
+			s[sp-1] = b2u(uint32(s[sp-1]) >= uint32(s[sp]))
+
+		// i64
+
+		case 0x50: // i64.eqz
+			s[sp-1] = b2u(s[sp-1] == 0)
+		case 0x51: // i64.eq
+			sp--
+			s[sp-1] = b2u(s[sp-1] == s[sp])
+		case 0x52: // i64.ne
+			sp--
+			s[sp-1] = b2u(s[sp-1] != s[sp])
+		case 0x53: // i64.lt_s
+			sp--
+			s[sp-1] = b2u(int64(s[sp-1]) < int64(s[sp]))
+		case 0x54: // i64.lt_u
+			sp--
+			s[sp-1] = b2u(s[sp-1] < s[sp])
+		case 0x55: // i64.gt_s
+			sp--
+			s[sp-1] = b2u(int64(s[sp-1]) > int64(s[sp]))
+		case 0x56: // i64.gt_u
+			sp--
+			s[sp-1] = b2u(s[sp-1] > s[sp])
+		case 0x57: // i64.le_s
+			sp--
+			s[sp-1] = b2u(int64(s[sp-1]) <= int64(s[sp]))
+		case 0x58: // i64.le_u
+			sp--
+			s[sp-1] = b2u(s[sp-1] <= s[sp])
+		case 0x59: // i64.ge_s
+			sp--
+			s[sp-1] = b2u(int64(s[sp-1]) >= int64(s[sp]))
+		case 0x5a: // i64.ge_u
+			sp--
+			s[sp-1] = b2u(s[sp-1] >= s[sp])
+
+		// f32 and f64 comparisons
+
+		case 0x5b: // f32.eq
+			sp--
+			s[sp-1] = b2u(f32(s[sp-1]) == f32(s[sp]))
+		case 0x5c: // f32.ne
+			sp--
+			s[sp-1] = b2u(f32(s[sp-1]) != f32(s[sp]))
+		case 0x5d: // f32.lt
+			sp--
+			s[sp-1] = b2u(f32(s[sp-1]) < f32(s[sp]))
+		case 0x5e: // f32.gt
+			sp--
+			s[sp-1] = b2u(f32(s[sp-1]) > f32(s[sp]))
+		case 0x5f: // f32.le
+			sp--
+			s[sp-1] = b2u(f32(s[sp-1]) <= f32(s[sp]))
+		case 0x60: // f32.ge
+			sp--
+			s[sp-1] = b2u(f32(s[sp-1]) >= f32(s[sp]))
+		case 0x61: // f64.eq
+			sp--
+			s[sp-1] = b2u(f64(s[sp-1]) == f64(s[sp]))
+		case 0x62: // f64.ne
+			sp--
+			s[sp-1] = b2u(f64(s[sp-1]) != f64(s[sp]))
+		case 0x63: // f64.lt
+			sp--
+			s[sp-1] = b2u(f64(s[sp-1]) < f64(s[sp]))
+		case 0x64: // f64.gt
+			sp--
+			s[sp-1] = b2u(f64(s[sp-1]) > f64(s[sp]))
+		case 0x65: // f64.le
+			sp--
+			s[sp-1] = b2u(f64(s[sp-1]) <= f64(s[sp]))
+		case 0x66: // f64.ge
+			sp--
+			s[sp-1] = b2u(f64(s[sp-1]) >= f64(s[sp]))
+
+		// i32 arithmetic
+
+		case 0x67: // i32.clz
+			s[sp-1] = uint64(bits.LeadingZeros32(uint32(s[sp-1])))
+		case 0x68: // i32.ctz
+			s[sp-1] = uint64(bits.TrailingZeros32(uint32(s[sp-1])))
+		case 0x69: // i32.popcnt
+			s[sp-1] = uint64(bits.OnesCount32(uint32(s[sp-1])))
+		case 0x6a: // i32.add
+			sp--
+			s[sp-1] = uint64(uint32(s[sp-1]) + uint32(s[sp]))
+		case 0x6b: // i32.sub
+			sp--
+			s[sp-1] = uint64(uint32(s[sp-1]) - uint32(s[sp]))
+		case 0x6c: // i32.mul
+			sp--
+			s[sp-1] = uint64(uint32(s[sp-1]) * uint32(s[sp]))
+		case 0x6d: // i32.div_s
+			sp--
+			a, b := int32(s[sp-1]), int32(s[sp])
+			if b == 0 {
+				panic(errDivByZero)
+			}
+			if a == math.MinInt32 && b == -1 {
+				panic(errOverflow)
+			}
+			s[sp-1] = uint64(uint32(a / b))
+		case 0x6e: // i32.div_u
+			sp--
+			a, b := uint32(s[sp-1]), uint32(s[sp])
+			if b == 0 {
+				panic(errDivByZero)
+			}
+			s[sp-1] = uint64(a / b)
+		case 0x6f: // i32.rem_s
+			sp--
+			a, b := int32(s[sp-1]), int32(s[sp])
+			if b == 0 {
+				panic(errDivByZero)
+			}
+			if b == -1 {
+				s[sp-1] = 0
+			} else {
+				s[sp-1] = uint64(uint32(a % b))
+			}
+		case 0x70: // i32.rem_u
+			sp--
+			a, b := uint32(s[sp-1]), uint32(s[sp])
+			if b == 0 {
+				panic(errDivByZero)
+			}
+			s[sp-1] = uint64(a % b)
+		case 0x71: // i32.and
+			sp--
+			s[sp-1] &= s[sp]
+		case 0x72: // i32.or
+			sp--
+			s[sp-1] |= s[sp]
+		case 0x73: // i32.xor
+			sp--
+			s[sp-1] ^= s[sp]
+		case 0x74: // i32.shl
+			sp--
+			s[sp-1] = uint64(uint32(s[sp-1]) << (uint32(s[sp]) & 31))
+		case 0x75: // i32.shr_s
+			sp--
+			s[sp-1] = uint64(uint32(int32(s[sp-1]) >> (uint32(s[sp]) & 31)))
+		case 0x76: // i32.shr_u
+			sp--
+			s[sp-1] = uint64(uint32(s[sp-1]) >> (uint32(s[sp]) & 31))
+		case 0x77: // i32.rotl
+			sp--
+			s[sp-1] = uint64(bits.RotateLeft32(uint32(s[sp-1]), int(uint32(s[sp])&31)))
+		case 0x78: // i32.rotr
+			sp--
+			s[sp-1] = uint64(bits.RotateLeft32(uint32(s[sp-1]), -int(uint32(s[sp])&31)))
+
+		// i64 arithmetic
+
+		case 0x79: // i64.clz
+			s[sp-1] = uint64(bits.LeadingZeros64(s[sp-1]))
+		case 0x7a: // i64.ctz
+			s[sp-1] = uint64(bits.TrailingZeros64(s[sp-1]))
+		case 0x7b: // i64.popcnt
+			s[sp-1] = uint64(bits.OnesCount64(s[sp-1]))
+		case 0x7c: // i64.add
+			sp--
+			s[sp-1] += s[sp]
+		case 0x7d: // i64.sub
+			sp--
+			s[sp-1] -= s[sp]
+		case 0x7e: // i64.mul
+			sp--
+			s[sp-1] *= s[sp]
+		case 0x7f: // i64.div_s
+			sp--
+			a, b := int64(s[sp-1]), int64(s[sp])
+			if b == 0 {
+				panic(errDivByZero)
+			}
+			if a == math.MinInt64 && b == -1 {
+				panic(errOverflow)
+			}
+			s[sp-1] = uint64(a / b)
+		case 0x80: // i64.div_u
+			sp--
+			if s[sp] == 0 {
+				panic(errDivByZero)
+			}
+			s[sp-1] /= s[sp]
+		case 0x81: // i64.rem_s
+			sp--
+			a, b := int64(s[sp-1]), int64(s[sp])
+			if b == 0 {
+				panic(errDivByZero)
+			}
+			if b == -1 {
+				s[sp-1] = 0
+			} else {
+				s[sp-1] = uint64(a % b)
+			}
+		case 0x82: // i64.rem_u
+			sp--
+			if s[sp] == 0 {
+				panic(errDivByZero)
+			}
+			s[sp-1] %= s[sp]
+		case 0x83: // i64.and
+			sp--
+			s[sp-1] &= s[sp]
+		case 0x84: // i64.or
+			sp--
+			s[sp-1] |= s[sp]
+		case 0x85: // i64.xor
+			sp--
+			s[sp-1] ^= s[sp]
+		case 0x86: // i64.shl
+			sp--
+			s[sp-1] <<= s[sp] & 63
+		case 0x87: // i64.shr_s
+			sp--
+			s[sp-1] = uint64(int64(s[sp-1]) >> (s[sp] & 63))
+		case 0x88: // i64.shr_u
+			sp--
+			s[sp-1] >>= s[sp] & 63
+		case 0x89: // i64.rotl
+			sp--
+			s[sp-1] = bits.RotateLeft64(s[sp-1], int(s[sp]&63))
+		case 0x8a: // i64.rotr
+			sp--
+			s[sp-1] = bits.RotateLeft64(s[sp-1], -int(s[sp]&63))
+
+		// f32 arithmetic
+
+		case 0x8b: // f32.abs
+			s[sp-1] &^= 1 << 31
+		case 0x8c: // f32.neg
+			s[sp-1] ^= 1 << 31
+		case 0x8d: // f32.ceil
+			s[sp-1] = f32bits(float32(math.Ceil(float64(f32(s[sp-1])))))
+		case 0x8e: // f32.floor
+			s[sp-1] = f32bits(float32(math.Floor(float64(f32(s[sp-1])))))
+		case 0x8f: // f32.trunc
+			s[sp-1] = f32bits(float32(math.Trunc(float64(f32(s[sp-1])))))
+		case 0x90: // f32.nearest
+			s[sp-1] = f32bits(float32(math.RoundToEven(float64(f32(s[sp-1])))))
+		case 0x91: // f32.sqrt
+			s[sp-1] = f32bits(float32(math.Sqrt(float64(f32(s[sp-1])))))
+		case 0x92: // f32.add
+			sp--
+			s[sp-1] = f32bits(f32(s[sp-1]) + f32(s[sp]))
+		case 0x93: // f32.sub
+			sp--
+			s[sp-1] = f32bits(f32(s[sp-1]) - f32(s[sp]))
+		case 0x94: // f32.mul
+			sp--
+			s[sp-1] = f32bits(f32(s[sp-1]) * f32(s[sp]))
+		case 0x95: // f32.div
+			sp--
+			s[sp-1] = f32bits(f32(s[sp-1]) / f32(s[sp]))
+		case 0x96: // f32.min
+			sp--
+			s[sp-1] = f32bits(float32(fmin(float64(f32(s[sp-1])), float64(f32(s[sp])))))
+		case 0x97: // f32.max
+			sp--
+			s[sp-1] = f32bits(float32(fmax(float64(f32(s[sp-1])), float64(f32(s[sp])))))
+		case 0x98: // f32.copysign
+			sp--
+			s[sp-1] = s[sp-1]&^(1<<31) | s[sp]&(1<<31)
+
+		// f64 arithmetic
+
+		case 0x99: // f64.abs
+			s[sp-1] &^= 1 << 63
+		case 0x9a: // f64.neg
+			s[sp-1] ^= 1 << 63
+		case 0x9b: // f64.ceil
+			s[sp-1] = math.Float64bits(math.Ceil(f64(s[sp-1])))
+		case 0x9c: // f64.floor
+			s[sp-1] = math.Float64bits(math.Floor(f64(s[sp-1])))
+		case 0x9d: // f64.trunc
+			s[sp-1] = math.Float64bits(math.Trunc(f64(s[sp-1])))
+		case 0x9e: // f64.nearest
+			s[sp-1] = math.Float64bits(math.RoundToEven(f64(s[sp-1])))
+		case 0x9f: // f64.sqrt
+			s[sp-1] = math.Float64bits(math.Sqrt(f64(s[sp-1])))
+		case 0xa0: // f64.add
+			sp--
+			s[sp-1] = math.Float64bits(f64(s[sp-1]) + f64(s[sp]))
+		case 0xa1: // f64.sub
+			sp--
+			s[sp-1] = math.Float64bits(f64(s[sp-1]) - f64(s[sp]))
+		case 0xa2: // f64.mul
+			sp--
+			s[sp-1] = math.Float64bits(f64(s[sp-1]) * f64(s[sp]))
+		case 0xa3: // f64.div
+			sp--
+			s[sp-1] = math.Float64bits(f64(s[sp-1]) / f64(s[sp]))
+		case 0xa4: // f64.min
+			sp--
+			s[sp-1] = math.Float64bits(fmin(f64(s[sp-1]), f64(s[sp])))
+		case 0xa5: // f64.max
+			sp--
+			s[sp-1] = math.Float64bits(fmax(f64(s[sp-1]), f64(s[sp])))
+		case 0xa6: // f64.copysign
+			sp--
+			s[sp-1] = s[sp-1]&^(1<<63) | s[sp]&(1<<63)
+
+		// conversions
+
+		case 0xa7: // i32.wrap_i64
+			s[sp-1] = uint64(uint32(s[sp-1]))
+		case 0xa8: // i32.trunc_f32_s
+			s[sp-1] = uint64(uint32(truncS32(float64(f32(s[sp-1])), false)))
+		case 0xa9: // i32.trunc_f32_u
+			s[sp-1] = uint64(truncU32(float64(f32(s[sp-1])), false))
+		case 0xaa: // i32.trunc_f64_s
+			s[sp-1] = uint64(uint32(truncS32(f64(s[sp-1]), false)))
+		case 0xab: // i32.trunc_f64_u
+			s[sp-1] = uint64(truncU32(f64(s[sp-1]), false))
+		case 0xac: // i64.extend_i32_s
+			s[sp-1] = uint64(int64(int32(s[sp-1])))
+		case 0xae: // i64.trunc_f32_s
+			s[sp-1] = uint64(truncS64(float64(f32(s[sp-1])), false))
+		case 0xaf: // i64.trunc_f32_u
+			s[sp-1] = truncU64(float64(f32(s[sp-1])), false)
+		case 0xb0: // i64.trunc_f64_s
+			s[sp-1] = uint64(truncS64(f64(s[sp-1]), false))
+		case 0xb1: // i64.trunc_f64_u
+			s[sp-1] = truncU64(f64(s[sp-1]), false)
+		case 0xb2: // f32.convert_i32_s
+			s[sp-1] = f32bits(float32(int32(s[sp-1])))
+		case 0xb3: // f32.convert_i32_u
+			s[sp-1] = f32bits(float32(uint32(s[sp-1])))
+		case 0xb4: // f32.convert_i64_s
+			s[sp-1] = f32bits(float32(int64(s[sp-1])))
+		case 0xb5: // f32.convert_i64_u
+			s[sp-1] = f32bits(u64ToF32(s[sp-1]))
+		case 0xb6: // f32.demote_f64
+			s[sp-1] = f32bits(float32(f64(s[sp-1])))
+		case 0xb7: // f64.convert_i32_s
+			s[sp-1] = math.Float64bits(float64(int32(s[sp-1])))
+		case 0xb8: // f64.convert_i32_u
+			s[sp-1] = math.Float64bits(float64(uint32(s[sp-1])))
+		case 0xb9: // f64.convert_i64_s
+			s[sp-1] = math.Float64bits(float64(int64(s[sp-1])))
+		case 0xba: // f64.convert_i64_u
+			s[sp-1] = math.Float64bits(u64ToF64(s[sp-1]))
+		case 0xbb: // f64.promote_f32
+			s[sp-1] = math.Float64bits(float64(f32(s[sp-1])))
+
+		// sign extensions
+
+		case 0xc0: // i32.extend8_s
+			s[sp-1] = uint64(uint32(int32(int8(s[sp-1]))))
+		case 0xc1: // i32.extend16_s
+			s[sp-1] = uint64(uint32(int32(int16(s[sp-1]))))
+		case 0xc2: // i64.extend8_s
+			s[sp-1] = uint64(int64(int8(s[sp-1])))
+		case 0xc3: // i64.extend16_s
+			s[sp-1] = uint64(int64(int16(s[sp-1])))
+		case 0xc4: // i64.extend32_s
+			s[sp-1] = uint64(int64(int32(s[sp-1])))
+
+		// non-trapping float-to-int conversions
+
+		case opPrefixFC + 0: // i32.trunc_sat_f32_s
+			s[sp-1] = uint64(uint32(truncS32(float64(f32(s[sp-1])), true)))
+		case opPrefixFC + 1: // i32.trunc_sat_f32_u
+			s[sp-1] = uint64(truncU32(float64(f32(s[sp-1])), true))
+		case opPrefixFC + 2: // i32.trunc_sat_f64_s
+			s[sp-1] = uint64(uint32(truncS32(f64(s[sp-1]), true)))
+		case opPrefixFC + 3: // i32.trunc_sat_f64_u
+			s[sp-1] = uint64(truncU32(f64(s[sp-1]), true))
+		case opPrefixFC + 4: // i64.trunc_sat_f32_s
+			s[sp-1] = uint64(truncS64(float64(f32(s[sp-1])), true))
+		case opPrefixFC + 5: // i64.trunc_sat_f32_u
+			s[sp-1] = truncU64(float64(f32(s[sp-1])), true)
+		case opPrefixFC + 6: // i64.trunc_sat_f64_s
+			s[sp-1] = uint64(truncS64(f64(s[sp-1]), true))
+		case opPrefixFC + 7: // i64.trunc_sat_f64_u
+			s[sp-1] = truncU64(f64(s[sp-1]), true)
+
+		// bulk memory
+
+		case opPrefixFC + 8: // memory.init
+			sp -= 3
+			var (
+				dst, src, n = uint64(uint32(s[sp])), uint64(uint32(s[sp+1])), uint64(uint32(s[sp+2]))
+				data        []byte
+			)
+			if !inst.dropped[in.a] {
+				data = inst.m.datas[in.a].data
+			}
+			if src+n > uint64(len(data)) || dst+n > uint64(len(mem)) {
+				panic(errOutOfBounds)
+			}
+			copy(mem[dst:dst+n], data[src:])
+		case opPrefixFC + 9: // data.drop
+			inst.dropped[in.a] = true
+		case opPrefixFC + 10: // memory.copy
+			sp -= 3
+			dst, src, n := uint64(uint32(s[sp])), uint64(uint32(s[sp+1])), uint64(uint32(s[sp+2]))
+			if src+n > uint64(len(mem)) || dst+n > uint64(len(mem)) {
+				panic(errOutOfBounds)
+			}
+			copy(mem[dst:dst+n], mem[src:src+n])
+		case opPrefixFC + 11: // memory.fill
+			sp -= 3
+			dst, val, n := uint64(uint32(s[sp])), byte(s[sp+1]), uint64(uint32(s[sp+2]))
+			if dst+n > uint64(len(mem)) {
+				panic(errOutOfBounds)
+			}
+			fill(mem[dst:dst+n], val)
+
+		default:
+			panic(newTrap("invalid instruction (opcode 0x%x)", in.op))
+		}
+	}
+}
+
+///////////
+// utils //
+///////////
+
+func effAddr(addr, offset uint64, size uint64, mem []byte) uint64 {
+	ea := uint64(uint32(addr)) + offset
+	if ea+size > uint64(len(mem)) {
+		panic(errOutOfBounds)
+	}
+	return ea
+}
+
+func b2u(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func f32(v uint64) float32     { return math.Float32frombits(uint32(v)) }
+func f32bits(f float32) uint64 { return uint64(math.Float32bits(f)) }
+func f64(v uint64) float64     { return math.Float64frombits(v) }
+
+func fmin(a, b float64) float64 {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b):
+		return math.NaN()
+	case a == 0 && b == 0:
+		if math.Signbit(a) {
+			return a
+		}
+		return b
+	case a < b:
+		return a
+	default:
+		return b
+	}
+}
+
+func fmax(a, b float64) float64 {
+	switch {
+	case math.IsNaN(a) || math.IsNaN(b):
+		return math.NaN()
+	case a == 0 && b == 0:
+		if math.Signbit(a) {
+			return b
+		}
+		return a
+	case a > b:
+		return a
+	default:
+		return b
+	}
+}
+
+// float to integer conversions: trap (or, if saturating, clamp) when out of range
+
+func truncS32(x float64, sat bool) int32 {
+	switch {
+	case math.IsNaN(x):
+		if sat {
+			return 0
+		}
+		panic(errConversion)
+	case x <= math.MinInt32-1:
+		if sat {
+			return math.MinInt32
+		}
+		panic(errOverflow)
+	case x >= math.MaxInt32+1:
+		if sat {
+			return math.MaxInt32
+		}
+		panic(errOverflow)
+	}
+	return int32(x)
+}
+
+func truncU32(x float64, sat bool) uint32 {
+	switch {
+	case math.IsNaN(x):
+		if sat {
+			return 0
+		}
+		panic(errConversion)
+	case x <= -1:
+		if sat {
+			return 0
+		}
+		panic(errOverflow)
+	case x >= math.MaxUint32+1:
+		if sat {
+			return math.MaxUint32
+		}
+		panic(errOverflow)
+	}
+	return uint32(int64(x))
+}
+
+func truncS64(x float64, sat bool) int64 {
+	switch {
+	case math.IsNaN(x):
+		if sat {
+			return 0
+		}
+		panic(errConversion)
+	case x < math.MinInt64:
+		if sat {
+			return math.MinInt64
+		}
+		panic(errOverflow)
+	case x >= -math.MinInt64:
+		if sat {
+			return math.MaxInt64
+		}
+		panic(errOverflow)
+	}
+	return int64(x)
+}
+
+func truncU64(x float64, sat bool) uint64 {
+	const two63 = -math.MinInt64
+	switch {
+	case math.IsNaN(x):
+		if sat {
+			return 0
+		}
+		panic(errConversion)
+	case x <= -1:
+		if sat {
+			return 0
+		}
+		panic(errOverflow)
+	case x >= 2*two63:
+		if sat {
+			return math.MaxUint64
+		}
+		panic(errOverflow)
+	case x >= two63:
+		return uint64(int64(x-two63)) + 1<<63
+	}
+	return uint64(int64(x))
+}
+
+// (correctly rounded, unlike converting the halves)
+func u64ToF32(v uint64) float32 {
+	if v < 1<<63 {
+		return float32(int64(v))
+	}
+	return 2 * float32(int64(v>>1|v&1))
+}
+
+func u64ToF64(v uint64) float64 {
+	if v < 1<<63 {
+		return float64(int64(v))
+	}
+	return 2 * float64(int64(v>>1|v&1))
+}
+
+func fill(b []byte, val byte) {
+	if len(b) == 0 {
+		return
+	}
+	b[0] = val
+	for i := 1; i < len(b); i *= 2 {
+		copy(b[i:], b[:i])
+	}
+}
@@ -0,0 +1,593 @@
+//go:build etlwasm
+// +build etlwasm
+
+// Package wasm provides sandboxed, in-process execution of WebAssembly (WASI) ETL transformers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package wasm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// binary format: https://webassembly.github.io/spec/core/binary/modules.html
+
+const (
+	magic   = "\x00asm"
+	version = 1
+)
+
+// sections
+const (
+	secCustom = iota
+	secType
+	secImport
+	secFunction
+	secTable
+	secMemory
+	secGlobal
+	secExport
+	secStart
+	secElement
+	secCode
+	secData
+	secDataCount
+)
+
+// value types
+const (
+	valI32       = 0x7f
+	valI64       = 0x7e
+	valF32       = 0x7d
+	valF64       = 0x7c
+	valV128      = 0x7b
+	valFuncref   = 0x70
+	valExternref = 0x6f
+)
+
+// external kinds (imports and exports)
+const (
+	externFunc = iota
+	externTable
+	externMemory
+	externGlobal
+)
+
+const (
+	maxPages     = 65536 // 4GiB
+	maxTableSize = 1 << 24
+	maxLocals    = 50000
+)
+
+type (
+	funcType struct {
+		params  []byte
+		results []byte
+		id      int // same for structurally equal types (call_indirect)
+	}
+	function struct {
+		typ  *funcType
+		name string // (imported function)
+		host hostFunc
+
+		nlocals  int // (excluding params)
+		maxStack int // operand stack height
+		code     []instr
+		tables   [][]brTarget // br_table
+	}
+	limits struct {
+		min, max uint32
+	}
+	global struct {
+		typ     byte
+		mutable bool
+		init    constExpr
+	}
+	export struct {
+		kind byte
+		idx  uint32
+	}
+	// initializer of a global, and offset of a data or element segment
+	constExpr struct {
+		op  byte
+		typ byte   // (ref.null)
+		val uint64 // (global.get: index)
+	}
+	elemSegment struct {
+		active bool
+		offset constExpr
+		funcs  []int32 // (-1: null)
+	}
+	dataSegment struct {
+		active bool
+		offset constExpr
+		data   []byte
+	}
+
+	reader struct {
+		b   []byte
+		off int
+	}
+)
+
+var errEOF = errors.New("unexpected end")
+
+////////////
+// Module //
+////////////
+
+func (m *Module) decode(r *reader) (err error) {
+	if len(r.b) < 8 || string(r.b[:4]) != magic {
+		return errors.New("invalid magic number")
+	}
+	if v := binary.LittleEndian.Uint32(r.b[4:]); v != version {
+		return fmt.Errorf("unsupported version %d", v)
+	}
+	r.off = 8
+	var (
+		funcTypes []uint32 // function section
+		hasCode   bool
+		lastID    byte
+		typeIDs   = make(map[string]int)
+	)
+	for r.off < len(r.b) {
+		id := r.byte()
+		sec := &reader{b: r.bytes(int(r.u32()))}
+		if id != secCustom {
+			// (the data count section goes between import and code sections)
+			order := id
+			if id == secDataCount {
+				order = secElement
+			}
+			if order < lastID {
+				return fmt.Errorf("section %d out of order", id)
+			}
+			lastID = order
+		}
+		switch id {
+		case secCustom:
+			sec.off = len(sec.b)
+		case secType:
+			m.types = make([]funcType, sec.u32())
+			for i := range m.types {
+				if form := sec.byte(); form != 0x60 {
+					return fmt.Errorf("invalid function type 0x%x", form)
+				}
+				ft := &m.types[i]
+				ft.params, ft.results = sec.valTypes(), sec.valTypes()
+				sig := string(ft.params) + ":" + string(ft.results)
+				if _, ok := typeIDs[sig]; !ok {
+					typeIDs[sig] = len(typeIDs)
+				}
+				ft.id = typeIDs[sig]
+			}
+		case secImport:
+			if err = m.decodeImports(sec); err != nil {
+				return
+			}
+		case secFunction:
+			funcTypes = make([]uint32, sec.u32())
+			for i := range funcTypes {
+				funcTypes[i] = sec.u32()
+				if _, err = m.funcType(funcTypes[i]); err != nil {
+					return
+				}
+			}
+		case secTable:
+			for n := sec.u32(); n > 0; n-- {
+				if m.table != nil {
+					return errors.New("multiple tables are not supported")
+				}
+				if typ := sec.byte(); typ != valFuncref {
+					return fmt.Errorf("unsupported table type 0x%x", typ)
+				}
+				m.table = sec.limits(maxTableSize)
+			}
+		case secMemory:
+			for n := sec.u32(); n > 0; n-- {
+				if m.mem != nil {
+					return errors.New("multiple memories are not supported")
+				}
+				m.mem = sec.limits(maxPages)
+			}
+		case secGlobal:
+			m.globals = make([]global, sec.u32())
+			for i := range m.globals {
+				g := &m.globals[i]
+				g.typ, g.mutable = sec.valType(), sec.byte() == 1
+				if g.init, err = m.constExpr(sec); err != nil {
+					return
+				}
+				// (may refer to the preceding immutable globals)
+				if err = m.validateConst(g.init, g.typ, i); err != nil {
+					return fmt.Errorf("global %d: %v", i, err)
+				}
+			}
+		case secExport:
+			n := sec.u32()
+			m.exports = make(map[string]export, n)
+			for ; n > 0; n-- {
+				name := sec.name()
+				if _, ok := m.exports[name]; ok {
+					return fmt.Errorf("duplicate export %q", name)
+				}
+				m.exports[name] = export{kind: sec.byte(), idx: sec.u32()}
+			}
+		case secStart:
+			idx := sec.u32()
+			if int(idx) >= len(m.funcs)+len(funcTypes) {
+				return fmt.Errorf("invalid start function %d", idx)
+			}
+			if idx < uint32(len(m.funcs)) {
+				if ft := m.funcs[idx].typ; len(ft.params) > 0 || len(ft.results) > 0 {
+					return fmt.Errorf("invalid start function type %s", ft)
+				}
+			} else if ft := &m.types[funcTypes[int(idx)-len(m.funcs)]]; len(ft.params) > 0 || len(ft.results) > 0 {
+				return fmt.Errorf("invalid start function type %s", ft)
+			}
+			m.start = int(idx)
+		case secElement:
+			if err = m.decodeElems(sec); err != nil {
+				return
+			}
+		case secCode:
+			n := int(sec.u32())
+			if n != len(funcTypes) {
+				return fmt.Errorf("function and code section have inconsistent lengths (%d vs %d)",
+					len(funcTypes), n)
+			}
+			hasCode = true
+			nimported := len(m.funcs)
+			for i := 0; i < n; i++ {
+				typ, _ := m.funcType(funcTypes[i])
+				m.funcs = append(m.funcs, &function{typ: typ})
+			}
+			for i := 0; i < n; i++ {
+				var (
+					f    = m.funcs[nimported+i]
+					body = sec.bytes(int(sec.u32()))
+				)
+				if err = m.validate(f, &reader{b: body}); err != nil {
+					return fmt.Errorf("function %d: %v", nimported+i, err)
+				}
+				if err = m.compile(f, &reader{b: body}); err != nil {
+					return fmt.Errorf("function %d: %v", nimported+i, err)
+				}
+			}
+		case secData:
+			if err = m.decodeData(sec); err != nil {
+				return
+			}
+		case secDataCount:
+			m.dataCount = int(sec.u32())
+		default:
+			return fmt.Errorf("unknown section %d", id)
+		}
+		if sec.off != len(sec.b) {
+			return fmt.Errorf("section %d size mismatch", id)
+		}
+	}
+	if len(funcTypes) > 0 && !hasCode {
+		return errors.New("function section without code section")
+	}
+	if m.dataCount >= 0 && m.dataCount != len(m.datas) {
+		return fmt.Errorf("data count and data section have inconsistent lengths (%d vs %d)",
+			m.dataCount, len(m.datas))
+	}
+	for name, exp := range m.exports {
+		var n int
+		switch exp.kind {
+		case externFunc:
+			n = len(m.funcs)
+		case externTable:
+			if m.table != nil {
+				n = 1
+			}
+		case externMemory:
+			if m.mem != nil {
+				n = 1
+			}
+		case externGlobal:
+			n = len(m.globals)
+		default:
+			return fmt.Errorf("export %q: invalid kind %d", name, exp.kind)
+		}
+		if int(exp.idx) >= n {
+			return fmt.Errorf("export %q: invalid index %d", name, exp.idx)
+		}
+	}
+	for _, seg := range m.elems {
+		for _, fi := range seg.funcs {
+			if int(fi) >= len(m.funcs) {
+				return fmt.Errorf("element segment: invalid function %d", fi)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Module) funcType(idx uint32) (*funcType, error) {
+	if int(idx) >= len(m.types) {
+		return nil, fmt.Errorf("invalid type index %d", idx)
+	}
+	return &m.types[idx], nil
+}
+
+// only WASI functions can be imported
+func (m *Module) decodeImports(r *reader) error {
+	for n := r.u32(); n > 0; n-- {
+		module, name := r.name(), r.name()
+		if kind := r.byte(); kind != externFunc {
+			return fmt.Errorf("import %s.%s: only functions can be imported", module, name)
+		}
+		typ, err := m.funcType(r.u32())
+		if err != nil {
+			return err
+		}
+		if module != wasiModule && module != wasiUnstableModule {
+			return fmt.Errorf("import %s.%s: unknown module %q", module, name, module)
+		}
+		f := &function{typ: typ, name: module + "." + name}
+		if f.host, err = wasiFunc(name, typ); err != nil {
+			return err
+		}
+		m.funcs = append(m.funcs, f)
+	}
+	return nil
+}
+
+func (m *Module) decodeElems(r *reader) (err error) {
+	m.elems = make([]elemSegment, r.u32())
+	for i := range m.elems {
+		var (
+			seg   = &m.elems[i]
+			flags = r.u32()
+		)
+		if flags > 7 {
+			return fmt.Errorf("invalid element segment flags %d", flags)
+		}
+		seg.active = flags&1 == 0
+		if flags&2 != 0 && seg.active {
+			if tableIdx := r.u32(); tableIdx != 0 {
+				return fmt.Errorf("invalid table index %d", tableIdx)
+			}
+		}
+		if seg.active {
+			if seg.offset, err = m.constExpr(r); err != nil {
+				return
+			}
+			if err = m.validateConst(seg.offset, valI32, len(m.globals)); err != nil {
+				return
+			}
+		}
+		if flags&3 != 0 {
+			r.byte() // elemkind (0x00: funcref) or reftype
+		}
+		seg.funcs = make([]int32, r.u32())
+		for j := range seg.funcs {
+			if flags&4 == 0 {
+				seg.funcs[j] = int32(r.u32())
+				continue
+			}
+			expr, err := m.constExpr(r)
+			if err != nil {
+				return err
+			}
+			switch expr.op {
+			case 0xd2: // ref.func
+				seg.funcs[j] = int32(expr.val)
+			case 0xd0: // ref.null
+				seg.funcs[j] = -1
+			default:
+				return fmt.Errorf("invalid element expression (opcode 0x%x)", expr.op)
+			}
+		}
+		if seg.active && m.table == nil {
+			return errors.New("element segment without table")
+		}
+	}
+	return nil
+}
+
+func (m *Module) decodeData(r *reader) (err error) {
+	m.datas = make([]dataSegment, r.u32())
+	for i := range m.datas {
+		var (
+			seg   = &m.datas[i]
+			flags = r.u32()
+		)
+		switch flags {
+		case 0:
+			seg.active = true
+		case 1:
+		case 2:
+			if memIdx := r.u32(); memIdx != 0 {
+				return fmt.Errorf("invalid memory index %d", memIdx)
+			}
+			seg.active = true
+		default:
+			return fmt.Errorf("invalid data segment flags %d", flags)
+		}
+		if seg.active {
+			if seg.offset, err = m.constExpr(r); err != nil {
+				return
+			}
+			if err = m.validateConst(seg.offset, valI32, len(m.globals)); err != nil {
+				return
+			}
+			if m.mem == nil {
+				return errors.New("data segment without memory")
+			}
+		}
+		seg.data = r.bytes(int(r.u32()))
+	}
+	return nil
+}
+
+func (m *Module) constExpr(r *reader) (expr constExpr, err error) {
+	expr.op = r.byte()
+	switch expr.op {
+	case 0x41: // i32.const
+		expr.val = uint64(uint32(r.s32()))
+	case 0x42: // i64.const
+		expr.val = uint64(r.s64())
+	case 0x43: // f32.const
+		expr.val = uint64(binary.LittleEndian.Uint32(r.bytes(4)))
+	case 0x44: // f64.const
+		expr.val = binary.LittleEndian.Uint64(r.bytes(8))
+	case 0x23: // global.get
+		expr.val = uint64(r.u32())
+		if expr.val >= uint64(len(m.globals)) {
+			return expr, fmt.Errorf("invalid global %d in constant expression", expr.val)
+		}
+	case 0xd0: // ref.null
+		expr.typ = r.byte()
+	case 0xd2: // ref.func
+		expr.val = uint64(r.u32())
+	default:
+		return expr, fmt.Errorf("unsupported constant expression (opcode 0x%x)", expr.op)
+	}
+	if end := r.byte(); end != 0x0b {
+		return expr, errors.New("unsupported constant expression (expected end)")
+	}
+	return
+}
+
+////////////
+// reader //
+////////////
+
+func (r *reader) byte() byte {
+	if r.off >= len(r.b) {
+		panic(errEOF)
+	}
+	b := r.b[r.off]
+	r.off++
+	return b
+}
+
+func (r *reader) bytes(n int) []byte {
+	if n < 0 || n > len(r.b)-r.off {
+		panic(errEOF)
+	}
+	b := r.b[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+func (r *reader) u32() uint32 {
+	v := r.uleb(32)
+	return uint32(v)
+}
+
+func (r *reader) uleb(bits uint) (v uint64) {
+	for shift := uint(0); ; shift += 7 {
+		if shift >= bits {
+			panic(errors.New("integer representation too long"))
+		}
+		b := r.byte()
+		v |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return
+		}
+	}
+}
+
+func (r *reader) s32() int32 { return int32(r.sleb(32)) }
+func (r *reader) s64() int64 { return r.sleb(64) }
+
+func (r *reader) sleb(bits uint) (v int64) {
+	var (
+		b     byte
+		shift uint
+	)
+	for {
+		if shift >= bits {
+			panic(errors.New("integer representation too long"))
+		}
+		b = r.byte()
+		v |= int64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	if shift < 64 && b&0x40 != 0 {
+		v |= -1 << shift
+	}
+	return
+}
+
+func (r *reader) name() string {
+	b := r.bytes(int(r.u32()))
+	if !utf8.Valid(b) {
+		panic(errors.New("invalid UTF-8 name"))
+	}
+	return string(b)
+}
+
+func (r *reader) valType() byte {
+	switch t := r.byte(); t {
+	case valI32, valI64, valF32, valF64, valFuncref, valExternref:
+		return t
+	case valV128:
+		panic(errors.New("SIMD is not supported"))
+	default:
+		panic(fmt.Errorf("invalid value type 0x%x", t))
+	}
+}
+
+func (r *reader) valTypes() []byte {
+	types := make([]byte, r.u32())
+	for i := range types {
+		types[i] = r.valType()
+	}
+	return types
+}
+
+func (r *reader) limits(max uint32) *limits {
+	l := &limits{max: max}
+	switch flags := r.byte(); flags {
+	case 0:
+		l.min = r.u32()
+	case 1:
+		l.min, l.max = r.u32(), r.u32()
+	default:
+		panic(fmt.Errorf("unsupported limits (flags 0x%x)", flags))
+	}
+	if l.max > max {
+		l.max = max
+	}
+	if l.min > l.max {
+		panic(fmt.Errorf("invalid limits [%d, %d]", l.min, l.max))
+	}
+	return l
+}
+
+// (for error messages)
+func (ft *funcType) String() string {
+	names := func(types []byte) string {
+		s := make([]string, len(types))
+		for i, t := range types {
+			switch t {
+			case valI32:
+				s[i] = "i32"
+			case valI64:
+				s[i] = "i64"
+			case valF32:
+				s[i] = "f32"
+			case valF64:
+				s[i] = "f64"
+			default:
+				s[i] = "ref"
+			}
+		}
+		return strings.Join(s, ", ")
+	}
+	return "(" + names(ft.params) + ") -> (" + names(ft.results) + ")"
+}
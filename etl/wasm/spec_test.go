@@ -0,0 +1,289 @@
+//go:build etlwasm
+// +build etlwasm
+
+// Package wasm provides sandboxed, in-process execution of WebAssembly (WASI) ETL transformers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package wasm
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+// Conformance tests in the style of the WebAssembly spec test suite
+// (https://github.com/WebAssembly/spec/tree/main/test/core): assert_return, assert_trap,
+// and assert_invalid of (hand-assembled) expressions - see `specBody`.
+
+func cat(parts ...[]byte) (b []byte) {
+	for _, part := range parts {
+		b = append(b, part...)
+	}
+	return
+}
+
+func sleb(v int64) (b []byte) {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func i32c(v int32) []byte { return append([]byte{0x41}, sleb(int64(v))...) }
+func i64c(v int64) []byte { return append([]byte{0x42}, sleb(v)...) }
+
+func f32c(v float32) []byte {
+	b := []byte{0x43, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(b[1:], math.Float32bits(v))
+	return b
+}
+
+func f64c(v float64) []byte {
+	b := []byte{0x44, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint64(b[1:], math.Float64bits(v))
+	return b
+}
+
+func u32(v int32) uint64 { return uint64(uint32(v)) }
+func u64(v int64) uint64 { return uint64(v) }
+
+// `_start` body that evaluates the expression (that leaves a single value of a given type
+// on the stack) and writes the value (little-endian) to stdout
+func specBody(typ byte, expr []byte) []byte {
+	size, store := int32(4), byte(0x36)
+	switch typ {
+	case valI64:
+		size, store = 8, 0x37
+	case valF32:
+		store = 0x38
+	case valF64:
+		size, store = 8, 0x39
+	}
+	return cat(
+		i32c(16), expr, []byte{store, 0, 0},
+		// iovec at 0: {buf: 16, len: size}; nwritten at 8
+		i32c(0), i32c(16), []byte{0x36, 2, 0}, i32c(4), i32c(size), []byte{0x36, 2, 0},
+		i32c(1), i32c(0), i32c(1), i32c(8), []byte{0x10, 1, 0x1a},
+	)
+}
+
+var (
+	negZero32 = float32(math.Copysign(0, -1))
+	negZero64 = math.Copysign(0, -1)
+	nan32     = float32(math.NaN())
+)
+
+func TestSpecReturn(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  byte
+		expr []byte
+		want uint64
+		nan  bool // (any NaN)
+	}{
+		// i32
+		{"i32.add", valI32, cat(i32c(math.MaxInt32), i32c(1), []byte{0x6a}), 0x80000000, false},
+		{"i32.div_s", valI32, cat(i32c(-7), i32c(2), []byte{0x6d}), u32(-3), false},
+		{"i32.div_u", valI32, cat(i32c(-1), i32c(2), []byte{0x6e}), 0x7fffffff, false},
+		{"i32.rem_s", valI32, cat(i32c(-7), i32c(2), []byte{0x6f}), u32(-1), false},
+		{"i32.rem_s min -1", valI32, cat(i32c(math.MinInt32), i32c(-1), []byte{0x6f}), 0, false},
+		{"i32.shl", valI32, cat(i32c(1), i32c(33), []byte{0x74}), 2, false},
+		{"i32.shr_s", valI32, cat(i32c(-8), i32c(1), []byte{0x75}), u32(-4), false},
+		{"i32.shr_u", valI32, cat(i32c(math.MinInt32), i32c(31), []byte{0x76}), 1, false},
+		{"i32.rotl", valI32, cat(i32c(-0x7fffffff), i32c(1), []byte{0x77}), 3, false},
+		{"i32.rotr", valI32, cat(i32c(1), i32c(1), []byte{0x78}), 0x80000000, false},
+		{"i32.clz", valI32, cat(i32c(0), []byte{0x67}), 32, false},
+		{"i32.ctz", valI32, cat(i32c(math.MinInt32), []byte{0x68}), 31, false},
+		{"i32.popcnt", valI32, cat(i32c(-1), []byte{0x69}), 32, false},
+		{"i32.eqz", valI32, cat(i32c(0), []byte{0x45}), 1, false},
+		{"i32.lt_s", valI32, cat(i32c(-1), i32c(0), []byte{0x48}), 1, false},
+		{"i32.lt_u", valI32, cat(i32c(-1), i32c(0), []byte{0x49}), 0, false},
+		{"i32.extend8_s", valI32, cat(i32c(0x80), []byte{0xc0}), 0xffffff80, false},
+		{"i32.extend16_s", valI32, cat(i32c(0x7fff), []byte{0xc1}), 0x7fff, false},
+		{"i32.wrap_i64", valI32, cat(i64c(0x100000001), []byte{0xa7}), 1, false},
+		{"i32.trunc_f32_s", valI32, cat(f32c(-1.9), []byte{0xa8}), u32(-1), false},
+		{"i32.trunc_f64_u", valI32, cat(f64c(4294967295.9), []byte{0xab}), 0xffffffff, false},
+		{"i32.trunc_sat_f32_s nan", valI32, cat(f32c(nan32), []byte{0xfc, 0}), 0, false},
+		{"i32.trunc_sat_f32_s max", valI32, cat(f32c(1e10), []byte{0xfc, 0}), math.MaxInt32, false},
+		{"i32.trunc_sat_f64_u", valI32, cat(f64c(-1), []byte{0xfc, 3}), 0, false},
+		{"i32.reinterpret_f32", valI32, cat(f32c(negZero32), []byte{0xbc}), 0x80000000, false},
+
+		// i64
+		{"i64.div_s", valI64, cat(i64c(math.MinInt64), i64c(2), []byte{0x7f}), 0xc000000000000000, false},
+		{"i64.rem_s min -1", valI64, cat(i64c(math.MinInt64), i64c(-1), []byte{0x81}), 0, false},
+		{"i64.rem_u", valI64, cat(i64c(-1), i64c(10), []byte{0x82}), 5, false},
+		{"i64.shl", valI64, cat(i64c(1), i64c(65), []byte{0x86}), 2, false},
+		{"i64.shr_s", valI64, cat(i64c(math.MinInt64), i64c(63), []byte{0x87}), math.MaxUint64, false},
+		{"i64.rotr", valI64, cat(i64c(1), i64c(1), []byte{0x8a}), 0x8000000000000000, false},
+		{"i64.clz", valI64, cat(i64c(1), []byte{0x79}), 63, false},
+		{"i64.lt_u", valI32, cat(i64c(1), i64c(-1), []byte{0x54}), 1, false},
+		{"i64.extend_i32_s", valI64, cat(i32c(-1), []byte{0xac}), math.MaxUint64, false},
+		{"i64.extend_i32_u", valI64, cat(i32c(-1), []byte{0xad}), 0xffffffff, false},
+		{"i64.extend32_s", valI64, cat(i64c(0x80000000), []byte{0xc4}), 0xffffffff80000000, false},
+		{"i64.trunc_f64_s", valI64, cat(f64c(-1e18), []byte{0xb0}), u64(-1e18), false},
+		{"i64.trunc_sat_f64_s", valI64, cat(f64c(-1e300), []byte{0xfc, 6}), 1 << 63, false},
+		{"i64.trunc_sat_f32_u", valI64, cat(f32c(1e20), []byte{0xfc, 5}), math.MaxUint64, false},
+
+		// f32
+		{"f32.min -0", valF32, cat(f32c(negZero32), f32c(0), []byte{0x96}), 0x80000000, false},
+		{"f32.max -0", valF32, cat(f32c(negZero32), f32c(0), []byte{0x97}), 0, false},
+		{"f32.min nan", valF32, cat(f32c(nan32), f32c(1), []byte{0x96}), 0, true},
+		{"f32.nearest", valF32, cat(f32c(2.5), []byte{0x90}), f32bits(2), false},
+		{"f32.nearest -0.5", valF32, cat(f32c(-0.5), []byte{0x90}), 0x80000000, false},
+		{"f32.neg nan", valF32, cat(f32c(math.Float32frombits(0x7fc00000)), []byte{0x8c}), 0xffc00000, false},
+		{"f32.abs", valF32, cat(f32c(negZero32), []byte{0x8b}), 0, false},
+		{"f32.copysign", valF32, cat(f32c(1), f32c(negZero32), []byte{0x98}), f32bits(-1), false},
+		{"f32.convert_i64_u", valF32, cat(i64c(-1), []byte{0xb5}), f32bits(1 << 64), false},
+		{"f32.demote_f64", valF32, cat(f64c(1e300), []byte{0xb6}), f32bits(float32(math.Inf(1))), false},
+		{"f32.div", valF32, cat(f32c(1), f32c(0), []byte{0x95}), f32bits(float32(math.Inf(1))), false},
+		{"f32.sqrt", valF32, cat(f32c(-1), []byte{0x91}), 0, true},
+
+		// f64
+		{"f64.nearest", valF64, cat(f64c(3.5), []byte{0x9e}), math.Float64bits(4), false},
+		{"f64.nearest -3.5", valF64, cat(f64c(-3.5), []byte{0x9e}), math.Float64bits(-4), false},
+		{"f64.max nan", valF64, cat(f64c(math.NaN()), f64c(math.Inf(1)), []byte{0xa5}), 0, true},
+		{"f64.trunc", valF64, cat(f64c(-1.5), []byte{0x9d}), math.Float64bits(-1), false},
+		{"f64.floor", valF64, cat(f64c(-1.5), []byte{0x9c}), math.Float64bits(-2), false},
+		{"f64.ceil", valF64, cat(f64c(-0.5), []byte{0x9b}), math.Float64bits(negZero64), false},
+		{"f64.promote_f32", valF64, cat(f32c(1.5), []byte{0xbb}), math.Float64bits(1.5), false},
+		{"f64.convert_i32_u", valF64, cat(i32c(-1), []byte{0xb8}), math.Float64bits(4294967295), false},
+		{"f64.reinterpret_i64", valF64, cat(i64c(0x7ff0000000000001), []byte{0xbf}), 0x7ff0000000000001, false},
+
+		// control
+		// block (result i32) (br 0 (i32.const 7)) (i32.const 8) end
+		{"br", valI32, cat([]byte{0x02, valI32}, i32c(7), []byte{0x0c, 0}, i32c(8), []byte{0x0b}), 7, false},
+		// block (result i32) (drop (br_if 0 (i32.const 1) (i32.const 0))) (i32.const 2) end
+		{"br_if", valI32, cat([]byte{0x02, valI32}, i32c(1), i32c(0), []byte{0x0d, 0, 0x1a}, i32c(2), []byte{0x0b}),
+			2, false},
+		// block (result i32) block (result i32) (br_table 0 1 0 (i32.const 5) (i32.const 1)) end
+		// (i32.add (i32.const 100)) end
+		{"br_table", valI32, cat([]byte{0x02, valI32, 0x02, valI32}, i32c(5), i32c(1),
+			[]byte{0x0e, 2, 0, 1, 0, 0x0b}, i32c(100), []byte{0x6a, 0x0b}), 5, false},
+		{"br_table default", valI32, cat([]byte{0x02, valI32, 0x02, valI32}, i32c(5), i32c(7),
+			[]byte{0x0e, 2, 0, 1, 0, 0x0b}, i32c(100), []byte{0x6a, 0x0b}), 105, false},
+		// (if (result i32) (i32.const 0) (then (i32.const 1)) (else (i32.const 2)))
+		{"if", valI32, cat(i32c(0), []byte{0x04, valI32}, i32c(1), []byte{0x05}, i32c(2), []byte{0x0b}), 2, false},
+		// loop (br_if 0 (i32.lt_u (local.tee 0 (i32.add (local.get 0) (i32.const 1))) (i32.const 10))) end
+		// (local.get 0)
+		{"loop", valI32, cat([]byte{0x03, 0x40, 0x20, 0}, i32c(1), []byte{0x6a, 0x22, 0}, i32c(10),
+			[]byte{0x49, 0x0d, 0, 0x0b, 0x20, 0}), 10, false},
+		// (i32.sub (block (type 3) (i32.const 1) (i32.const 2)))
+		{"multi-value block", valI32, cat(i32c(1), i32c(2), []byte{0x02, 3, 0x0b, 0x6b}), u32(-1), false},
+		// (i32.sub (block (type 3) (i32.const 10) (i32.const 3) (br 0)))
+		{"multi-value br", valI32, cat(i32c(10), i32c(3), []byte{0x02, 3, 0x0c, 0, 0x0b, 0x6b}), 7, false},
+		// (select (i32.const 1) (i32.const 2) (i32.const 0))
+		{"select", valI32, cat(i32c(1), i32c(2), i32c(0), []byte{0x1b}), 2, false},
+		// block (result i32) (br 0 (i32.const 3)) i32.add end - unreachable code is polymorphic
+		{"unreachable code", valI32, cat([]byte{0x02, valI32}, i32c(3), []byte{0x0c, 0, 0x6a, 0x0b}), 3, false},
+
+		// memory
+		// (i32.store8 (i32.const 100) (i32.const 255)) (i32.load8_s (i32.const 100))
+		{"i32.load8_s", valI32, cat(i32c(100), i32c(255), []byte{0x3a, 0, 0}, i32c(100), []byte{0x2c, 0, 0}),
+			u32(-1), false},
+		// (i64.store offset=4 (i32.const 96) (i64.const -1)) (i64.load32_u (i32.const 100))
+		{"i64.load32_u", valI64, cat(i32c(96), i64c(-1), []byte{0x37, 3, 4}, i32c(100), []byte{0x35, 2, 0}),
+			0xffffffff, false},
+		// (memory.fill (i32.const 200) (i32.const 0xab) (i32.const 4)) (i32.load (i32.const 200))
+		{"memory.fill", valI32, cat(i32c(200), i32c(0xab), i32c(4), []byte{0xfc, 11, 0}, i32c(200),
+			[]byte{0x28, 2, 0}), 0xabababab, false},
+		// (i32.store (i32.const 300) (i32.const 0x04030201))
+		// (memory.copy (i32.const 301) (i32.const 300) (i32.const 3)) (i32.load (i32.const 300))
+		{"memory.copy", valI32, cat(i32c(300), i32c(0x04030201), []byte{0x36, 2, 0}, i32c(301), i32c(300), i32c(3),
+			[]byte{0xfc, 10, 0, 0}, i32c(300), []byte{0x28, 2, 0}), 0x03020101, false},
+		// (drop (memory.grow (i32.const 1))) (memory.size)
+		{"memory.grow", valI32, cat(i32c(1), []byte{0x40, 0, 0x1a, 0x3f, 0}), 2, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, err := run(t, testModule(1, specBody(test.typ, test.expr)...), &Config{})
+			tassert.CheckFatal(t, err)
+			b := make([]byte, 8)
+			copy(b, out)
+			got := binary.LittleEndian.Uint64(b)
+			switch {
+			case test.nan && test.typ == valF32:
+				tassert.Errorf(t, math.IsNaN(float64(f32(got))), "expected NaN, got 0x%x", got)
+			case test.nan:
+				tassert.Errorf(t, math.IsNaN(f64(got)), "expected NaN, got 0x%x", got)
+			default:
+				tassert.Errorf(t, got == test.want, "expected 0x%x, got 0x%x", test.want, got)
+			}
+		})
+	}
+}
+
+func TestSpecTrap(t *testing.T) {
+	tests := []struct {
+		name string
+		expr []byte
+		trap string
+	}{
+		{"i32.div_s by zero", cat(i32c(1), i32c(0), []byte{0x6d}), "integer divide by zero"},
+		{"i32.div_s overflow", cat(i32c(math.MinInt32), i32c(-1), []byte{0x6d}), "integer overflow"},
+		{"i64.rem_u by zero", cat(i64c(1), i64c(0), []byte{0x82, 0xa7}), "integer divide by zero"},
+		{"i32.trunc_f32_s nan", cat(f32c(nan32), []byte{0xa8}), "invalid conversion to integer"},
+		{"i32.trunc_f64_s overflow", cat(f64c(2147483648), []byte{0xaa}), "integer overflow"},
+		{"i64.trunc_f32_u overflow", cat(f32c(-1), []byte{0xaf, 0xa7}), "integer overflow"},
+		{"unreachable", []byte{0x00}, "unreachable"},
+		{"i32.load", cat(i32c(65533), []byte{0x28, 2, 0}), "out of bounds"},
+		// (i64.load offset=0xffffffff (i32.const 1)) - the effective address does not wrap
+		{"i64.load offset", cat(i32c(1), []byte{0x29, 3, 0xff, 0xff, 0xff, 0xff, 0x0f, 0xa7}), "out of bounds"},
+		{"memory.fill", cat(i32c(65535), i32c(0), i32c(2), []byte{0xfc, 11, 0}, i32c(0)), "out of bounds"},
+		{"memory.copy", cat(i32c(0), i32c(65535), i32c(2), []byte{0xfc, 10, 0, 0}, i32c(0)), "out of bounds"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, err := run(t, testModule(1, specBody(valI32, test.expr)...), &Config{})
+			tassert.Fatalf(t, err != nil, "expected trap %q, got output %x", test.trap, out)
+			tassert.Errorf(t, strings.Contains(err.Error(), test.trap), "expected trap %q, got %v", test.trap, err)
+			tassert.Errorf(t, out == "", "unexpected output %x", out)
+		})
+	}
+}
+
+func TestSpecInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body []byte
+	}{
+		{"operand type", cat(i32c(0), i64c(0), []byte{0x6a, 0x1a})},
+		{"values remaining", i32c(1)},
+		{"block result", []byte{0x02, valI32, 0x0b, 0x1a}},
+		{"block value type", cat([]byte{0x02, valI32}, i64c(0), []byte{0x0b, 0x1a})},
+		{"if without else", cat(i32c(1), []byte{0x04, valI32}, i32c(1), []byte{0x0b, 0x1a})},
+		{"if condition", cat(i64c(1), []byte{0x04, 0x40, 0x0b})},
+		{"br_if value", cat([]byte{0x02, valI32}, i64c(0), i32c(1), []byte{0x0d, 0, 0x0b, 0x1a})},
+		{"br_table arity", cat([]byte{0x02, valI32, 0x02, 0x40}, i32c(0), i32c(0),
+			[]byte{0x0e, 1, 0, 1, 0x0b, 0x0b, 0x1a})},
+		{"multi-value block params", cat(i32c(1), []byte{0x02, 3, 0x0b, 0x1a, 0x1a})},
+		{"select operand types", cat(i32c(0), i64c(0), i32c(1), []byte{0x1b, 0x1a})},
+		{"local.set type", cat(i64c(0), []byte{0x21, 0})},
+		{"invalid local", cat(i32c(0), []byte{0x21, 1})},
+		{"invalid global", []byte{0x23, 0, 0x1a}},
+		{"call argument type", cat(i64c(0), []byte{0x10, 2})},
+		{"call_indirect without table", cat(i32c(0), []byte{0x11, 2, 0})},
+		{"alignment", cat(i32c(0), []byte{0x28, 3, 0, 0x1a})},
+		{"store value type", cat(i32c(0), i64c(0), []byte{0x36, 2, 0})},
+		{"memory.init without data count", cat(i32c(0), i32c(0), i32c(0), []byte{0xfc, 8, 0, 0})},
+		{"data.drop without data count", []byte{0xfc, 9, 0}},
+		{"unreachable code operand type", cat([]byte{0x00}, i64c(0), []byte{0x6a, 0x1a})},
+		{"else without if", []byte{0x02, 0x40, 0x05, 0x0b}},
+		{"conversion operand type", cat(i32c(0), []byte{0xa7, 0x1a})},
+		{"trunc_sat operand type", cat(f64c(0), []byte{0xfc, 0, 0x1a})},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Compile(testModule(1, test.body...))
+			tassert.Errorf(t, err != nil, "expected invalid module")
+		})
+	}
+}
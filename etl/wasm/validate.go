@@ -0,0 +1,499 @@
+//go:build etlwasm
+// +build etlwasm
+
+// Package wasm provides sandboxed, in-process execution of WebAssembly (WASI) ETL transformers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package wasm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Function bodies are validated (type-checked) before they get compiled - see
+// https://webassembly.github.io/spec/core/valid/instructions.html and the validation
+// algorithm in https://webassembly.github.io/spec/core/appendix/algorithm.html.
+// The compiler (and the interpreter) then rely on the operand types, the stack heights,
+// and the indices being valid.
+
+// (operand of unknown type - in unreachable code)
+const valUnknown = 0
+
+type (
+	vframe struct {
+		op          byte // opBlock, opLoop, opIf, or opElse
+		params      []byte
+		results     []byte
+		height      int // operand stack height at the entry
+		unreachable bool
+	}
+	validator struct {
+		m      *Module
+		f      *function
+		r      *reader
+		locals []byte // (including params)
+		vals   []byte
+		ctl    []vframe
+	}
+)
+
+// load and store instructions (0x28 - 0x3e): operand type and natural alignment (log2)
+var memOps = [...]struct{ typ, align byte }{
+	{valI32, 2}, {valI64, 3}, {valF32, 2}, {valF64, 3}, // load
+	{valI32, 0}, {valI32, 0}, {valI32, 1}, {valI32, 1}, // i32.load8, i32.load16
+	{valI64, 0}, {valI64, 0}, {valI64, 1}, {valI64, 1}, {valI64, 2}, {valI64, 2}, // i64.load8, 16, 32
+	{valI32, 2}, {valI64, 3}, {valF32, 2}, {valF64, 3}, // store
+	{valI32, 0}, {valI32, 1}, {valI64, 0}, {valI64, 1}, {valI64, 2}, // i32.store8, 16; i64.store8, 16, 32
+}
+
+// conversions (0xa7 - 0xc4): operand and result types
+var convOps = [...][2]byte{
+	{valI64, valI32},                   // i32.wrap_i64
+	{valF32, valI32}, {valF32, valI32}, // i32.trunc_f32_*
+	{valF64, valI32}, {valF64, valI32}, // i32.trunc_f64_*
+	{valI32, valI64}, {valI32, valI64}, // i64.extend_i32_*
+	{valF32, valI64}, {valF32, valI64}, // i64.trunc_f32_*
+	{valF64, valI64}, {valF64, valI64}, // i64.trunc_f64_*
+	{valI32, valF32}, {valI32, valF32}, // f32.convert_i32_*
+	{valI64, valF32}, {valI64, valF32}, // f32.convert_i64_*
+	{valF64, valF32},                   // f32.demote_f64
+	{valI32, valF64}, {valI32, valF64}, // f64.convert_i32_*
+	{valI64, valF64}, {valI64, valF64}, // f64.convert_i64_*
+	{valF32, valF64},                   // f64.promote_f32
+	{valF32, valI32}, {valF64, valI64}, // i32.reinterpret_f32, i64.reinterpret_f64
+	{valI32, valF32}, {valI64, valF64}, // f32.reinterpret_i32, f64.reinterpret_i64
+	{valI32, valI32}, {valI32, valI32}, // i32.extend8_s, i32.extend16_s
+	{valI64, valI64}, {valI64, valI64}, {valI64, valI64}, // i64.extend8_s, 16_s, 32_s
+}
+
+func typeName(t byte) string {
+	switch t {
+	case valI32:
+		return "i32"
+	case valI64:
+		return "i64"
+	case valF32:
+		return "f32"
+	case valF64:
+		return "f64"
+	case valFuncref:
+		return "funcref"
+	case valExternref:
+		return "externref"
+	default:
+		return "unknown"
+	}
+}
+
+func isNum(t byte) bool {
+	return t == valI32 || t == valI64 || t == valF32 || t == valF64 || t == valUnknown
+}
+
+// returns the type of the constant expression
+func (m *Module) constType(expr constExpr) byte {
+	switch expr.op {
+	case 0x41:
+		return valI32
+	case 0x42:
+		return valI64
+	case 0x43:
+		return valF32
+	case 0x44:
+		return valF64
+	case 0x23:
+		return m.globals[expr.val].typ
+	case 0xd0:
+		return expr.typ
+	default: // ref.func
+		return valFuncref
+	}
+}
+
+// validates the constant expression of a given type; `nglobals` is the number of globals
+// it can refer to (only immutable ones)
+func (m *Module) validateConst(expr constExpr, typ byte, nglobals int) error {
+	if expr.op == 0x23 {
+		if int(expr.val) >= nglobals || m.globals[expr.val].mutable {
+			return fmt.Errorf("constant expression: invalid global %d", expr.val)
+		}
+	}
+	if t := m.constType(expr); t != typ {
+		return fmt.Errorf("constant expression: type mismatch (expected %s, got %s)", typeName(typ), typeName(t))
+	}
+	return nil
+}
+
+// validates the function body
+func (m *Module) validate(f *function, r *reader) (err error) {
+	v := &validator{m: m, f: f, r: r, locals: append([]byte{}, f.typ.params...)}
+	for n := r.u32(); n > 0; n-- {
+		cnt, typ := r.u32(), r.valType()
+		if uint64(len(v.locals)-len(f.typ.params))+uint64(cnt) > maxLocals {
+			return errors.New("too many locals")
+		}
+		for i := uint32(0); i < cnt; i++ {
+			v.locals = append(v.locals, typ)
+		}
+	}
+	v.pushCtl(opBlock, nil, f.typ.results)
+	for len(v.ctl) > 0 {
+		if err = v.next(); err != nil {
+			return
+		}
+	}
+	if r.off != len(r.b) {
+		return errors.New("unexpected instructions after the end of function")
+	}
+	return nil
+}
+
+func (v *validator) push(t byte) { v.vals = append(v.vals, t) }
+
+func (v *validator) pushVals(types []byte) { v.vals = append(v.vals, types...) }
+
+func (v *validator) pop() byte {
+	fr := &v.ctl[len(v.ctl)-1]
+	if len(v.vals) == fr.height {
+		if fr.unreachable {
+			return valUnknown
+		}
+		panic(errors.New("type mismatch: operand stack underflow"))
+	}
+	t := v.vals[len(v.vals)-1]
+	v.vals = v.vals[:len(v.vals)-1]
+	return t
+}
+
+func (v *validator) popExpect(expected byte) byte {
+	t := v.pop()
+	if t != expected && t != valUnknown && expected != valUnknown {
+		panic(fmt.Errorf("type mismatch: expected %s, got %s", typeName(expected), typeName(t)))
+	}
+	if t == valUnknown {
+		return expected
+	}
+	return t
+}
+
+func (v *validator) popVals(types []byte) {
+	for i := len(types) - 1; i >= 0; i-- {
+		v.popExpect(types[i])
+	}
+}
+
+func (v *validator) pushCtl(op byte, params, results []byte) {
+	v.ctl = append(v.ctl, vframe{op: op, params: params, results: results, height: len(v.vals)})
+	v.pushVals(params)
+}
+
+func (v *validator) popCtl() vframe {
+	fr := v.ctl[len(v.ctl)-1]
+	v.popVals(fr.results)
+	if len(v.vals) != fr.height {
+		panic(errors.New("type mismatch: values remaining on the operand stack at the end of block"))
+	}
+	v.ctl = v.ctl[:len(v.ctl)-1]
+	return fr
+}
+
+func (v *validator) labelTypes(depth uint32) []byte {
+	if int(depth) >= len(v.ctl) {
+		panic(fmt.Errorf("invalid branch depth %d", depth))
+	}
+	fr := &v.ctl[len(v.ctl)-1-int(depth)]
+	if fr.op == opLoop {
+		return fr.params
+	}
+	return fr.results
+}
+
+func (v *validator) setUnreachable() {
+	fr := &v.ctl[len(v.ctl)-1]
+	v.vals = v.vals[:fr.height]
+	fr.unreachable = true
+}
+
+func (v *validator) blockType() (params, results []byte) {
+	switch b := v.r.b[v.r.off]; b {
+	case 0x40:
+		v.r.off++
+		return nil, nil
+	case valI32, valI64, valF32, valF64, valFuncref, valExternref:
+		v.r.off++
+		return nil, []byte{b}
+	default:
+		idx := v.r.sleb(33)
+		if idx < 0 || idx >= int64(len(v.m.types)) {
+			panic(fmt.Errorf("invalid block type %d", idx))
+		}
+		ft := &v.m.types[idx]
+		return ft.params, ft.results
+	}
+}
+
+func (v *validator) memory() {
+	if v.m.mem == nil {
+		panic(errors.New("memory access without memory"))
+	}
+}
+
+func (v *validator) dataIdx(idx uint32) {
+	if v.m.dataCount < 0 {
+		panic(errors.New("data count section required"))
+	}
+	if int(idx) >= v.m.dataCount {
+		panic(fmt.Errorf("invalid data segment %d", idx))
+	}
+}
+
+func (v *validator) next() error {
+	var (
+		r  = v.r
+		op = r.byte()
+	)
+	switch {
+	case op == 0x00: // unreachable
+		v.setUnreachable()
+	case op == 0x01: // nop
+	case op == opBlock || op == opLoop || op == opIf:
+		params, results := v.blockType()
+		if op == opIf {
+			v.popExpect(valI32)
+		}
+		v.popVals(params)
+		v.pushCtl(op, params, results)
+	case op == opElse:
+		if v.ctl[len(v.ctl)-1].op != opIf {
+			return errors.New("unexpected else")
+		}
+		fr := v.popCtl()
+		v.pushCtl(opElse, fr.params, fr.results)
+	case op == opEnd:
+		fr := v.popCtl()
+		if fr.op == opIf && string(fr.params) != string(fr.results) {
+			return errors.New("type mismatch: if without else must leave its params on the stack")
+		}
+		v.pushVals(fr.results)
+	case op == opBr:
+		v.popVals(v.labelTypes(r.u32()))
+		v.setUnreachable()
+	case op == opBrIf:
+		types := v.labelTypes(r.u32())
+		v.popExpect(valI32)
+		v.popVals(types)
+		v.pushVals(types)
+	case op == opBrTable:
+		depths := make([]uint32, r.u32()+1)
+		for i := range depths {
+			depths[i] = r.u32()
+		}
+		v.popExpect(valI32)
+		dflt := v.labelTypes(depths[len(depths)-1])
+		for _, depth := range depths[:len(depths)-1] {
+			types := v.labelTypes(depth)
+			if len(types) != len(dflt) {
+				return errors.New("type mismatch: br_table targets of different arity")
+			}
+			saved := append([]byte{}, v.vals...)
+			v.popVals(types)
+			v.vals = saved
+		}
+		v.popVals(dflt)
+		v.setUnreachable()
+	case op == opReturn:
+		v.popVals(v.f.typ.results)
+		v.setUnreachable()
+	case op == 0x10: // call
+		idx := r.u32()
+		if int(idx) >= len(v.m.funcs) {
+			return fmt.Errorf("invalid function %d", idx)
+		}
+		ft := v.m.funcs[idx].typ
+		v.popVals(ft.params)
+		v.pushVals(ft.results)
+	case op == 0x11: // call_indirect
+		tidx, table := r.u32(), r.u32()
+		ft, err := v.m.funcType(tidx)
+		if err != nil {
+			return err
+		}
+		if table != 0 || v.m.table == nil {
+			return fmt.Errorf("call_indirect: invalid table %d", table)
+		}
+		v.popExpect(valI32)
+		v.popVals(ft.params)
+		v.pushVals(ft.results)
+	case op == 0x1a: // drop
+		v.pop()
+	case op == 0x1b: // select
+		v.popExpect(valI32)
+		t1, t2 := v.pop(), v.pop()
+		if !isNum(t1) || !isNum(t2) {
+			return errors.New("type mismatch: select of reference types requires type annotation")
+		}
+		if t1 != t2 && t1 != valUnknown && t2 != valUnknown {
+			return fmt.Errorf("type mismatch: select of %s and %s", typeName(t2), typeName(t1))
+		}
+		if t1 == valUnknown {
+			t1 = t2
+		}
+		v.push(t1)
+	case op == 0x1c: // select t
+		types := r.valTypes()
+		if len(types) != 1 {
+			return errors.New("invalid select type")
+		}
+		v.popExpect(valI32)
+		v.popExpect(types[0])
+		v.popExpect(types[0])
+		v.push(types[0])
+	case op >= 0x20 && op <= 0x22: // local.get, local.set, local.tee
+		idx := r.u32()
+		if int(idx) >= len(v.locals) {
+			return fmt.Errorf("invalid local %d", idx)
+		}
+		if op != 0x20 {
+			v.popExpect(v.locals[idx])
+		}
+		if op != 0x21 {
+			v.push(v.locals[idx])
+		}
+	case op == 0x23 || op == 0x24: // global.get, global.set
+		idx := r.u32()
+		if int(idx) >= len(v.m.globals) {
+			return fmt.Errorf("invalid global %d", idx)
+		}
+		g := &v.m.globals[idx]
+		if op == 0x23 {
+			v.push(g.typ)
+		} else {
+			if !g.mutable {
+				return fmt.Errorf("global.set: global %d is immutable", idx)
+			}
+			v.popExpect(g.typ)
+		}
+	case op >= 0x28 && op <= 0x3e: // load and store
+		v.memory()
+		mop := memOps[op-0x28]
+		if align := r.u32(); align > uint32(mop.align) {
+			return fmt.Errorf("alignment must not be larger than natural (opcode 0x%x)", op)
+		}
+		r.u32() // offset
+		if op >= 0x36 {
+			v.popExpect(mop.typ)
+			v.popExpect(valI32)
+		} else {
+			v.popExpect(valI32)
+			v.push(mop.typ)
+		}
+	case op == 0x3f || op == 0x40: // memory.size, memory.grow
+		if r.byte() != 0 {
+			return errors.New("invalid memory")
+		}
+		v.memory()
+		if op == 0x40 {
+			v.popExpect(valI32)
+		}
+		v.push(valI32)
+	case op == 0x41: // i32.const
+		r.s32()
+		v.push(valI32)
+	case op == 0x42: // i64.const
+		r.s64()
+		v.push(valI64)
+	case op == 0x43: // f32.const
+		r.bytes(4)
+		v.push(valF32)
+	case op == 0x44: // f64.const
+		r.bytes(8)
+		v.push(valF64)
+	case op == 0x45: // i32.eqz
+		v.popExpect(valI32)
+		v.push(valI32)
+	case op == 0x50: // i64.eqz
+		v.popExpect(valI64)
+		v.push(valI32)
+	case op >= 0x46 && op <= 0x66: // comparisons
+		t := cmpType(op)
+		v.popExpect(t)
+		v.popExpect(t)
+		v.push(valI32)
+	case op >= 0x67 && op <= 0xa6: // arithmetic
+		t, binary := arithType(op)
+		if binary {
+			v.popExpect(t)
+		}
+		v.popExpect(t)
+		v.push(t)
+	case op >= 0xa7 && op <= 0xc4: // conversions and sign extensions
+		conv := convOps[op-0xa7]
+		v.popExpect(conv[0])
+		v.push(conv[1])
+	case op == 0xfc:
+		return v.nextFC()
+	default:
+		return fmt.Errorf("unsupported instruction (opcode 0x%x)", op)
+	}
+	return nil
+}
+
+func (v *validator) nextFC() error {
+	r := v.r
+	switch sub := r.u32(); {
+	case sub <= 7: // *.trunc_sat_*
+		conv := convOps[1+sub+sub/4*2] // (same types as *.trunc_*)
+		v.popExpect(conv[0])
+		v.push(conv[1])
+	case sub == 8: // memory.init
+		v.dataIdx(r.u32())
+		if r.byte() != 0 {
+			return errors.New("invalid memory")
+		}
+		v.memory()
+		v.popVals([]byte{valI32, valI32, valI32})
+	case sub == 9: // data.drop
+		v.dataIdx(r.u32())
+	case sub == 10 || sub == 11: // memory.copy, memory.fill
+		if sub == 10 && r.byte() != 0 {
+			return errors.New("invalid memory")
+		}
+		if r.byte() != 0 {
+			return errors.New("invalid memory")
+		}
+		v.memory()
+		v.popVals([]byte{valI32, valI32, valI32})
+	default:
+		return fmt.Errorf("unsupported instruction (opcode 0xfc %d)", sub)
+	}
+	return nil
+}
+
+// operand type of the comparison (0x46 - 0x66)
+func cmpType(op byte) byte {
+	switch {
+	case op <= 0x4f:
+		return valI32
+	case op <= 0x5a:
+		return valI64
+	case op <= 0x60:
+		return valF32
+	default:
+		return valF64
+	}
+}
+
+// operand type of the arithmetic instruction (0x67 - 0xa6), and whether it is binary
+func arithType(op byte) (byte, bool) {
+	switch {
+	case op <= 0x78:
+		return valI32, op >= 0x6a
+	case op <= 0x8a:
+		return valI64, op >= 0x7c
+	case op <= 0x98:
+		return valF32, op >= 0x92
+	default:
+		return valF64, op >= 0xa0
+	}
+}
@@ -0,0 +1,461 @@
+//go:build etlwasm
+// +build etlwasm
+
+// Package wasm provides sandboxed, in-process execution of WebAssembly (WASI) ETL transformers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package wasm
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// WASI (https://github.com/WebAssembly/WASI/blob/main/legacy/preview1/docs.md):
+// arguments, environment, clocks, random, stdin/stdout/stderr (fd 0-2), and proc_exit.
+// There are no preopened directories (hence, no filesystem access) - the functions
+// that require any other file descriptor fail with EBADF, the rest with ENOSYS.
+
+const (
+	wasiModule         = "wasi_snapshot_preview1"
+	wasiUnstableModule = "wasi_unstable"
+)
+
+// errno
+const (
+	errnoSuccess = 0
+	errnoBadf    = 8
+	errnoFault   = 21
+	errnoInval   = 28
+	errnoIO      = 29
+	errnoNosys   = 52
+	errnoSpipe   = 70
+)
+
+const (
+	clockRealtime  = 0
+	clockMonotonic = 1
+
+	filetypeCharDevice = 2
+
+	eventClock   = 0
+	eventFdRead  = 1
+	eventFdWrite = 2
+
+	subscriptionClockAbstime = 1
+
+	rightsAll = 1<<30 - 1
+)
+
+type wasiDef struct {
+	params, results string // (i: i32, I: i64)
+	fn              hostFunc
+}
+
+var wasiFuncs = map[string]wasiDef{
+	"args_get":          {"ii", "i", argsGet},
+	"args_sizes_get":    {"ii", "i", argsSizesGet},
+	"environ_get":       {"ii", "i", environGet},
+	"environ_sizes_get": {"ii", "i", environSizesGet},
+	"clock_res_get":     {"ii", "i", clockResGet},
+	"clock_time_get":    {"iIi", "i", clockTimeGet},
+	"fd_close":          {"i", "i", fdClose},
+	"fd_fdstat_get":     {"ii", "i", fdFdstatGet},
+	"fd_fdstat_set_flags": {"ii", "i", func(inst *instance, s []uint64) {
+		s[0] = stdFd(s[0], errnoSuccess)
+	}},
+	"fd_filestat_get": {"ii", "i", fdFilestatGet},
+	"fd_prestat_get": {"ii", "i", func(_ *instance, s []uint64) {
+		s[0] = errnoBadf
+	}},
+	"fd_prestat_dir_name": {"iii", "i", func(_ *instance, s []uint64) {
+		s[0] = errnoBadf
+	}},
+	"fd_read": {"iiii", "i", fdRead},
+	"fd_seek": {"iIii", "i", func(_ *instance, s []uint64) {
+		s[0] = stdFd(s[0], errnoSpipe)
+	}},
+	"fd_write":      {"iiii", "i", fdWrite},
+	"poll_oneoff":   {"iiii", "i", pollOneoff},
+	"proc_exit":     {"i", "", procExit},
+	"random_get":    {"ii", "i", randomGet},
+	"sched_yield":   {"", "i", func(_ *instance, s []uint64) { s[0] = errnoSuccess }},
+	"proc_raise":    {"i", "i", nosys},
+	"sock_accept":   {"iii", "i", nosys},
+	"sock_recv":     {"iiiiii", "i", nosys},
+	"sock_send":     {"iiiii", "i", nosys},
+	"sock_shutdown": {"ii", "i", nosys},
+}
+
+// imported function (by name); unknown functions fail with ENOSYS
+func wasiFunc(name string, typ *funcType) (hostFunc, error) {
+	if def, ok := wasiFuncs[name]; ok {
+		if sig := wasiSig(def.params) + ":" + wasiSig(def.results); sig != string(typ.params)+":"+string(typ.results) {
+			return nil, fmt.Errorf("import %s.%s: invalid signature %s", wasiModule, name, typ)
+		}
+		return def.fn, nil
+	}
+	if len(typ.results) != 1 || typ.results[0] != valI32 {
+		return nil, fmt.Errorf("import %s.%s: unsupported function", wasiModule, name)
+	}
+	return nosys, nil
+}
+
+func wasiSig(s string) string {
+	b := make([]byte, len(s))
+	for i := range s {
+		b[i] = valI32
+		if s[i] == 'I' {
+			b[i] = valI64
+		}
+	}
+	return string(b)
+}
+
+func nosys(_ *instance, s []uint64) { s[0] = errnoNosys }
+
+// stdin, stdout, and stderr
+func stdFd(fd uint64, errno uint64) uint64 {
+	if uint32(fd) <= 2 {
+		return errno
+	}
+	return errnoBadf
+}
+
+//
+// memory access: out-of-bounds pointers result in EFAULT
+//
+
+func (inst *instance) memSlice(ptr, size uint64) ([]byte, bool) {
+	ptr = uint64(uint32(ptr))
+	if ptr+size > uint64(len(inst.mem)) {
+		return nil, false
+	}
+	return inst.mem[ptr : ptr+size], true
+}
+
+func (inst *instance) putU32(ptr uint64, v uint32) bool {
+	b, ok := inst.memSlice(ptr, 4)
+	if ok {
+		binary.LittleEndian.PutUint32(b, v)
+	}
+	return ok
+}
+
+func (inst *instance) putU64(ptr uint64, v uint64) bool {
+	b, ok := inst.memSlice(ptr, 8)
+	if ok {
+		binary.LittleEndian.PutUint64(b, v)
+	}
+	return ok
+}
+
+//
+// args and environ
+//
+
+func argsGet(inst *instance, s []uint64) { s[0] = inst.putStrings(inst.cfg.Args, s[0], s[1]) }
+func argsSizesGet(inst *instance, s []uint64) {
+	s[0] = inst.putSizes(inst.cfg.Args, s[0], s[1])
+}
+func environGet(inst *instance, s []uint64) { s[0] = inst.putStrings(inst.cfg.Env, s[0], s[1]) }
+func environSizesGet(inst *instance, s []uint64) {
+	s[0] = inst.putSizes(inst.cfg.Env, s[0], s[1])
+}
+
+func (inst *instance) putStrings(strs []string, ptrs, buf uint64) uint64 {
+	for i, str := range strs {
+		b, ok := inst.memSlice(buf, uint64(len(str)+1))
+		if !ok || !inst.putU32(ptrs+uint64(4*i), uint32(buf)) {
+			return errnoFault
+		}
+		copy(b, str)
+		b[len(str)] = 0
+		buf += uint64(len(str) + 1)
+	}
+	return errnoSuccess
+}
+
+func (inst *instance) putSizes(strs []string, countPtr, sizePtr uint64) uint64 {
+	size := 0
+	for _, str := range strs {
+		size += len(str) + 1
+	}
+	if !inst.putU32(countPtr, uint32(len(strs))) || !inst.putU32(sizePtr, uint32(size)) {
+		return errnoFault
+	}
+	return errnoSuccess
+}
+
+//
+// clocks and random
+//
+
+func clockResGet(inst *instance, s []uint64) {
+	if uint32(s[0]) > 3 {
+		s[0] = errnoInval
+		return
+	}
+	if !inst.putU64(s[1], 1) {
+		s[0] = errnoFault
+		return
+	}
+	s[0] = errnoSuccess
+}
+
+func clockTimeGet(inst *instance, s []uint64) {
+	var ts uint64
+	switch uint32(s[0]) {
+	case clockRealtime:
+		ts = uint64(time.Now().UnixNano())
+	case clockMonotonic, 2, 3: // (process and thread CPU time - same as monotonic)
+		ts = uint64(time.Since(inst.started).Nanoseconds())
+	default:
+		s[0] = errnoInval
+		return
+	}
+	if !inst.putU64(s[2], ts) {
+		s[0] = errnoFault
+		return
+	}
+	s[0] = errnoSuccess
+}
+
+func randomGet(inst *instance, s []uint64) {
+	b, ok := inst.memSlice(s[0], uint64(uint32(s[1])))
+	if !ok {
+		s[0] = errnoFault
+		return
+	}
+	if _, err := rand.Read(b); err != nil {
+		s[0] = errnoIO
+		return
+	}
+	s[0] = errnoSuccess
+}
+
+//
+// file descriptors
+//
+
+func fdClose(_ *instance, s []uint64) { s[0] = stdFd(s[0], errnoSuccess) }
+
+func fdFdstatGet(inst *instance, s []uint64) {
+	if uint32(s[0]) > 2 {
+		s[0] = errnoBadf
+		return
+	}
+	b, ok := inst.memSlice(s[1], 24)
+	if !ok {
+		s[0] = errnoFault
+		return
+	}
+	for i := range b {
+		b[i] = 0
+	}
+	b[0] = filetypeCharDevice
+	binary.LittleEndian.PutUint64(b[8:], rightsAll)
+	s[0] = errnoSuccess
+}
+
+func fdFilestatGet(inst *instance, s []uint64) {
+	if uint32(s[0]) > 2 {
+		s[0] = errnoBadf
+		return
+	}
+	b, ok := inst.memSlice(s[1], 64)
+	if !ok {
+		s[0] = errnoFault
+		return
+	}
+	for i := range b {
+		b[i] = 0
+	}
+	b[16] = filetypeCharDevice
+	binary.LittleEndian.PutUint64(b[24:], 1) // nlink
+	s[0] = errnoSuccess
+}
+
+// iovec: (buf u32, len u32)
+func (inst *instance) iovecs(ptr, cnt uint64) ([][]byte, bool) {
+	raw, ok := inst.memSlice(ptr, 8*uint64(uint32(cnt)))
+	if !ok {
+		return nil, false
+	}
+	iovs := make([][]byte, uint32(cnt))
+	for i := range iovs {
+		base, size := binary.LittleEndian.Uint32(raw[8*i:]), binary.LittleEndian.Uint32(raw[8*i+4:])
+		if iovs[i], ok = inst.memSlice(uint64(base), uint64(size)); !ok {
+			return nil, false
+		}
+	}
+	return iovs, true
+}
+
+func fdRead(inst *instance, s []uint64) {
+	if uint32(s[0]) != 0 {
+		s[0] = stdFd(s[0], errnoBadf)
+		return
+	}
+	iovs, ok := inst.iovecs(s[1], s[2])
+	if !ok {
+		s[0] = errnoFault
+		return
+	}
+	var (
+		total int
+		err   error
+		now   = time.Now()
+	)
+	if inst.cfg.Stdin != nil {
+		for _, iov := range iovs {
+			var n int
+			for n == 0 && err == nil && len(iov) > 0 {
+				n, err = inst.cfg.Stdin.Read(iov)
+			}
+			total += n
+			if n < len(iov) || err != nil {
+				break
+			}
+		}
+	}
+	inst.blocked += time.Since(now)
+	if err != nil && err != io.EOF && total == 0 {
+		s[0] = errnoIO
+		return
+	}
+	if !inst.putU32(s[3], uint32(total)) {
+		s[0] = errnoFault
+		return
+	}
+	s[0] = errnoSuccess
+}
+
+func fdWrite(inst *instance, s []uint64) {
+	var w io.Writer
+	switch uint32(s[0]) {
+	case 1:
+		w = inst.cfg.Stdout
+	case 2:
+		w = inst.cfg.Stderr
+	default:
+		s[0] = stdFd(s[0], errnoBadf)
+		return
+	}
+	iovs, ok := inst.iovecs(s[1], s[2])
+	if !ok {
+		s[0] = errnoFault
+		return
+	}
+	var (
+		total int
+		now   = time.Now()
+	)
+	for _, iov := range iovs {
+		if w != nil {
+			n, err := w.Write(iov)
+			if total += n; err != nil {
+				inst.blocked += time.Since(now)
+				if total == 0 {
+					s[0] = errnoIO
+					return
+				}
+				break
+			}
+		} else {
+			total += len(iov)
+		}
+	}
+	inst.blocked += time.Since(now)
+	if !inst.putU32(s[3], uint32(total)) {
+		s[0] = errnoFault
+		return
+	}
+	s[0] = errnoSuccess
+}
+
+// poll_oneoff(in, out, nsubscriptions, nevents): stdin and stdout are always ready
+// (reading and writing blocks), the clocks are waited for.
+// subscription: userdata u64, tag u8 @8, clock: id u32 @16, timeout u64 @24, flags u16 @40; fd: fd u32 @16
+// event:        userdata u64, errno u16 @8, type u8 @10, nbytes u64 @16
+func pollOneoff(inst *instance, s []uint64) {
+	const subSize, eventSize = 48, 32
+	var (
+		nsubs     = uint64(uint32(s[2]))
+		subs, ok1 = inst.memSlice(s[0], nsubs*subSize)
+		evs, ok2  = inst.memSlice(s[1], nsubs*eventSize)
+	)
+	if nsubs == 0 {
+		s[0] = errnoInval
+		return
+	}
+	if !ok1 || !ok2 {
+		s[0] = errnoFault
+		return
+	}
+	var (
+		nevents int
+		fdReady bool
+		timeout = time.Duration(-1)
+	)
+	for i := uint64(0); i < nsubs; i++ {
+		sub := subs[i*subSize:]
+		switch sub[8] {
+		case eventFdRead, eventFdWrite:
+			fdReady = true
+		case eventClock:
+			d := time.Duration(binary.LittleEndian.Uint64(sub[24:]))
+			if binary.LittleEndian.Uint16(sub[40:])&subscriptionClockAbstime != 0 {
+				if binary.LittleEndian.Uint32(sub[16:]) == clockRealtime {
+					d = time.Until(time.Unix(0, int64(d)))
+				} else {
+					d -= time.Since(inst.started)
+				}
+			}
+			if timeout < 0 || d < timeout {
+				timeout = d
+			}
+		}
+	}
+	if !fdReady && timeout > 0 {
+		now := time.Now()
+		select {
+		case <-time.After(timeout):
+		case <-inst.ctx.Done():
+		}
+		inst.blocked += time.Since(now)
+	}
+	for i := uint64(0); i < nsubs; i++ {
+		sub := subs[i*subSize:]
+		if sub[8] == eventClock && fdReady {
+			continue
+		}
+		ev := evs[nevents*eventSize : (nevents+1)*eventSize]
+		for j := range ev {
+			ev[j] = 0
+		}
+		copy(ev, sub[:8]) // userdata
+		ev[10] = sub[8]
+		if sub[8] != eventClock {
+			if fd := binary.LittleEndian.Uint32(sub[16:]); fd > 2 || (sub[8] == eventFdRead) != (fd == 0) {
+				binary.LittleEndian.PutUint16(ev[8:], errnoBadf)
+			} else {
+				binary.LittleEndian.PutUint64(ev[16:], 1)
+			}
+		}
+		nevents++
+	}
+	if !inst.putU32(s[3], uint32(nevents)) {
+		s[0] = errnoFault
+		return
+	}
+	s[0] = errnoSuccess
+}
+
+func procExit(_ *instance, s []uint64) {
+	panic(&ExitError{Code: uint32(s[0])})
+}
@@ -0,0 +1,118 @@
+//go:build etlwasm
+// +build etlwasm
+
+// Package wasm provides sandboxed, in-process execution of WebAssembly (WASI) ETL transformers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// The package implements a (pure Go) interpreter of WebAssembly modules
+// (https://webassembly.github.io/spec/core/) - the MVP plus sign-extension,
+// non-trapping float-to-int conversion, bulk memory, and multi-value proposals - along
+// with the subset of WASI (`wasi_snapshot_preview1`) that is needed to run a *command*
+// that transforms its standard input into its standard output.
+//
+// The module is sandboxed:
+// * its only view of the outside world is its arguments, environment, and stdin/stdout/stderr
+//   (no filesystem, sockets, or other file descriptors);
+// * its linear memory is limited by `Config.MemLimit`;
+// * its execution time (excluding the time spent waiting for its input or output)
+//   is limited by `Config.CPULimit`;
+// * a trap (e.g., out-of-bounds memory access) terminates the execution with error.
+//
+// The module is validated and compiled once (`Compile`) and then can be run any number of times,
+// concurrently - each run (`Module.Run`) is a new instance with its own memory.
+
+const (
+	Enabled  = true
+	pageSize = 64 * 1024
+)
+
+type (
+	// Module is a compiled (decoded and prepared for execution) WebAssembly module.
+	Module struct {
+		types     []funcType
+		funcs     []*function // imported (host) functions first
+		table     *limits
+		mem       *limits
+		globals   []global
+		exports   map[string]export
+		elems     []elemSegment
+		datas     []dataSegment
+		dataCount int // data count section (-1: none)
+		start     int // start function (-1: none)
+	}
+
+	// trap terminates the execution of the module
+	trap struct {
+		err error
+	}
+)
+
+func newTrap(format string, a ...interface{}) trap { return trap{fmt.Errorf(format, a...)} }
+
+// Compile decodes the binary module and prepares it for execution.
+func Compile(code []byte) (m *Module, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			m, err = nil, fmt.Errorf("invalid WebAssembly module: %v", r)
+		}
+	}()
+	m = &Module{start: -1, dataCount: -1}
+	if err = m.decode(&reader{b: code}); err != nil {
+		return nil, fmt.Errorf("invalid WebAssembly module: %v", err)
+	}
+	return m, nil
+}
+
+// Run instantiates the module and executes it as WASI command (`_start`), until it returns,
+// exits, traps, exceeds the CPU time limit, or the context is done.
+func (m *Module) Run(ctx context.Context, cfg *Config) (err error) {
+	exp, ok := m.exports["_start"]
+	if !ok || exp.kind != externFunc {
+		return errors.New("WebAssembly module does not export \"_start\" function (not a WASI command)")
+	}
+	inst, err := m.instantiate(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = inst.recovered(r)
+		}
+	}()
+	if m.start >= 0 {
+		inst.invoke(m.funcs[m.start], 0)
+	}
+	inst.invoke(m.funcs[exp.idx], 0)
+	return nil
+}
+
+func (inst *instance) recovered(r interface{}) (err error) {
+	switch e := r.(type) {
+	case *ExitError:
+		if e.Code == 0 {
+			return nil
+		}
+		err = e
+	case trap:
+		err = e.err
+	case runtime.Error:
+		// (not expected - the module is validated when compiled)
+		err = fmt.Errorf("invalid WebAssembly module: %v", e)
+	default:
+		panic(r)
+	}
+	if inst.memLimited && !errors.Is(err, ErrCPULimit) {
+		err = fmt.Errorf("%v (%w: %d bytes)", err, ErrMemLimit, uint64(inst.maxPages)*pageSize)
+	}
+	return err
+}
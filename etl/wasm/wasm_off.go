@@ -0,0 +1,19 @@
+//go:build !etlwasm
+// +build !etlwasm
+
+// Package wasm provides sandboxed, in-process execution of WebAssembly (WASI) ETL transformers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package wasm
+
+import "context"
+
+const Enabled = false
+
+// Module is a placeholder - the interpreter is not built (see api.go).
+type Module struct{}
+
+func Compile([]byte) (*Module, error) { return nil, ErrNotBuilt }
+
+func (*Module) Run(context.Context, *Config) error { return ErrNotBuilt }
@@ -0,0 +1,247 @@
+//go:build etlwasm
+// +build etlwasm
+
+// Package wasm provides sandboxed, in-process execution of WebAssembly (WASI) ETL transformers.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+// Hand-assembled modules: all import fd_read (0), fd_write (1), and proc_exit (2),
+// have one page of memory, and export `_start` (3) with the given body.
+// (Type 3 - (i32, i32) -> (i32, i32) - is for multi-value blocks.)
+func testModule(nlocals byte, body ...byte) []byte {
+	var (
+		fn    = append([]byte{1, nlocals, valI32}, append(body, opEnd)...)
+		wasi  = []byte("\x16wasi_snapshot_preview1")
+		iovFn = []byte{0x60, 4, valI32, valI32, valI32, valI32, 1, valI32}
+	)
+	imp := func(name string, typ byte) []byte {
+		return append(append(append([]byte{}, wasi...), append([]byte{byte(len(name))}, name...)...), 0, typ)
+	}
+	sec := func(id byte, items ...[]byte) []byte {
+		b := []byte{byte(len(items))}
+		for _, item := range items {
+			b = append(b, item...)
+		}
+		return append([]byte{id, byte(len(b))}, b...)
+	}
+	m := []byte("\x00asm\x01\x00\x00\x00")
+	m = append(m, sec(1, iovFn, []byte{0x60, 1, valI32, 0}, []byte{0x60, 0, 0},
+		[]byte{0x60, 2, valI32, valI32, 2, valI32, valI32})...)
+	m = append(m, sec(2, imp("fd_read", 0), imp("fd_write", 0), imp("proc_exit", 1))...)
+	m = append(m, sec(3, []byte{2})...)
+	m = append(m, sec(5, []byte{0, 1})...)
+	m = append(m, sec(7, []byte("\x06_start\x00\x03"))...)
+	return append(m, sec(10, append([]byte{byte(len(fn))}, fn...))...)
+}
+
+// upperBody uppercases stdin:
+//
+//	(local $n i32) (local $i i32)
+//	block
+//	  loop
+//	    ;; iovec at 0: {buf: 64, len: 1024}; nread at 8
+//	    (i32.store (i32.const 0) (i32.const 64)) (i32.store (i32.const 4) (i32.const 1024))
+//	    (br_if 1 (call $fd_read (i32.const 0) (i32.const 0) (i32.const 1) (i32.const 8)))
+//	    (br_if 1 (i32.eqz (local.tee $n (i32.load (i32.const 8)))))
+//	    (local.set $i (i32.const 0))
+//	    loop
+//	      (if (i32.lt_u (i32.sub (i32.load8_u offset=64 (local.get $i)) (i32.const 97)) (i32.const 26))
+//	        (then (i32.store8 offset=64 (local.get $i) (i32.sub (i32.load8_u offset=64 (local.get $i)) (i32.const 32)))))
+//	      (br_if 0 (i32.lt_u (local.tee $i (i32.add (local.get $i) (i32.const 1))) (local.get $n)))
+//	    end
+//	    (i32.store (i32.const 4) (local.get $n))
+//	    (drop (call $fd_write (i32.const 1) (i32.const 0) (i32.const 1) (i32.const 12)))
+//	    (br 0)
+//	  end
+//	end
+var upperBody = []byte{
+	0x02, 0x40, 0x03, 0x40,
+	0x41, 0x00, 0x41, 0xc0, 0x00, 0x36, 0x02, 0x00, 0x41, 0x04, 0x41, 0x80, 0x08, 0x36, 0x02, 0x00,
+	0x41, 0x00, 0x41, 0x00, 0x41, 0x01, 0x41, 0x08, 0x10, 0x00, 0x0d, 0x01,
+	0x41, 0x08, 0x28, 0x02, 0x00, 0x22, 0x00, 0x45, 0x0d, 0x01,
+	0x41, 0x00, 0x21, 0x01,
+	0x03, 0x40,
+	0x20, 0x01, 0x2d, 0x00, 0x40, 0x41, 0xe1, 0x00, 0x6b, 0x41, 0x1a, 0x49,
+	0x04, 0x40, 0x20, 0x01, 0x20, 0x01, 0x2d, 0x00, 0x40, 0x41, 0x20, 0x6b, 0x3a, 0x00, 0x40, 0x0b,
+	0x20, 0x01, 0x41, 0x01, 0x6a, 0x22, 0x01, 0x20, 0x00, 0x49, 0x0d, 0x00,
+	0x0b,
+	0x41, 0x04, 0x20, 0x00, 0x36, 0x02, 0x00,
+	0x41, 0x01, 0x41, 0x00, 0x41, 0x01, 0x41, 0x0c, 0x10, 0x01, 0x1a,
+	0x0c, 0x00,
+	0x0b, 0x0b,
+}
+
+func run(tb testing.TB, code []byte, cfg *Config) (string, error) {
+	m, err := Compile(code)
+	tassert.CheckFatal(tb, err)
+	var out bytes.Buffer
+	cfg.Stdout = &out
+	if cfg.Stdin == nil {
+		cfg.Stdin = strings.NewReader("")
+	}
+	err = m.Run(context.Background(), cfg)
+	return out.String(), err
+}
+
+func TestRun(t *testing.T) {
+	in := strings.Repeat("Hello, World! ", 1000)
+	out, err := run(t, testModule(2, upperBody...), &Config{Stdin: strings.NewReader(in)})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, out == strings.ToUpper(in), "unexpected output: %.64q...", out)
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body []byte
+		cfg  Config
+		is   error
+		exit uint32
+	}{
+		// (call $proc_exit (i32.const 3))
+		{name: "exit", body: []byte{0x41, 0x03, 0x10, 0x02}, exit: 3},
+		// (drop (i32.div_s (i32.const 1) (i32.const 0)))
+		{name: "trap", body: []byte{0x41, 0x01, 0x41, 0x00, 0x6d, 0x1a}},
+		// (i32.store (i32.const 65536) (i32.const 0))
+		{name: "out-of-bounds", body: []byte{0x41, 0x80, 0x80, 0x04, 0x41, 0x00, 0x36, 0x02, 0x00}},
+		// loop (br 0) end
+		{name: "cpu-limit", body: []byte{0x03, 0x40, 0x0c, 0x00, 0x0b}, cfg: Config{CPULimit: 100 * time.Millisecond},
+			is: ErrCPULimit},
+		// loop (br_if 0 (i32.ne (memory.grow (i32.const 1)) (i32.const -1))) end unreachable
+		{name: "mem-limit", body: []byte{0x03, 0x40, 0x41, 0x01, 0x40, 0x00, 0x41, 0x7f, 0x47, 0x0d, 0x00, 0x0b, 0x00},
+			cfg: Config{MemLimit: 16 * pageSize}, is: ErrMemLimit},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := run(t, testModule(0, test.body...), &test.cfg)
+			tassert.Fatalf(t, err != nil, "expected error")
+			if test.is != nil {
+				tassert.Errorf(t, errors.Is(err, test.is), "expected %v, got %v", test.is, err)
+			}
+			var exitErr *ExitError
+			if errors.As(err, &exitErr) != (test.exit != 0) || (exitErr != nil && exitErr.Code != test.exit) {
+				t.Errorf("expected exit status %d, got %v", test.exit, err)
+			}
+		})
+	}
+
+	// initial memory does not fit
+	m, err := Compile(testModule(0))
+	tassert.CheckFatal(t, err)
+	err = m.Run(context.Background(), &Config{Stdin: &bytes.Buffer{}, Stdout: &bytes.Buffer{}, MemLimit: pageSize / 2})
+	tassert.Errorf(t, errors.Is(err, ErrMemLimit), "expected %v, got %v", ErrMemLimit, err)
+
+	// context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m, err = Compile(testModule(0, 0x03, 0x40, 0x0c, 0x00, 0x0b))
+	tassert.CheckFatal(t, err)
+	err = m.Run(ctx, &Config{Stdin: &bytes.Buffer{}, Stdout: &bytes.Buffer{}})
+	tassert.Errorf(t, errors.Is(err, context.Canceled), "expected %v, got %v", context.Canceled, err)
+}
+
+func TestCompileErrors(t *testing.T) {
+	valid := testModule(2, upperBody...)
+	for _, code := range [][]byte{
+		nil,
+		[]byte("\x00asm\x02\x00\x00\x00"),
+		valid[:len(valid)-1],
+		testModule(0, 0x6a),       // i32.add: stack underflow
+		testModule(0, 0x0c, 0x05), // invalid branch depth
+	} {
+		if _, err := Compile(code); err == nil {
+			t.Errorf("expected error compiling %x", code)
+		}
+	}
+}
+
+// WASI command built with Go (requires go1.21+ - skipped otherwise)
+const goSource = `package main
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+func main() {
+	if os.Args[1] == "fail" {
+		os.Exit(2)
+	}
+	b, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		panic(err)
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		zw := gzip.NewWriter(pw)
+		zw.Write(b)
+		zw.Close()
+		pw.Close()
+	}()
+	zr, err := gzip.NewReader(pr)
+	if err != nil {
+		panic(err)
+	}
+	rt, _ := io.ReadAll(zr)
+	fmt.Printf("%s %x %s|%s", strings.ToUpper(string(rt[:5])), sha256.Sum256(rt), os.Args[1], os.Getenv("AIS_ETL_ARGS"))
+	fmt.Fprintln(os.Stderr, "done")
+}
+`
+
+func TestGoModule(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+	dir := t.TempDir()
+	tassert.CheckFatal(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(goSource), cos.PermRWR))
+	cmd := exec.Command("go", "build", "-o", "main.wasm", "main.go")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm", "GO111MODULE=off")
+	if b, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("failed to build wasip1 module: %v (%s)", err, b)
+	}
+	code, err := os.ReadFile(filepath.Join(dir, "main.wasm"))
+	tassert.CheckFatal(t, err)
+
+	in := bytes.Repeat([]byte("hello world "), 100_000)
+	var stderr bytes.Buffer
+	out, err := run(t, code, &Config{
+		Stdin:    bytes.NewReader(in),
+		Stderr:   &stderr,
+		Args:     []string{"etl", "a b"},
+		Env:      []string{"AIS_ETL_ARGS=a b"},
+		MemLimit: 256 * 1024 * 1024,
+		CPULimit: time.Minute,
+	})
+	tassert.CheckFatal(t, err)
+	expected := fmt.Sprintf("HELLO %x a b|a b", sha256.Sum256(in))
+	tassert.Errorf(t, out == expected, "expected %q, got %q", expected, out)
+	tassert.Errorf(t, stderr.String() == "done\n", "unexpected stderr %q", stderr.String())
+
+	_, err = run(t, code, &Config{Args: []string{"etl", "fail"}})
+	var exitErr *ExitError
+	tassert.Errorf(t, errors.As(err, &exitErr) && exitErr.Code == 2, "expected exit status 2, got %v", err)
+}
@@ -33,10 +33,15 @@ import (
 const (
 	contentTypeLen = 2
 
-	ObjectType   = "ob"
-	WorkfileType = "wk"
-	ECSliceType  = "ec"
-	ECMetaType   = "mt"
+	ObjectType     = "ob"
+	WorkfileType   = "wk"
+	ECSliceType    = "ec"
+	ECMetaType     = "mt"
+	ObjVersionType = "vr" // retained (noncurrent) versions of objects
+	ETLCacheType   = "et" // cached results of ETL (see etl/cache.go)
+
+	// length of the (hex) key that identifies cached ETL result
+	ETLCacheKeyLen = 16
 )
 
 type (
@@ -162,10 +167,12 @@ func (f *contentSpecMgr) PermToProcess(fqn string) (ok bool) {
 // FIXME: This should be probably placed somewhere else \/
 
 type (
-	ObjectContentResolver   struct{}
-	WorkfileContentResolver struct{}
-	ECSliceContentResolver  struct{}
-	ECMetaContentResolver   struct{}
+	ObjectContentResolver     struct{}
+	WorkfileContentResolver   struct{}
+	ECSliceContentResolver    struct{}
+	ECMetaContentResolver     struct{}
+	ObjVersionContentResolver struct{}
+	ETLCacheContentResolver   struct{}
 )
 
 func (*ObjectContentResolver) PermToMove() bool                   { return true }
@@ -234,3 +241,40 @@ func (*ECMetaContentResolver) GenUniqueFQN(base, _ string) string { return base
 func (*ECMetaContentResolver) ParseUniqueFQN(base string) (orig string, old, ok bool) {
 	return base, false, true
 }
+
+// Retained version of an object is stored as <object-name>.<version>
+// (see versioning.retain); the version is passed as prefix. Global rebalance
+// moves retained versions along with their objects.
+func (*ObjVersionContentResolver) PermToMove() bool    { return true }
+func (*ObjVersionContentResolver) PermToEvict() bool   { return false }
+func (*ObjVersionContentResolver) PermToProcess() bool { return false }
+
+func (*ObjVersionContentResolver) GenUniqueFQN(base, ver string) string { return base + "." + ver }
+
+func (*ObjVersionContentResolver) ParseUniqueFQN(base string) (orig string, old, ok bool) {
+	idx := strings.LastIndex(base, ".")
+	if idx <= 0 {
+		return "", false, false
+	}
+	return base[:idx], false, true
+}
+
+// Cached result of ETL is stored as <object-name>.<key>, where the key
+// (ETLCacheKeyLen hex digits) is passed as prefix. The results can always be
+// recomputed: they are never moved and are evicted first.
+func (*ETLCacheContentResolver) PermToMove() bool    { return false }
+func (*ETLCacheContentResolver) PermToEvict() bool   { return true }
+func (*ETLCacheContentResolver) PermToProcess() bool { return false }
+
+func (*ETLCacheContentResolver) GenUniqueFQN(base, key string) string { return base + "." + key }
+
+func (*ETLCacheContentResolver) ParseUniqueFQN(base string) (orig string, old, ok bool) {
+	idx := len(base) - ETLCacheKeyLen - 1
+	if idx <= 0 || base[idx] != '.' {
+		return "", false, false
+	}
+	if _, err := strconv.ParseUint(base[idx+1:], 16, 64); err != nil {
+		return "", false, false
+	}
+	return base[:idx], false, true
+}
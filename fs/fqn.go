@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
 )
@@ -28,8 +29,17 @@ const (
 	WorkfileAppend       = "append"         // APPEND to object (as file)
 	WorkfileAppendToArch = "append-to-arch" // APPEND to existing archive
 	WorkfileCreateArch   = "create-arch"    // CREATE multi-object archive
+	WorkfileMptPart      = "mpt-part"       // S3 multipart upload: uploaded part
+	WorkfilePartial      = "partial"        // range GET: cached extent of remote object
+	WorkfileETLCache     = "etl-cache"      // on-the-fly ETL: result to be cached
+	WorkfileWriteBack    = "write-back"     // write-back: snapshot of the object being written
+	WorkfileReplicate    = "replicate"      // replication: snapshot of the object being replicated
 )
 
+// S3 multipart upload parts outlive target restarts (the uploads are persisted) and
+// are removed when the upload is completed, aborted, or expires
+const MptPartMaxAge = 7 * 24 * time.Hour
+
 type ParsedFQN struct {
 	MpathInfo   *MountpathInfo
 	ContentType string
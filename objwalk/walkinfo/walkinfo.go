@@ -159,11 +159,12 @@ func (wi *WalkInfo) lsObject(lom *cluster.LOM, objStatus uint16) *cmn.BucketEntr
 		return fileInfo
 	}
 
+	oa := lom.PlainAttrs() // (encrypted objects: plaintext size and checksum)
 	if wi.needAtime() {
 		fileInfo.Atime = cos.FormatUnixNano(lom.AtimeUnix(), wi.timeFormat)
 	}
-	if wi.needCksum() && lom.Checksum() != nil {
-		fileInfo.Checksum = lom.Checksum().Value()
+	if wi.needCksum() && oa.Cksum != nil {
+		fileInfo.Checksum = oa.Cksum.Value()
 	}
 	if wi.needVersion() {
 		fileInfo.Version = lom.Version()
@@ -175,7 +176,7 @@ func (wi *WalkInfo) lsObject(lom *cluster.LOM, objStatus uint16) *cmn.BucketEntr
 		fileInfo.TargetURL = wi.t.Snode().URL(cmn.NetworkPublic)
 	}
 	if wi.needSize() {
-		fileInfo.Size = lom.SizeBytes()
+		fileInfo.Size = oa.Size
 	}
 	if wi.postCallback != nil {
 		wi.postCallback(lom)
@@ -193,27 +194,20 @@ func (wi *WalkInfo) Callback(fqn string, de fs.DirEntry) (*cmn.BucketEntry, erro
 		return nil, nil
 	}
 
-	var objStatus uint16 = cmn.ObjStatusOK
 	lom := &cluster.LOM{FQN: fqn}
 	if err := lom.Init(cmn.Bck{}); err != nil {
 		return nil, err
 	}
-
-	_, local, err := lom.HrwTarget(wi.smap)
+	objStatus, err := wi.objStatus(lom)
 	if err != nil {
 		return nil, err
 	}
-	if !local {
-		objStatus = cmn.ObjStatusMovedNode
-	} else if !lom.IsHRW() {
-		objStatus = cmn.ObjStatusMovedMpath
-	}
-
 	if isObjMoved(objStatus) && !wi.msg.IsFlagSet(cmn.LsMisplaced) {
 		return nil, nil
 	}
-	// LsOnlyNames skips loading object's metadata.
-	if wi.msg.IsFlagSet(cmn.LsOnlyNames) {
+	// LsOnlyNames skips loading object's metadata
+	// (except buckets that may contain delete markers - see versioning.retain)
+	if wi.msg.IsFlagSet(cmn.LsOnlyNames) && !lom.VersionConf().Retain {
 		return wi.lsObject(lom, objStatus), nil
 	}
 
@@ -228,3 +222,74 @@ func (wi *WalkInfo) Callback(fqn string, de fs.DirEntry) (*cmn.BucketEntry, erro
 	}
 	return wi.lsObject(lom, objStatus), nil
 }
+
+// CallbackVersions is Callback's counterpart that lists all versions of the
+// object (cmn.LsVersions): retained (noncurrent) versions, if any, followed by
+// the object itself or its delete marker.
+func (wi *WalkInfo) CallbackVersions(fqn string, de fs.DirEntry) ([]*cmn.BucketEntry, error) {
+	if de.IsDir() {
+		return nil, nil
+	}
+	lom := &cluster.LOM{FQN: fqn}
+	if err := lom.Init(cmn.Bck{}); err != nil {
+		return nil, err
+	}
+	objStatus, err := wi.objStatus(lom)
+	if err != nil {
+		return nil, err
+	}
+	if isObjMoved(objStatus) && !wi.msg.IsFlagSet(cmn.LsMisplaced) {
+		return nil, nil
+	}
+	if err := lom.LoadLatest(false /*locked*/); err != nil {
+		if cmn.IsErrObjNought(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if lom.IsCopy() {
+		return nil, nil
+	}
+	head := wi.lsObject(lom, objStatus)
+	if head == nil {
+		return nil, nil
+	}
+	head.Version = lom.Version()
+	if lom.IsDelMarker() {
+		head.Flags |= cmn.EntryDelMarker
+	}
+	vers, err := lom.Versions()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*cmn.BucketEntry, 0, len(vers)+1)
+	for _, ver := range vers {
+		vlom, err := lom.LoadVersion(ver)
+		if err != nil {
+			continue // removed in the meantime
+		}
+		entry := wi.lsObject(vlom, objStatus)
+		cluster.FreeLOM(vlom)
+		if entry == nil {
+			continue
+		}
+		entry.Version = ver
+		entry.Flags |= cmn.EntryNoncurrent
+		entries = append(entries, entry)
+	}
+	return append(entries, head), nil
+}
+
+func (wi *WalkInfo) objStatus(lom *cluster.LOM) (uint16, error) {
+	_, local, err := lom.HrwTarget(wi.smap)
+	if err != nil {
+		return 0, err
+	}
+	if !local {
+		return cmn.ObjStatusMovedNode, nil
+	}
+	if !lom.IsHRW() {
+		return cmn.ObjStatusMovedMpath, nil
+	}
+	return cmn.ObjStatusOK, nil
+}
@@ -573,6 +573,7 @@ func (reb *Reb) retransmit(rargs *rebArgs) (cnt int) {
 			if roc, err := _prepSend(lom); err != nil {
 				glog.Errorf("%s: failed to retransmit %s => %s: %v", loghdr, lom, tsi.StringEx(), err)
 			} else {
+				rj.sendVersions(lom, tsi)
 				rj.doSend(lom, tsi, roc)
 				glog.Warningf("%s: retransmitting %s => %s", loghdr, lom, tsi.StringEx())
 				cnt++
@@ -722,6 +723,7 @@ func (rj *rebJogger) _lwalk(lom *cluster.LOM) (err error) {
 	}
 	// transmit
 	rj.m.addLomAck(lom)
+	rj.sendVersions(lom, tsi)
 	rj.doSend(lom, tsi, roc)
 	return
 }
@@ -729,7 +731,7 @@ func (rj *rebJogger) _lwalk(lom *cluster.LOM) (err error) {
 func _prepSend(lom *cluster.LOM) (roc cos.ReadOpenCloser, err error) {
 	clone := lom.Clone(lom.FQN)
 	lom.Lock(false)
-	if err = lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+	if err = lom.LoadLatest(true /*locked*/); err != nil { // (delete markers move as well)
 		goto retErr
 	}
 	if lom.IsCopy() {
@@ -755,6 +757,52 @@ retErr:
 	return
 }
 
+// Retained versions of the object (see versioning.retain) are sent ahead of the object
+// over the same stream and are not acknowledged: the object's ACK covers them as well.
+// The caller must hold the object's r-lock (see _prepSend).
+func (rj *rebJogger) sendVersions(lom *cluster.LOM, tsi *cluster.Snode) {
+	if !lom.VersionConf().Retain {
+		return
+	}
+	vers, err := lom.Versions()
+	if err != nil {
+		glog.Errorf("%s: failed to list versions of %s: %v", rj.m.t.Snode(), lom, err)
+		return
+	}
+	ack := regularAck{rebID: rj.m.RebID(), daemonID: rj.m.t.SID()}
+	for _, ver := range vers {
+		vlom, err := lom.LoadVersion(ver)
+		if err != nil {
+			glog.Errorf("%s: failed to load %s version %q: %v", rj.m.t.Snode(), lom, ver, err)
+			continue
+		}
+		fh, err := cos.NewFileHandle(vlom.FQN)
+		if err != nil {
+			glog.Errorf("%s: failed to open %s version %q: %v", rj.m.t.Snode(), lom, ver, err)
+			cluster.FreeLOM(vlom)
+			continue
+		}
+		o := transport.AllocSend()
+		o.Hdr.Bck = lom.Bucket()
+		o.Hdr.ObjName = lom.ObjName
+		o.Hdr.Opaque = ack.newPack(rebMsgVersion)
+		o.Hdr.ObjAttrs.CopyFrom(vlom.ObjAttrs())
+		o.Callback, o.CmplArg = rj.verSentCallback, vlom
+		rj.m.inQueue.Inc()
+		rj.m.dm.Send(o, fh, tsi)
+	}
+}
+
+func (rj *rebJogger) verSentCallback(hdr transport.ObjHdr, _ io.ReadCloser, arg interface{}, err error) {
+	rj.m.inQueue.Dec()
+	cluster.FreeLOM(arg.(*cluster.LOM))
+	if err != nil {
+		glog.Errorf("%s: failed to send o[%s] version %q: %v", rj.m.t.Snode(), hdr.FullName(), hdr.ObjAttrs.Ver, err)
+		return
+	}
+	rj.xreb.OutObjsAdd(1, hdr.ObjAttrs.Size)
+}
+
 func (rj *rebJogger) doSend(lom *cluster.LOM, tsi *cluster.Snode, roc cos.ReadOpenCloser) {
 	var (
 		ack    = regularAck{rebID: rj.m.RebID(), daemonID: rj.m.t.SID()}
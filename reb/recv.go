@@ -42,6 +42,10 @@ func (reb *Reb) recvObj(hdr transport.ObjHdr, objReader io.Reader, err error) {
 		reb.recvObjRegular(hdr, smap, unpacker, objReader)
 		return
 	}
+	if act == rebMsgVersion {
+		reb.recvVersion(hdr, unpacker, objReader)
+		return
+	}
 	if act != rebMsgEC {
 		glog.Errorf("Invalid ACK type %d, expected %d", act, rebMsgEC)
 	}
@@ -143,6 +147,42 @@ func (reb *Reb) recvObjRegular(hdr transport.ObjHdr, smap *cluster.Smap, unpacke
 	}
 }
 
+// retained version of an object (see sendVersions)
+func (reb *Reb) recvVersion(hdr transport.ObjHdr, unpacker *cos.ByteUnpack, objReader io.Reader) {
+	defer cos.DrainReader(objReader)
+
+	ack := &regularAck{}
+	if err := unpacker.ReadAny(ack); err != nil {
+		glog.Errorf("Failed to parse acknowledgement: %v", err)
+		return
+	}
+	if ack.rebID != reb.RebID() {
+		glog.Warningf("received %s: %s", hdr.FullName(), reb.rebIDMismatchMsg(ack.rebID))
+		return
+	}
+	lom := cluster.AllocLOM(hdr.ObjName)
+	defer cluster.FreeLOM(lom)
+	if err := lom.Init(hdr.Bck); err != nil {
+		glog.Error(err)
+		return
+	}
+	marked := xreg.GetRebMarked()
+	if marked.Interrupted || marked.Xact == nil {
+		return
+	}
+	buf, slab := reb.t.PageMM().Alloc()
+	lom.Lock(true)
+	err := lom.PutVersion(objReader, &hdr.ObjAttrs, buf)
+	lom.Unlock(true)
+	slab.Free(buf)
+	if err != nil {
+		glog.Errorf("%s: failed to receive %s version %q from %s: %v", reb.t.Snode(), lom, hdr.ObjAttrs.Ver,
+			ack.daemonID, err)
+		return
+	}
+	reb.xact().InObjsAdd(1, hdr.ObjAttrs.Size)
+}
+
 func (reb *Reb) recvRegularAck(hdr transport.ObjHdr, unpacker *cos.ByteUnpack) {
 	ack := &regularAck{}
 	if err := unpacker.ReadAny(ack); err != nil {
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -306,7 +307,7 @@ func (j *clnJ) jogBck() (size int64, err error) {
 	opts := &fs.Options{
 		Mi:       j.mi,
 		Bck:      j.bck,
-		CTs:      []string{fs.WorkfileType, fs.ObjectType, fs.ECSliceType, fs.ECMetaType},
+		CTs:      []string{fs.WorkfileType, fs.ObjectType, fs.ECSliceType, fs.ECMetaType, fs.ETLCacheType},
 		Callback: j.walk,
 		Sorted:   false,
 	}
@@ -325,9 +326,16 @@ func (j *clnJ) visitCT(parsedFQN fs.ParsedFQN, fqn string) {
 		contentResolver := fs.CSM.Resolver(fs.WorkfileType)
 		_, old, ok := contentResolver.ParseUniqueFQN(base)
 		// workfiles: remove old or do nothing
-		if ok && old {
-			j.oldWork = append(j.oldWork, fqn)
+		if !ok || !old {
+			return
+		}
+		// (unless it's a part of S3 multipart upload that may be still in progress)
+		if strings.HasPrefix(base, fs.WorkfileMptPart+".") {
+			if finfo, err := os.Stat(fqn); err == nil && j.now-finfo.ModTime().UnixNano() < int64(fs.MptPartMaxAge) {
+				return
+			}
 		}
+		j.oldWork = append(j.oldWork, fqn)
 	case fs.ECSliceType:
 		// EC slices:
 		// - EC enabled: remove only slices with missing metafiles
@@ -369,6 +377,16 @@ func (j *clnJ) visitCT(parsedFQN fs.ParsedFQN, fqn string) {
 			return
 		}
 		j.oldWork = append(j.oldWork, fqn)
+	case fs.ETLCacheType:
+		// cached ETL results: remove those of the objects that no longer exist (on this mountpath)
+		objName, _, ok := fs.CSM.Resolver(fs.ETLCacheType).ParseUniqueFQN(parsedFQN.ObjName)
+		if !ok {
+			j.oldWork = append(j.oldWork, fqn)
+			return
+		}
+		if fs.Access(parsedFQN.MpathInfo.MakePathFQN(parsedFQN.Bck, fs.ObjectType, objName)) != nil {
+			j.oldWork = append(j.oldWork, fqn)
+		}
 	default:
 		debug.Assertf(false, "Unsupported content type: %s", parsedFQN.ContentType)
 	}
@@ -505,10 +523,11 @@ func (j *clnJ) rmLeftovers() (size int64, err error) {
 				removed bool
 			)
 			lom := &cluster.LOM{ObjName: mlom.ObjName} // yes placed
-			if lom.Init(j.bck) != nil {
-				removed = os.Remove(fqn) == nil
-			} else if lom.FromFS() != nil {
-				removed = os.Remove(fqn) == nil
+			if lom.Init(j.bck) != nil || lom.FromFS() != nil {
+				// unless retained (and not placed elsewhere)
+				if mlom.CheckRetention(false /*bypass*/) == nil {
+					removed = os.Remove(fqn) == nil
+				}
 			} else {
 				removed, _ = lom.DelExtraCopies(fqn)
 			}
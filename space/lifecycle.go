@@ -0,0 +1,403 @@
+// Package space provides storage cleanup and eviction functionality (the latter based on the
+// least recently used cache replacement). It also serves as a built-in garbage-collection
+// mechanism for orphaned workfiles.
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package space
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/xaction"
+	"github.com/NVIDIA/aistore/xreg"
+)
+
+// Lifecycle enforces per-bucket, time-based rules (bucket property `lifecycle`):
+//   - expire (remove) objects that were last modified more than `expire_days` ago;
+//   - evict cached remote objects that were not accessed for more than `evict_days`;
+//   - remove retained (noncurrent) versions of objects that became noncurrent
+//     more than `noncurrent_days` ago (see `versioning.retain`).
+// Unlike LRU, lifecycle does not depend on the used capacity. The xaction runs
+// periodically (see ais/tgtspace.go) and can also be started via API/CLI.
+// Objects in remote buckets are never removed from the respective backends.
+
+// tunables
+const lcThrottleBatch = 1024 // check mountpath utilization every so many visited objects
+
+type (
+	IniLifecycle struct {
+		T       cluster.Target
+		Xaction *XactLifecycle
+		Buckets []cmn.Bck // optional list of specific buckets (default: all buckets with enabled lifecycle)
+		WG      *sync.WaitGroup
+	}
+	XactLifecycle struct {
+		xaction.XactBase
+	}
+)
+
+// private
+type (
+	// parent (contains mpath joggers)
+	lcP struct {
+		wg      sync.WaitGroup
+		joggers map[string]*lcJ
+		ini     IniLifecycle
+		bcks    []*cluster.Bck
+	}
+	// lcJ represents a single lifecycle context and a single /jogger/
+	// that traverses a single given mountpath.
+	lcJ struct {
+		// runtime
+		bck      *cluster.Bck
+		now      int64
+		visited  int64
+		throttle bool
+		// init-time
+		p      *lcP
+		ini    *IniLifecycle
+		stopCh chan struct{}
+		mi     *fs.MountpathInfo
+		config *cmn.Config
+	}
+	lcFactory struct {
+		xreg.RenewBase
+		xact *XactLifecycle
+	}
+)
+
+// interface guard
+var _ xreg.Renewable = (*lcFactory)(nil)
+
+func (*XactLifecycle) Run(*sync.WaitGroup) { debug.Assert(false) }
+
+///////////////
+// lcFactory //
+///////////////
+
+func (*lcFactory) New(args xreg.Args, _ *cluster.Bck) xreg.Renewable {
+	return &lcFactory{RenewBase: xreg.RenewBase{Args: args}}
+}
+
+func (p *lcFactory) Start() error {
+	p.xact = &XactLifecycle{}
+	p.xact.InitBase(p.UUID(), cmn.ActLifecycle, nil)
+	return nil
+}
+
+func (*lcFactory) Kind() string        { return cmn.ActLifecycle }
+func (p *lcFactory) Get() cluster.Xact { return p.xact }
+
+func (p *lcFactory) WhenPrevIsRunning(prevEntry xreg.Renewable) (wpr xreg.WPR, err error) {
+	err = fmt.Errorf("%s is already running - not starting %q", prevEntry.Get(), p.Str(p.Kind()))
+	return
+}
+
+// LifecycleBcks returns buckets with enabled lifecycle rules.
+func LifecycleBcks(bowner cluster.Bowner) (bcks []*cluster.Bck) {
+	bowner.Get().Range(nil, nil, func(bck *cluster.Bck) bool {
+		if bck.Props.Lifecycle.Enabled && len(bck.Props.Lifecycle.Rules) > 0 {
+			bcks = append(bcks, bck)
+		}
+		return false
+	})
+	return
+}
+
+func RunLifecycle(ini *IniLifecycle) {
+	var (
+		xlc            = ini.Xaction
+		config         = cmn.GCO.Get()
+		availablePaths = fs.GetAvail()
+		num            = len(availablePaths)
+		joggers        = make(map[string]*lcJ, num)
+		parent         = &lcP{joggers: joggers, ini: *ini}
+	)
+	defer func() {
+		if ini.WG != nil {
+			ini.WG.Done()
+		}
+	}()
+	if num == 0 {
+		glog.Warning(cmn.ErrNoMountpaths)
+		xlc.Finish(cmn.ErrNoMountpaths)
+		return
+	}
+	parent.bcks = parent.initBcks()
+	if len(parent.bcks) == 0 {
+		glog.Infof("%s: no buckets with enabled lifecycle, nothing to do", xlc)
+		xlc.Finish(nil)
+		return
+	}
+	for mpath, mi := range availablePaths {
+		joggers[mpath] = &lcJ{
+			stopCh: make(chan struct{}, 1),
+			mi:     mi,
+			config: config,
+			ini:    &parent.ini,
+			p:      parent,
+		}
+	}
+	for _, j := range joggers {
+		parent.wg.Add(1)
+		go j.run()
+	}
+	glog.Infof("%s started, buckets: %v", xlc, parent.bcks)
+	if ini.WG != nil {
+		ini.WG.Done()
+		ini.WG = nil
+	}
+	parent.wg.Wait()
+
+	for _, j := range joggers {
+		j.stop()
+	}
+	xlc.Finish(nil)
+	glog.Infof("%s finished", xlc)
+}
+
+/////////
+// lcP //
+/////////
+
+func (p *lcP) initBcks() (bcks []*cluster.Bck) {
+	bowner := p.ini.T.Bowner()
+	if len(p.ini.Buckets) == 0 {
+		bcks = LifecycleBcks(bowner)
+	} else {
+		bcks = make([]*cluster.Bck, 0, len(p.ini.Buckets))
+		for _, b := range p.ini.Buckets {
+			bck := cluster.NewBckEmbed(b)
+			if err := bck.Init(bowner); err != nil {
+				glog.Errorf("%s: %v", p.ini.Xaction, err)
+				continue
+			}
+			if !bck.Props.Lifecycle.Enabled {
+				glog.Warningf("%s: lifecycle is disabled for %s - skipping", p.ini.Xaction, bck)
+				continue
+			}
+			bcks = append(bcks, bck)
+		}
+	}
+	// filter out buckets where object deletion is not allowed
+	res := bcks[:0]
+	for _, bck := range bcks {
+		if err := bck.Allow(cmn.AceObjDELETE); err != nil {
+			glog.Errorf("%s: %v - skipping %s", p.ini.Xaction, err, bck)
+			continue
+		}
+		res = append(res, bck)
+	}
+	return res
+}
+
+//////////////////////
+// mountpath jogger //
+//////////////////////
+
+func (j *lcJ) String() string {
+	return fmt.Sprintf("%s: jog-%s", j.ini.Xaction, j.mi)
+}
+
+func (j *lcJ) stop() { j.stopCh <- struct{}{} }
+
+func (j *lcJ) run() {
+	var err error
+	defer j.p.wg.Done()
+	for _, bck := range j.p.bcks {
+		j.bck = bck
+		cts := []string{fs.ObjectType}
+		if bck.Props.Versioning.Retain {
+			cts = append(cts, fs.ObjVersionType)
+		}
+		opts := &fs.Options{
+			Mi:       j.mi,
+			Bck:      bck.Bck,
+			CTs:      cts,
+			Callback: j.walk,
+			Sorted:   false,
+		}
+		j.now = time.Now().UnixNano()
+		if err = fs.Walk(opts); err != nil {
+			break
+		}
+	}
+	if err == nil || cmn.IsErrBucketNought(err) || cmn.IsErrObjNought(err) {
+		return
+	}
+	glog.Errorf("%s: exited with err %v", j, err)
+}
+
+func (j *lcJ) walk(fqn string, de fs.DirEntry) error {
+	if de.IsDir() {
+		return nil
+	}
+	if err := j.yieldTerm(); err != nil {
+		return err
+	}
+	parsedFQN, _, err := cluster.ResolveFQN(fqn)
+	if err != nil {
+		return nil
+	}
+	switch parsedFQN.ContentType {
+	case fs.ObjectType:
+		j.visitLOM(parsedFQN)
+	case fs.ObjVersionType:
+		j.visitVersion(fqn, parsedFQN)
+	}
+	return nil
+}
+
+// remove noncurrent version of the object (`<object-name>.<version>`) that's
+// older than `noncurrent_days`
+func (j *lcJ) visitVersion(fqn string, parsedFQN fs.ParsedFQN) {
+	i := strings.LastIndex(parsedFQN.ObjName, ".")
+	if i <= 0 {
+		return
+	}
+	objName, ver := parsedFQN.ObjName[:i], parsedFQN.ObjName[i+1:]
+	rules := j.bck.Props.Lifecycle.Match(objName)
+	if len(rules) == 0 {
+		return
+	}
+	lom := cluster.AllocLOM(objName)
+	defer cluster.FreeLOM(lom)
+	if err := lom.Init(j.bck.Bck); err != nil {
+		return
+	}
+	vlom, err := lom.LoadVersion(ver)
+	if err != nil {
+		return
+	}
+	var (
+		expired    bool
+		size       = vlom.SizeBytes()
+		noncurrent = vlom.NoncurrentUnix()
+	)
+	if noncurrent == 0 {
+		// (retained prior to the noncurrent time being stored - see cluster/llifecycle.go)
+		if finfo, err := os.Stat(fqn); err == nil {
+			noncurrent = finfo.ModTime().UnixNano()
+		}
+	}
+	cluster.FreeLOM(vlom)
+	for _, rule := range rules {
+		if rule.NoncurrentDays > 0 && noncurrent != 0 && j.now-noncurrent > rule.NoncurrentDays*int64(hk.DayInterval) {
+			expired = true
+			break
+		}
+	}
+	if !expired {
+		return
+	}
+	lom.Lock(true)
+	if vlom, errV := lom.LoadVersion(ver); errV == nil {
+		errV = vlom.CheckRetention(false /*bypass*/)
+		cluster.FreeLOM(vlom)
+		if errV != nil {
+			lom.Unlock(true)
+			return // retained
+		}
+	}
+	err = lom.DelVersion(ver)
+	lom.Unlock(true)
+	if err != nil {
+		if !cmn.IsObjNotExist(err) {
+			glog.Errorf("%s: failed to remove %s version %q, err: %v", j, lom, ver, err)
+		}
+		return
+	}
+	if verbose {
+		glog.Infof("%s: removed %s version %q (%s)", j, lom, ver, fqn)
+	}
+	j.ini.Xaction.ObjsAdd(1, size)
+}
+
+func (j *lcJ) visitLOM(parsedFQN fs.ParsedFQN) {
+	rules := j.bck.Props.Lifecycle.Match(parsedFQN.ObjName)
+	if len(rules) == 0 {
+		return
+	}
+	lom := cluster.AllocLOM(parsedFQN.ObjName)
+	defer cluster.FreeLOM(lom)
+	if err := lom.Init(j.bck.Bck); err != nil {
+		return
+	}
+	if err := lom.Load(false /*cache it*/, false /*locked*/); err != nil {
+		return
+	}
+	if lom.HasCopies() && lom.IsCopy() {
+		return
+	}
+	if lom.CheckRetention(false /*bypass*/) != nil {
+		return // retained
+	}
+	if lom.WriteBackPending() {
+		return // not yet written to the remote backend
+	}
+	if !j.expired(lom, rules) {
+		return
+	}
+	// NOTE: evict (rather than delete) objects that have remote backend
+	size := lom.SizeBytes()
+	if _, err := j.ini.T.DeleteObject(lom, lom.Bck().IsRemote() /*evict*/); err != nil {
+		if !cmn.IsObjNotExist(err) {
+			glog.Errorf("%s: failed to remove %s, err: %v", j, lom, err)
+		}
+		return
+	}
+	if verbose {
+		glog.Infof("%s: removed %s", j, lom)
+	}
+	j.ini.Xaction.ObjsAdd(1, size)
+}
+
+func (j *lcJ) expired(lom *cluster.LOM, rules []*cmn.LifecycleRule) bool {
+	var (
+		mtime  = lom.LastModifiedUnix()
+		remote = lom.Bck().IsRemote()
+	)
+	for _, rule := range rules {
+		if remote && rule.EvictDays > 0 && j.now-lom.AtimeUnix() > rule.EvictDays*int64(hk.DayInterval) {
+			return true
+		}
+		if rule.ExpireDays > 0 && j.now-mtime > rule.ExpireDays*int64(hk.DayInterval) {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *lcJ) yieldTerm() error {
+	xlc := j.ini.Xaction
+	select {
+	case <-xlc.ChanAbort():
+		return cmn.NewErrAborted(xlc.Name(), "", nil)
+	case <-j.stopCh:
+		return cmn.NewErrAborted(xlc.Name(), "", nil)
+	default:
+		j.visited++
+		if j.visited%lcThrottleBatch == 0 {
+			j.throttle = !j.mi.IsIdle(j.config)
+		}
+		if j.throttle {
+			time.Sleep(cmn.ThrottleMinDur)
+		}
+		break
+	}
+	if xlc.Finished() {
+		return cmn.NewErrAborted(xlc.Name(), "", nil)
+	}
+	return nil
+}
@@ -68,6 +68,13 @@ type (
 	// minHeap keeps fileInfo sorted by access time with oldest on top of the heap.
 	minHeap []*cluster.LOM
 
+	// cached result of ETL (fs.ETLCacheType)
+	etlCached struct {
+		fqn   string
+		atime int64
+		size  int64
+	}
+
 	// parent (contains mpath joggers)
 	lruP struct {
 		wg      sync.WaitGroup
@@ -271,6 +278,12 @@ func (j *lruJ) jogBcks(bcks []cmn.Bck, force bool) (err error) {
 }
 
 func (j *lruJ) jogBck() (size int64, err error) {
+	// 0. cached ETL results go first - they can always be recomputed
+	j.now = time.Now().UnixNano()
+	if size, err = j.evictETL(); err != nil || j.totalSize <= 0 {
+		return
+	}
+
 	// 1. init per-bucket min-heap (and reuse the slice)
 	h := (*j.heap)[:0]
 	j.heap = &h
@@ -289,7 +302,60 @@ func (j *lruJ) jogBck() (size int64, err error) {
 		return
 	}
 	// 3. evict
-	size, err = j.evict()
+	var evicted int64
+	evicted, err = j.evict()
+	size += evicted
+	return
+}
+
+// evict cached ETL results (see etl/cache.go), oldest first
+func (j *lruJ) evictETL() (size int64, err error) {
+	var (
+		cached   []etlCached
+		capCheck int64
+		fevicted int64
+		xlru     = j.ini.Xaction
+	)
+	opts := &fs.Options{
+		Mi:  j.mi,
+		Bck: j.bck,
+		CTs: []string{fs.ETLCacheType},
+		Callback: func(fqn string, de fs.DirEntry) error {
+			if de.IsDir() {
+				return nil
+			}
+			if err := j.yieldTerm(); err != nil {
+				return err
+			}
+			finfo, atime, err := ios.FinfoAtime(fqn)
+			if err != nil || atime+int64(j.config.LRU.DontEvictTime) > j.now {
+				return nil
+			}
+			cached = append(cached, etlCached{fqn: fqn, atime: atime, size: finfo.Size()})
+			return nil
+		},
+	}
+	if err = fs.Walk(opts); err != nil || len(cached) == 0 {
+		return
+	}
+	sort.Slice(cached, func(i, k int) bool { return cached[i].atime < cached[k].atime })
+	for _, c := range cached {
+		if j.totalSize <= 0 {
+			break
+		}
+		if errRm := cos.RemoveFile(c.fqn); errRm != nil {
+			glog.Errorf("%s: failed to remove %s: %v", j, c.fqn, errRm)
+			continue
+		}
+		size += c.size
+		fevicted++
+		if capCheck, err = j.postRemove(capCheck, c.size); err != nil {
+			break
+		}
+	}
+	j.ini.StatsT.Add(stats.LruEvictSize, size)
+	j.ini.StatsT.Add(stats.LruEvictCount, fevicted)
+	xlru.ObjsAdd(int(fevicted), size)
 	return
 }
 
@@ -312,6 +378,14 @@ func (j *lruJ) visitLOM(parsedFQN fs.ParsedFQN) {
 	if lom.HasCopies() && lom.IsCopy() {
 		return
 	}
+	// retained objects are never evicted
+	if lom.CheckRetention(false /*bypass*/) != nil {
+		return
+	}
+	// nor are those that are yet to be written to the remote backend
+	if lom.WriteBackPending() {
+		return
+	}
 
 	// do nothing if the heap's curSize >= totalSize and
 	// the file is more recent then the the heap's newest.
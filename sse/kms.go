@@ -0,0 +1,178 @@
+// Package sse provides server-side encryption of objects at rest
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Each encrypted object has its own random data key. The latter is wrapped
+// (encrypted) by a master key that never leaves the key management service (KMS)
+// and is stored, in its wrapped form, with the object's metadata.
+//
+// KMS providers are pluggable (see RegisterKMS); the built-in "file" provider is a
+// stand-in that keeps named master keys in a local JSON file:
+// {
+//   "default": "key-1",
+//   "keys": {"key-1": "<base64-encoded 32 bytes>", "key-2": "..."}
+// }
+
+type (
+	KMS interface {
+		// DataKey generates a new data key and wraps it with the given master key
+		// (or the KMS default key if keyID is empty); returns the ID of the wrapping key
+		DataKey(keyID string) (key, wrapped []byte, kid string, err error)
+		// Unwrap decrypts the data key that was wrapped by DataKey
+		Unwrap(keyID string, wrapped []byte) (key []byte, err error)
+	}
+	NewKMSFunc func(conf *cmn.KMSConf) (KMS, error)
+
+	fileKMS struct {
+		keys map[string][]byte
+		dflt string
+	}
+	fileKMSKeys struct {
+		Default string            `json:"default"`
+		Keys    map[string]string `json:"keys"`
+	}
+)
+
+// interface guard
+var _ KMS = (*fileKMS)(nil)
+
+var (
+	providers = map[string]NewKMSFunc{cmn.KMSProviderFile: newFileKMS}
+	kms       struct {
+		sync.Mutex
+		conf cmn.KMSConf
+		kms  KMS
+	}
+	ErrNoKMS = errors.New("sse: key management service (kms) is not configured")
+)
+
+// RegisterKMS makes KMS provider available via cluster configuration (`kms.provider`).
+func RegisterKMS(provider string, newKMS NewKMSFunc) {
+	kms.Lock()
+	providers[provider] = newKMS
+	kms.Unlock()
+}
+
+// GetKMS returns KMS in accordance with the current cluster configuration.
+func GetKMS() (KMS, error) {
+	conf := cmn.GCO.Get().KMS
+	kms.Lock()
+	defer kms.Unlock()
+	if kms.kms != nil && kms.conf == conf {
+		return kms.kms, nil
+	}
+	if conf.Provider == "" {
+		return nil, ErrNoKMS
+	}
+	newKMS, ok := providers[conf.Provider]
+	if !ok {
+		return nil, fmt.Errorf("sse: unknown kms provider %q", conf.Provider)
+	}
+	k, err := newKMS(&conf)
+	if err != nil {
+		return nil, err
+	}
+	kms.conf, kms.kms = conf, k
+	return k, nil
+}
+
+/////////////
+// fileKMS //
+/////////////
+
+func newFileKMS(conf *cmn.KMSConf) (KMS, error) {
+	b, err := os.ReadFile(conf.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("sse: failed to read kms key file: %v", err)
+	}
+	var (
+		in fileKMSKeys
+		k  = &fileKMS{keys: make(map[string][]byte)}
+	)
+	if err := jsoniter.Unmarshal(b, &in); err != nil {
+		return nil, fmt.Errorf("sse: invalid kms key file %q: %v", conf.KeyFile, err)
+	}
+	for kid, s := range in.Keys {
+		key, err := base64.StdEncoding.DecodeString(s)
+		if err != nil || len(key) != KeySize {
+			return nil, fmt.Errorf("sse: invalid key %q in %q (expecting base64-encoded %d bytes)",
+				kid, conf.KeyFile, KeySize)
+		}
+		k.keys[kid] = key
+	}
+	k.dflt = in.Default
+	if k.dflt == "" && len(k.keys) == 1 {
+		for kid := range k.keys {
+			k.dflt = kid
+		}
+	}
+	return k, nil
+}
+
+func (k *fileKMS) key(keyID string) (string, []byte, error) {
+	if keyID == "" {
+		if keyID = k.dflt; keyID == "" {
+			return "", nil, errors.New("sse: kms has no default key")
+		}
+	}
+	key, ok := k.keys[keyID]
+	if !ok {
+		return "", nil, cmn.NewErrNotFound("sse: kms key %q", keyID)
+	}
+	return keyID, key, nil
+}
+
+func (k *fileKMS) DataKey(keyID string) (key, wrapped []byte, kid string, err error) {
+	var master []byte
+	if kid, master, err = k.key(keyID); err != nil {
+		return
+	}
+	key = make([]byte, KeySize)
+	if _, err = rand.Read(key); err != nil {
+		return
+	}
+	aead, err := newAEAD(master)
+	if err != nil {
+		return
+	}
+	wrapped = make([]byte, aead.NonceSize(), aead.NonceSize()+KeySize+aead.Overhead())
+	if _, err = rand.Read(wrapped); err != nil {
+		return
+	}
+	wrapped = aead.Seal(wrapped, wrapped, key, []byte(kid))
+	return
+}
+
+func (k *fileKMS) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	kid, master, err := k.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(master)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errAuth
+	}
+	ns := aead.NonceSize()
+	key, err := aead.Open(nil, wrapped[:ns], wrapped[ns:], []byte(kid))
+	if err != nil {
+		return nil, errAuth
+	}
+	return key, nil
+}
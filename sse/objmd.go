@@ -0,0 +1,51 @@
+// Package sse provides server-side encryption of objects at rest
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ObjMD is encryption metadata of an object that gets stored (packed) as the
+// object's custom metadata - see cmn.SSEObjMD.
+type ObjMD struct {
+	KeyID   string // master key that wraps the data key
+	Wrapped []byte // wrapped data key
+	Size    int64  // plaintext size
+	Cksum   string // plaintext checksum (same type as the object's checksum)
+}
+
+// format: "<size>:<cksum>:<wrapped>:<key-id>" (key ID goes last as it may contain ':')
+func (md *ObjMD) Pack() string {
+	return strconv.FormatInt(md.Size, 10) + ":" + md.Cksum + ":" +
+		base64.StdEncoding.EncodeToString(md.Wrapped) + ":" + md.KeyID
+}
+
+func UnpackObjMD(s string) (md *ObjMD, err error) {
+	parts := strings.SplitN(s, ":", 4)
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("sse: invalid object metadata %q", s)
+	}
+	md = &ObjMD{Cksum: parts[1], KeyID: parts[3]}
+	if md.Size, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
+		return nil, fmt.Errorf("sse: invalid object metadata %q: %v", s, err)
+	}
+	if md.Wrapped, err = base64.StdEncoding.DecodeString(parts[2]); err != nil {
+		return nil, fmt.Errorf("sse: invalid object metadata %q: %v", s, err)
+	}
+	return
+}
+
+// Key unwraps the object's data key.
+func (md *ObjMD) Key() ([]byte, error) {
+	k, err := GetKMS()
+	if err != nil {
+		return nil, err
+	}
+	return k.Unwrap(md.KeyID, md.Wrapped)
+}
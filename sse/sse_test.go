@@ -0,0 +1,162 @@
+// Package sse provides server-side encryption of objects at rest
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func genKey(t *testing.T) []byte {
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	tassert.CheckFatal(t, err)
+	return key
+}
+
+func encrypt(t *testing.T, key, plain []byte) []byte {
+	var (
+		buf bytes.Buffer
+		w   *Writer
+		err error
+	)
+	w, err = NewWriter(&buf, key)
+	tassert.CheckFatal(t, err)
+	// write in odd-sized pieces to cross chunk boundaries
+	for p := plain; len(p) > 0; {
+		n := cos.Min(len(p), 1000)
+		_, err = w.Write(p[:n])
+		tassert.CheckFatal(t, err)
+		p = p[n:]
+	}
+	tassert.CheckFatal(t, w.Close())
+	tassert.Errorf(t, w.Written() == int64(buf.Len()), "written %d != %d", w.Written(), buf.Len())
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	key := genKey(t)
+	for _, size := range []int{0, 1, ChunkSize - 1, ChunkSize, ChunkSize + 1, 3*ChunkSize + 17} {
+		plain := make([]byte, size)
+		_, err := rand.Read(plain)
+		tassert.CheckFatal(t, err)
+
+		enc := encrypt(t, key, plain)
+		tassert.Errorf(t, int64(len(enc)) == EncSize(int64(size)),
+			"size %d: encrypted %d, expected %d", size, len(enc), EncSize(int64(size)))
+
+		r, err := NewReader(bytes.NewReader(enc), key, int64(size))
+		tassert.CheckFatal(t, err)
+		got, err := io.ReadAll(r)
+		tassert.CheckFatal(t, err)
+		tassert.Fatalf(t, bytes.Equal(got, plain), "size %d: plaintext mismatch", size)
+	}
+}
+
+func TestReadRange(t *testing.T) {
+	var (
+		key   = genKey(t)
+		plain = make([]byte, 2*ChunkSize+100)
+	)
+	_, err := rand.Read(plain)
+	tassert.CheckFatal(t, err)
+	enc := encrypt(t, key, plain)
+
+	r, err := NewReader(bytes.NewReader(enc), key, int64(len(plain)))
+	tassert.CheckFatal(t, err)
+	for _, rng := range [][2]int{{0, 10}, {ChunkSize - 5, 10}, {ChunkSize, ChunkSize}, {2 * ChunkSize, 100}, {7, 2*ChunkSize + 93}} {
+		off, length := rng[0], rng[1]
+		b := make([]byte, length)
+		n, err := r.ReadAt(b, int64(off))
+		if err != nil && err != io.EOF {
+			t.Fatal(err)
+		}
+		tassert.Errorf(t, n == length, "range %v: read %d", rng, n)
+		tassert.Errorf(t, bytes.Equal(b, plain[off:off+length]), "range %v: plaintext mismatch", rng)
+	}
+
+	_, err = r.Seek(-50, io.SeekEnd)
+	tassert.CheckFatal(t, err)
+	got, err := io.ReadAll(r)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bytes.Equal(got, plain[len(plain)-50:]), "seek: plaintext mismatch")
+}
+
+func TestTamper(t *testing.T) {
+	var (
+		key   = genKey(t)
+		plain = make([]byte, ChunkSize+10)
+		size  = int64(len(plain))
+	)
+	enc := encrypt(t, key, plain)
+
+	// flipped bit
+	bad := append([]byte{}, enc...)
+	bad[ChunkSize+Overhead+3] ^= 1
+	r, _ := NewReader(bytes.NewReader(bad), key, size)
+	_, err := io.ReadAll(r)
+	tassert.Errorf(t, err == errAuth, "expected authentication failure, got %v", err)
+
+	// truncated: the first chunk cannot pass for the final one
+	r, _ = NewReader(bytes.NewReader(enc[:ChunkSize+Overhead]), key, ChunkSize)
+	_, err = io.ReadAll(r)
+	tassert.Errorf(t, err == errAuth, "expected authentication failure, got %v", err)
+
+	// wrong key
+	r, _ = NewReader(bytes.NewReader(enc), genKey(t), size)
+	_, err = io.ReadAll(r)
+	tassert.Errorf(t, err == errAuth, "expected authentication failure, got %v", err)
+}
+
+func TestObjMD(t *testing.T) {
+	md := &ObjMD{KeyID: "arn:aws:kms:key-1", Wrapped: []byte{1, 2, 3}, Size: 12345, Cksum: "abcdef"}
+	got, err := UnpackObjMD(md.Pack())
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, got.KeyID == md.KeyID && got.Size == md.Size && got.Cksum == md.Cksum &&
+		bytes.Equal(got.Wrapped, md.Wrapped), "%+v != %+v", got, md)
+
+	_, err = UnpackObjMD("12345:abcdef")
+	tassert.Errorf(t, err != nil, "expected error")
+}
+
+func TestFileKMS(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		file = filepath.Join(dir, "keys.json")
+		k1   = base64.StdEncoding.EncodeToString(genKey(t))
+		k2   = base64.StdEncoding.EncodeToString(genKey(t))
+	)
+	err := os.WriteFile(file, []byte(`{"default": "k1", "keys": {"k1": "`+k1+`", "k2": "`+k2+`"}}`), 0o600)
+	tassert.CheckFatal(t, err)
+
+	config := cmn.GCO.BeginUpdate()
+	config.KMS = cmn.KMSConf{Provider: cmn.KMSProviderFile, KeyFile: file}
+	cmn.GCO.CommitUpdate(config)
+
+	k, err := GetKMS()
+	tassert.CheckFatal(t, err)
+
+	key, wrapped, kid, err := k.DataKey("")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, kid == "k1", "expected default key, got %q", kid)
+	unwrapped, err := k.Unwrap(kid, wrapped)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bytes.Equal(key, unwrapped), "data key mismatch")
+
+	_, err = k.Unwrap("k2", wrapped)
+	tassert.Errorf(t, err != nil, "expected error unwrapping with a different key")
+
+	_, _, _, err = k.DataKey("k3")
+	tassert.Errorf(t, cmn.IsErrNotFound(err), "expected not-found, got %v", err)
+}
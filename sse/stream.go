@@ -0,0 +1,232 @@
+// Package sse provides server-side encryption of objects at rest
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package sse
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// On-disk format: the object's content is split into chunks of (at most) ChunkSize bytes,
+// and each chunk is sealed separately with AES-256-GCM using the object's own (random)
+// data key. The nonce of a chunk is its big-endian index followed by the "final chunk"
+// flag, which makes truncation and reordering of the chunks detectable. An empty object
+// is stored as a single (empty) final chunk. There is no header: all the metadata that
+// is needed to decrypt (the wrapped data key and the plaintext size) is stored with the
+// object's metadata - see ObjMD.
+
+const (
+	ChunkSize = 64 * 1024
+	Overhead  = 16 // GCM tag (per chunk)
+	KeySize   = 32 // AES-256
+
+	encChunkSize = ChunkSize + Overhead
+)
+
+var errAuth = errors.New("sse: message authentication failed")
+
+type (
+	// Writer encrypts everything that's written into it; Close() must be called
+	// to seal the last chunk (Close does not close the underlying writer).
+	Writer struct {
+		w       io.Writer
+		aead    cipher.AEAD
+		buf     []byte
+		n       int
+		idx     uint64
+		written int64
+	}
+	// Reader decrypts (and authenticates) encrypted content of a given plaintext size.
+	// Reader is not safe for concurrent use, ReadAt included.
+	Reader struct {
+		r     io.ReaderAt
+		aead  cipher.AEAD
+		size  int64
+		off   int64
+		buf   []byte
+		plain []byte
+		idx   int64
+	}
+)
+
+// interface guard
+var (
+	_ io.WriteCloser = (*Writer)(nil)
+	_ io.ReadSeeker  = (*Reader)(nil)
+	_ io.ReaderAt    = (*Reader)(nil)
+)
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("sse: invalid key size %d (expecting %d)", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func nonce(b []byte, idx uint64, final bool) []byte {
+	binary.BigEndian.PutUint64(b, idx)
+	b[8], b[9], b[10], b[11] = 0, 0, 0, 0
+	if final {
+		b[11] = 1
+	}
+	return b
+}
+
+// EncSize returns the size of encrypted content given its plaintext size.
+func EncSize(size int64) int64 {
+	return size + numChunks(size)*Overhead
+}
+
+func numChunks(size int64) int64 {
+	if size == 0 {
+		return 1
+	}
+	return (size + ChunkSize - 1) / ChunkSize
+}
+
+////////////
+// Writer //
+////////////
+
+func NewWriter(w io.Writer, key []byte) (*Writer, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{w: w, aead: aead, buf: make([]byte, ChunkSize, encChunkSize)}, nil
+}
+
+func (w *Writer) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		if w.n == ChunkSize {
+			// seal the full chunk only when there's more to write: the last chunk is sealed by Close
+			if err = w.seal(false); err != nil {
+				return
+			}
+		}
+		m := copy(w.buf[w.n:ChunkSize], p)
+		w.n += m
+		n += m
+		p = p[m:]
+	}
+	return
+}
+
+func (w *Writer) Close() error { return w.seal(true) }
+
+// Written returns the number of encrypted bytes written so far.
+func (w *Writer) Written() int64 { return w.written }
+
+func (w *Writer) seal(final bool) error {
+	var nb [12]byte
+	sealed := w.aead.Seal(w.buf[:0], nonce(nb[:], w.idx, final), w.buf[:w.n], nil)
+	n, err := w.w.Write(sealed)
+	w.written += int64(n)
+	if err != nil {
+		return err
+	}
+	w.idx++
+	w.n = 0
+	w.buf = w.buf[:ChunkSize]
+	return nil
+}
+
+////////////
+// Reader //
+////////////
+
+func NewReader(r io.ReaderAt, key []byte, size int64) (*Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{r: r, aead: aead, size: size, buf: make([]byte, encChunkSize), idx: -1}, nil
+}
+
+// Clone returns a new Reader (positioned at the beginning) of the same content read via r.
+func (r *Reader) Clone(ra io.ReaderAt) *Reader {
+	return &Reader{r: ra, aead: r.aead, size: r.size, buf: make([]byte, encChunkSize), idx: -1}
+}
+
+func (r *Reader) Size() int64 { return r.size }
+
+func (r *Reader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadAt(p, r.off)
+	r.off += int64(n)
+	return
+}
+
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.off
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, fmt.Errorf("sse: invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, errors.New("sse: negative position")
+	}
+	r.off = offset
+	return offset, nil
+}
+
+func (r *Reader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("sse: negative offset")
+	}
+	for n < len(p) {
+		if off >= r.size {
+			return n, io.EOF
+		}
+		idx := off / ChunkSize
+		if err = r.open(idx); err != nil {
+			return
+		}
+		m := copy(p[n:], r.plain[off-idx*ChunkSize:])
+		n += m
+		off += int64(m)
+	}
+	return
+}
+
+// read and decrypt a given chunk (unless already done)
+func (r *Reader) open(idx int64) error {
+	if idx == r.idx {
+		return nil
+	}
+	var (
+		nb    [12]byte
+		final = idx == numChunks(r.size)-1
+		plen  = int64(ChunkSize)
+	)
+	if final {
+		plen = r.size - idx*ChunkSize
+	}
+	buf := r.buf[:plen+Overhead]
+	if n, err := r.r.ReadAt(buf, idx*encChunkSize); n < len(buf) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	plain, err := r.aead.Open(buf[:0], nonce(nb[:], uint64(idx), final), buf, nil)
+	if err != nil {
+		r.idx = -1
+		return errAuth
+	}
+	r.plain, r.idx = plain, idx
+	return nil
+}
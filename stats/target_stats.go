@@ -54,6 +54,16 @@ const (
 	// special
 	RestartCount = "restart.n"
 
+	// remote backends: throttled requests (see ais/backend/throttle.go)
+	RemoteThrottleCount   = "remote.throttle.n"  // throttled by the backend (HTTP 429 and 503, S3 SlowDown, etc.)
+	RemoteRetryCount      = "remote.retry.n"     // retried (after having been throttled)
+	RemoteRateWaitCount   = "remote.ratewait.n"  // delayed by the rate limiter (cluster config and bucket `rate_limit`)
+	RemoteRateWaitLatency = "remote.ratewait.ns" // ditto, time delayed
+
+	// on-the-fly ETL: cached results (see etl/cache.go)
+	ETLCacheHitCount  = "etl.cache.hit.n"
+	ETLCacheMissCount = "etl.cache.miss.n"
+
 	// KindLatency
 	PutLatency      = "put.ns"
 	AppendLatency   = "append.ns"
@@ -188,6 +198,16 @@ func (r *Trunner) RegMetrics(node *cluster.Snode) {
 	// special
 	r.reg(RestartCount, KindCounter)
 
+	// remote backends
+	r.reg(RemoteThrottleCount, KindCounter)
+	r.reg(RemoteRetryCount, KindCounter)
+	r.reg(RemoteRateWaitCount, KindCounter)
+	r.reg(RemoteRateWaitLatency, KindLatency)
+
+	// ETL
+	r.reg(ETLCacheHitCount, KindCounter)
+	r.reg(ETLCacheMissCount, KindCounter)
+
 	// download
 	r.reg(DownloadSize, KindCounter)
 	r.reg(DownloadLatency, KindLatency)
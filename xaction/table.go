@@ -32,6 +32,7 @@ var Table = map[string]Record{
 	// bucket-less xactions that will typically have a 'cluster' scope (with resilver being a notable exception)
 	cmn.ActLRU:          {Scope: ScopeG, Startable: true, Mountpath: true},
 	cmn.ActStoreCleanup: {Scope: ScopeG, Startable: true, Mountpath: true},
+	cmn.ActLifecycle:    {Scope: ScopeG, Startable: true, Mountpath: true},
 	cmn.ActElection:     {Scope: ScopeG, Startable: false},
 	cmn.ActResilver:     {Scope: ScopeT, Startable: true, Mountpath: true},
 	cmn.ActRebalance:    {Scope: ScopeG, Startable: true, Metasync: true, Owned: false, Mountpath: true},
@@ -44,6 +45,9 @@ var Table = map[string]Record{
 	cmn.ActECRespond:       {Scope: ScopeBck, Startable: false},
 	cmn.ActMakeNCopies:     {Scope: ScopeBck, Access: cmn.AccessRW, Startable: true, Metasync: true, Owned: false, RefreshCap: true, Mountpath: true},
 	cmn.ActPutCopies:       {Scope: ScopeBck, Startable: false, Mountpath: true, RefreshCap: true},
+	cmn.ActWriteBack:       {Scope: ScopeBck, Access: cmn.AccessRW, Startable: true},
+	cmn.ActReplicate:       {Scope: ScopeBck, Access: cmn.AccessRW, Startable: true},
+	cmn.ActResync:          {Scope: ScopeBck, Access: cmn.AccessRW, Startable: true, Mountpath: true},
 	cmn.ActArchive:         {Scope: ScopeBck, Startable: false, RefreshCap: true},
 	cmn.ActCopyObjects:     {Scope: ScopeBck, Startable: false, RefreshCap: true},
 	cmn.ActETLObjects:      {Scope: ScopeBck, Startable: false, RefreshCap: true},
@@ -290,3 +290,26 @@ func (t *bsummXact) Result() (interface{}, error) {
 	}
 	return ts.Result, ts.Err
 }
+
+// LocalBckUsage returns the number of objects and their total size in a given bucket
+// on this target (the same way bucket summary does it - see "slow path" above).
+func LocalBckUsage(ctx context.Context, t cluster.Target, bck *cluster.Bck) (objCount, size int64, err error) {
+	lsmsg := &cmn.ListObjsMsg{Props: cmn.GetPropsSize, Flags: cmn.LsPresent}
+	for {
+		var (
+			list *cmn.BucketList
+			walk = objwalk.NewWalk(ctx, t, bck, lsmsg)
+		)
+		if list, err = walk.DefaultLocalObjPage(lsmsg); err != nil {
+			return
+		}
+		for _, entry := range list.Entries {
+			objCount++
+			size += entry.Size
+		}
+		if list.ContinuationToken == "" {
+			return
+		}
+		lsmsg.ContinuationToken = list.ContinuationToken
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/dbdriver"
 	"github.com/NVIDIA/aistore/xaction"
 )
 
@@ -46,6 +47,11 @@ type (
 		Tag    string
 		Copies int
 	}
+
+	ResyncArgs struct {
+		DB        dbdriver.Driver
+		Replicate func(lom *cluster.LOM) // queues the (logged) object for replication
+	}
 )
 
 //////////////
@@ -147,6 +153,30 @@ func (r *registry) renewPutMirror(t cluster.Target, lom *cluster.LOM) RenewRes {
 	return r.renewBucketXact(cmn.ActPutCopies, lom.Bck(), Args{T: t, Custom: lom})
 }
 
+func RenewWriteBack(t cluster.Target, bck *cluster.Bck, db dbdriver.Driver) RenewRes {
+	return defaultReg.renewWriteBack(t, bck, db)
+}
+
+func (r *registry) renewWriteBack(t cluster.Target, bck *cluster.Bck, db dbdriver.Driver) RenewRes {
+	return r.renewBucketXact(cmn.ActWriteBack, bck, Args{T: t, Custom: db})
+}
+
+func RenewReplicate(t cluster.Target, bck *cluster.Bck, db dbdriver.Driver) RenewRes {
+	return defaultReg.renewReplicate(t, bck, db)
+}
+
+func (r *registry) renewReplicate(t cluster.Target, bck *cluster.Bck, db dbdriver.Driver) RenewRes {
+	return r.renewBucketXact(cmn.ActReplicate, bck, Args{T: t, Custom: db})
+}
+
+func RenewResync(t cluster.Target, uuid string, bck *cluster.Bck, args *ResyncArgs) RenewRes {
+	return defaultReg.renewResync(t, uuid, bck, args)
+}
+
+func (r *registry) renewResync(t cluster.Target, uuid string, bck *cluster.Bck, args *ResyncArgs) RenewRes {
+	return r.renewBucketXact(cmn.ActResync, bck, Args{T: t, UUID: uuid, Custom: args})
+}
+
 func RenewTCB(t cluster.Target, uuid, kind string, custom *TCBArgs) RenewRes {
 	return defaultReg.renewTCB(t, uuid, kind, custom)
 }
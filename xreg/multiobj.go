@@ -25,10 +25,10 @@ func (r *registry) renewEvictDelete(uuid string, t cluster.Target, kind string,
 	return r.renewBucketXact(kind, bck, Args{T: t, UUID: uuid, Custom: msg})
 }
 
-func RenewPrefetch(uuid string, t cluster.Target, bck *cluster.Bck, msg *cmn.ListRangeMsg) RenewRes {
+func RenewPrefetch(uuid string, t cluster.Target, bck *cluster.Bck, msg *cmn.PrefetchMsg) RenewRes {
 	return defaultReg.renewPrefetch(uuid, t, bck, msg)
 }
 
-func (r *registry) renewPrefetch(uuid string, t cluster.Target, bck *cluster.Bck, msg *cmn.ListRangeMsg) RenewRes {
+func (r *registry) renewPrefetch(uuid string, t cluster.Target, bck *cluster.Bck, msg *cmn.PrefetchMsg) RenewRes {
 	return r.renewBucketXact(cmn.ActPrefetchObjects, bck, Args{T: t, UUID: uuid, Custom: msg})
 }
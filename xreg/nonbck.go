@@ -62,6 +62,13 @@ func (r *registry) renewStoreCleanup(id string) RenewRes {
 	return r.renew(e, nil)
 }
 
+func RenewLifecycle(id string) RenewRes { return defaultReg.renewLifecycle(id) }
+
+func (r *registry) renewLifecycle(id string) RenewRes {
+	e := r.nonbckXacts[cmn.ActLifecycle].New(Args{UUID: id}, nil)
+	return r.renew(e, nil)
+}
+
 func RenewDownloader(t cluster.Target, statsT stats.Tracker) RenewRes {
 	return defaultReg.renewDownloader(t, statsT)
 }
@@ -8,7 +8,6 @@ package xs
 import (
 	"archive/tar"
 	"archive/zip"
-	"compress/gzip"
 	"fmt"
 	"io"
 	"net/http"
@@ -46,9 +45,9 @@ type (
 		baseW
 		tw *tar.Writer
 	}
-	tgzWriter struct {
-		tw  tarWriter
-		gzw *gzip.Writer
+	tcompWriter struct { // compressed TAR: gzip, lz4, or zstd
+		tw tarWriter
+		cw io.WriteCloser
 	}
 	zipWriter struct {
 		baseW
@@ -93,7 +92,7 @@ var (
 	_ lrwi           = (*archwi)(nil)
 
 	_ archWriter = (*tarWriter)(nil)
-	_ archWriter = (*tgzWriter)(nil)
+	_ archWriter = (*tcompWriter)(nil)
 	_ archWriter = (*zipWriter)(nil)
 )
 
@@ -168,9 +167,13 @@ func (r *XactCreateArchMultiObj) Begin(msg *cmn.ArchiveMsg) (err error) {
 		case cos.ExtTar:
 			tw := &tarWriter{}
 			tw.init(wi)
-		case cos.ExtTgz, cos.ExtTarTgz:
-			tzw := &tgzWriter{}
-			tzw.init(wi)
+		case cos.ExtTgz, cos.ExtTarTgz, cos.ExtTarLz4, cos.ExtTarZst:
+			tcw := &tcompWriter{}
+			if err = tcw.init(wi); err != nil {
+				cos.Close(wi.fh)
+				cos.RemoveFile(wi.fqn)
+				return
+			}
 		case cos.ExtZip:
 			zw := &zipWriter{}
 			zw.init(wi)
@@ -267,7 +270,7 @@ func (r *XactCreateArchMultiObj) doSend(lom *cluster.LOM, wi *archwi, fh cos.Rea
 	{
 		hdr.Bck = wi.msg.ToBck
 		hdr.ObjName = lom.ObjName
-		hdr.ObjAttrs.CopyFrom(lom.ObjAttrs())
+		hdr.ObjAttrs.CopyFrom(lom.PlainAttrs())
 		hdr.Opaque = []byte(wi.msg.TxnUUID)
 	}
 	o.Callback = func(_ transport.ObjHdr, _ io.ReadCloser, _ interface{}, _ error) {
@@ -381,8 +384,7 @@ func (wi *archwi) do(lom *cluster.LOM, lrit *lriterator) {
 			return
 		}
 	}
-	fh, err := cos.NewFileHandle(lom.FQN)
-	debug.AssertNoErr(err)
+	fh, err := lom.OpenPlain() // (decrypting if need be)
 	if err != nil {
 		wi.r.raiseErr(err, 0, wi.msg.ContinueOnError)
 		return
@@ -392,7 +394,7 @@ func (wi *archwi) do(lom *cluster.LOM, lrit *lriterator) {
 		return
 	}
 	debug.Assert(wi.fh != nil) // see Begin
-	err = wi.writer.write(wi.nameInArch(lom.ObjName), lom, fh)
+	err = wi.writer.write(wi.nameInArch(lom.ObjName), lom.PlainAttrs(), fh)
 	cluster.FreeLOM(lom)
 	cos.Close(fh)
 	if err != nil {
@@ -491,24 +493,27 @@ func (tw *tarWriter) write(fullname string, oah cmn.ObjAttrsHolder, reader io.Re
 	return
 }
 
-///////////////
-// tgzWriter //
-///////////////
-func (tzw *tgzWriter) init(wi *archwi) {
-	tzw.tw.archwi = wi
-	tzw.tw.wmul = cos.NewWriterMulti(wi.fh, &wi.cksum)
-	tzw.gzw = gzip.NewWriter(tzw.tw.wmul)
-	tzw.tw.tw = tar.NewWriter(tzw.gzw)
-	wi.writer = tzw
+/////////////////
+// tcompWriter //
+/////////////////
+func (tcw *tcompWriter) init(wi *archwi) (err error) {
+	tcw.tw.archwi = wi
+	tcw.tw.wmul = cos.NewWriterMulti(wi.fh, &wi.cksum)
+	if tcw.cw, err = cos.NewTarCompressor(tcw.tw.wmul, wi.msg.Mime, false /*fast*/); err != nil {
+		return
+	}
+	tcw.tw.tw = tar.NewWriter(tcw.cw)
+	wi.writer = tcw
+	return
 }
 
-func (tzw *tgzWriter) fini() {
-	tzw.tw.fini()
-	tzw.gzw.Close()
+func (tcw *tcompWriter) fini() {
+	tcw.tw.fini()
+	tcw.cw.Close()
 }
 
-func (tzw *tgzWriter) write(fullname string, oah cmn.ObjAttrsHolder, reader io.Reader) error {
-	return tzw.tw.write(fullname, oah, reader)
+func (tcw *tcompWriter) write(fullname string, oah cmn.ObjAttrsHolder, reader io.Reader) error {
+	return tcw.tw.write(fullname, oah, reader)
 }
 
 ///////////////
@@ -0,0 +1,329 @@
+// Package xs contains eXtended actions (xactions) except storage services
+// (mirror, ec) and extensions (downloader, lru).
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/xaction"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Durable queue of the objects that are yet to be written elsewhere - the common part
+// of write-back (xs/writeback.go) and replication (xs/replicate.go):
+// - the queue is the database collection of records keyed by object's uname; the in-memory
+//   queue holds (up to dqBurst) names of the bucket's objects that are due to be written;
+// - the records that do not fit get picked up by the next Load (upon overflow, periodically);
+// - the xaction's workers write the objects (see `flush`); failures are retried with
+//   exponential backoff, and after dqMaxAttempts the record is considered failed until
+//   explicitly retried (RetryFailed).
+
+const (
+	dqWorkers       = 4
+	dqBurst         = 1024 // queued in memory (the rest remains in the database until the next Load)
+	dqMaxAttempts   = 5
+	dqRetryDelay    = 10 * time.Second // doubles with each failed attempt
+	dqMaxRetryDelay = 10 * time.Minute
+	dqReload        = 10 * time.Second // (when the in-memory queue has overflowed)
+)
+
+type (
+	dqueue struct {
+		xact     *xaction.DemandBase
+		db       dbdriver.Driver
+		coll     string
+		newRec   func() dqRec
+		flush    func(objName string) (again bool)
+		workCh   chan string
+		stopCh   *cos.StopCh
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		queued   map[string]struct{} // by object name
+		overflow atomic.Bool
+		logged   atomic.Int64 // records in the collection (as of the last Load)
+		failed   atomic.Int64
+		oldest   atomic.Int64 // time the oldest record was queued (ditto)
+	}
+	// (durable) queue record
+	dqRecord struct {
+		Queued   int64  `json:"queued,string"` // time queued (Unix nanoseconds)
+		Next     int64  `json:"next,string"`   // not to retry before
+		Err      string `json:"err,omitempty"` // last error
+		Attempts int    `json:"attempts"`
+		Failed   bool   `json:"failed"` // attempts exhausted
+	}
+	// (records that embed dqRecord)
+	dqRec interface {
+		base() *dqRecord
+	}
+)
+
+func (rec *dqRecord) base() *dqRecord { return rec }
+
+// returns the buckets that have queued objects
+func dqBuckets(db dbdriver.Driver, coll string) (bcks []cmn.Bck, err error) {
+	unames, err := db.List(coll, "")
+	if err != nil {
+		return
+	}
+	seen := make(map[string]struct{}, 4)
+	for _, uname := range unames {
+		bck, _ := cmn.ParseUname(uname)
+		if _, ok := seen[bck.MakeUname("")]; ok {
+			continue
+		}
+		seen[bck.MakeUname("")] = struct{}{}
+		bcks = append(bcks, bck)
+	}
+	return
+}
+
+// removes all records of a given bucket
+func dqDrop(db dbdriver.Driver, coll string, bck cmn.Bck) (n int, err error) {
+	unames, err := db.List(coll, bck.MakeUname(""))
+	if err != nil {
+		return
+	}
+	for _, uname := range unames {
+		if err = db.Delete(coll, uname); err != nil && !dbdriver.IsErrNotFound(err) {
+			return
+		}
+		n++
+	}
+	return n, nil
+}
+
+func dqBackoff(attempts int) time.Duration {
+	delay := dqRetryDelay
+	for i := 1; i < attempts && delay < dqMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return cos.MinDuration(delay, dqMaxRetryDelay)
+}
+
+// Hard-links the object to a workfile, to write the object's content without holding its lock
+// (the caller holds the read lock and has loaded the object).
+func dqSnapshot(lom *cluster.LOM, tag string) (snap *cluster.LOM, finfo os.FileInfo, err error) {
+	workFQN := fs.CSM.Gen(lom, fs.WorkfileType, tag)
+	if err = cos.CreateDir(filepath.Dir(workFQN)); err != nil {
+		return
+	}
+	if err = os.Link(lom.FQN, workFQN); err != nil {
+		return
+	}
+	if finfo, err = os.Stat(workFQN); err != nil {
+		if errRm := cos.RemoveFile(workFQN); errRm != nil {
+			glog.Errorf("Nested (%v): failed to remove %s, err: %v", err, workFQN, errRm)
+		}
+		return
+	}
+	snap = lom.Clone(workFQN)
+	// (not to modify the cached metadata)
+	md := make(cos.SimpleKVs, len(lom.GetCustomMD())+2)
+	for k, v := range lom.GetCustomMD() {
+		md[k] = v
+	}
+	snap.SetCustomMD(md)
+	return
+}
+
+////////////
+// dqueue //
+////////////
+
+func (q *dqueue) init(xact *xaction.DemandBase, db dbdriver.Driver, coll string, newRec func() dqRec,
+	flush func(string) bool) {
+	q.xact, q.db, q.coll, q.newRec, q.flush = xact, db, coll, newRec, flush
+	q.workCh = make(chan string, dqBurst)
+	q.stopCh = cos.NewStopCh()
+	q.queued = make(map[string]struct{}, dqBurst)
+}
+
+// runs the workers until the xaction gets idle or aborted
+func (q *dqueue) run() error {
+	for i := 0; i < dqWorkers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	ticker := time.NewTicker(dqReload)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if len(q.workCh) < dqBurst/2 && q.overflow.CAS(true, false) {
+				if err := q.Load(); err != nil {
+					glog.Errorf("%s: %v", q.xact, err)
+				}
+			}
+		case <-q.xact.IdleTimer():
+			q.stop()
+			return nil
+		case <-q.xact.ChanAbort():
+			q.stop()
+			return cmn.NewErrAborted(q.xact.Name(), "", nil)
+		}
+	}
+}
+
+// Add queues the object to be written; it is a no-op if the object is already queued or
+// the queue is full - in the latter case, the object will be picked up by the next Load.
+func (q *dqueue) Add(objName string) {
+	q.mu.Lock()
+	if _, ok := q.queued[objName]; ok {
+		q.mu.Unlock()
+		return
+	}
+	q.queued[objName] = struct{}{}
+	q.mu.Unlock()
+
+	q.xact.IncPending()
+	select {
+	case q.workCh <- objName:
+	default:
+		q.overflow.Store(true)
+		q.dequeue(objName)
+	}
+}
+
+// Load queues the bucket's objects that are due to be written (again), and updates
+// the stats: number of records, failed ones, and the time the oldest one was queued.
+func (q *dqueue) Load() error {
+	recs, err := q.db.GetAll(q.coll, q.xact.Bck().MakeUname(""))
+	if err != nil {
+		return err
+	}
+	var (
+		failed, oldest int64
+		now            = time.Now().UnixNano()
+	)
+	for uname, val := range recs {
+		rec := &dqRecord{}
+		if err := jsoniter.UnmarshalFromString(val, rec); err != nil {
+			glog.Errorf("%s: invalid record %q: %v", q.xact, uname, err)
+			continue
+		}
+		if oldest == 0 || rec.Queued < oldest {
+			oldest = rec.Queued
+		}
+		if rec.Failed {
+			failed++
+			continue
+		}
+		if rec.Next <= now {
+			_, objName := cmn.ParseUname(uname)
+			q.Add(objName)
+		}
+	}
+	q.logged.Store(int64(len(recs)))
+	q.failed.Store(failed)
+	q.oldest.Store(oldest)
+	return nil
+}
+
+// RetryFailed resets the records that have exhausted their attempts, and queues them again.
+func (q *dqueue) RetryFailed() error {
+	recs, err := q.db.GetAll(q.coll, q.xact.Bck().MakeUname(""))
+	if err != nil {
+		return err
+	}
+	for uname, val := range recs {
+		rec := q.newRec()
+		if err := jsoniter.UnmarshalFromString(val, rec); err != nil || !rec.base().Failed {
+			continue
+		}
+		b := rec.base()
+		b.Failed, b.Attempts, b.Next = false, 0, 0
+		if err := q.db.Set(q.coll, uname, rec); err != nil {
+			return err
+		}
+	}
+	return q.Load()
+}
+
+func (q *dqueue) dequeue(objName string) {
+	q.mu.Lock()
+	delete(q.queued, objName)
+	q.mu.Unlock()
+	q.xact.DecPending()
+}
+
+func (q *dqueue) work() {
+	defer q.wg.Done()
+	for {
+		select {
+		case objName := <-q.workCh:
+			again := q.flush(objName)
+			q.dequeue(objName)
+			if again {
+				q.Add(objName)
+			}
+		case <-q.stopCh.Listen():
+			return
+		}
+	}
+}
+
+// updates the record upon failure to write the object; returns true if the attempts
+// are exhausted
+func (q *dqueue) retry(uname string, rec dqRec, err error) (failed bool) {
+	var (
+		b   = rec.base()
+		now = time.Now()
+	)
+	if b.Queued == 0 {
+		b.Queued = now.UnixNano()
+	}
+	b.Attempts++
+	b.Err = err.Error()
+	if b.Attempts >= dqMaxAttempts {
+		b.Failed = true
+		q.failed.Inc()
+		failed = true
+	} else {
+		b.Next = now.Add(dqBackoff(b.Attempts)).UnixNano()
+	}
+	if errSet := q.db.Set(q.coll, uname, rec); errSet != nil {
+		glog.Errorf("%s: failed to update %q record: %v", q.xact, uname, errSet)
+	}
+	return
+}
+
+// removes the record of the object that has been written
+func (q *dqueue) done(uname string) {
+	if err := q.db.Delete(q.coll, uname); err != nil && !dbdriver.IsErrNotFound(err) {
+		glog.Errorf("%s: failed to remove %q record: %v", q.xact, uname, err)
+	}
+}
+
+// objects that remain queued will be written by the next xaction (see Load)
+func (q *dqueue) stop() {
+	q.xact.Stop()
+	q.stopCh.Close()
+	q.wg.Wait()
+	var n int
+	for {
+		select {
+		case objName := <-q.workCh:
+			q.dequeue(objName)
+			n++
+		default:
+			if n > 0 {
+				glog.Infof("%s: %d object%s left in the queue", q.xact, n, cos.Plural(n))
+			}
+			return
+		}
+	}
+}
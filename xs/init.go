@@ -31,6 +31,9 @@ func Init() {
 
 	xreg.RegBckXact(&proFactory{})
 	xreg.RegBckXact(&llcFactory{})
+	xreg.RegBckXact(&wbFactory{})
+	xreg.RegBckXact(&rpFactory{})
+	xreg.RegBckXact(&resyncFactory{})
 
 	xreg.RegBckXact(&tcoFactory{streamingF: streamingF{kind: cmn.ActETLObjects}})
 	xreg.RegBckXact(&tcoFactory{streamingF: streamingF{kind: cmn.ActCopyObjects}})
@@ -69,11 +69,15 @@ type (
 	prfFactory struct {
 		xreg.RenewBase
 		xact *prefetch
-		msg  *cmn.ListRangeMsg
+		msg  *cmn.PrefetchMsg
 	}
 	prefetch struct {
 		xaction.XactBase
 		lriterator
+		pmsg   *cmn.PrefetchMsg
+		workCh chan string // object names to prefetch
+		wg     sync.WaitGroup
+		ticker *time.Ticker // rate budget (nil: unlimited)
 	}
 
 	TestXFactory struct{ prfFactory } // tests only
@@ -145,9 +149,6 @@ func (r *lriterator) iteratePrefix(smap *cluster.Smap, prefix string, wi lrwi) e
 	bremote := bck.IsRemote()
 	if !bremote {
 		smap = nil // not needed
-	} else if bck.IsHTTP() {
-		return fmt.Errorf("cannot list bucket %s for prefix %q (plain HTTP buckets are not list-able) - use alternative templating",
-			bck, prefix)
 	}
 	msg := &cmn.ListObjsMsg{Prefix: prefix, Props: cmn.GetPropsStatus}
 	for {
@@ -288,7 +289,7 @@ func (r *evictDelete) do(lom *cluster.LOM, _ *lriterator) {
 //////////////
 
 func (*prfFactory) New(args xreg.Args, bck *cluster.Bck) xreg.Renewable {
-	msg := args.Custom.(*cmn.ListRangeMsg)
+	msg := args.Custom.(*cmn.PrefetchMsg)
 	debug.Assert(!msg.IsList() || !msg.HasTemplate())
 	np := &prfFactory{RenewBase: xreg.RenewBase{Args: args, Bck: bck}, msg: msg}
 	return np
@@ -317,9 +318,9 @@ func (*prfFactory) WhenPrevIsRunning(xreg.Renewable) (xreg.WPR, error) {
 	return xreg.WprKeepAndStartNew, nil
 }
 
-func newPrefetch(xargs *xreg.Args, kind string, bck *cluster.Bck, msg *cmn.ListRangeMsg) (prf *prefetch) {
-	prf = &prefetch{}
-	prf.lriterator.init(prf, xargs.T, msg, true /*freeLOM*/)
+func newPrefetch(xargs *xreg.Args, kind string, bck *cluster.Bck, msg *cmn.PrefetchMsg) (prf *prefetch) {
+	prf = &prefetch{pmsg: msg}
+	prf.lriterator.init(prf, xargs.T, &msg.ListRangeMsg, true /*freeLOM*/)
 	prf.InitBase(xargs.UUID, kind, bck)
 	prf.lriterator.xact = prf
 	return
@@ -327,18 +328,72 @@ func newPrefetch(xargs *xreg.Args, kind string, bck *cluster.Bck, msg *cmn.ListR
 
 func (r *prefetch) Run(*sync.WaitGroup) {
 	var (
-		err  error
-		smap = r.t.Sowner().Get()
+		err        error
+		smap       = r.t.Sowner().Get()
+		numWorkers = cos.Max(r.pmsg.NumWorkers, 1)
 	)
-	if r.msg.IsList() {
+	r.workCh = make(chan string, numWorkers+r.pmsg.ReadAhead)
+	if r.pmsg.Rate > 0 {
+		r.ticker = time.NewTicker(time.Second / time.Duration(r.pmsg.Rate))
+	}
+	r.wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go r.work()
+	}
+	switch {
+	case r.pmsg.HasManifest():
+		err = r.runManifest(smap)
+	case r.msg.IsList():
 		err = r.iterateList(r, smap)
-	} else {
+	default:
 		err = r.iterateRange(r, smap)
 	}
+	close(r.workCh)
+	r.wg.Wait()
+	if r.ticker != nil {
+		r.ticker.Stop()
+	}
 	r.Finish(err)
 }
 
+// hand over the (locally owned) object to one of the workers
 func (r *prefetch) do(lom *cluster.LOM, _ *lriterator) {
+	select {
+	case r.workCh <- lom.ObjName:
+	case <-r.ChanAbort():
+	}
+}
+
+func (r *prefetch) work() {
+	defer r.wg.Done()
+	for objName := range r.workCh {
+		if r.Aborted() || !r.throttle() {
+			continue // drain
+		}
+		lom := cluster.AllocLOM(objName)
+		if err := lom.Init(r.Bck().Bck); err != nil {
+			glog.Warning(err)
+		} else {
+			r.prefetchObj(lom)
+		}
+		cluster.FreeLOM(lom)
+	}
+}
+
+// wait for the next token, if the rate is limited
+func (r *prefetch) throttle() bool {
+	if r.ticker == nil {
+		return true
+	}
+	select {
+	case <-r.ticker.C:
+		return true
+	case <-r.ChanAbort():
+		return false
+	}
+}
+
+func (r *prefetch) prefetchObj(lom *cluster.LOM) {
 	if err := lom.Load(true /*cache it*/, false /*locked*/); err != nil {
 		if !cmn.IsObjNotExist(err) {
 			return
@@ -8,13 +8,11 @@ package xs
 import (
 	"archive/tar"
 	"archive/zip"
-	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"path"
 	"path/filepath"
 	"sort"
@@ -257,7 +255,9 @@ func (r *ObjListXact) nextPageAIS(cnt uint) error {
 		if cmn.TokenIncludesObject(r.token, obj.Name) {
 			continue
 		}
-		read++
+		if !obj.IsNoncurrent() {
+			read++
+		}
 		r.lastPage = append(r.lastPage, obj)
 	}
 	return nil
@@ -278,7 +278,8 @@ func (r *ObjListXact) isPageCached(marker string, cnt uint) bool {
 		return true
 	}
 	idx := r.findMarker(marker)
-	return idx+cnt < uint(len(r.lastPage))
+	_, full := cmn.PageLen(r.lastPage[idx:], cnt+1)
+	return full
 }
 
 func (r *ObjListXact) nextPageRemote() error {
@@ -312,14 +313,15 @@ func (r *ObjListXact) getPage(marker string, cnt uint) *cmn.BucketList {
 		list = r.lastPage[idx:]
 	)
 
-	debug.Assert(uint(len(list)) >= cnt || r.walkDone)
+	n, full := cmn.PageLen(list, cnt)
+	debug.Assert(full || r.walkDone)
 
-	if uint(len(list)) >= cnt {
-		entries := list[:cnt]
+	if full {
+		entries := list[:n]
 		return &cmn.BucketList{
 			UUID:              r.msg.UUID,
 			Entries:           entries,
-			ContinuationToken: entries[cnt-1].Name,
+			ContinuationToken: entries[n-1].Name,
 		}
 	}
 	return &cmn.BucketList{Entries: list, UUID: r.msg.UUID}
@@ -383,6 +385,9 @@ func (r *ObjListXact) traverseBucket(msg *cmn.ListObjsMsg) {
 	wi := walkinfo.NewWalkInfo(r.walkCtx(), r.t, msg)
 	defer r.walkWg.Done()
 	cb := func(fqn string, de fs.DirEntry) error {
+		if msg.IsFlagSet(cmn.LsVersions) {
+			return r.traverseVersions(wi, msg, fqn, de)
+		}
 		entry, err := wi.Callback(fqn, de)
 		if err != nil || entry == nil {
 			return err
@@ -441,6 +446,26 @@ func (r *ObjListXact) traverseBucket(msg *cmn.ListObjsMsg) {
 	close(r.objCache)
 }
 
+// (LsVersions) all versions of a given object, including delete markers
+func (r *ObjListXact) traverseVersions(wi *walkinfo.WalkInfo, msg *cmn.ListObjsMsg, fqn string, de fs.DirEntry) error {
+	entries, err := wi.CallbackVersions(fqn, de)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+	if entries[0].Name <= msg.StartAfter {
+		return nil
+	}
+	for _, entry := range entries {
+		select {
+		case r.objCache <- entry:
+			/* do nothing */
+		case <-r.walkStopCh.Listen():
+			return errStopped
+		}
+	}
+	return nil
+}
+
 func listZip(readerAt cos.ReadReaderAt, size int64) ([]*archEntry, error) {
 	zr, err := zip.NewReader(readerAt, size)
 	if err != nil {
@@ -483,12 +508,14 @@ func listTar(reader io.Reader) ([]*archEntry, error) {
 	}
 }
 
-func listTgz(reader io.Reader) ([]*archEntry, error) {
-	gzr, err := gzip.NewReader(reader)
+// compressed TAR: gzip, lz4, or zstd
+func listTarComp(reader io.Reader, arch string) ([]*archEntry, error) {
+	dr, err := cos.NewTarDecompressor(reader, arch)
 	if err != nil {
 		return nil, err
 	}
-	return listTar(gzr)
+	defer dr.Close()
+	return listTar(dr)
 }
 
 func listArchive(fqn string) ([]*archEntry, error) {
@@ -502,28 +529,31 @@ func listArchive(fqn string) ([]*archEntry, error) {
 	if arch == "" {
 		return nil, nil
 	}
-	// list the archive content
-	var (
-		archList []*archEntry
-		finfo    os.FileInfo
-	)
-	f, err := os.Open(fqn)
-	if err == nil {
-		switch arch {
-		case cos.ExtTar:
-			archList, err = listTar(f)
-		case cos.ExtTgz, cos.ExtTarTgz:
-			archList, err = listTgz(f)
-		case cos.ExtZip:
-			finfo, err = os.Stat(fqn)
-			if err == nil {
-				archList, err = listZip(f, finfo.Size())
-			}
-		default:
-			debug.Assert(false, arch)
-		}
+	// list the archive content (decrypting if need be)
+	lom := cluster.AllocLOMbyFQN(fqn)
+	defer cluster.FreeLOM(lom)
+	if err := lom.Init(cmn.Bck{}); err != nil {
+		return nil, err
+	}
+	if err := lom.Load(false /*cache it*/, false /*locked*/); err != nil {
+		return nil, err
+	}
+	fh, err := lom.OpenPlain()
+	if err != nil {
+		return nil, err
 	}
-	f.Close()
+	var archList []*archEntry
+	switch arch {
+	case cos.ExtTar:
+		archList, err = listTar(fh)
+	case cos.ExtTgz, cos.ExtTarTgz, cos.ExtTarLz4, cos.ExtTarZst:
+		archList, err = listTarComp(fh, arch)
+	case cos.ExtZip:
+		archList, err = listZip(fh.(cos.ReadReaderAt), lom.PlainAttrs().Size)
+	default:
+		debug.Assert(false, arch)
+	}
+	fh.Close()
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,307 @@
+// Package xs contains eXtended actions (xactions) except storage services
+// (mirror, ec) and extensions (downloader, lru).
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/mono"
+)
+
+// Manifest-driven prefetch (cmn.PrefetchMsg.Manifest):
+// - manifest is an object that names the objects to prefetch, one per line; WebDataset-style
+//   brace templates (e.g. `shard-{000000..000999}.tar`) get expanded, empty lines and #-comments
+//   are skipped;
+// - with no read-ahead, each target prefetches (in the manifest order) all the objects it owns;
+// - with read-ahead, each target tracks the GETs of the objects it owns, separately for each
+//   reader session (cmn.HdrReaderSession or, if unspecified, the client's address), and keeps
+//   prefetching the (owned) objects that are within ReadAhead manifest entries of the reader.
+//   The xaction finishes when all the objects are prefetched or read, when aborted, or after
+//   raIdleTimeout with no reads. Given HRW distribution, ReadAhead must be (much) greater than
+//   the number of targets.
+
+const (
+	raIdleTimeout    = 10 * time.Minute
+	raSessionTimeout = 5 * time.Minute
+	raCheckInterval  = 10 * time.Second
+)
+
+// manifest entry state
+const (
+	raRemote = iota // owned by another target
+	raLocal         // to prefetch
+	raDone          // prefetched or read
+)
+
+type (
+	readAhead struct {
+		r        *prefetch
+		names    []string
+		state    []uint8
+		pos      map[string]int // manifest position (owned objects only)
+		sessions map[string]*raSession
+		doneCh   chan struct{}
+		mu       sync.Mutex
+		last     int64 // last read (mono time)
+		todo     int   // owned objects yet to be prefetched or read
+		stopped  bool
+	}
+	raSession struct {
+		hi   int   // the highest manifest position prefetched (or skipped) on behalf of the session
+		last int64 // last read (mono time)
+	}
+)
+
+// active read-aheads, by bucket
+var readAheads struct {
+	sync.RWMutex
+	m map[string]*readAhead
+	n atomic.Int32
+}
+
+// ReadAhead is called upon GET of a remote object by a given reader session
+func ReadAhead(lom *cluster.LOM, session string) {
+	if readAheads.n.Load() == 0 {
+		return
+	}
+	readAheads.RLock()
+	ra := readAheads.m[lom.Bck().MakeUname("")]
+	readAheads.RUnlock()
+	if ra != nil {
+		ra.read(lom.ObjName, session)
+	}
+}
+
+// one object name or bash-style template per line
+func parseManifest(r io.Reader) (names []string, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if pt, err := cos.ParseBashTemplate(line); err == nil && len(pt.Ranges) > 0 {
+			names = append(names, pt.ToSlice()...)
+			continue
+		}
+		names = append(names, line)
+	}
+	err = scanner.Err()
+	return
+}
+
+//////////////
+// prefetch //
+//////////////
+
+func (r *prefetch) runManifest(smap *cluster.Smap) error {
+	names, err := r.loadManifest(smap)
+	if err != nil {
+		return err
+	}
+	if r.pmsg.ReadAhead == 0 {
+		r.lriterator.msg = &cmn.ListRangeMsg{ObjNames: names}
+		return r.iterateList(r, smap)
+	}
+	ra, err := newReadAhead(r, names, smap)
+	if err != nil || ra.todo == 0 {
+		return err
+	}
+	ra.register()
+	ra.wait()
+	ra.unregister()
+	return nil
+}
+
+// read the manifest from its (HRW) target - possibly, this one
+func (r *prefetch) loadManifest(smap *cluster.Smap) (names []string, err error) {
+	var (
+		req     *http.Request
+		resp    *http.Response
+		tsi     *cluster.Snode
+		bck     = r.pmsg.ManifestBck
+		objName = r.pmsg.Manifest
+	)
+	if bck.IsEmpty() {
+		bck = r.Bck().Bck
+	}
+	if tsi, err = cluster.HrwTarget(bck.MakeUname(objName), smap); err != nil {
+		return
+	}
+	url := tsi.URL(cmn.NetworkIntraData) + cmn.URLPathObjects.Join(bck.Name, objName)
+	if req, err = http.NewRequestWithContext(r.ctx, http.MethodGet, url, http.NoBody); err != nil {
+		return
+	}
+	req.URL.RawQuery = cmn.AddBckToQuery(nil, bck).Encode()
+	req.Header.Set(cmn.HdrCallerID, r.t.SID())
+	req.Header.Set(cmn.HdrCallerName, r.t.Sname())
+	resp, err = r.t.DataClient().Do(req) // nolint:bodyclose // closed below
+	if err != nil {
+		return
+	}
+	defer cos.Close(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("%s: failed to read manifest %s/%s from %s: status %d", r, bck, objName, tsi, resp.StatusCode)
+		return
+	}
+	if names, err = parseManifest(resp.Body); err != nil {
+		return
+	}
+	if verbose {
+		glog.Infof("%s: manifest %s/%s: %d object(s)", r, bck, objName, len(names))
+	}
+	return
+}
+
+///////////////
+// readAhead //
+///////////////
+
+func newReadAhead(r *prefetch, names []string, smap *cluster.Smap) (*readAhead, error) {
+	ra := &readAhead{
+		r:        r,
+		names:    names,
+		state:    make([]uint8, len(names)),
+		pos:      make(map[string]int, len(names)/cos.Max(smap.CountActiveTargets(), 1)),
+		sessions: make(map[string]*raSession, 4),
+		doneCh:   make(chan struct{}),
+		last:     mono.NanoTime(),
+	}
+	bck := r.Bck()
+	for i, name := range names {
+		si, err := cluster.HrwTarget(bck.MakeUname(name), smap)
+		if err != nil {
+			return nil, err
+		}
+		if si.ID() != r.t.SID() {
+			continue
+		}
+		ra.state[i] = raLocal
+		ra.pos[name] = i
+		ra.todo++
+	}
+	return ra, nil
+}
+
+// at most one read-ahead per bucket: the most recent one takes over
+func (ra *readAhead) register() {
+	key := ra.r.Bck().MakeUname("")
+	readAheads.Lock()
+	if readAheads.m == nil {
+		readAheads.m = make(map[string]*readAhead, 4)
+	}
+	prev := readAheads.m[key]
+	readAheads.m[key] = ra
+	if prev == nil {
+		readAheads.n.Inc()
+	}
+	readAheads.Unlock()
+	if prev != nil {
+		prev.r.Abort(fmt.Errorf("%s: superseded by %s", prev.r, ra.r))
+	}
+}
+
+func (ra *readAhead) unregister() {
+	key := ra.r.Bck().MakeUname("")
+	readAheads.Lock()
+	if readAheads.m[key] == ra {
+		delete(readAheads.m, key)
+		readAheads.n.Dec()
+	}
+	readAheads.Unlock()
+
+	ra.mu.Lock()
+	ra.stopped = true // (no more sending to the work channel)
+	ra.mu.Unlock()
+}
+
+func (ra *readAhead) wait() {
+	ticker := time.NewTicker(raCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ra.doneCh:
+			return
+		case <-ra.r.ChanAbort():
+			return
+		case <-ticker.C:
+			if ra.housekeep() {
+				glog.Infof("%s: no reads in %v - finishing", ra.r, raIdleTimeout)
+				return
+			}
+		}
+	}
+}
+
+func (ra *readAhead) housekeep() (idle bool) {
+	now := mono.NanoTime()
+	ra.mu.Lock()
+	for session, s := range ra.sessions {
+		if time.Duration(now-s.last) > raSessionTimeout {
+			delete(ra.sessions, session)
+		}
+	}
+	idle = time.Duration(now-ra.last) > raIdleTimeout
+	ra.mu.Unlock()
+	return
+}
+
+func (ra *readAhead) read(objName, session string) {
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+	i, ok := ra.pos[objName]
+	if !ok || ra.stopped {
+		return
+	}
+	var (
+		now    = mono.NanoTime()
+		window = ra.r.pmsg.ReadAhead
+		s      = ra.sessions[session]
+	)
+	ra.last = now
+	ra.done(i)
+	if s == nil {
+		s = &raSession{hi: i}
+		ra.sessions[session] = s
+	} else if i+window < s.hi {
+		s.hi = i // rewound (e.g., the next epoch)
+	}
+	s.last = now
+	end := cos.Min(i+window, len(ra.names)-1)
+	for j := s.hi + 1; j <= end; j++ {
+		if ra.state[j] == raLocal {
+			select {
+			case ra.r.workCh <- ra.names[j]:
+				ra.done(j)
+			default:
+				return // busy - will catch up upon the next read
+			}
+		}
+		s.hi = j
+	}
+}
+
+func (ra *readAhead) done(i int) {
+	if ra.state[i] != raLocal {
+		return
+	}
+	ra.state[i] = raDone
+	ra.todo--
+	if ra.todo == 0 {
+		close(ra.doneCh)
+	}
+}
@@ -0,0 +1,86 @@
+// Package xs contains eXtended actions (xactions) except storage services
+// (mirror, ec) and extensions (downloader, lru).
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestParseManifest(t *testing.T) {
+	const manifest = `
+# train split
+a.tar
+  b.tar
+
+shard-{01..03}.tar
+c@1.tar
+`
+	names, err := parseManifest(strings.NewReader(manifest))
+	tassert.CheckFatal(t, err)
+	expected := []string{"a.tar", "b.tar", "shard-01.tar", "shard-02.tar", "shard-03.tar", "c@1.tar"}
+	tassert.Errorf(t, reflect.DeepEqual(names, expected), "expected %v, got %v", expected, names)
+}
+
+func TestReadAheadWindow(t *testing.T) {
+	var (
+		names = []string{"o0", "o1", "o2", "o3", "o4", "o5", "o6", "o7"}
+		r     = &prefetch{pmsg: &cmn.PrefetchMsg{ReadAhead: 3}, workCh: make(chan string, 16)}
+		ra    = &readAhead{
+			r:        r,
+			names:    names,
+			state:    make([]uint8, len(names)),
+			pos:      make(map[string]int),
+			sessions: make(map[string]*raSession),
+			doneCh:   make(chan struct{}),
+		}
+	)
+	for i, name := range names {
+		if i%4 == 3 {
+			continue // owned by another target
+		}
+		ra.state[i] = raLocal
+		ra.pos[name] = i
+		ra.todo++
+	}
+	queued := func() (objNames []string) {
+		for len(r.workCh) > 0 {
+			objNames = append(objNames, <-r.workCh)
+		}
+		return
+	}
+
+	ra.read("o0", "s1")
+	q := queued()
+	tassert.Errorf(t, reflect.DeepEqual(q, []string{"o1", "o2"}), "expected [o1 o2], got %v", q)
+	ra.read("o2", "s1")
+	q = queued()
+	tassert.Errorf(t, reflect.DeepEqual(q, []string{"o4", "o5"}), "expected [o4 o5], got %v", q)
+
+	// another session: nothing left to prefetch in its window
+	ra.read("o1", "s2")
+	q = queued()
+	tassert.Errorf(t, len(q) == 0, "expected nothing queued, got %v", q)
+
+	ra.read("o5", "s1")
+	q = queued()
+	tassert.Errorf(t, reflect.DeepEqual(q, []string{"o6"}), "expected [o6], got %v", q)
+	select {
+	case <-ra.doneCh:
+	default:
+		t.Fatalf("expected all (%d remaining) owned objects to be done", ra.todo)
+	}
+
+	// not owned or unknown
+	ra.read("o3", "s1")
+	ra.read("xyz", "s1")
+	q = queued()
+	tassert.Errorf(t, len(q) == 0, "expected nothing queued, got %v", q)
+}
@@ -0,0 +1,394 @@
+// Package xs contains eXtended actions (xactions) except storage services
+// (mirror, ec) and extensions (downloader, lru).
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/xaction"
+	"github.com/NVIDIA/aistore/xreg"
+)
+
+// Replication (bucket property `replication`):
+// - upon PUT or DELETE the target durably records the change in its database (the change log),
+//   while still holding the object's write lock; the record is keyed by the object and carries
+//   the last operation, so that multiple updates of the same object collapse into one;
+// - per-bucket on-demand xaction applies the changes to the destination bucket (remote AIS
+//   cluster or Cloud): puts the object's current content or deletes the object, and, upon
+//   success, removes the record; the object is not locked while being replicated - the xaction
+//   puts its snapshot (hard link) and replicates it again if the change gets logged anew meanwhile;
+// - failures are retried with exponential backoff (same as write-back - see xs/dqueue.go); after
+//   dqMaxAttempts the change is considered failed until the xaction is explicitly started
+//   (`ais job start replicate BUCKET`);
+// - the change log survives restarts: target's housekeeping periodically renews the xaction
+//   for the buckets that have pending changes (see Load);
+// - global rebalance: the target that receives an object logs it for resync (which is how
+//   a pending change gets handed off); the sending target drops its record once it finds
+//   the object gone;
+// - replicas (cmn.ReplicaObjMD) are not replicated any further - neither upon PUT and DELETE
+//   nor by resync - which is what prevents ping-pong between buckets that replicate to each other;
+// - replication lag: the number of logged changes and the age of the oldest one (see Snap);
+// - resync xaction (`ais job start resync BUCKET`) logs all the bucket's objects - to bootstrap
+//   the destination or catch up after replication was disabled; objects that are already
+//   replicated (same size and checksum) are skipped.
+
+const rpCollection = "replication"
+
+// change log operations
+const (
+	RpPut    = "put"
+	RpDelete = "delete"
+	RpResync = "resync"
+)
+
+type (
+	rpFactory struct {
+		xreg.RenewBase
+		xact *XactReplicate
+	}
+	XactReplicate struct {
+		xaction.DemandBase
+		dqueue
+		t cluster.Target
+	}
+	ExtReplicateStats struct {
+		Pending int64        `json:"rp.pending.n,string"`
+		Logged  int64        `json:"rp.logged.n,string"`
+		Failed  int64        `json:"rp.failed.n,string"`
+		Lag     cos.Duration `json:"rp.lag"`
+		IsIdle  bool         `json:"is_idle"`
+	}
+
+	// change log record
+	rpRecord struct {
+		Op string `json:"op"` // RpPut | RpDelete | RpResync
+		dqRecord
+	}
+
+	resyncFactory struct {
+		xreg.RenewBase
+		xact *xactResync
+	}
+	xactResync struct {
+		xaction.XactBckJog
+		args *xreg.ResyncArgs
+	}
+)
+
+// interface guard
+var (
+	_ cluster.Xact   = (*XactReplicate)(nil)
+	_ xreg.Renewable = (*rpFactory)(nil)
+	_ cluster.Xact   = (*xactResync)(nil)
+	_ xreg.Renewable = (*resyncFactory)(nil)
+)
+
+// LogReplication durably records the object's change (RpPut, RpDelete, or RpResync) that is
+// yet to be replicated; the caller is expected to write-lock the object.
+func LogReplication(db dbdriver.Driver, lom *cluster.LOM, op string) error {
+	return db.Set(rpCollection, lom.Uname(), &rpRecord{Op: op, dqRecord: dqRecord{Queued: time.Now().UnixNano()}})
+}
+
+// ReplicationBuckets returns the buckets that have logged changes.
+func ReplicationBuckets(db dbdriver.Driver) ([]cmn.Bck, error) {
+	return dqBuckets(db, rpCollection)
+}
+
+// DropReplication removes the change log of a given bucket (e.g., destroyed or no longer replicated).
+func DropReplication(db dbdriver.Driver, bck cmn.Bck) (int, error) {
+	return dqDrop(db, rpCollection, bck)
+}
+
+///////////////
+// rpFactory //
+///////////////
+
+func (*rpFactory) New(args xreg.Args, bck *cluster.Bck) xreg.Renewable {
+	return &rpFactory{RenewBase: xreg.RenewBase{Args: args, Bck: bck}}
+}
+
+func (p *rpFactory) Start() error {
+	uuid := p.UUID()
+	if uuid == "" {
+		uuid = cos.GenUUID()
+	}
+	p.xact = newXactReplicate(p.T, uuid, p.Bck, p.Custom.(dbdriver.Driver))
+	go p.xact.Run(nil)
+	return nil
+}
+
+func (*rpFactory) Kind() string        { return cmn.ActReplicate }
+func (p *rpFactory) Get() cluster.Xact { return p.xact }
+
+func (p *rpFactory) WhenPrevIsRunning(xprev xreg.Renewable) (xreg.WPR, error) {
+	debug.Assertf(false, "%s vs %s", p.Str(p.Kind()), xprev) // xreg.usePrev() must've returned true
+	return xreg.WprUse, nil
+}
+
+///////////////////
+// XactReplicate //
+///////////////////
+
+func newXactReplicate(t cluster.Target, uuid string, bck *cluster.Bck, db dbdriver.Driver) (r *XactReplicate) {
+	r = &XactReplicate{t: t}
+	r.DemandBase.Init(uuid, cmn.ActReplicate, bck, 0 /*use default*/)
+	r.dqueue.init(&r.DemandBase, db, rpCollection, func() dqRec { return &rpRecord{} }, r.flush)
+	return
+}
+
+func (r *XactReplicate) Run(*sync.WaitGroup) {
+	glog.Infoln(r.Name())
+	r.Finish(r.run())
+}
+
+// Replicates the logged change without holding the object's lock: puts the snapshot (hard link)
+// taken under the read lock, or deletes the object, and then, under the read lock, removes the
+// record. Returns true if the change has been logged anew in the meantime and must be replicated
+// again.
+func (r *XactReplicate) flush(objName string) (again bool) {
+	lom := cluster.AllocLOM(objName)
+	defer cluster.FreeLOM(lom)
+	if err := lom.Init(r.Bck().Bck); err != nil {
+		glog.Errorf("%s: %v", r, err) // (the record stays - see ais/tgtreplicate.go)
+		return
+	}
+	// NOTE: reading and updating the record while holding the lock (a concurrent PUT or DELETE logs it anew)
+	lom.Lock(false)
+	rec := r.record(lom)
+	if rec == nil {
+		lom.Unlock(false)
+		return // (replicated in the meantime)
+	}
+	snap, err := r.snapshot(lom)
+	if err != nil {
+		r.retry(lom, rec, err)
+		lom.Unlock(false)
+		return
+	}
+	lom.Unlock(false)
+
+	err = r.apply(lom, snap, rec)
+	if snap != nil {
+		if errRm := cos.RemoveFile(snap.FQN); errRm != nil {
+			glog.Errorf("%s: failed to remove %s: %v", r, snap.FQN, errRm)
+		}
+		cluster.FreeLOM(snap)
+	}
+
+	lom.Lock(false)
+	defer lom.Unlock(false)
+	switch cur := r.record(lom); {
+	case cur == nil:
+		// (dropped in the meantime)
+	case cur.Queued != rec.Queued || cur.Op != rec.Op:
+		again = true // logged anew
+	case err != nil:
+		r.retry(lom, rec, err)
+	default:
+		r.done(lom.Uname())
+	}
+	return
+}
+
+// the caller holds the lock
+func (r *XactReplicate) record(lom *cluster.LOM) *rpRecord {
+	rec := &rpRecord{}
+	if err := r.db.Get(rpCollection, lom.Uname(), rec); err != nil {
+		if !dbdriver.IsErrNotFound(err) {
+			glog.Errorf("%s: failed to read %s record: %v", r, lom, err)
+		}
+		return nil
+	}
+	return rec
+}
+
+// snapshots the object, if exists (the caller holds the read lock)
+func (*XactReplicate) snapshot(lom *cluster.LOM) (snap *cluster.LOM, err error) {
+	if err = lom.Load(true /*cache it*/, true /*locked*/); err != nil {
+		if cmn.IsObjNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	snap, _, err = dqSnapshot(lom, fs.WorkfileReplicate)
+	return
+}
+
+func (r *XactReplicate) apply(lom, snap *cluster.LOM, rec *rpRecord) error {
+	conf := &lom.Bprops().Replication
+	if !conf.Enabled {
+		if verbose {
+			glog.Infof("%s: replication disabled - dropping %s %s", r, rec.Op, lom)
+		}
+		return nil
+	}
+	bck, err := conf.DstBck()
+	if err != nil {
+		return err
+	}
+	dstBck := cluster.NewBckEmbed(bck)
+	if err := dstBck.Init(r.t.Bowner()); err != nil {
+		return err
+	}
+	switch {
+	case snap != nil:
+		return r.put(snap, dstBck, rec.Op == RpResync)
+	case rec.Op == RpDelete:
+		return r.delete(lom.ObjName, dstBck)
+	default:
+		// migrated by global rebalance and logged by the target that has received it
+		if verbose {
+			glog.Infof("%s: %s does not exist - not replicating", r, lom)
+		}
+		return nil
+	}
+}
+
+// put the snapshot's (plaintext) content marked as replica (see cmn.ReplicaObjMD)
+func (r *XactReplicate) put(snap *cluster.LOM, dstBck *cluster.Bck, resync bool) (err error) {
+	var (
+		fh      cos.ReadOpenCloser
+		oa      = snap.PlainAttrs()
+		backend = r.t.Backend(dstBck)
+		dst     = cluster.AllocLOM(snap.ObjName)
+	)
+	defer cluster.FreeLOM(dst)
+	if err = dst.Init(dstBck.Bck); err != nil {
+		return
+	}
+	if resync {
+		roa, _, errHead := backend.HeadObj(context.Background(), dst)
+		if errHead == nil && roa.Size == oa.Size && roa.Cksum.Equal(oa.Cksum) {
+			return // already replicated
+		}
+	}
+	dst.SetSize(oa.Size)
+	dst.SetCksum(oa.Cksum)
+	dst.SetCustomKey(cmn.ReplicaObjMD, snap.Bucket().String())
+	if fh, err = snap.OpenPlain(); err != nil {
+		return
+	}
+	if _, err = backend.PutObj(fh, dst); err == nil {
+		r.ObjsAdd(1, oa.Size)
+	}
+	return
+}
+
+func (r *XactReplicate) delete(objName string, dstBck *cluster.Bck) error {
+	dst := cluster.AllocLOM(objName)
+	defer cluster.FreeLOM(dst)
+	if err := dst.Init(dstBck.Bck); err != nil {
+		return err
+	}
+	errCode, err := r.t.Backend(dstBck).DeleteObj(dst)
+	if err != nil && errCode != http.StatusNotFound {
+		return err
+	}
+	r.ObjsAdd(1, 0)
+	return nil
+}
+
+func (r *XactReplicate) retry(lom *cluster.LOM, rec *rpRecord, err error) {
+	if r.dqueue.retry(lom.Uname(), rec, err) {
+		glog.Errorf("%s: failed to replicate %s %s (attempts: %d): %v", r, rec.Op, lom, rec.Attempts, err)
+	} else if verbose {
+		glog.Infof("%s: failed to replicate %s %s (attempt %d): %v - will retry", r, rec.Op, lom, rec.Attempts, err)
+	}
+}
+
+func (r *XactReplicate) Snap() cluster.XactionSnap {
+	snap := r.DemandBase.ExtSnap()
+	ext := &ExtReplicateStats{
+		Pending: r.Pending(),
+		Logged:  r.logged.Load(),
+		Failed:  r.failed.Load(),
+		IsIdle:  r.Pending() == 0,
+	}
+	if oldest := r.oldest.Load(); oldest != 0 && ext.Logged > 0 {
+		ext.Lag = cos.Duration(time.Now().UnixNano() - oldest)
+	}
+	snap.Ext = ext
+	return snap
+}
+
+///////////////////
+// resyncFactory //
+///////////////////
+
+func (*resyncFactory) New(args xreg.Args, bck *cluster.Bck) xreg.Renewable {
+	return &resyncFactory{RenewBase: xreg.RenewBase{Args: args, Bck: bck}}
+}
+
+func (p *resyncFactory) Start() error {
+	p.xact = newXactResync(p.T, p.UUID(), p.Bck, p.Custom.(*xreg.ResyncArgs))
+	go p.xact.Run(nil)
+	return nil
+}
+
+func (*resyncFactory) Kind() string        { return cmn.ActResync }
+func (p *resyncFactory) Get() cluster.Xact { return p.xact }
+
+func (*resyncFactory) WhenPrevIsRunning(xreg.Renewable) (xreg.WPR, error) { return xreg.WprUse, nil }
+
+////////////////
+// xactResync //
+////////////////
+
+func newXactResync(t cluster.Target, uuid string, bck *cluster.Bck, args *xreg.ResyncArgs) (r *xactResync) {
+	r = &xactResync{args: args}
+	mpopts := &mpather.JoggerGroupOpts{
+		T:        t,
+		Bck:      bck.Bck,
+		CTs:      []string{fs.ObjectType},
+		VisitObj: r.visitObj,
+		DoLoad:   mpather.LoadRLock,
+	}
+	r.XactBckJog.Init(uuid, cmn.ActResync, bck, mpopts)
+	return
+}
+
+func (r *xactResync) Run(*sync.WaitGroup) {
+	r.XactBckJog.Run()
+	glog.Infoln(r.Name())
+	err := r.XactBckJog.Wait()
+	r.Finish(err)
+}
+
+// log the object unless it is a replica or has a pending change already (the caller holds the read lock)
+func (r *xactResync) visitObj(lom *cluster.LOM, _ []byte) error {
+	var (
+		rec   = &rpRecord{}
+		uname = lom.Uname()
+	)
+	if lom.IsReplica() {
+		return nil
+	}
+	err := r.args.DB.Get(rpCollection, uname, rec)
+	if err == nil {
+		return nil
+	}
+	if !dbdriver.IsErrNotFound(err) {
+		return err
+	}
+	rec.Op, rec.Queued = RpResync, time.Now().UnixNano()
+	if err := r.args.DB.Set(rpCollection, uname, rec); err != nil {
+		return err
+	}
+	r.ObjsAdd(1, lom.SizeBytes())
+	r.args.Replicate(lom)
+	return nil
+}
@@ -0,0 +1,177 @@
+// Package xs contains eXtended actions (xactions) except storage services
+// (mirror, ec) and extensions (downloader, lru).
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+	"github.com/NVIDIA/aistore/xaction"
+	"github.com/NVIDIA/aistore/xreg"
+)
+
+func newReplicateTest(t *testing.T) (tgt *memTarget, bck *cluster.Bck, db dbdriver.Driver, r *XactReplicate) {
+	bck = cluster.NewBck("rp", cmn.ProviderAIS, cmn.NsGlobal, &cmn.BucketProps{
+		Cksum:       cmn.CksumConf{Type: cos.ChecksumXXHash},
+		Replication: cmn.BckReplicationConf{Dst: "s3://rp-dst", Enabled: true},
+	})
+	dst := cluster.NewBck("rp-dst", cmn.ProviderAmazon, cmn.NsGlobal,
+		&cmn.BucketProps{Cksum: cmn.CksumConf{Type: cos.ChecksumXXHash}})
+	tgt, db = newMemTarget(t, bck, dst)
+	r = newXactReplicate(tgt, cos.GenUUID(), bck, db)
+	t.Cleanup(r.stop)
+	return
+}
+
+func logTestObj(t *testing.T, bck *cluster.Bck, db dbdriver.Driver, objName, content, op string) *cluster.LOM {
+	lom := &cluster.LOM{ObjName: objName}
+	tassert.CheckFatal(t, lom.Init(bck.Bck))
+	if content != "" {
+		putTestObj(t, lom, content, "")
+	}
+	tassert.CheckFatal(t, LogReplication(db, lom, op))
+	return lom
+}
+
+func rpLogged(t *testing.T, db dbdriver.Driver, lom *cluster.LOM) *rpRecord {
+	rec := &rpRecord{}
+	if err := db.Get(rpCollection, lom.Uname(), rec); err != nil {
+		tassert.Errorf(t, dbdriver.IsErrNotFound(err), "unexpected error: %v", err)
+		return nil
+	}
+	return rec
+}
+
+func TestReplicateFlush(t *testing.T) {
+	tgt, bck, db, r := newReplicateTest(t)
+
+	// put, marked as replica
+	lom := logTestObj(t, bck, db, "a", "content of a", RpPut)
+	tassert.Errorf(t, !r.flush("a"), "expected %s to be replicated", lom)
+	data, ok := tgt.backend.get("a")
+	tassert.Errorf(t, ok && data == "content of a", "unexpected replicated content %q", data)
+	tassert.Errorf(t, tgt.backend.md["a"][cmn.ReplicaObjMD] == bck.Bucket().String(),
+		"expected replica of %s, got %v", bck, tgt.backend.md["a"])
+	tassert.Errorf(t, rpLogged(t, db, lom) == nil, "expected %s record to be removed", lom)
+
+	// overwritten and logged anew while being replicated: the object is not locked, and gets
+	// replicated again
+	lom = logTestObj(t, bck, db, "b", "old", RpPut)
+	tgt.backend.putCb = func(*cluster.LOM) {
+		tgt.backend.putCb = nil
+		lom.Lock(true)
+		putTestObj(t, lom, "new", "")
+		time.Sleep(time.Millisecond) // (different time logged)
+		tassert.CheckFatal(t, LogReplication(db, lom, RpPut))
+		lom.Unlock(true)
+	}
+	tassert.Errorf(t, r.flush("b"), "expected %s to be replicated again", lom)
+	data, _ = tgt.backend.get("b")
+	tassert.Errorf(t, data == "old", "expected the snapshot to be replicated, got %q", data)
+	tassert.Errorf(t, rpLogged(t, db, lom) != nil, "expected %s to remain logged", lom)
+	tassert.Errorf(t, !r.flush("b"), "expected %s to be replicated", lom)
+	data, _ = tgt.backend.get("b")
+	tassert.Errorf(t, data == "new" && rpLogged(t, db, lom) == nil, "expected %q replicated, got %q", "new", data)
+
+	// delete
+	lom = loadTestObj(t, bck, "a")
+	tassert.CheckFatal(t, lom.Remove())
+	tassert.CheckFatal(t, LogReplication(db, lom, RpDelete))
+	tassert.Errorf(t, !r.flush("a"), "expected %s to be deleted", lom)
+	_, ok = tgt.backend.get("a")
+	tassert.Errorf(t, !ok && rpLogged(t, db, lom) == nil, "expected %s to be deleted and its record removed", lom)
+
+	// put of the object that's no longer here (e.g., migrated): dropped
+	puts := tgt.backend.puts
+	lom = logTestObj(t, bck, db, "c", "", RpPut)
+	tassert.Errorf(t, !r.flush("c"), "unexpected 'again'")
+	tassert.Errorf(t, tgt.backend.puts == puts && rpLogged(t, db, lom) == nil, "expected %s to be dropped", lom)
+
+	// resync: skipped when already replicated
+	lom = logTestObj(t, bck, db, "b", "new", RpResync)
+	r.flush("b")
+	tassert.Errorf(t, tgt.backend.puts == puts && rpLogged(t, db, lom) == nil, "expected %s to be skipped", lom)
+	lom = logTestObj(t, bck, db, "b", "newer", RpResync)
+	r.flush("b")
+	data, _ = tgt.backend.get("b")
+	tassert.Errorf(t, tgt.backend.puts == puts+1 && data == "newer", "expected %s to be replicated, got %q", lom, data)
+}
+
+func TestReplicateRetry(t *testing.T) {
+	tgt, bck, db, r := newReplicateTest(t)
+	tgt.backend.err = errors.New("service unavailable")
+
+	lom := logTestObj(t, bck, db, "a", "content of a", RpPut)
+	for i := 1; i <= dqMaxAttempts; i++ {
+		started := time.Now()
+		tassert.Errorf(t, !r.flush("a"), "unexpected 'again'")
+		rec := rpLogged(t, db, lom)
+		tassert.Fatalf(t, rec != nil && rec.Op == RpPut && rec.Attempts == i && strings.Contains(rec.Err, "unavailable"),
+			"unexpected record %+v", rec)
+		if i < dqMaxAttempts {
+			next := time.Unix(0, rec.Next)
+			tassert.Errorf(t, !rec.Failed && !next.Before(started.Add(dqBackoff(i))),
+				"attempt %d: expected retry not before %v, got %+v", i, dqBackoff(i), rec)
+		} else {
+			tassert.Errorf(t, rec.Failed, "expected failed, got %+v", rec)
+		}
+	}
+
+	// lag and failed changes are reported, but not queued until explicitly retried
+	tassert.CheckFatal(t, r.Load())
+	ext := r.Snap().(*xaction.SnapExt).Ext.(*ExtReplicateStats)
+	tassert.Errorf(t, ext.Logged == 1 && ext.Failed == 1 && ext.Pending == 0 && ext.Lag > 0,
+		"unexpected stats %+v", ext)
+	tgt.backend.err = nil
+	tassert.CheckFatal(t, r.RetryFailed())
+	rec := rpLogged(t, db, lom)
+	tassert.Fatalf(t, rec != nil && rec.Op == RpPut && !rec.Failed && rec.Attempts == 0, "unexpected record %+v", rec)
+	tassert.Fatalf(t, len(r.workCh) == 1 && r.Pending() == 1, "expected 1 queued (pending %d)", r.Pending())
+	objName := <-r.workCh
+	r.flush(objName)
+	r.dequeue(objName)
+	data, _ := tgt.backend.get("a")
+	tassert.Errorf(t, data == "content of a" && rpLogged(t, db, lom) == nil && r.Pending() == 0,
+		"expected %s to be replicated, got %q", lom, data)
+}
+
+func TestResyncLog(t *testing.T) {
+	_, bck, db, _ := newReplicateTest(t)
+	var replicated []string
+	r := &xactResync{args: &xreg.ResyncArgs{DB: db, Replicate: func(lom *cluster.LOM) {
+		replicated = append(replicated, lom.ObjName)
+	}}}
+
+	// logged and queued
+	lom := &cluster.LOM{ObjName: "a"}
+	tassert.CheckFatal(t, lom.Init(bck.Bck))
+	putTestObj(t, lom, "a", "")
+	tassert.CheckFatal(t, r.visitObj(lom, nil))
+	rec := rpLogged(t, db, lom)
+	tassert.Errorf(t, rec != nil && rec.Op == RpResync, "expected resync logged, got %+v", rec)
+
+	// pending change stays as is
+	lom = logTestObj(t, bck, db, "b", "b", RpDelete)
+	tassert.CheckFatal(t, r.visitObj(lom, nil))
+	rec = rpLogged(t, db, lom)
+	tassert.Errorf(t, rec != nil && rec.Op == RpDelete, "expected delete logged, got %+v", rec)
+
+	// replicas are not replicated any further
+	lom = &cluster.LOM{ObjName: "c"}
+	tassert.CheckFatal(t, lom.Init(bck.Bck))
+	putTestObj(t, lom, "c", cmn.ReplicaObjMD)
+	tassert.CheckFatal(t, r.visitObj(lom, nil))
+	tassert.Errorf(t, rpLogged(t, db, lom) == nil, "expected replica %s not to be logged", lom)
+
+	tassert.Errorf(t, len(replicated) == 1 && replicated[0] == "a", "expected [a] replicated, got %v", replicated)
+}
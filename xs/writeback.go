@@ -0,0 +1,270 @@
+// Package xs contains eXtended actions (xactions) except storage services
+// (mirror, ec) and extensions (downloader, lru).
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/xaction"
+	"github.com/NVIDIA/aistore/xreg"
+)
+
+// Write-back (bucket property `write_policy`):
+// - PUT completes once the object is stored locally (and, possibly, mirrored or erasure coded);
+//   the object is marked (cmn.WriteBackObjMD) and durably queued in the target's database;
+// - per-bucket on-demand xaction writes queued objects to the remote backend and, upon success,
+//   removes the mark and the queue record; the object is not locked while being written - the
+//   xaction uploads its snapshot (hard link) and writes it again if the object changes meanwhile,
+//   or deletes it from the remote backend if the object gets deleted meanwhile (the deletion
+//   that has found nothing to delete remotely - see ais/target.go);
+// - failures are retried with exponential backoff; after dqMaxAttempts the object is considered
+//   failed until the xaction is explicitly started (`ais job start write-back BUCKET`) -
+//   see xs/dqueue.go;
+// - the queue survives restarts: target's housekeeping periodically renews the xaction for the buckets
+//   that have queued objects (see Load);
+// - global rebalance: the target that receives a marked object queues it anew (the record
+//   on the sending target gets removed once the latter finds the object gone);
+// - objects pending write-back are never evicted (see LRU, lifecycle).
+
+const wbCollection = "writeback"
+
+type (
+	wbFactory struct {
+		xreg.RenewBase
+		xact *XactWriteBack
+	}
+	XactWriteBack struct {
+		xaction.DemandBase
+		dqueue
+		t cluster.Target
+	}
+	ExtWriteBackStats struct {
+		Pending int64 `json:"wb.pending.n,string"`
+		Failed  int64 `json:"wb.failed.n,string"`
+		IsIdle  bool  `json:"is_idle"`
+	}
+)
+
+// interface guard
+var (
+	_ cluster.Xact   = (*XactWriteBack)(nil)
+	_ xreg.Renewable = (*wbFactory)(nil)
+)
+
+// QueueWriteBack durably records the object that is yet to be written to its remote backend;
+// the caller is expected to write-lock the object.
+func QueueWriteBack(db dbdriver.Driver, lom *cluster.LOM) error {
+	return db.Set(wbCollection, lom.Uname(), &dqRecord{Queued: time.Now().UnixNano()})
+}
+
+// WriteBackBuckets returns the buckets that have objects queued for write-back.
+func WriteBackBuckets(db dbdriver.Driver) ([]cmn.Bck, error) {
+	return dqBuckets(db, wbCollection)
+}
+
+// DropWriteBack removes all write-back records of a given (e.g., destroyed) bucket.
+func DropWriteBack(db dbdriver.Driver, bck cmn.Bck) (int, error) {
+	return dqDrop(db, wbCollection, bck)
+}
+
+///////////////
+// wbFactory //
+///////////////
+
+func (*wbFactory) New(args xreg.Args, bck *cluster.Bck) xreg.Renewable {
+	return &wbFactory{RenewBase: xreg.RenewBase{Args: args, Bck: bck}}
+}
+
+func (p *wbFactory) Start() error {
+	uuid := p.UUID()
+	if uuid == "" {
+		uuid = cos.GenUUID()
+	}
+	p.xact = newXactWriteBack(p.T, uuid, p.Bck, p.Custom.(dbdriver.Driver))
+	go p.xact.Run(nil)
+	return nil
+}
+
+func (*wbFactory) Kind() string        { return cmn.ActWriteBack }
+func (p *wbFactory) Get() cluster.Xact { return p.xact }
+
+func (p *wbFactory) WhenPrevIsRunning(xprev xreg.Renewable) (xreg.WPR, error) {
+	debug.Assertf(false, "%s vs %s", p.Str(p.Kind()), xprev) // xreg.usePrev() must've returned true
+	return xreg.WprUse, nil
+}
+
+///////////////////
+// XactWriteBack //
+///////////////////
+
+func newXactWriteBack(t cluster.Target, uuid string, bck *cluster.Bck, db dbdriver.Driver) (r *XactWriteBack) {
+	r = &XactWriteBack{t: t}
+	r.DemandBase.Init(uuid, cmn.ActWriteBack, bck, 0 /*use default*/)
+	r.dqueue.init(&r.DemandBase, db, wbCollection, func() dqRec { return &dqRecord{} }, r.flush)
+	return
+}
+
+func (r *XactWriteBack) Run(*sync.WaitGroup) {
+	glog.Infoln(r.Name())
+	r.Finish(r.run())
+}
+
+// Writes the object to the remote backend without holding its lock: uploads the snapshot
+// (hard link) taken under the read lock, and then, under the write lock, makes sure that
+// the object has not changed before clearing the mark. Returns true if the object has been
+// overwritten in the meantime and must be written again.
+func (r *XactWriteBack) flush(objName string) (again bool) {
+	lom := cluster.AllocLOM(objName)
+	defer cluster.FreeLOM(lom)
+	if err := lom.Init(r.Bck().Bck); err != nil {
+		glog.Errorf("%s: %v", r, err) // (the record stays - see ais/tgtwriteback.go)
+		return
+	}
+	// NOTE: updating the record while holding the lock (a concurrent PUT queues it anew)
+	lom.Lock(false)
+	snap, finfo, err := r.snapshot(lom)
+	if snap == nil {
+		if err == nil {
+			r.dequeued(lom)
+		} else {
+			r.retry(lom, err)
+		}
+		lom.Unlock(false)
+		return
+	}
+	lom.Unlock(false)
+
+	err = r.write(snap)
+	if errRm := cos.RemoveFile(snap.FQN); errRm != nil {
+		glog.Errorf("%s: failed to remove %s: %v", r, snap.FQN, errRm)
+	}
+
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if unchanged, deleted := r.unchanged(lom, snap, finfo); !unchanged {
+		cluster.FreeLOM(snap)
+		if deleted && err == nil {
+			r.undo(lom)
+		}
+		return true // overwritten, deleted, or written-through in the meantime
+	}
+	if err != nil {
+		cluster.FreeLOM(snap)
+		r.retry(lom, err)
+		return
+	}
+	for k, v := range snap.GetCustomMD() {
+		if k != cmn.WriteBackObjMD {
+			lom.SetCustomKey(k, v)
+		}
+	}
+	lom.ObjAttrs().DelCustomKeys(cmn.WriteBackObjMD)
+	size := snap.PlainAttrs().Size
+	cluster.FreeLOM(snap)
+	if err = lom.Persist(); err != nil {
+		r.retry(lom, err)
+		return
+	}
+	r.ObjsAdd(1, size)
+	r.dequeued(lom)
+	return
+}
+
+// Snapshots the object that is pending write-back (the caller holds the read lock);
+// returns nil if there is nothing to write (e.g., the object was deleted or overwritten
+// while the bucket was write-through).
+func (*XactWriteBack) snapshot(lom *cluster.LOM) (snap *cluster.LOM, finfo os.FileInfo, err error) {
+	if err = lom.Load(true /*cache it*/, true /*locked*/); err != nil {
+		if cmn.IsObjNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	if !lom.WriteBackPending() {
+		return
+	}
+	return dqSnapshot(lom, fs.WorkfileWriteBack)
+}
+
+// whether the object (the caller holds the write lock) is still the one that was snapshot:
+// same file (objects get overwritten by renaming) and checksum, and still pending write-back
+func (*XactWriteBack) unchanged(lom, snap *cluster.LOM, finfo os.FileInfo) (unchanged, deleted bool) {
+	if err := lom.Load(true /*cache it*/, true /*locked*/); err != nil {
+		return false, cmn.IsObjNotExist(err)
+	}
+	if !lom.WriteBackPending() {
+		return
+	}
+	ty, val := lom.Checksum().Get()
+	if sty, sval := snap.Checksum().Get(); ty != sty || val != sval || lom.SizeBytes() != snap.SizeBytes() {
+		return
+	}
+	fi, err := os.Stat(lom.FQN)
+	return err == nil && os.SameFile(fi, finfo), false
+}
+
+// the object has been deleted while being written: delete the (just written) remote copy
+// (the caller holds the write lock)
+func (r *XactWriteBack) undo(lom *cluster.LOM) {
+	if _, err := r.t.Backend(lom.Bck()).DeleteObj(lom); err != nil {
+		glog.Errorf("%s: %s deleted while being written, failed to delete it from %s: %v",
+			r, lom, lom.Bck(), err)
+	}
+}
+
+// write the snapshot's (plaintext) content to the remote backend
+func (r *XactWriteBack) write(snap *cluster.LOM) (err error) {
+	var (
+		fh      cos.ReadOpenCloser
+		backend = r.t.Backend(snap.Bck())
+		cksum   = snap.Checksum()
+	)
+	if fh, err = snap.OpenPlain(); err != nil {
+		return
+	}
+	if snap.IsEncrypted() {
+		snap.SetCksum(snap.PlainAttrs().Cksum)
+	}
+	_, err = backend.PutObj(fh, snap)
+	snap.SetCksum(cksum)
+	if err == nil {
+		snap.SetCustomKey(cmn.SourceObjMD, backend.Provider())
+	}
+	return
+}
+
+func (r *XactWriteBack) dequeued(lom *cluster.LOM) { r.done(lom.Uname()) }
+
+func (r *XactWriteBack) retry(lom *cluster.LOM, err error) {
+	rec := &dqRecord{}
+	if errGet := r.db.Get(wbCollection, lom.Uname(), rec); errGet != nil {
+		rec = &dqRecord{} // (e.g., dropped in the meantime)
+	}
+	if r.dqueue.retry(lom.Uname(), rec, err) {
+		glog.Errorf("%s: failed to write %s (attempts: %d): %v", r, lom, rec.Attempts, err)
+	} else if verbose {
+		glog.Infof("%s: failed to write %s (attempt %d): %v - will retry", r, lom, rec.Attempts, err)
+	}
+}
+
+func (r *XactWriteBack) Snap() cluster.XactionSnap {
+	snap := r.DemandBase.ExtSnap()
+	snap.Ext = &ExtWriteBackStats{
+		Pending: r.Pending(),
+		Failed:  r.failed.Load(),
+		IsIdle:  r.Pending() == 0,
+	}
+	return snap
+}
@@ -0,0 +1,272 @@
+// Package xs contains eXtended actions (xactions) except storage services
+// (mirror, ec) and extensions (downloader, lru).
+/*
+ * Copyright (c) 2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package xs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cluster/mock"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/hk"
+)
+
+type (
+	// remote backend that keeps the objects in memory
+	memBackend struct {
+		cluster.BackendProvider
+		objs  map[string]string
+		md    map[string]cos.SimpleKVs // custom metadata (as in: written by PutObj)
+		err   error
+		putCb func(lom *cluster.LOM) // (called while writing)
+		puts  int
+		mu    sync.Mutex
+	}
+	memTarget struct {
+		*mock.TargetMock
+		backend *memBackend
+	}
+)
+
+func (t *memTarget) Backend(*cluster.Bck) cluster.BackendProvider { return t.backend }
+
+func (*memBackend) Provider() string { return cmn.ProviderAmazon }
+
+func (b *memBackend) PutObj(r io.ReadCloser, lom *cluster.LOM) (int, error) {
+	defer r.Close()
+	if b.putCb != nil {
+		b.putCb(lom)
+	}
+	if b.err != nil {
+		return http.StatusServiceUnavailable, b.err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	b.mu.Lock()
+	b.objs[lom.ObjName] = string(data)
+	b.md[lom.ObjName] = make(cos.SimpleKVs, 2)
+	for k, v := range lom.GetCustomMD() {
+		b.md[lom.ObjName][k] = v
+	}
+	b.puts++
+	b.mu.Unlock()
+	lom.SetCustomKey(cmn.ETag, "etag")
+	return 0, nil
+}
+
+func (b *memBackend) HeadObj(_ context.Context, lom *cluster.LOM) (*cmn.ObjAttrs, int, error) {
+	data, ok := b.get(lom.ObjName)
+	if !ok {
+		return nil, http.StatusNotFound, cmn.NewErrNotFound("%s", lom)
+	}
+	cksum, err := cos.ChecksumBytes([]byte(data), cos.ChecksumXXHash)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &cmn.ObjAttrs{Size: int64(len(data)), Cksum: cksum}, 0, nil
+}
+
+func (b *memBackend) DeleteObj(lom *cluster.LOM) (int, error) {
+	if b.err != nil {
+		return http.StatusServiceUnavailable, b.err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.objs[lom.ObjName]; !ok {
+		return http.StatusNotFound, cmn.NewErrNotFound("%s", lom)
+	}
+	delete(b.objs, lom.ObjName)
+	return 0, nil
+}
+
+func (b *memBackend) get(objName string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.objs[objName]
+	return data, ok
+}
+
+func newMemTarget(t *testing.T, bcks ...*cluster.Bck) (tgt *memTarget, db dbdriver.Driver) {
+	mpath := t.TempDir()
+	fs.TestNew(nil)
+	fs.TestDisableValidation()
+	_, err := fs.Add(mpath, "daeID")
+	tassert.CheckFatal(t, err)
+	_ = fs.CSM.Reg(fs.ObjectType, &fs.ObjectContentResolver{})
+	_ = fs.CSM.Reg(fs.WorkfileType, &fs.WorkfileContentResolver{})
+	hk.TestInit()
+
+	tgt = &memTarget{
+		TargetMock: mock.NewTarget(cluster.NewBaseBownerMock(bcks...)),
+		backend:    &memBackend{objs: make(map[string]string), md: make(map[string]cos.SimpleKVs)},
+	}
+	db, err = dbdriver.NewBuntDB(filepath.Join(t.TempDir(), "test.db"))
+	tassert.CheckFatal(t, err)
+	t.Cleanup(func() { db.Close() })
+	return
+}
+
+// writes the object and marks it (custom key), if any; the caller holds the write lock, if need be
+func putTestObj(t *testing.T, lom *cluster.LOM, content, mark string) {
+	workFQN := lom.FQN + ".tmp"
+	fh, err := cos.CreateFile(workFQN)
+	tassert.CheckFatal(t, err)
+	_, err = fh.WriteString(content)
+	tassert.CheckFatal(t, err)
+	fh.Close()
+	tassert.CheckFatal(t, cos.Rename(workFQN, lom.FQN))
+	lom.SetSize(int64(len(content)))
+	cksum, err := cos.ChecksumBytes([]byte(content), cos.ChecksumXXHash)
+	tassert.CheckFatal(t, err)
+	lom.SetCksum(cksum)
+	lom.SetAtimeUnix(time.Now().UnixNano())
+	if mark != "" {
+		lom.SetCustomKey(mark, "true")
+	}
+	tassert.CheckFatal(t, lom.Persist())
+}
+
+func loadTestObj(t *testing.T, bck *cluster.Bck, objName string) *cluster.LOM {
+	lom := &cluster.LOM{ObjName: objName}
+	tassert.CheckFatal(t, lom.Init(bck.Bck))
+	tassert.CheckFatal(t, lom.Load(false /*cache it*/, false /*locked*/))
+	return lom
+}
+
+func newWriteBackTest(t *testing.T) (tgt *memTarget, bck *cluster.Bck, db dbdriver.Driver, r *XactWriteBack) {
+	bck = cluster.NewBck("wb", cmn.ProviderAmazon, cmn.NsGlobal,
+		&cmn.BucketProps{Cksum: cmn.CksumConf{Type: cos.ChecksumXXHash}, WritePolicy: cmn.WriteBack})
+	tgt, db = newMemTarget(t, bck)
+	r = newXactWriteBack(tgt, cos.GenUUID(), bck, db)
+	t.Cleanup(r.stop)
+	return
+}
+
+func queueTestObj(t *testing.T, bck *cluster.Bck, db dbdriver.Driver, objName, content string) *cluster.LOM {
+	lom := &cluster.LOM{ObjName: objName}
+	tassert.CheckFatal(t, lom.Init(bck.Bck))
+	putTestObj(t, lom, content, cmn.WriteBackObjMD)
+	tassert.CheckFatal(t, QueueWriteBack(db, lom))
+	return lom
+}
+
+func TestWriteBackFlush(t *testing.T) {
+	tgt, bck, db, r := newWriteBackTest(t)
+
+	lom := queueTestObj(t, bck, db, "a", "content of a")
+	again := r.flush("a")
+	tassert.Errorf(t, !again, "expected %s to be written", lom)
+	data, ok := tgt.backend.get("a")
+	tassert.Errorf(t, ok && data == "content of a", "unexpected remote content %q", data)
+	lom = loadTestObj(t, bck, "a")
+	tassert.Errorf(t, !lom.WriteBackPending(), "expected %s to be written back", lom)
+	etag, _ := lom.GetCustomKey(cmn.ETag)
+	src, _ := lom.GetCustomKey(cmn.SourceObjMD)
+	tassert.Errorf(t, etag == "etag" && src == cmn.ProviderAmazon, "expected backend metadata, got %v", lom.GetCustomMD())
+	err := db.Get(wbCollection, lom.Uname(), &dqRecord{})
+	tassert.Errorf(t, dbdriver.IsErrNotFound(err), "expected no record, got %v", err)
+	snaps, _ := filepath.Glob(filepath.Join(filepath.Dir(fs.CSM.Gen(lom, fs.WorkfileType, fs.WorkfileWriteBack)), "*"))
+	tassert.Errorf(t, len(snaps) == 0, "expected no snapshots, got %v", snaps)
+
+	// overwritten while being written: the object is not locked, and gets written again
+	lom = queueTestObj(t, bck, db, "b", "old")
+	tgt.backend.putCb = func(*cluster.LOM) {
+		tgt.backend.putCb = nil
+		lom.Lock(true)
+		putTestObj(t, lom, "new", cmn.WriteBackObjMD)
+		lom.Unlock(true)
+	}
+	again = r.flush("b")
+	tassert.Errorf(t, again, "expected overwritten %s to be written again", lom)
+	data, _ = tgt.backend.get("b")
+	tassert.Errorf(t, data == "old", "expected the snapshot to be written, got %q", data)
+	tassert.Errorf(t, loadTestObj(t, bck, "b").WriteBackPending(), "expected %s to remain pending", lom)
+	again = r.flush("b")
+	data, _ = tgt.backend.get("b")
+	tassert.Errorf(t, !again && data == "new", "expected %q written, got %q (again %t)", "new", data, again)
+	tassert.Errorf(t, !loadTestObj(t, bck, "b").WriteBackPending(), "expected %s to be written back", lom)
+
+	// deleted in the meantime: nothing to write
+	lom = queueTestObj(t, bck, db, "c", "c")
+	tassert.CheckFatal(t, lom.Remove())
+	again = r.flush("c")
+	_, ok = tgt.backend.get("c")
+	err = db.Get(wbCollection, lom.Uname(), &dqRecord{})
+	tassert.Errorf(t, !again && !ok && dbdriver.IsErrNotFound(err), "expected %s to be dropped (%v)", lom, err)
+
+	// deleted while being written: deleted from the remote backend as well
+	lom = queueTestObj(t, bck, db, "d", "d")
+	tgt.backend.putCb = func(*cluster.LOM) {
+		tgt.backend.putCb = nil
+		lom.Lock(true)
+		tassert.CheckFatal(t, lom.Remove())
+		lom.Unlock(true)
+	}
+	again = r.flush("d")
+	_, ok = tgt.backend.get("d")
+	tassert.Errorf(t, again && !ok, "expected %s to be deleted remotely (again %t)", lom, again)
+	again = r.flush("d")
+	err = db.Get(wbCollection, lom.Uname(), &dqRecord{})
+	tassert.Errorf(t, !again && dbdriver.IsErrNotFound(err), "expected %s to be dropped (%v)", lom, err)
+}
+
+func TestWriteBackRetry(t *testing.T) {
+	tgt, bck, db, r := newWriteBackTest(t)
+	tgt.backend.err = errors.New("service unavailable")
+
+	lom := queueTestObj(t, bck, db, "a", "content of a")
+	for i := 1; i <= dqMaxAttempts; i++ {
+		started := time.Now()
+		tassert.Errorf(t, !r.flush("a"), "unexpected 'again'")
+		rec := &dqRecord{}
+		tassert.CheckFatal(t, db.Get(wbCollection, lom.Uname(), rec))
+		tassert.Fatalf(t, rec.Attempts == i && strings.Contains(rec.Err, "unavailable"), "unexpected record %+v", rec)
+		if i < dqMaxAttempts {
+			next := time.Unix(0, rec.Next)
+			tassert.Errorf(t, !rec.Failed && !next.Before(started.Add(dqBackoff(i))),
+				"attempt %d: expected retry not before %v, got %+v", i, dqBackoff(i), rec)
+		} else {
+			tassert.Errorf(t, rec.Failed && r.failed.Load() == 1, "expected failed, got %+v", rec)
+		}
+	}
+	tassert.Errorf(t, loadTestObj(t, bck, "a").WriteBackPending(), "expected %s to remain pending", lom)
+
+	// failed objects are not queued until explicitly retried
+	tassert.CheckFatal(t, r.Load())
+	tassert.Errorf(t, len(r.workCh) == 0 && r.failed.Load() == 1, "expected nothing queued and 1 failed")
+	tgt.backend.err = nil
+	tassert.CheckFatal(t, r.RetryFailed())
+	tassert.Fatalf(t, len(r.workCh) == 1 && r.Pending() == 1 && r.failed.Load() == 0,
+		"expected 1 queued (pending %d, failed %d)", r.Pending(), r.failed.Load())
+	objName := <-r.workCh
+	r.flush(objName)
+	r.dequeue(objName)
+	err := db.Get(wbCollection, lom.Uname(), &dqRecord{})
+	tassert.Errorf(t, dbdriver.IsErrNotFound(err) && r.Pending() == 0, "expected %s to be written (%v)", lom, err)
+	data, _ := tgt.backend.get("a")
+	tassert.Errorf(t, data == "content of a", "unexpected remote content %q", data)
+
+	// backoff
+	for attempts, delay := range map[int]time.Duration{1: dqRetryDelay, 2: 2 * dqRetryDelay, 4: 8 * dqRetryDelay,
+		10: dqMaxRetryDelay, 100: dqMaxRetryDelay} {
+		tassert.Errorf(t, dqBackoff(attempts) == delay, "attempts %d: expected %v, got %v", attempts, delay,
+			dqBackoff(attempts))
+	}
+}
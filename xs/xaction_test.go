@@ -5,6 +5,7 @@
 package xs_test
 
 import (
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -13,7 +14,9 @@ import (
 	"github.com/NVIDIA/aistore/cluster/mock"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/dbdriver"
 	"github.com/NVIDIA/aistore/devtools/tassert"
+	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/space"
 	"github.com/NVIDIA/aistore/xaction"
 	"github.com/NVIDIA/aistore/xreg"
@@ -66,7 +69,7 @@ func TestXactionRenewLRU(t *testing.T) {
 
 func TestXactionRenewPrefetch(t *testing.T) {
 	var (
-		evArgs = &cmn.ListRangeMsg{}
+		evArgs = &cmn.PrefetchMsg{}
 		bmd    = cluster.NewBaseBownerMock()
 		bck    = cluster.NewBck(
 			"test", cmn.ProviderGoogle, cmn.NsGlobal,
@@ -226,7 +229,7 @@ func TestXactionQueryFinished(t *testing.T) {
 
 	rns1 = xreg.RenewBckRename(tMock, bck1, bck1, cos.GenUUID(), 123, "phase")
 	tassert.Errorf(t, rns1.Err == nil && rns1.Entry.Get() != nil, "Xaction must be created")
-	rns3 := xreg.RenewPrefetch(cos.GenUUID(), tMock, bck3, &cmn.ListRangeMsg{})
+	rns3 := xreg.RenewPrefetch(cos.GenUUID(), tMock, bck3, &cmn.PrefetchMsg{})
 	tassert.Errorf(t, rns3.Entry.Get() != nil, "Xaction must be created %v", rns3.Err)
 
 	xactBck1 := rns1.Entry.Get()
@@ -276,3 +279,47 @@ func TestXactionQueryFinished(t *testing.T) {
 		f(t, test)
 	}
 }
+
+func TestXactionRenewWriteBack(t *testing.T) {
+	var (
+		bmd = cluster.NewBaseBownerMock()
+		bck = cluster.NewBck(
+			"test", cmn.ProviderAmazon, cmn.NsGlobal,
+			&cmn.BucketProps{Cksum: cmn.CksumConf{Type: cos.ChecksumXXHash}, WritePolicy: cmn.WriteBack},
+		)
+		tMock = mock.NewTarget(bmd)
+	)
+	xreg.TestReset()
+	bmd.Add(bck)
+	xs.Init()
+	hk.TestInit()
+	defer xreg.AbortAll()
+
+	db, err := dbdriver.NewBuntDB(filepath.Join(t.TempDir(), "test.db"))
+	tassert.CheckFatal(t, err)
+	defer db.Close()
+
+	rns := xreg.RenewWriteBack(tMock, bck, db)
+	tassert.CheckFatal(t, rns.Err)
+	xwb := rns.Entry.Get().(*xs.XactWriteBack)
+	rns = xreg.RenewWriteBack(tMock, bck, db)
+	tassert.CheckFatal(t, rns.Err)
+	tassert.Errorf(t, rns.Entry.Get() == xwb, "expected the same (on-demand) xaction")
+
+	// a record of the object that has exhausted its attempts
+	tassert.CheckFatal(t, db.SetString("writeback", bck.MakeUname("obj"), `{"attempts": 5, "failed": true}`))
+	tassert.CheckFatal(t, xwb.Load())
+	ext := xwb.Snap().(*xaction.SnapExt).Ext.(*xs.ExtWriteBackStats)
+	tassert.Errorf(t, ext.Failed == 1 && ext.Pending == 0, "expected (failed, pending) = (1, 0), got (%d, %d)",
+		ext.Failed, ext.Pending)
+
+	bcks, err := xs.WriteBackBuckets(db)
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(bcks) == 1 && bcks[0].Equal(bck.Bck), "expected %s, got %v", bck, bcks)
+	n, err := xs.DropWriteBack(db, bck.Bck)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, n == 1, "expected 1 dropped record, got %d", n)
+	bcks, err = xs.WriteBackBuckets(db)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(bcks) == 0, "expected no buckets, got %v", bcks)
+}